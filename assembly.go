@@ -0,0 +1,192 @@
+package main
+
+//  █████╗ ███████╗███████╗███████╗███╗   ███╗██████╗ ██╗  ██╗   ██╗
+// ██╔══██╗██╔════╝██╔════╝██╔════╝████╗ ████║██╔══██╗██║  ╚██╗ ██╔╝
+// ███████║███████╗███████╗█████╗  ██╔████╔██║██████╔╝██║   ╚████╔╝
+// ██╔══██║╚════██║╚════██║██╔══╝  ██║╚██╔╝██║██╔══██╗██║    ╚██╔╝
+// ██║  ██║███████║███████║███████╗██║ ╚═╝ ██║██████╔╝███████╗██║
+// ╚═╝  ╚═╝╚══════╝╚══════╝╚══════╝╚═╝     ╚═╝╚═════╝ ╚══════╝╚═╝
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+// Assembly limits
+const (
+	assemblySupports = 2   // placed neighbours a panel needs if it is not on the base
+	assemblyStepTime = 0.5 // s, each step is shown for when playing
+)
+
+// Colours of the panels when playing the assembly
+var (
+	assemblyPlaced = math32.Color{R: 0.7, G: 0.7, B: 0.7}
+	assemblyLatest = math32.Color{R: 1, G: 0.5, B: 0}
+)
+
+// AssemblyStep is putting up one panel, on the base or on the panels already up beside it
+type AssemblyStep struct {
+	N        int // from 1
+	Panel    *Panel
+	Course   int
+	Supports []*Panel // placed neighbours it is fixed to, none if it stands on the base
+}
+
+// AssemblyPlan is the order the panels go up in, and any which can never be supported
+type AssemblyPlan struct {
+	Steps    []AssemblyStep
+	Unplaced []*Panel
+}
+
+// standsOnBase is true if a corner of the panel is on the base
+func (e *EShell) standsOnBase(p *Panel) bool {
+	for _, v := range p.Corners {
+		if v.Position.Z() < e.Base+onBaseTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// AssemblySequence builds the shell from the bottom up: course by course from the base ring,
+//   each panel either standing on the base or fixed to at least assemblySupports panels
+//   already up. The lowest panel which can go up next always does, by course, then height,
+//   then serial.
+func (e *EShell) AssemblySequence() AssemblyPlan {
+	todo := alivePanels(e.Panels)
+	course := map[*Panel]int{}
+	for _, p := range todo {
+		course[p] = e.Course(p)
+	}
+	sort.Slice(todo, func(i, j int) bool {
+		a, b := todo[i], todo[j]
+		if course[a] != course[b] {
+			return course[a] < course[b]
+		}
+		if a.Center.Z() != b.Center.Z() {
+			return a.Center.Z() < b.Center.Z()
+		}
+		return a.Serial < b.Serial
+	})
+	placed := map[*Panel]bool{}
+	var plan AssemblyPlan
+	for len(todo) > 0 {
+		next := -1
+		var sups []*Panel
+		for i, p := range todo {
+			sups = nil
+			if e.standsOnBase(p) {
+				next = i
+				break
+			}
+			for _, q := range p.Neighbours() {
+				if placed[q] {
+					sups = append(sups, q)
+				}
+			}
+			if len(sups) >= assemblySupports {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			plan.Unplaced = todo
+			break
+		}
+		p := todo[next]
+		placed[p] = true
+		plan.Steps = append(plan.Steps, AssemblyStep{N: len(plan.Steps) + 1, Panel: p, Course: course[p], Supports: sups})
+		todo = append(todo[:next], todo[next+1:]...)
+	}
+	return plan
+}
+
+func (s AssemblyStep) String() string {
+	if len(s.Supports) == 0 {
+		return fmt.Sprintf("Step %d: %s, course %d, on the base", s.N, PanelLabel(s.Panel), s.Course)
+	}
+	var ls []string
+	for _, q := range s.Supports {
+		ls = append(ls, PanelLabel(q))
+	}
+	return fmt.Sprintf("Step %d: %s, course %d, to %s", s.N, PanelLabel(s.Panel), s.Course, strings.Join(ls, ", "))
+}
+
+// String lists the steps, and any panels which cannot be put up
+func (a AssemblyPlan) String() string {
+	s := fmt.Sprintf("Assembly: %d steps\n", len(a.Steps))
+	for _, st := range a.Steps {
+		s += fmt.Sprintf("   %s\n", st)
+	}
+	if len(a.Unplaced) > 0 {
+		var ls []string
+		for _, p := range a.Unplaced {
+			ls = append(ls, PanelLabel(p))
+		}
+		s += fmt.Sprintf("   ERROR: nothing to fix %s to\n", strings.Join(ls, ", "))
+	}
+	return s
+}
+
+// CSV is the steps, one to a row, for the assembly guide
+func (a AssemblyPlan) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Step", "Panel", "Course", "Fixed to"})
+	for _, st := range a.Steps {
+		var ls []string
+		for _, q := range st.Supports {
+			ls = append(ls, PanelLabel(q))
+		}
+		w.Write([]string{fmt.Sprint(st.N), PanelLabel(st.Panel), fmt.Sprint(st.Course), strings.Join(ls, " ")})
+	}
+	for _, p := range a.Unplaced {
+		w.Write([]string{"", PanelLabel(p), fmt.Sprint(p.Shell.Course(p)), "unsupported"})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// PrepAssembly makes the mesh of the panels up after n steps, the last one put up highlighted,
+//   for a material which shows vertex colours
+func (e *EShell) PrepAssembly(a AssemblyPlan, n int, mat material.IMaterial) *EShellMesh {
+	if n > len(a.Steps) {
+		n = len(a.Steps)
+	}
+	geom := geometry.NewGeometry()
+	positions := math32.NewArrayF32(0, 3*3*n)
+	colours := math32.NewArrayF32(0, 3*3*n)
+	indices := math32.NewArrayU32(0, 3*n)
+	var idx uint32
+	for i, st := range a.Steps[:n] {
+		c := assemblyPlaced
+		if i == n-1 {
+			c = assemblyLatest
+		}
+		vs := st.Panel.drawCorners()
+		if len(vs) < 3 {
+			continue
+		}
+		for _, v := range vs[:3] {
+			positions = appendXZY(positions, v.Position)
+			colours = append(colours, c.R, c.G, c.B)
+		}
+		indices = append(indices, idx, idx+1, idx+2)
+		idx += 3
+	}
+	geom.SetIndices(indices)
+	geom.AddVBO(gls.NewVBO(positions).AddAttrib(gls.VertexPosition))
+	geom.AddVBO(gls.NewVBO(colours).AddAttrib(gls.VertexColor))
+
+	m := EShellMesh{}
+	m.Mesh.Init(geom, mat)
+	return &m
+}
@@ -0,0 +1,196 @@
+package main
+
+// ██████╗  █████╗ ███████╗███████╗    ███╗   ███╗███████╗███╗   ███╗██████╗ ███████╗██████╗
+// ██╔══██╗██╔══██╗██╔════╝██╔════╝    ████╗ ████║██╔════╝████╗ ████║██╔══██╗██╔════╝██╔══██╗
+// ██████╔╝███████║███████╗█████╗      ██╔████╔██║█████╗  ██╔████╔██║██████╔╝█████╗  ██████╔╝
+// ██╔══██╗██╔══██║╚════██║██╔══╝      ██║╚██╔╝██║██╔══╝  ██║╚██╔╝██║██╔══██╗██╔══╝  ██╔══██╗
+// ██████╔╝██║  ██║███████║███████╗    ██║ ╚═╝ ██║███████╗██║ ╚═╝ ██║██████╔╝███████╗██║  ██║
+// ╚═════╝ ╚═╝  ╚═╝╚══════╝╚══════╝    ╚═╝     ╚═╝╚══════╝╚═╝     ╚═╝╚═════╝ ╚══════╝╚═╝  ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	v3 "./vec"
+)
+
+// Sizes for the base ring member, an angle rolled to follow the floor line with its horizontal
+//   leg on the slab, inside the shell
+const (
+	baseMemberShip    = 20 * ft2m // m, longest piece that can be shipped
+	baseMemberWeb     = 0.075     // m, height of the vertical leg
+	baseMemberLeg     = 0.075     // m, width of the horizontal leg
+	baseMemberSamples = 720       // points round the floor line
+	spliceHoleDia     = 0.014     // m, for M12 bolts
+	splicePitch       = 0.05      // m, between the bolts of a splice, and from them to the joint
+	spliceRows        = 2         // bolts each side of the joint
+)
+
+// BaseMember is one shippable piece of the base ring
+type BaseMember struct {
+	N          int      // number, anticlockwise from +X
+	Start, End float64  // m, along the floor line from +X
+	Curve      []v3.Vec // the heel of the angle, along the floor line
+}
+
+// floorLine samples the line where the shell meets the floor, closed, with the distance along
+//   it to each point
+func (e *EShell) floorLine() ([]v3.Vec, []float64) {
+	var pts []v3.Vec
+	var at []float64
+	for i := 0; i <= baseMemberSamples; i++ {
+		a := 2 * math.Pi * float64(i) / baseMemberSamples
+		p := e.surface().SectionAt(e.Base, v3.NewSimVec(math.Cos(a), math.Sin(a), 0))
+		s := 0.0
+		if i > 0 {
+			s = at[i-1] + p.Subtract(pts[i-1]).Length()
+		}
+		pts = append(pts, p)
+		at = append(at, s)
+	}
+	return pts, at
+}
+
+// BaseMembers divides the base ring into the fewest equal pieces which can be shipped
+func (e *EShell) BaseMembers() []BaseMember {
+	pts, at := e.floorLine()
+	total := at[len(at)-1]
+	n := int(math.Ceil(total / baseMemberShip))
+	if n < 1 {
+		return nil
+	}
+	interp := func(s float64) v3.Vec {
+		for i := 1; i < len(at); i++ {
+			if at[i] >= s {
+				f := (s - at[i-1]) / (at[i] - at[i-1])
+				return pts[i-1].Add(pts[i].Subtract(pts[i-1]).Scale(f))
+			}
+		}
+		return pts[len(pts)-1]
+	}
+	ms := []BaseMember{}
+	for k := 0; k < n; k++ {
+		m := BaseMember{N: k + 1, Start: total * float64(k) / float64(n), End: total * float64(k+1) / float64(n)}
+		m.Curve = append(m.Curve, interp(m.Start))
+		for i, s := range at {
+			if s > m.Start && s < m.End {
+				m.Curve = append(m.Curve, pts[i])
+			}
+		}
+		m.Curve = append(m.Curve, interp(m.End))
+		ms = append(ms, m)
+	}
+	return ms
+}
+
+// Length is the length of the piece along its heel
+func (m BaseMember) Length() float64 {
+	return m.End - m.Start
+}
+
+// Radius is the radius it has to be rolled to, from the circle through its ends and middle
+func (m BaseMember) Radius() float64 {
+	a, b, c := m.Curve[0], m.Curve[len(m.Curve)/2], m.Curve[len(m.Curve)-1]
+	ab, bc, ca := b.Subtract(a).Length(), c.Subtract(b).Length(), a.Subtract(c).Length()
+	area2 := b.Subtract(a).Cross(c.Subtract(a)).Length()
+	if area2 < v3.PlanckLength {
+		return math.Inf(1)
+	}
+	return ab * bc * ca / (2 * area2)
+}
+
+// inward is the unit vector in plan square to the curve at point i, towards the inside of the ring
+func (m BaseMember) inward(i int) cam.Vec2 {
+	j, k := i-1, i+1
+	if j < 0 {
+		j = 0
+	}
+	if k >= len(m.Curve) {
+		k = len(m.Curve) - 1
+	}
+	t := m.Curve[k].Subtract(m.Curve[j])
+	in := cam.NewVec2(-t.Y(), t.X()) // left of anticlockwise
+	return in.Scale(1 / in.Length())
+}
+
+// spliceHoles are the bolt holes at each end, at distances along from the joint
+func spliceHoles() []float64 {
+	var hs []float64
+	for i := 0; i < spliceRows; i++ {
+		hs = append(hs, splicePitch*float64(i+1))
+	}
+	return hs
+}
+
+// RolledDrawing is the plan of the horizontal leg, full size in mm, for checking the rolling:
+//   the heel as a fold line, the toe as an edge, the ends and the splice holes
+func (m BaseMember) RolledDrawing() cam.Drawing {
+	d := cam.Drawing{Name: fmt.Sprintf("Base member %d rolled, %.0fmm long, radius %.0fmm", m.N, m.Length()*m2mm, m.Radius()*m2mm), ID: m.N}
+	var heel, toe []cam.Vec2
+	for i, p := range m.Curve {
+		h := cam.NewVec2(p.X()*m2mm, p.Y()*m2mm)
+		heel = append(heel, h)
+		toe = append(toe, h.Add(m.inward(i).Scale(baseMemberLeg*m2mm)))
+	}
+	last := len(heel) - 1
+	for i := 1; i <= last; i++ {
+		d.Paths = append(d.Paths, linePath(heel[i-1], heel[i], cam.FoldPath))
+		d.Paths = append(d.Paths, linePath(toe[i-1], toe[i], cam.EdgePath))
+	}
+	d.Paths = append(d.Paths, linePath(heel[0], toe[0], cam.EdgePath), linePath(heel[last], toe[last], cam.EdgePath))
+
+	// Splice holes in the middle of the leg, measured along the heel from each end
+	at := []float64{0}
+	for i := 1; i <= last; i++ {
+		at = append(at, at[i-1]+heel[i].Subtract(heel[i-1]).Length())
+	}
+	hole := func(s float64) {
+		for i := 1; i <= last; i++ {
+			if at[i] >= s {
+				f := (s - at[i-1]) / (at[i] - at[i-1])
+				c := heel[i-1].Add(heel[i].Subtract(heel[i-1]).Scale(f)).Add(m.inward(i).Scale(baseMemberLeg * m2mm / 2))
+				d.Paths = append(d.Paths, cam.NewCirclePath(c, spliceHoleDia*m2mm/2, cam.EdgePath))
+				return
+			}
+		}
+	}
+	for _, s := range spliceHoles() {
+		hole(s * m2mm)
+		hole(at[last] - s*m2mm)
+	}
+	return d
+}
+
+// FlatDrawing is the vertical leg developed flat, mm: a strip the length of the heel with the
+//   heel as a fold line and the splice holes at each end
+func (m BaseMember) FlatDrawing() cam.Drawing {
+	l, w := m.Length()*m2mm, baseMemberWeb*m2mm
+	d := cam.Drawing{Name: fmt.Sprintf("Base member %d web, %.0fmm x %.0fmm", m.N, l, w), ID: m.N}
+	d.Paths = append(d.Paths, linePath(cam.NewVec2(0, 0), cam.NewVec2(l, 0), cam.FoldPath))
+	d.Paths = append(d.Paths, linePath(cam.NewVec2(l, 0), cam.NewVec2(l, w), cam.EdgePath))
+	d.Paths = append(d.Paths, linePath(cam.NewVec2(l, w), cam.NewVec2(0, w), cam.EdgePath))
+	d.Paths = append(d.Paths, linePath(cam.NewVec2(0, w), cam.NewVec2(0, 0), cam.EdgePath))
+	for _, s := range spliceHoles() {
+		for _, x := range []float64{s * m2mm, l - s*m2mm} {
+			d.Paths = append(d.Paths, cam.NewCirclePath(cam.NewVec2(x, w/2), spliceHoleDia*m2mm/2, cam.EdgePath))
+		}
+	}
+	d.Paths = append(d.Paths, labelAt(fmt.Sprintf("%d", m.N), cam.Plain, cam.NewVec2(l/2, w/2), cam.NewVec2(0, 1)))
+	return d
+}
+
+// SplicePlate is the flat plate bolted across each joint, one inside the web and one on top
+//   of the leg, mm
+func SplicePlate() cam.Drawing {
+	d := cam.Drawing{Name: fmt.Sprintf("Base member splice plate, %.0fmm x %.0fmm",
+		2*splicePitch*(spliceRows+0.5)*m2mm, baseMemberWeb*m2mm)}
+	d.Paths = append(d.Paths, rectPath(2*splicePitch*(spliceRows+0.5), baseMemberWeb, cam.EdgePath))
+	l, w := 2*splicePitch*(spliceRows+0.5)*m2mm, baseMemberWeb*m2mm
+	for _, s := range spliceHoles() {
+		for _, x := range []float64{l/2 - s*m2mm, l/2 + s*m2mm} {
+			d.Paths = append(d.Paths, cam.NewCirclePath(cam.NewVec2(x, w/2), spliceHoleDia*m2mm/2, cam.EdgePath))
+		}
+	}
+	return d
+}
@@ -0,0 +1,254 @@
+package main
+
+// ██████╗  ██████╗ ███╗   ███╗
+// ██╔══██╗██╔═══██╗████╗ ████║
+// ██████╔╝██║   ██║██╔████╔██║
+// ██╔══██╗██║   ██║██║╚██╔╝██║
+// ██████╔╝╚██████╔╝██║ ╚═╝ ██║
+// ╚═════╝  ╚═════╝ ╚═╝     ╚═╝
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	cam "./cam"
+)
+
+// bomNestEfficacy is the fraction of a sheet we expect to use with triangles on it
+const bomNestEfficacy = 0.7
+
+// BOMItem is one line of the cut list
+type BOMItem struct {
+	Panel     int     `json:"panel"`       // serial, -1 for a part which is not a panel
+	Label     string  `json:"label"`       // as engraved on the part
+	Area      float64 `json:"area_m2"`     // of the flat pattern, including hems and flanges
+	Perimeter float64 `json:"perimeter_m"` // of the flat pattern
+	Material  string  `json:"material"`
+	Gauge     string  `json:"gauge"`
+	Thickness float64 `json:"thickness_m"`
+	Mass      float64 `json:"mass_kg"`
+	Stock     string  `json:"stock"` // size of sheet it is cut from
+	Sheet     int     `json:"sheet"` // which sheet it is cut from, counting from 1
+	width     float64 // m, bounding box of the flat pattern
+	length    float64
+	stock     cam.StockSheet
+	sheetCost float64 // of one sheet of its stock
+}
+
+// BOMStock is the number of sheets of one size of one gauge to buy
+type BOMStock struct {
+	Material string  `json:"material"`
+	Gauge    string  `json:"gauge"`
+	Stock    string  `json:"stock"`
+	Sheets   int     `json:"sheets"`
+	Cost     float64 `json:"cost"` // of them all, 0 if not known
+	sheet    cam.StockSheet
+}
+
+// String is what to order, eg 11 sheets of 16ga 4x8
+func (s BOMStock) String() string {
+	return strings.Replace(s.sheet.Count(s.Sheets), " of ", " of "+s.Gauge+" ", 1)
+}
+
+// BOM is the bill of materials: a cut list of all the panels
+type BOM struct {
+	Items     []BOMItem  `json:"items"`
+	Area      float64    `json:"area_m2"`     // total
+	Perimeter float64    `json:"perimeter_m"` // total
+	Mass      float64    `json:"mass_kg"`     // total
+	Sheets    int        `json:"sheets"`      // number of sheets needed
+	Stock     []BOMStock `json:"stock"`       // the sheets needed of each size of each gauge
+}
+
+// Needs is what stock to order, eg needs 11 sheets of 16ga 4x8
+func (b BOM) Needs() string {
+	if len(b.Stock) == 0 {
+		return "needs no sheets"
+	}
+	var ss []string
+	for _, s := range b.Stock {
+		ss = append(ss, s.String())
+	}
+	return "needs " + strings.Join(ss, ", ")
+}
+
+// Stock is the size of sheet the panel is cut from: the shell's, if its gauge comes in it,
+//   else the first size it does come in
+func (p *Panel) Stock() cam.StockSheet {
+	name := ""
+	if p.Shell != nil {
+		name = p.Shell.Sheet.Stock
+	}
+	return p.SheetGauge().FindStock(name)
+}
+
+// Stock is the size of sheet the shell's panels are cut from
+func (e *EShell) Stock() cam.StockSheet {
+	g := cam.Materials[e.Sheet.Material].SheetData[e.Sheet.Gauge]
+	return g.FindStock(e.Sheet.Stock)
+}
+
+// BOM makes the cut list for all the alive panels, from their flat patterns, and the parts
+//   of the doors
+func (e *EShell) BOM(mats cam.MaterialSet) BOM {
+	return bomOf(append(panelParts(alivePanels(e.Panels)), e.doorParts()...), mats)
+}
+
+// EmittedBOM makes the cut list for just the panels to be emitted, and the parts of the doors
+func (e *EShell) EmittedBOM(mats cam.MaterialSet) BOM {
+	return bomOf(append(panelParts(e.Emitted()), e.doorParts()...), mats)
+}
+
+// bomPart is a flat part for the cut list, and what it is cut from
+type bomPart struct {
+	Pattern  cam.Drawing
+	Serial   int // of the panel, -1 if it is not one
+	Label    string
+	Material cam.MaterialID
+	Gauge    cam.SheetGauge
+	Stock    cam.StockSheet
+}
+
+// panelParts are the panels' flat patterns for the cut list
+func panelParts(ps []*Panel) []bomPart {
+	var bs []bomPart
+	for _, p := range ps {
+		bs = append(bs, bomPart{Pattern: p.FlatPattern(), Serial: p.Serial, Label: PanelLabel(p),
+			Material: p.SheetMaterial(), Gauge: p.SheetGauge(), Stock: p.Stock()})
+	}
+	return bs
+}
+
+// doorParts are the flat parts of the door frames and leaves for the cut list, cut from the
+//   shell's sheet
+func (e *EShell) doorParts() []bomPart {
+	var bs []bomPart
+	g := cam.Materials[e.Sheet.Material].SheetData[e.Sheet.Gauge]
+	for _, dr := range e.DoorParts() {
+		bs = append(bs, bomPart{Pattern: dr, Serial: -1, Label: dr.Name, Material: e.Sheet.Material, Gauge: g, Stock: e.Stock()})
+	}
+	return bs
+}
+
+// bomOf makes the cut list for the parts
+func bomOf(bs []bomPart, mats cam.MaterialSet) BOM {
+	b := BOM{}
+	for _, bp := range bs {
+		fp := bp.Pattern
+		if len(fp.Paths) == 0 {
+			continue
+		}
+		outline := fp.Paths[0]
+		it := BOMItem{Panel: bp.Serial, Label: bp.Label, Area: math.Abs(outline.Area()) / (m2mm * m2mm)}
+		minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, s := range outline.Segments {
+			it.Perimeter += s.End.Subtract(s.Start).Length() / m2mm
+			minX, maxX = math.Min(minX, s.Start.X), math.Max(maxX, s.Start.X)
+			minY, maxY = math.Min(minY, s.Start.Y), math.Max(maxY, s.Start.Y)
+		}
+		it.width, it.length = (maxX-minX)/m2mm, (maxY-minY)/m2mm
+
+		matID, g := bp.Material, bp.Gauge
+		it.Material, it.Gauge, it.Thickness = string(matID), string(g.ID), g.Thickness
+		if g.ArealDensity > 0 {
+			it.Mass = it.Area * g.ArealDensity
+		} else if m, ok := mats[matID]; ok {
+			it.Mass = it.Area * g.Thickness * m.Density
+		}
+		it.stock = bp.Stock
+		it.Stock = it.stock.Name
+		if m, ok := mats[matID]; ok {
+			it.sheetCost = m.StockCost(g, it.stock)
+		}
+
+		b.Items = append(b.Items, it)
+		b.Area += it.Area
+		b.Perimeter += it.Perimeter
+		b.Mass += it.Mass
+	}
+	b.assignSheets()
+	return b
+}
+
+// assignSheets puts the parts on sheets of their material, gauge and size, biggest first, into
+//   the first sheet with room for them. Sheets are numbered on from one size to the next.
+func (b *BOM) assignSheets() {
+	order := make([]int, len(b.Items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return b.Items[order[i]].width*b.Items[order[i]].length > b.Items[order[j]].width*b.Items[order[j]].length
+	})
+	type key struct{ material, gauge, stock string }
+	groups := map[key]*BOMStock{}
+	var keys []key
+	used := map[key][]float64{}
+	for _, i := range order {
+		it := &b.Items[i]
+		k := key{it.Material, it.Gauge, it.Stock}
+		if groups[k] == nil {
+			groups[k] = &BOMStock{Material: it.Material, Gauge: it.Gauge, Stock: it.Stock, sheet: it.stock}
+			keys = append(keys, k)
+		}
+		w, l := it.stock.Size()
+		room := w * l * bomNestEfficacy
+		need := it.width * it.length
+		if math.Min(it.width, it.length) > w || math.Max(it.width, it.length) > l {
+			console.Errorf("%s (%.2fm x %.2fm) does not fit on a sheet of %s", it.Label, it.width, it.length, it.stock)
+		}
+		it.Sheet = 0
+		for s := range used[k] {
+			if used[k][s]+need <= room {
+				used[k][s] += need
+				it.Sheet = s + 1
+				break
+			}
+		}
+		if it.Sheet == 0 {
+			used[k] = append(used[k], need)
+			it.Sheet = len(used[k])
+			groups[k].Sheets++
+			groups[k].Cost += it.sheetCost
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		return a.material < b.material || a.material == b.material && (a.gauge < b.gauge || a.gauge == b.gauge && a.stock < b.stock)
+	})
+	first := map[key]int{}
+	b.Sheets, b.Stock = 0, nil
+	for _, k := range keys {
+		first[k] = b.Sheets
+		b.Sheets += groups[k].Sheets
+		b.Stock = append(b.Stock, *groups[k])
+	}
+	for i := range b.Items {
+		it := &b.Items[i]
+		it.Sheet += first[key{it.Material, it.Gauge, it.Stock}]
+	}
+}
+
+// CSV writes the cut list, one line per part
+func (b BOM) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Panel", "Label", "Area m2", "Perimeter m", "Material", "Gauge", "Thickness mm", "Mass kg", "Stock", "Sheet"})
+	for _, it := range b.Items {
+		w.Write([]string{fmt.Sprint(it.Panel), it.Label, fmt.Sprintf("%.4f", it.Area), fmt.Sprintf("%.3f", it.Perimeter),
+			it.Material, it.Gauge, fmt.Sprintf("%.3f", it.Thickness*m2mm), fmt.Sprintf("%.2f", it.Mass), it.Stock, fmt.Sprint(it.Sheet)})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// JSON writes the whole bill of materials
+func (b BOM) JSON() (string, error) {
+	j, err := json.MarshalIndent(b, "", "  ")
+	return string(j), err
+}
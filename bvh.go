@@ -0,0 +1,146 @@
+package main
+
+// ██████╗ ██╗   ██╗██╗  ██╗
+// ██╔══██╗██║   ██║██║  ██║
+// ██████╔╝██║   ██║███████║
+// ██╔══██╗╚██╗ ██╔╝██╔══██║
+// ██████╔╝ ╚████╔╝ ██║  ██║
+// ╚═════╝   ╚═══╝  ╚═╝  ╚═╝
+
+import (
+	"sort"
+
+	v3 "./vec"
+)
+
+// Sizes for the tree of panels
+const (
+	bvhLeafSize = 4    // most panels in a leaf
+	bvhMargin   = 1e-6 // m, added round each panel's box so things just touching are found
+)
+
+// panelBVH is a bounding volume hierarchy over the alive panels, for finding quickly those
+//   which a segment or a region might touch
+type panelBVH struct {
+	box         v3.Box
+	left, right *panelBVH
+	panels      []*Panel // in leaves only
+}
+
+// panelBox is the box round the panel's corners, with a margin
+func panelBox(p *Panel) v3.Box {
+	b := v3.EmptyBox
+	for _, c := range p.Corners {
+		b = b.Extend(c.Position)
+	}
+	return b.Grow(bvhMargin)
+}
+
+// newPanelBVH builds the tree, splitting each node at the median of the panels' centers
+//   along the longest side of its box
+func newPanelBVH(ps []*Panel, boxes map[*Panel]v3.Box) *panelBVH {
+	n := &panelBVH{box: v3.EmptyBox}
+	for _, p := range ps {
+		n.box = n.box.Union(boxes[p])
+	}
+	if len(ps) <= bvhLeafSize {
+		n.panels = ps
+		return n
+	}
+	size := n.box.Size()
+	axis := func(v v3.Vec) float64 { return v.X() }
+	if size.Y() > size.X() && size.Y() >= size.Z() {
+		axis = func(v v3.Vec) float64 { return v.Y() }
+	} else if size.Z() > size.X() && size.Z() > size.Y() {
+		axis = func(v v3.Vec) float64 { return v.Z() }
+	}
+	sort.Slice(ps, func(i, j int) bool { return axis(boxes[ps[i]].Center()) < axis(boxes[ps[j]].Center()) })
+	mid := len(ps) / 2
+	n.left = newPanelBVH(ps[:mid], boxes)
+	n.right = newPanelBVH(ps[mid:], boxes)
+	return n
+}
+
+// visit calls f with the panels of every leaf whose box passes the test
+func (n *panelBVH) visit(test func(b v3.Box) bool, f func(p *Panel)) {
+	if n == nil || n.box.Empty() || !test(n.box) {
+		return
+	}
+	for _, p := range n.panels {
+		f(p)
+	}
+	n.left.visit(test, f)
+	n.right.visit(test, f)
+}
+
+// nearest finds the panel closest to pt by the distance function, going into the nearer
+//   child first and skipping any box further away than the best found so far
+func (n *panelBVH) nearest(pt v3.Vec, dist func(p *Panel) float64, best *Panel, bestD float64) (*Panel, float64) {
+	if n == nil || n.box.Distance(pt) >= bestD {
+		return best, bestD
+	}
+	for _, p := range n.panels {
+		if d := dist(p); d < bestD {
+			best, bestD = p, d
+		}
+	}
+	first, second := n.left, n.right
+	if first != nil && second != nil && second.box.Distance(pt) < first.box.Distance(pt) {
+		first, second = second, first
+	}
+	best, bestD = first.nearest(pt, dist, best, bestD)
+	return second.nearest(pt, dist, best, bestD)
+}
+
+// panelTree is the shell's BVH, built again if anything has changed since it was last used
+func (e *EShell) panelTree() *panelBVH {
+	if e.bvh == nil {
+		ps := []*Panel{}
+		boxes := map[*Panel]v3.Box{}
+		for _, p := range e.Panels {
+			if p.Alive && len(p.Corners) == 3 {
+				ps = append(ps, p)
+				boxes[p] = panelBox(p)
+			}
+		}
+		e.bvh = newPanelBVH(ps, boxes)
+	}
+	return e.bvh
+}
+
+// touch notes that the panels have changed, so the BVH must be rebuilt before it is next used
+func (e *EShell) touch() {
+	if e != nil {
+		e.bvh = nil
+	}
+}
+
+// PanelsNearSegment lists the alive panels whose boxes the segment passes through, in serial order
+func (e *EShell) PanelsNearSegment(seg v3.Segment) []*Panel {
+	var ps []*Panel
+	e.panelTree().visit(seg.IntersectsBox, func(p *Panel) {
+		if panelBox(p).IntersectsSegment(seg) {
+			ps = append(ps, p)
+		}
+	})
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Serial < ps[j].Serial })
+	return ps
+}
+
+// PanelsInBox lists the alive panels whose boxes overlap the box, in serial order
+func (e *EShell) PanelsInBox(b v3.Box) []*Panel {
+	var ps []*Panel
+	e.panelTree().visit(b.Overlaps, func(p *Panel) {
+		if panelBox(p).Overlaps(b) {
+			ps = append(ps, p)
+		}
+	})
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Serial < ps[j].Serial })
+	return ps
+}
+
+// patchBox is the box round the parallelogram of the patch
+func patchBox(pat v3.Patch) v3.Box {
+	c := pat.Corner
+	return v3.NewBox(c, c.Add(pat.Sides[0]), c.Add(pat.Sides[1]), c.Add(pat.Sides[0]).Add(pat.Sides[1]))
+}
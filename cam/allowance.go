@@ -0,0 +1,84 @@
+package cam
+
+//  █████╗ ██╗     ██╗      ██████╗ ██╗    ██╗ █████╗ ███╗   ██╗ ██████╗███████╗
+// ██╔══██╗██║     ██║     ██╔═══██╗██║    ██║██╔══██╗████╗  ██║██╔════╝██╔════╝
+// ███████║██║     ██║     ██║   ██║██║ █╗ ██║███████║██╔██╗ ██║██║     █████╗
+// ██╔══██║██║     ██║     ██║   ██║██║███╗██║██╔══██║██║╚██╗██║██║     ██╔══╝
+// ██║  ██║███████╗███████╗╚██████╔╝╚███╔███╔╝██║  ██║██║ ╚████║╚██████╗███████╗
+// ╚═╝  ╚═╝╚══════╝╚══════╝ ╚═════╝  ╚══╝╚══╝ ╚═╝  ╚═╝╚═╝  ╚═══╝ ╚═════╝╚══════╝
+
+import "math"
+
+// DefaultKFactor is where the neutral axis lies in a bend, as a fraction of the thickness from
+//   the inside, for a bend as tight as the sheet is thick
+const DefaultKFactor = 0.33
+
+// kFactors are typical K-factors for air bending by how many thicknesses the inside radius is:
+//   the tighter the bend, the more the outside stretches and the further in the neutral axis goes
+var kFactors = []struct{ ratio, k float64 }{
+	{0, 0.30},
+	{1, DefaultKFactor},
+	{3, 0.42},
+	{8, 0.50},
+}
+
+// K is the K-factor of a bend with the inside radius, the gauge's own if it has one, else
+//   interpolated from the typical ones
+func (g SheetGauge) K(radius float64) float64 {
+	if g.KFactor > 0 {
+		return g.KFactor
+	}
+	if g.Thickness <= 0 {
+		return DefaultKFactor
+	}
+	r := radius / g.Thickness
+	for i := 1; i < len(kFactors); i++ {
+		a, b := kFactors[i-1], kFactors[i]
+		if r <= b.ratio {
+			return a.k + (b.k-a.k)*math.Max(0, r-a.ratio)/(b.ratio-a.ratio)
+		}
+	}
+	return kFactors[len(kFactors)-1].k
+}
+
+// Allowance is the length of flat material taken up by a bend of angle (degrees) with the
+//   given inside radius, the length of the neutral axis round it. The gauge's own
+//   BendAllowance is used for 90deg bends at its minimum radius.
+func (g SheetGauge) Allowance(angle, radius float64) float64 {
+	if g.BendAllowance > 0 && angle == 90 && radius == g.MinBendRadius {
+		return g.BendAllowance
+	}
+	return angle * d2r * (radius + g.K(radius)*g.Thickness)
+}
+
+// OutsideSetback is how far back from where the outer faces of the legs meet the bend starts
+func (g SheetGauge) OutsideSetback(angle, radius float64) float64 {
+	return (radius + g.Thickness) * math.Tan(angle*d2r/2)
+}
+
+// Deduction is how much shorter the flat pattern is than the legs measured to where their outer
+//   faces meet
+func (g SheetGauge) Deduction(angle, radius float64) float64 {
+	return 2*g.OutsideSetback(angle, radius) - g.Allowance(angle, radius)
+}
+
+// BendRadius is the inside radius the brake bends to, never tighter than the gauge allows nor
+//   than the sheet is thick
+func (g SheetGauge) BendRadius() float64 {
+	return math.Max(g.MinBendRadius, g.Thickness)
+}
+
+// withBends fills in the bend data of the gauges: the minimum radius is ratio times the
+//   thickness, and the allowance of a 90deg bend at it is worked out
+func withBends(gs GaugeStats, ratio float64) GaugeStats {
+	for id, g := range gs {
+		if g.MinBendRadius <= 0 {
+			g.MinBendRadius = ratio * g.Thickness
+		}
+		if g.BendAllowance <= 0 {
+			g.BendAllowance = g.Allowance(90, g.MinBendRadius)
+		}
+		gs[id] = g
+	}
+	return gs
+}
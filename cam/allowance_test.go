@@ -0,0 +1,56 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKFactor(t *testing.T) {
+	g := SheetGauge{Thickness: 0.001}
+	if k := g.K(0.001); math.Abs(k-DefaultKFactor) > 1e-9 {
+		t.Errorf("K-factor of a bend as tight as the sheet is thick is %.3f", k)
+	}
+	if k := g.K(0.002); math.Abs(k-(DefaultKFactor+0.42)/2) > 1e-9 {
+		t.Errorf("K-factor at 2 thicknesses is not interpolated, %.3f", k)
+	}
+	if k := g.K(1); k != 0.5 {
+		t.Errorf("K-factor of a very gentle bend is %.3f", k)
+	}
+	g.KFactor = 0.44
+	if k := g.K(0.001); k != 0.44 {
+		t.Errorf("Gauge's own K-factor is not used, %.3f", k)
+	}
+}
+
+func TestAllowance(t *testing.T) {
+	g := SheetGauge{Thickness: 0.001}
+	if ba := g.Allowance(90, 0.001); math.Abs(ba-math.Pi/2*0.00133) > 1e-12 {
+		t.Errorf("Allowance of a square bend is %.6f", ba)
+	}
+	if ssb := g.OutsideSetback(90, 0.001); math.Abs(ssb-0.002) > 1e-12 {
+		t.Errorf("Setback of a square bend is %.6f", ssb)
+	}
+	// Two 50mm legs to the outside with a square bend between
+	flat := 0.1 - g.Deduction(90, 0.001)
+	if math.Abs(flat-(0.1-0.004+math.Pi/2*0.00133)) > 1e-12 {
+		t.Errorf("Flat length of a square bend is %.6f", flat)
+	}
+
+	gs := withBends(GaugeStats{"x": g}, 2)
+	if gs["x"].MinBendRadius != 0.002 || gs["x"].BendAllowance != g.Allowance(90, 0.002) {
+		t.Errorf("Bend data not filled in: %+v", gs["x"])
+	}
+	measured := gs["x"]
+	measured.BendAllowance = 0.005
+	if ba := measured.Allowance(90, 0.002); ba != 0.005 {
+		t.Errorf("Measured allowance is not used, %.6f", ba)
+	}
+	if ba := measured.Allowance(45, 0.002); ba == 0.005 {
+		t.Error("Measured allowance is used for a bend it was not measured for")
+	}
+	for id, g := range Materials["Stainless304"].SheetData {
+		if g.MinBendRadius <= 0 || g.BendAllowance <= 0 {
+			t.Errorf("Gauge %s has no bend data", id)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cam
+
+// ██████╗ ███████╗███╗   ██╗██████╗
+// ██╔══██╗██╔════╝████╗  ██║██╔══██╗
+// ██████╔╝█████╗  ██╔██╗ ██║██║  ██║
+// ██╔══██╗██╔══╝  ██║╚██╗██║██║  ██║
+// ██████╔╝███████╗██║ ╚████║██████╔╝
+// ╚═════╝ ╚══════╝╚═╝  ╚═══╝╚═════╝
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bend is what the brake operator does at a fold line
+type Bend struct {
+	Angle  float64 // radians the sheet is bent through from flat
+	Up     bool    // towards someone looking at the drawing, else away
+	Radius float64 // mm, inside radius
+}
+
+const bendTextHeight = 8.0 // mm, of bend labels drawn full size
+
+// String is the bend as it is labelled on drawings, eg UP 90° R2, leaving out the radius if
+//   it is as tight as the brake will go
+func (b Bend) String() string {
+	dir := "DOWN"
+	if b.Up {
+		dir = "UP"
+	}
+	s := fmt.Sprintf("%s %.0f°", dir, b.Angle/d2r)
+	if b.Radius > 0 {
+		s += fmt.Sprintf(" R%.3g", b.Radius)
+	}
+	return s
+}
+
+// NewFoldPath is a fold line from a to b marking the bend
+func NewFoldPath(a, b Vec2, bend Bend) Path {
+	p := Path{}
+	p.Add(Segment{Kind: FoldPath, Start: a, End: b, Bend: &bend})
+	return p
+}
+
+// bendLabel is where a bend's label goes and which way it reads
+type bendLabel struct {
+	At    Vec2    // middle of the text's baseline
+	Angle float64 // radians anticlockwise from +x, so it always reads left to right
+	Text  string
+}
+
+// bendLabels are a label for each bend, on the longest segment marked with it, just to one
+//   side of its middle so the line does not strike it through
+func bendLabels(segs []Segment, height float64) []bendLabel {
+	longest := map[*Bend]Segment{}
+	var order []*Bend
+	for _, s := range segs {
+		if s.Bend == nil {
+			continue
+		}
+		l, seen := longest[s.Bend]
+		if !seen {
+			order = append(order, s.Bend)
+		}
+		if !seen || s.Length() > l.Length() {
+			longest[s.Bend] = s
+		}
+	}
+	var ls []bendLabel
+	for _, b := range order {
+		s := longest[b]
+		along := s.End.Subtract(s.Start)
+		a := math.Atan2(along.Y, along.X)
+		if a > deg90 || a <= -deg90 {
+			a -= math.Copysign(pi, a)
+		}
+		mid := s.Start.Add(along.Scale(0.5))
+		up := NewVec2(-math.Sin(a), math.Cos(a)).Scale(height / 2)
+		ls = append(ls, bendLabel{At: mid.Add(up), Angle: a, Text: b.String()})
+	}
+	return ls
+}
+
+// segments are all the segments of the drawing's paths
+func (d Drawing) segments() []Segment {
+	var segs []Segment
+	for _, p := range d.Paths {
+		segs = append(segs, p.Segments...)
+	}
+	return segs
+}
@@ -0,0 +1,62 @@
+package cam
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBendString(t *testing.T) {
+	if s := (Bend{Angle: deg90, Up: true, Radius: 1.5}).String(); s != "UP 90° R1.5" {
+		t.Errorf("Bend label is %q", s)
+	}
+	if s := (Bend{Angle: pi}).String(); s != "DOWN 180°" {
+		t.Errorf("Bend label with no radius is %q", s)
+	}
+}
+
+func TestBendLabels(t *testing.T) {
+	b := &Bend{Angle: deg90}
+	segs := []Segment{
+		{Kind: FoldPath, Start: NewVec2(0, 0), End: NewVec2(-10, 0), Bend: b},
+		{Kind: FoldPath, Start: NewVec2(-10, 0), End: NewVec2(-40, 0), Bend: b},
+		{Kind: EdgePath, Start: NewVec2(0, 0), End: NewVec2(0, 10)},
+	}
+	ls := bendLabels(segs, 4)
+	if len(ls) != 1 {
+		t.Fatalf("Expected one label for the bend, got %d", len(ls))
+	}
+	if math.Abs(ls[0].Angle) > 1e-9 {
+		t.Errorf("Label along a line going left should read left to right, angle %.3f", ls[0].Angle)
+	}
+	if ls[0].At.Subtract(NewVec2(-25, 2)).Length() > 1e-9 {
+		t.Errorf("Label should be just above the middle of the longest segment, is at %s", ls[0].At)
+	}
+}
+
+func TestBendOutputs(t *testing.T) {
+	d := Drawing{Name: "flange"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {100, 50}, {0, 50}}, EdgePath))
+	d.Paths = append(d.Paths, NewFoldPath(NewVec2(0, 20), NewVec2(100, 20), Bend{Angle: deg90, Radius: 2}))
+
+	s := d.SVG(5)
+	if !strings.Contains(s, "stroke-dasharray") {
+		t.Error("Folds are not dashed in the SVG")
+	}
+	if !strings.Contains(s, ">DOWN 90° R2</text>") {
+		t.Error("Bend is not labelled in the SVG")
+	}
+
+	x := d.DXF()
+	if !strings.Contains(x, "  2\nFOLD\n 70\n0\n 62\n5\n  6\nDASHED\n") {
+		t.Error("FOLD layer is not dashed in the DXF")
+	}
+	if !strings.Contains(x, "  0\nTEXT\n  8\nFOLD\n") || !strings.Contains(x, "DOWN 90%%d R2") {
+		t.Error("Bend is not labelled in the DXF")
+	}
+
+	moved := d.Transformed(deg90, NewVec2(0, 0))
+	if moved.Paths[1].Segments[0].Bend == nil {
+		t.Error("Moving a drawing loses its bends")
+	}
+}
@@ -0,0 +1,194 @@
+package cam
+
+// ██████╗ ██╗  ██╗███████╗
+// ██╔══██╗╚██╗██╔╝██╔════╝
+// ██║  ██║ ╚███╔╝ █████╗
+// ██║  ██║ ██╔██╗ ██╔══╝
+// ██████╔╝██╔╝ ██╗██║
+// ╚═════╝ ╚═╝  ╚═╝╚═╝
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// dxfColours are the AutoCAD colour numbers of the layers
+var dxfColours = map[PathKind]int{
+	EdgePath: 7, // white (black on a light background)
+	FoldPath: 5, // blue
+	MarkPath: 3, // green
+	MetaPath: 8, // grey
+}
+
+// dxfLinetype is the linetype of the layer for a kind of path: folds are dashed
+func dxfLinetype(k PathKind) string {
+	if k == FoldPath {
+		return "DASHED"
+	}
+	return "CONTINUOUS"
+}
+
+// dxfLayer is the name of the layer for a kind of path
+func dxfLayer(k PathKind) string {
+	return strings.ToUpper(k.String())
+}
+
+// dxfWriter writes group code and value pairs, keeping the first error
+type dxfWriter struct {
+	w   io.Writer
+	err error
+}
+
+// pair writes one group code and value
+func (d *dxfWriter) pair(code int, value interface{}) {
+	if d.err != nil {
+		return
+	}
+	if f, ok := value.(float64); ok {
+		value = fmt.Sprintf("%.4f", f)
+	}
+	_, d.err = fmt.Fprintf(d.w, "%3d\n%v\n", code, value)
+}
+
+// polyline is a run of joined up segments of one kind
+type polyline struct {
+	kind   PathKind
+	pts    []Vec2
+	closed bool
+}
+
+// polylines splits the path into runs of joined up segments of the same kind. A closed path
+//   which is all one run is one closed polyline.
+func (p Path) polylines() []polyline {
+	var pls []polyline
+	for i, s := range p.Segments {
+		if i == 0 || s.Kind != pls[len(pls)-1].kind || s.Start.Subtract(p.Segments[i-1].End).Length() > IntersectTolerance {
+			pls = append(pls, polyline{kind: s.Kind, pts: []Vec2{s.Start}})
+		}
+		pls[len(pls)-1].pts = append(pls[len(pls)-1].pts, s.End)
+	}
+	if p.Closed && len(pls) == 1 && len(pls[0].pts) > 2 {
+		pl := &pls[0]
+		if pl.pts[len(pl.pts)-1].Subtract(pl.pts[0]).Length() <= IntersectTolerance {
+			pl.pts, pl.closed = pl.pts[:len(pl.pts)-1], true
+		}
+	}
+	return pls
+}
+
+// WriteDXF writes the drawing as an R12 DXF file, which almost any CAM program reads: a layer
+//   for each kind of path (EDGE, FOLD, MARK, META) and each path as polylines, closed where it
+//   is. Folds are dashed, with their bends as text on the FOLD layer. Units are the drawing's,
+//   given in $INSUNITS.
+func (d Drawing) WriteDXF(w io.Writer) error {
+	dw := &dxfWriter{w: w}
+	u := d.Units
+	dw.pair(999, d.Name)
+	dw.pair(0, "SECTION")
+	dw.pair(2, "HEADER")
+	dw.pair(9, "$ACADVER")
+	dw.pair(1, "AC1009")
+	dw.pair(9, "$INSUNITS")
+	dw.pair(70, u.dxfInsUnits())
+	lo, hi := d.Bounds()
+	dw.pair(9, "$EXTMIN")
+	dw.pair(10, lo.X)
+	dw.pair(20, lo.Y)
+	dw.pair(9, "$EXTMAX")
+	dw.pair(10, hi.X)
+	dw.pair(20, hi.Y)
+	dw.pair(0, "ENDSEC")
+
+	kinds := []PathKind{EdgePath, FoldPath, MarkPath, MetaPath}
+	dw.pair(0, "SECTION")
+	dw.pair(2, "TABLES")
+	dw.pair(0, "TABLE")
+	dw.pair(2, "LTYPE")
+	dw.pair(70, 2)
+	for _, lt := range []struct {
+		name, look string
+		dashes     []float64
+	}{{"CONTINUOUS", "Solid line", nil}, {"DASHED", "__ __ __", []float64{10, -5}}} {
+		dw.pair(0, "LTYPE")
+		dw.pair(2, lt.name)
+		dw.pair(70, 0)
+		dw.pair(3, lt.look)
+		dw.pair(72, 65)
+		dw.pair(73, len(lt.dashes))
+		total := 0.0
+		for _, d := range lt.dashes {
+			total += math.Abs(d)
+		}
+		dw.pair(40, u.FromMM(total))
+		for _, d := range lt.dashes {
+			dw.pair(49, u.FromMM(d))
+		}
+	}
+	dw.pair(0, "ENDTAB")
+	dw.pair(0, "TABLE")
+	dw.pair(2, "LAYER")
+	dw.pair(70, len(kinds))
+	for _, k := range kinds {
+		dw.pair(0, "LAYER")
+		dw.pair(2, dxfLayer(k))
+		dw.pair(70, 0)
+		dw.pair(62, dxfColours[k])
+		dw.pair(6, dxfLinetype(k))
+	}
+	dw.pair(0, "ENDTAB")
+	dw.pair(0, "ENDSEC")
+
+	dw.pair(0, "SECTION")
+	dw.pair(2, "ENTITIES")
+	for _, p := range d.Paths {
+		for _, pl := range p.polylines() {
+			dw.pair(0, "POLYLINE")
+			dw.pair(8, dxfLayer(pl.kind))
+			dw.pair(66, 1)
+			dw.pair(10, 0.0)
+			dw.pair(20, 0.0)
+			dw.pair(30, 0.0)
+			if pl.closed {
+				dw.pair(70, 1)
+			} else {
+				dw.pair(70, 0)
+			}
+			for _, v := range pl.pts {
+				dw.pair(0, "VERTEX")
+				dw.pair(8, dxfLayer(pl.kind))
+				dw.pair(10, v.X)
+				dw.pair(20, v.Y)
+				dw.pair(30, 0.0)
+			}
+			dw.pair(0, "SEQEND")
+			dw.pair(8, dxfLayer(pl.kind))
+		}
+	}
+	h := u.FromMM(bendTextHeight)
+	for _, l := range bendLabels(d.segments(), h) {
+		dw.pair(0, "TEXT")
+		dw.pair(8, dxfLayer(FoldPath))
+		dw.pair(10, l.At.X)
+		dw.pair(20, l.At.Y)
+		dw.pair(30, 0.0)
+		dw.pair(40, h)
+		dw.pair(1, strings.Replace(l.Text, "°", "%%d", -1)) // DXF's code for a degree sign
+		dw.pair(50, l.Angle/d2r)
+		dw.pair(72, 1) // centered on the point
+		dw.pair(11, l.At.X)
+		dw.pair(21, l.At.Y)
+		dw.pair(31, 0.0)
+	}
+	dw.pair(0, "ENDSEC")
+	dw.pair(0, "EOF")
+	return dw.err
+}
+
+// DXF is the drawing as an R12 DXF file, as written by WriteDXF
+func (d Drawing) DXF() string {
+	var b strings.Builder
+	d.WriteDXF(&b) // cannot fail
+	return b.String()
+}
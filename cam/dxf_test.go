@@ -0,0 +1,56 @@
+package cam
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDXF(t *testing.T) {
+
+	d := Drawing{Name: "square"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, EdgePath))
+	s := d.DXF()
+
+	if n := strings.Count(s, "POLYLINE\n"); n != 1 {
+		t.Errorf("Expected 1 polyline in DXF, got %d", n)
+	}
+	if n := strings.Count(s, "VERTEX\n"); n != 4 {
+		t.Errorf("Expected 4 vertices in DXF, got %d", n)
+	}
+	if !strings.Contains(s, " 70\n1\n  0\nVERTEX") {
+		t.Error("Square not written as a closed polyline")
+	}
+	if !strings.HasSuffix(s, "EOF\n") {
+		t.Error("DXF does not end with EOF")
+	}
+	if !strings.Contains(s, "\n  8\nEDGE\n") || !strings.Contains(s, "AC1009") {
+		t.Error("Edge paths not on the EDGE layer of an R12 file")
+	}
+}
+
+func TestDXFPolylines(t *testing.T) {
+
+	p := Path{}
+	p.Add(Segment{Kind: EdgePath, Start: Vec2{0, 0}, End: Vec2{10, 0}})
+	p.Add(Segment{Kind: FoldPath, Start: Vec2{10, 0}, End: Vec2{10, 10}})
+	p.Add(Segment{Kind: FoldPath, Start: Vec2{10, 10}, End: Vec2{0, 10}})
+	p.Add(Segment{Kind: EdgePath, Start: Vec2{5, 5}, End: Vec2{6, 6}}) // not joined on
+	pls := p.polylines()
+	if len(pls) != 3 || len(pls[1].pts) != 3 || pls[1].kind != FoldPath || pls[1].closed {
+		t.Errorf("Path split into polylines wrong: %v", pls)
+	}
+}
+
+// failWriter fails every write
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteDXFError(t *testing.T) {
+	if err := (Drawing{Name: "x"}).WriteDXF(failWriter{}); err == nil {
+		t.Error("Write error not returned")
+	}
+}
@@ -0,0 +1,359 @@
+package cam
+
+// ██████╗ ███████╗ █████╗ ██████╗     ██████╗ ██╗  ██╗███████╗
+// ██╔══██╗██╔════╝██╔══██╗██╔══██╗    ██╔══██╗╚██╗██╔╝██╔════╝
+// ██████╔╝█████╗  ███████║██║  ██║    ██║  ██║ ╚███╔╝ █████╗
+// ██╔══██╗██╔══╝  ██╔══██║██║  ██║    ██║  ██║ ██╔██╗ ██╔══╝
+// ██║  ██║███████╗██║  ██║██████╔╝    ██████╔╝██╔╝ ██╗██║
+// ╚═╝  ╚═╝╚══════╝╚═╝  ╚═╝╚═════╝     ╚═════╝ ╚═╝  ╚═╝╚═╝
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// dxfUnits are mm in each of the units of the $INSUNITS header variable
+var dxfUnits = map[int]float64{1: 25.4, 2: 304.8, 4: 1, 5: 10, 6: 1000}
+
+// dxfPair is one group code and its value
+type dxfPair struct {
+	code  int
+	value string
+}
+
+// dxfEntity is an entity's type and its group codes in order, and mm in the file's units
+type dxfEntity struct {
+	kind  string
+	pairs []dxfPair
+	scale float64
+}
+
+// num is the value of the first code in the entity, or def
+func (e dxfEntity) num(code int, def float64) float64 {
+	for _, p := range e.pairs {
+		if p.code == code {
+			if x, err := strconv.ParseFloat(p.value, 64); err == nil {
+				return x
+			}
+		}
+	}
+	return def
+}
+
+// pt is the point of the x code and the y code 10 after it, in mm
+func (e dxfEntity) pt(code int) Vec2 {
+	return NewVec2(e.num(code, 0), e.num(code+10, 0)).Scale(e.scale)
+}
+
+// str is the value of the first code in the entity, or ""
+func (e dxfEntity) str(code int) string {
+	for _, p := range e.pairs {
+		if p.code == code {
+			return p.value
+		}
+	}
+	return ""
+}
+
+// vertices are the points of an LWPOLYLINE, in mm, each with the bulge of the segment from it
+func (e dxfEntity) vertices() (pts []Vec2, bulges []float64) {
+	for _, p := range e.pairs {
+		x, _ := strconv.ParseFloat(p.value, 64)
+		switch p.code {
+		case 10:
+			pts = append(pts, NewVec2(x, 0))
+			bulges = append(bulges, 0)
+		case 20:
+			if len(pts) > 0 {
+				pts[len(pts)-1].Y = x
+			}
+		case 42:
+			if len(bulges) > 0 {
+				bulges[len(bulges)-1] = x
+			}
+		}
+	}
+	for i := range pts {
+		pts[i] = pts[i].Scale(e.scale)
+	}
+	return pts, bulges
+}
+
+// dxfLayerKind is the kind of path on the layer, EdgePath unless it is named for another kind
+func dxfLayerKind(layer string) PathKind {
+	for _, k := range []PathKind{FoldPath, MarkPath, MetaPath} {
+		if strings.EqualFold(layer, dxfLayer(k)) {
+			return k
+		}
+	}
+	return EdgePath
+}
+
+// arcPoints are the points along an arc about c of radius r from angle a0 anticlockwise to a1,
+//   radians, close enough to it to cut, both ends included
+func arcPoints(c Vec2, r, a0, a1 float64) []Vec2 {
+	for a1 < a0 {
+		a1 += deg360
+	}
+	n := 1
+	if r > CurveTolerance {
+		n = int(math.Ceil((a1 - a0) / (2 * math.Acos(1-CurveTolerance/r))))
+	}
+	if n < 1 {
+		n = 1
+	}
+	var pts []Vec2
+	for i := 0; i <= n; i++ {
+		a := a0 + (a1-a0)*float64(i)/float64(n)
+		pts = append(pts, c.Add(NewVec2(r*math.Cos(a), r*math.Sin(a))))
+	}
+	return pts
+}
+
+// bulgePoints are the points from a to b along the arc of the bulge, the tangent of a quarter
+//   of the angle it turns through, positive anticlockwise, not including a
+func bulgePoints(a, b Vec2, bulge float64) []Vec2 {
+	if bulge == 0 {
+		return []Vec2{b}
+	}
+	chord := b.Subtract(a)
+	turn := 4 * math.Atan(bulge)
+	r := chord.Length() / 2 / math.Sin(turn/2)
+	// the center is off the middle of the chord, square to it
+	mid := a.Add(chord.Scale(0.5))
+	off := NewVec2(-chord.Y, chord.X).Scale(1 / chord.Length()).Scale(r * math.Cos(turn/2))
+	c := mid.Add(off)
+	a0 := math.Atan2(a.Y-c.Y, a.X-c.X)
+	var pts []Vec2
+	if turn > 0 {
+		pts = arcPoints(c, math.Abs(r), a0, a0+turn)
+	} else {
+		pts = arcPoints(c, math.Abs(r), a0+turn, a0)
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	pts[len(pts)-1] = b
+	return pts[1:]
+}
+
+// polylinePath is the path through the points, along the bulges, closed if asked
+func polylinePath(pts []Vec2, bulges []float64, closed bool, kind PathKind) Path {
+	p := Path{}
+	n := len(pts)
+	last := n - 1
+	if closed {
+		last = n
+	}
+	for i := 0; i < last; i++ {
+		at := pts[i]
+		for _, q := range bulgePoints(pts[i], pts[(i+1)%n], bulges[i]) {
+			p.Add(Segment{Kind: kind, Start: at, End: q})
+			at = q
+		}
+	}
+	p.Closed = closed
+	return p
+}
+
+// ReadDXF reads the lines, arcs, circles and polylines of a DXF file, ASCII of any version,
+//   into a drawing in mm. Paths are of the kind their layer is named for (EDGE, FOLD, MARK,
+//   META), EdgePath on any other layer. Lines, arcs and open polylines which meet end to end
+//   are joined into paths. Other entities, such as text and splines, are left out.
+func ReadDXF(r io.Reader) (Drawing, error) {
+	d := Drawing{}
+	sc := bufio.NewScanner(r)
+	var pairs []dxfPair
+	for sc.Scan() {
+		code, err := strconv.Atoi(strings.TrimSpace(sc.Text()))
+		if err != nil {
+			return d, fmt.Errorf("Bad group code %q in DXF", sc.Text())
+		}
+		if !sc.Scan() {
+			return d, fmt.Errorf("DXF ends in the middle of group code %d", code)
+		}
+		pairs = append(pairs, dxfPair{code, strings.TrimSpace(sc.Text())})
+	}
+	if err := sc.Err(); err != nil {
+		return d, err
+	}
+
+	// Split into entities, noting the units from the header
+	scale := 1.0
+	var ents []dxfEntity
+	section := ""
+	for i := 0; i < len(pairs); i++ {
+		p := pairs[i]
+		switch {
+		case p.code == 2 && i > 0 && pairs[i-1].code == 0 && pairs[i-1].value == "SECTION":
+			section = p.value
+		case p.code == 9 && p.value == "$INSUNITS" && i+1 < len(pairs):
+			if u, err := strconv.Atoi(pairs[i+1].value); err == nil {
+				if mm, ok := dxfUnits[u]; ok {
+					scale = mm
+				}
+			}
+		case p.code == 0 && section == "ENTITIES":
+			ents = append(ents, dxfEntity{kind: p.value, scale: scale})
+		case len(ents) > 0 && section == "ENTITIES":
+			ents[len(ents)-1].pairs = append(ents[len(ents)-1].pairs, p)
+		}
+	}
+
+	var loose []Path
+	// add keeps closed polylines as they are, and open ones to join up
+	add := func(p Path, n int) {
+		switch {
+		case n < 2:
+		case p.Closed:
+			d.Paths = append(d.Paths, p)
+		default:
+			loose = append(loose, p)
+		}
+	}
+	for i := 0; i < len(ents); i++ {
+		e := ents[i]
+		kind := dxfLayerKind(e.str(8))
+		switch e.kind {
+		case "LINE":
+			p := Path{}
+			p.Add(Segment{Kind: kind, Start: e.pt(10), End: e.pt(11)})
+			loose = append(loose, p)
+		case "ARC":
+			pts := arcPoints(e.pt(10), e.num(40, 0)*e.scale, e.num(50, 0)*d2r, e.num(51, 0)*d2r)
+			loose = append(loose, polylinePath(pts, make([]float64, len(pts)), false, kind))
+		case "CIRCLE":
+			d.Paths = append(d.Paths, NewCirclePath(e.pt(10), e.num(40, 0)*e.scale, kind))
+		case "LWPOLYLINE":
+			pts, bulges := e.vertices()
+			add(polylinePath(pts, bulges, int(e.num(70, 0))&1 == 1, kind), len(pts))
+		case "POLYLINE":
+			var pts []Vec2
+			var bulges []float64
+			for i+1 < len(ents) && ents[i+1].kind == "VERTEX" {
+				i++
+				pts = append(pts, ents[i].pt(10))
+				bulges = append(bulges, ents[i].num(42, 0))
+			}
+			add(polylinePath(pts, bulges, int(e.num(70, 0))&1 == 1, kind), len(pts))
+		}
+	}
+	d.Paths = append(d.Paths, joinPaths(loose)...)
+	if len(d.Paths) == 0 {
+		return d, fmt.Errorf("No lines, arcs, circles or polylines in the DXF")
+	}
+	return d, nil
+}
+
+// joinPaths joins open paths of the same kind which meet end to end, turning them round if
+//   need be, and closes those which come back to where they started
+func joinPaths(ps []Path) []Path {
+	near := func(a, b Vec2) bool { return a.Subtract(b).Length() <= IntersectTolerance }
+	reversed := func(p Path) Path {
+		r := Path{}
+		for i := len(p.Segments) - 1; i >= 0; i-- {
+			s := p.Segments[i]
+			s.Start, s.End = s.End, s.Start
+			r.Add(s)
+		}
+		return r
+	}
+	used := make([]bool, len(ps))
+	var out []Path
+	for i := range ps {
+		if used[i] || len(ps[i].Segments) == 0 {
+			continue
+		}
+		used[i] = true
+		p := ps[i]
+		kind := p.Segments[0].Kind
+		for pass := 0; pass < 2; pass++ { // on from its end, then turned round, on from its start
+			for grown := true; grown; {
+				grown = false
+				end := p.Segments[len(p.Segments)-1].End
+				for j := range ps {
+					if used[j] || len(ps[j].Segments) == 0 || ps[j].Segments[0].Kind != kind {
+						continue
+					}
+					q := ps[j]
+					if near(q.Segments[len(q.Segments)-1].End, end) {
+						q = reversed(q)
+					}
+					if near(q.Segments[0].Start, end) {
+						p.Segments = append(p.Segments, q.Segments...)
+						used[j], grown = true, true
+						break
+					}
+				}
+			}
+			p = reversed(p)
+		}
+		if len(p.Segments) > 1 && near(p.Segments[len(p.Segments)-1].End, p.Segments[0].Start) {
+			p.Closed = true
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// LoadDXF reads a DXF file into a drawing named for the file
+func LoadDXF(fname string) (Drawing, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return Drawing{}, err
+	}
+	defer f.Close()
+	d, err := ReadDXF(f)
+	if err != nil {
+		return d, fmt.Errorf("%s: %s", fname, err)
+	}
+	d.Name = fname
+	return d, nil
+}
+
+// Outline is the corners of the largest closed path, going round anticlockwise, nil if there
+//   is none
+func (d Drawing) Outline() []Vec2 {
+	i := d.outline()
+	if i < 0 {
+		return nil
+	}
+	p := d.Paths[i]
+	var pts []Vec2
+	for _, s := range p.Segments {
+		pts = append(pts, s.Start)
+	}
+	if p.Area() < 0 {
+		for i, j := 0, len(pts)-1; i < j; i, j = i+1, j-1 {
+			pts[i], pts[j] = pts[j], pts[i]
+		}
+	}
+	return pts
+}
+
+// Fitted is the drawing scaled to the width, keeping its shape, and moved so the middle of its
+//   bounds is at the center
+func (d Drawing) Fitted(width float64, center Vec2) Drawing {
+	lo, hi := d.Bounds()
+	k := 1.0
+	if hi.X > lo.X {
+		k = width / (hi.X - lo.X)
+	}
+	mid := lo.Add(hi).Scale(0.5)
+	f := Drawing{Name: d.Name, ID: d.ID, Units: d.Units}
+	for _, p := range d.Paths {
+		q := Path{Closed: p.Closed}
+		for _, s := range p.Segments {
+			s.Start = s.Start.Subtract(mid).Scale(k).Add(center)
+			s.End = s.End.Subtract(mid).Scale(k).Add(center)
+			q.Add(s)
+		}
+		f.Paths = append(f.Paths, q)
+	}
+	return f
+}
@@ -0,0 +1,66 @@
+package cam
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadDXF(t *testing.T) {
+
+	d := Drawing{Name: "square"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, EdgePath))
+	d.Paths = append(d.Paths, NewFoldPath(NewVec2(5, 0), NewVec2(5, 10), Bend{Angle: deg90}))
+	r, err := ReadDXF(strings.NewReader(d.DXF()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Paths) != 2 || !r.Paths[0].Closed || len(r.Paths[0].Segments) != 4 || r.Paths[1].Segments[0].Kind != FoldPath {
+		t.Errorf("Drawing read back wrong: %+v", r.Paths)
+	}
+	if a := r.Paths[0].Area(); math.Abs(math.Abs(a)-100) > 1e-6 {
+		t.Errorf("Square read back with area %.3f", a)
+	}
+
+	// Loose lines in inches, one the wrong way round, and a half circle bulge
+	in := "0\nSECTION\n2\nHEADER\n9\n$INSUNITS\n70\n1\n0\nENDSEC\n0\nSECTION\n2\nENTITIES\n" +
+		"0\nLINE\n8\n0\n10\n0\n20\n0\n11\n1\n21\n0\n" +
+		"0\nLINE\n8\n0\n10\n1\n20\n1\n11\n1\n21\n0\n" +
+		"0\nLINE\n8\nMARK\n10\n0.2\n20\n0.2\n11\n0.4\n21\n0.2\n" +
+		"0\nLWPOLYLINE\n8\n0\n90\n2\n70\n0\n10\n1\n20\n1\n42\n1\n10\n0\n20\n1\n" +
+		"0\nLINE\n8\n0\n10\n0\n20\n1\n11\n0\n21\n0\n" +
+		"0\nENDSEC\n0\nEOF\n"
+	r, err = ReadDXF(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Paths) != 2 {
+		t.Fatalf("Expected the polyline joined into the lines and a mark, got %d paths", len(r.Paths))
+	}
+	o := r.Paths[r.outline()]
+	if !o.Closed || o.Segments[0].Kind != EdgePath {
+		t.Error("Lines joined end to end not closed")
+	}
+	if lo, hi := r.Bounds(); math.Abs(hi.X-25.4) > 1e-9 || math.Abs(lo.Y) > 1e-9 || math.Abs(hi.Y-1.5*25.4) > 0.1 {
+		t.Errorf("Outline in inches read as %v to %v mm", lo, hi)
+	}
+	if r.Paths[0].Segments[0].Kind != MarkPath && r.Paths[1].Segments[0].Kind != MarkPath {
+		t.Error("Line on the MARK layer not a mark")
+	}
+
+	pts := r.Outline()
+	if len(pts) < 8 || NewPolygonPath(pts, EdgePath).Area() <= 0 {
+		t.Errorf("Outline has %d corners, going round clockwise", len(pts))
+	}
+	f := r.Fitted(100, NewVec2(0, 0))
+	if lo, hi := f.Bounds(); math.Abs(hi.X-lo.X-100) > 1e-9 || math.Abs(hi.X+lo.X) > 1e-9 {
+		t.Errorf("Fitted drawing is %v to %v", lo, hi)
+	}
+
+	if _, err := ReadDXF(strings.NewReader("0\nSECTION\n2\nENTITIES\n0\nENDSEC\n0\nEOF\n")); err == nil {
+		t.Error("Empty DXF read")
+	}
+	if _, err := ReadDXF(strings.NewReader("zero\nSECTION\n")); err == nil {
+		t.Error("DXF with a bad group code read")
+	}
+}
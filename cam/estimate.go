@@ -0,0 +1,161 @@
+package cam
+
+// ███████╗███████╗████████╗██╗███╗   ███╗ █████╗ ████████╗███████╗
+// ██╔════╝██╔════╝╚══██╔══╝██║████╗ ████║██╔══██╗╚══██╔══╝██╔════╝
+// █████╗  ███████╗   ██║   ██║██╔████╔██║███████║   ██║   █████╗
+// ██╔══╝  ╚════██║   ██║   ██║██║╚██╔╝██║██╔══██║   ██║   ██╔══╝
+// ███████╗███████║   ██║   ██║██║ ╚═╝ ██║██║  ██║   ██║   ███████╗
+// ╚══════╝╚══════╝   ╚═╝   ╚═╝╚═╝     ╚═╝╚═╝  ╚═╝   ╚═╝   ╚══════╝
+
+import (
+	"fmt"
+	"math"
+)
+
+// CutRate is how fast a machine cuts sheet up to a thickness
+type CutRate struct {
+	Thickness float64 // mm
+	Feed      float64 // mm/min
+	Pierce    float64 // s
+}
+
+// Consumable is a part of the machine worn out by cutting, such as a nozzle, and how long it
+//   lasts: whichever runs out first of its pierces and its time cutting
+type Consumable struct {
+	Name    string
+	Pierces int     // it lasts, 0 if they do not wear it
+	ArcTime float64 // minutes cutting it lasts, 0 if cutting does not wear it
+	Cost    float64 // of a new one
+}
+
+// ConsumableUse is how many of a consumable an estimate uses up
+type ConsumableUse struct {
+	Consumable
+	Used float64
+}
+
+// ForThickness is the machine set up for sheet of the thickness, in m: the feed and pierce time
+//   of the thinnest rate it is no thicker than, or the thickest there is
+func (m Machine) ForThickness(t float64) Machine {
+	if len(m.Rates) == 0 {
+		return m
+	}
+	r := m.Rates[len(m.Rates)-1]
+	for _, rt := range m.Rates {
+		if t*1000 <= rt.Thickness+1e-9 {
+			r = rt
+			break
+		}
+	}
+	m.Feed, m.PierceDelay = r.Feed, r.Pierce
+	return m
+}
+
+// CutEstimate is how much cutting there is in something and how long the machine takes
+type CutEstimate struct {
+	Length  float64 // mm cut
+	Pierces int     // times the cut is started
+	Rapid   float64 // mm moved between cuts
+	Time    float64 // s
+}
+
+// Add is the two estimates together
+func (e CutEstimate) Add(o CutEstimate) CutEstimate {
+	return CutEstimate{Length: e.Length + o.Length, Pierces: e.Pierces + o.Pierces, Rapid: e.Rapid + o.Rapid, Time: e.Time + o.Time}
+}
+
+func (e CutEstimate) String() string {
+	return fmt.Sprintf("%.1fm cut, %d pierces, %.0fmin", e.Length/1000, e.Pierces, e.Time/60)
+}
+
+// cutRuns are the runs of joined up segments the machine cuts without stopping, in the order
+//   it cuts them
+func (p Path) cutRuns(m Machine) [][]Segment {
+	var runs [][]Segment
+	joined := false
+	for i, s := range p.Segments {
+		if !m.cuts(s.Kind) {
+			joined = false
+			continue
+		}
+		if !joined || s.Start.Subtract(p.Segments[i-1].End).Length() > IntersectTolerance {
+			runs = append(runs, nil)
+		}
+		runs[len(runs)-1] = append(runs[len(runs)-1], s)
+		joined = true
+	}
+	return runs
+}
+
+// Estimate is how long the machine takes to cut the drawing, in the order GCode cuts it, from
+//   and back to the origin: the cuts at its feed, a pierce at the start of each, and rapids
+//   between them. It is in mm whatever the drawing's units.
+func (d Drawing) Estimate(m Machine) CutEstimate {
+	d = d.InUnits(Millimetre)
+	var e CutEstimate
+	order := []int{}
+	outer := d.outline()
+	for i := range d.Paths {
+		if i != outer {
+			order = append(order, i)
+		}
+	}
+	if outer >= 0 {
+		order = append(order, outer)
+	}
+	at := Origin
+	for _, i := range order {
+		for _, run := range d.Paths[i].cutRuns(m) {
+			e.Rapid += run[0].Start.Subtract(at).Length()
+			e.Pierces++
+			for _, s := range run {
+				e.Length += s.Length()
+			}
+			at = run[len(run)-1].End
+		}
+	}
+	e.Rapid += at.Length()
+	e.Time = e.ArcTime(m)
+	if m.Rapid > 0 {
+		e.Time += e.Rapid / m.Rapid * 60
+	}
+	return e
+}
+
+// ArcTime is how long the machine is cutting, pierces included, s
+func (e CutEstimate) ArcTime(m Machine) float64 {
+	t := float64(e.Pierces) * m.PierceDelay
+	if m.Feed > 0 {
+		t += e.Length / m.Feed * 60
+	}
+	return t
+}
+
+// Consumables are how many of each of the machine's consumables the estimate uses up
+func (e CutEstimate) Consumables(m Machine) []ConsumableUse {
+	arc := e.ArcTime(m) / 60
+	var us []ConsumableUse
+	for _, c := range m.Consumables {
+		u := ConsumableUse{Consumable: c}
+		if c.Pierces > 0 {
+			u.Used = float64(e.Pierces) / float64(c.Pierces)
+		}
+		if c.ArcTime > 0 {
+			u.Used = math.Max(u.Used, arc/c.ArcTime)
+		}
+		us = append(us, u)
+	}
+	return us
+}
+
+// Cost is what running the machine for the estimate costs, its time and the consumables used
+func (e CutEstimate) Cost(m Machine) (machine, consumables float64) {
+	for _, u := range e.Consumables(m) {
+		consumables += u.Used * u.Cost
+	}
+	return e.Time / 3600 * m.HourlyRate, consumables
+}
+
+func (u ConsumableUse) String() string {
+	return fmt.Sprintf("%.2f %s", u.Used, u.Name)
+}
@@ -0,0 +1,70 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	d := Drawing{Name: "plate"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {100, 50}, {0, 50}}, EdgePath))
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{10, 10}, {20, 10}, {20, 20}, {10, 20}}, EdgePath))
+	d.Paths = append(d.Paths, NewFoldPath(NewVec2(50, 0), NewVec2(50, 50), Bend{Angle: deg90}))
+
+	m := Machine{Feed: 600, Rapid: 6000, PierceDelay: 1, HourlyRate: 60, Cuts: []PathKind{EdgePath},
+		Consumables: []Consumable{{Name: "Nozzle", Pierces: 4, Cost: 2}}}
+	e := d.Estimate(m)
+	if e.Pierces != 2 || math.Abs(e.Length-340) > 1e-9 {
+		t.Errorf("Expected the hole and the outline cut, got %s", e)
+	}
+	// Out to the hole, from it to the outline, which ends where it starts, then home
+	if rapid := 10*math.Sqrt2 + 10*math.Sqrt2; math.Abs(e.Rapid-rapid) > 1e-9 {
+		t.Errorf("Rapids are %.3fmm, expected %.3fmm", e.Rapid, rapid)
+	}
+	if time := 2 + 340/600.0*60 + e.Rapid/6000*60; math.Abs(e.Time-time) > 1e-9 {
+		t.Errorf("Time is %.3fs, expected %.3fs", e.Time, time)
+	}
+	machine, consumables := e.Cost(m)
+	if math.Abs(machine-e.Time/60) > 1e-9 || consumables != 1 {
+		t.Errorf("Costs are %.3f and %.3f", machine, consumables)
+	}
+	m.Consumables = append(m.Consumables, Consumable{Name: "Electrode", Pierces: 100, ArcTime: 0.5, Cost: 10})
+	us := e.Consumables(m)
+	if len(us) != 2 || us[0].Used != 0.5 || math.Abs(us[1].Used-e.ArcTime(m)/30) > 1e-9 {
+		t.Errorf("Consumables used are %v", us)
+	}
+	if _, consumables = e.Cost(m); math.Abs(consumables-1-10*us[1].Used) > 1e-9 {
+		t.Errorf("Costs are %.3f and %.3f", machine, consumables)
+	}
+
+	// A tab is a gap in the cut, so another pierce
+	tabbed := Drawing{Paths: []Path{d.Paths[0].WithTabs(Tabs{Spacing: 100, Width: 1, Clear: 5})}}
+	if te := tabbed.Estimate(m); te.Pierces < 2 || te.Length >= 300 {
+		t.Errorf("Tabbed outline estimate is %s", te)
+	}
+	if sum := e.Add(e); sum.Pierces != 4 || sum.Time != 2*e.Time {
+		t.Errorf("Sum of estimates is %s", sum)
+	}
+}
+
+func TestForThickness(t *testing.T) {
+	m := Machine{Feed: 1000, PierceDelay: 1, Rates: []CutRate{{1, 5000, 0.2}, {3, 2000, 0.5}}}
+	if f := m.ForThickness(0.0008); f.Feed != 5000 || f.PierceDelay != 0.2 {
+		t.Errorf("Thin sheet cut at %.0f", f.Feed)
+	}
+	if f := m.ForThickness(0.001); f.Feed != 5000 {
+		t.Errorf("Sheet as thick as a rate cut at %.0f", f.Feed)
+	}
+	if f := m.ForThickness(0.002); f.Feed != 2000 || f.PierceDelay != 0.5 {
+		t.Errorf("Sheet between rates cut at %.0f", f.Feed)
+	}
+	if f := m.ForThickness(0.01); f.Feed != 2000 {
+		t.Errorf("Sheet thicker than every rate cut at %.0f", f.Feed)
+	}
+	if f := (Machine{Feed: 1000}).ForThickness(0.01); f.Feed != 1000 {
+		t.Errorf("Machine with no rates cut at %.0f", f.Feed)
+	}
+	if Machines[ProcPlasma].ForThickness(0.001).Feed <= Machines[ProcPlasma].ForThickness(0.006).Feed {
+		t.Error("Plasma cuts thick sheet as fast as thin")
+	}
+}
@@ -0,0 +1,154 @@
+package cam
+
+//  ██████╗  ██████╗ ██████╗ ██████╗ ███████╗
+// ██╔════╝ ██╔════╝██╔═══██╗██╔══██╗██╔════╝
+// ██║  ███╗██║     ██║   ██║██║  ██║█████╗
+// ██║   ██║██║     ██║   ██║██║  ██║██╔══╝
+// ╚██████╔╝╚██████╗╚██████╔╝██████╔╝███████╗
+//  ╚═════╝  ╚═════╝ ╚═════╝ ╚═════╝ ╚══════╝
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Machine is what we need to know about a CNC machine to write G-code for it, and to estimate
+//   how long and what it costs to cut with. Lengths are mm, feeds mm/min.
+type Machine struct {
+	Name        string
+	Torch       bool       // the cutter is switched on and off for each path (plasma, laser, waterjet), not left running (router)
+	SafeZ       float64    // height for rapid moves between paths
+	PierceZ     float64    // height to start the cut at
+	CutZ        float64    // height, or for a router depth (negative), while cutting
+	PierceDelay float64    // s, wait after starting to cut before moving
+	Feed        float64    // along the cut
+	PlungeFeed  float64    // down to the cut
+	Spindle     float64    // rpm for a router, 0 for a torch
+	Cuts        []PathKind // the kinds of path cut, the rest are left out
+	Rapid       float64    // between cuts
+	HourlyRate  float64    // cost of an hour of the machine and its operator
+	Rates       []CutRate  // feed and pierce time by thickness, thinnest first, Feed and PierceDelay if none
+	Consumables []Consumable
+}
+
+// Machines are typical profiles for each process
+var Machines = map[CutProcess]Machine{
+	ProcPlasma: {Name: "Plasma table", Torch: true, SafeZ: 10, PierceZ: 3.8, CutZ: 1.5,
+		PierceDelay: 0.5, Feed: 2500, PlungeFeed: 1000, Cuts: []PathKind{EdgePath},
+		Rapid: 15000, HourlyRate: 90,
+		Rates: []CutRate{{0.5, 8000, 0.1}, {1, 6500, 0.2}, {1.5, 5000, 0.3}, {3, 3000, 0.4}, {6, 1600, 0.5}, {12, 700, 1}},
+		Consumables: []Consumable{{Name: "Electrode", Pierces: 600, ArcTime: 120, Cost: 12},
+			{Name: "Nozzle", Pierces: 300, ArcTime: 60, Cost: 8}}},
+	ProcLaser: {Name: "Laser", Torch: true, SafeZ: 5, PierceZ: 1, CutZ: 1,
+		PierceDelay: 0.1, Feed: 6000, PlungeFeed: 2000, Cuts: []PathKind{EdgePath},
+		Rapid: 40000, HourlyRate: 150,
+		Rates: []CutRate{{1, 20000, 0.05}, {2, 10000, 0.1}, {3, 6000, 0.2}, {6, 2000, 0.5}, {12, 600, 2}},
+		Consumables: []Consumable{{Name: "Nozzle", Pierces: 5000, Cost: 5},
+			{Name: "Protective window", ArcTime: 1200, Cost: 10}}},
+	ProcWaterjet: {Name: "Waterjet", Torch: true, SafeZ: 10, PierceZ: 3, CutZ: 2,
+		PierceDelay: 1, Feed: 800, PlungeFeed: 500, Cuts: []PathKind{EdgePath},
+		Rapid: 10000, HourlyRate: 120,
+		Rates: []CutRate{{1, 2000, 1}, {3, 900, 2}, {6, 500, 4}, {12, 250, 8}},
+		Consumables: []Consumable{{Name: "Garnet (0.3kg)", ArcTime: 1, Cost: 0.15},
+			{Name: "Orifice", ArcTime: 6000, Cost: 40}, {Name: "Mixing tube", ArcTime: 6000, Cost: 150}}},
+	ProcRouter: {Name: "Router", SafeZ: 10, PierceZ: 0, CutZ: -2,
+		Feed: 1200, PlungeFeed: 300, Spindle: 18000, Cuts: []PathKind{EdgePath},
+		Rapid: 8000, HourlyRate: 60,
+		Rates:       []CutRate{{1, 1500, 0}, {3, 1000, 0}, {6, 600, 0}},
+		Consumables: []Consumable{{Name: "Bit", ArcTime: 480, Cost: 25}}},
+}
+
+// cuts is true if the machine cuts paths of kind k
+func (m Machine) cuts(k PathKind) bool {
+	for _, c := range m.Cuts {
+		if c == k {
+			return true
+		}
+	}
+	return false
+}
+
+// gcodeMove writes one move, leaving out the axes not given
+func gcodeMove(b *strings.Builder, g string, axes ...interface{}) {
+	b.WriteString(g)
+	for i := 0; i+1 < len(axes); i += 2 {
+		fmt.Fprintf(b, " %s%.3f", axes[i], axes[i+1])
+	}
+	b.WriteString("\n")
+}
+
+// GCode is the moves to cut the path on the machine: a rapid to its start, down into the cut,
+//   along every segment of a kind the machine cuts, and back up at the end or wherever the
+//   segments do not join up. It leaves a router's spindle running and a torch off.
+func (p Path) GCode(m Machine) string {
+	var b strings.Builder
+	cutting := false
+	var at Vec2
+	up := func() {
+		if cutting {
+			if m.Torch {
+				b.WriteString("M5\n")
+			}
+			gcodeMove(&b, "G0", "Z", m.SafeZ)
+		}
+		cutting = false
+	}
+	for _, s := range p.Segments {
+		if !m.cuts(s.Kind) {
+			up()
+			continue
+		}
+		if cutting && s.Start.Subtract(at).Length() > IntersectTolerance {
+			up()
+		}
+		if !cutting {
+			gcodeMove(&b, "G0", "X", s.Start.X, "Y", s.Start.Y)
+			gcodeMove(&b, "G0", "Z", m.PierceZ)
+			if m.Torch {
+				b.WriteString("M3\n")
+			}
+			if m.PierceDelay > 0 {
+				fmt.Fprintf(&b, "G4 P%.2f\n", m.PierceDelay)
+			}
+			gcodeMove(&b, "G1", "Z", m.CutZ, "F", m.PlungeFeed)
+			fmt.Fprintf(&b, "F%.0f\n", m.Feed)
+			cutting = true
+		}
+		gcodeMove(&b, "G1", "X", s.End.X, "Y", s.End.Y)
+		at = s.End
+	}
+	up()
+	return b.String()
+}
+
+// GCode is the program to cut the drawing on the machine, absolute, in the drawing's units,
+//   holes and other paths first and the outline last so the part stays put until it is done
+func (d Drawing) GCode(m Machine) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "(%s on %s)\n", d.Name, m.Name)
+	m = m.InUnits(d.Units)
+	if d.Units == Inch {
+		b.WriteString("G20 G90 G17\n")
+	} else {
+		b.WriteString("G21 G90 G17\n")
+	}
+	gcodeMove(&b, "G0", "Z", m.SafeZ)
+	if !m.Torch {
+		fmt.Fprintf(&b, "M3 S%.0f\n", m.Spindle)
+	}
+	outer := d.outline()
+	for i, p := range d.Paths {
+		if i != outer {
+			b.WriteString(p.GCode(m))
+		}
+	}
+	if outer >= 0 {
+		b.WriteString(d.Paths[outer].GCode(m))
+	}
+	if !m.Torch {
+		b.WriteString("M5\n")
+	}
+	gcodeMove(&b, "G0", "X", 0.0, "Y", 0.0)
+	b.WriteString("M30\n")
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package cam
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGCode(t *testing.T) {
+
+	d := Drawing{Name: "plate"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {100, 50}, {0, 50}}, EdgePath))
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{10, 10}, {10, 20}, {20, 20}, {20, 10}}, EdgePath))
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{50, 0}, {50, 50}}, FoldPath))
+
+	g := d.GCode(Machines[ProcPlasma])
+	if n := strings.Count(g, "M3\n"); n != 2 {
+		t.Errorf("Expected the torch lit twice, got %d", n)
+	}
+	if strings.Count(g, "M5\n") != 2 {
+		t.Error("Torch not put out after each path")
+	}
+	if n := strings.Count(g, "G1 X"); n != 8 {
+		t.Errorf("Expected 8 cutting moves, got %d", n)
+	}
+	if strings.Index(g, "G0 X10.000 Y10.000") > strings.Index(g, "G0 X0.000 Y0.000") {
+		t.Error("Outline cut before the hole")
+	}
+	if strings.Contains(g, "X50.000 Y50.000") {
+		t.Error("Fold line was cut")
+	}
+	if !strings.HasSuffix(g, "M30\n") {
+		t.Error("Program does not end with M30")
+	}
+
+	r := d.GCode(Machines[ProcRouter])
+	if strings.Count(r, "M3 S") != 1 || strings.Contains(r, "M3\n") || !strings.Contains(r, "G1 Z-2.000 F300.000") {
+		t.Errorf("Router program wrong:\n%s", r)
+	}
+}
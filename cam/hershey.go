@@ -0,0 +1,108 @@
+package cam
+
+// ██╗  ██╗███████╗██████╗ ███████╗██╗  ██╗███████╗██╗   ██╗
+// ██║  ██║██╔════╝██╔══██╗██╔════╝██║  ██║██╔════╝╚██╗ ██╔╝
+// ███████║█████╗  ██████╔╝███████╗███████║█████╗   ╚████╔╝
+// ██╔══██║██╔══╝  ██╔══██╗╚════██║██╔══██║██╔══╝    ╚██╔╝
+// ██║  ██║███████╗██║  ██║███████║██║  ██║███████╗   ██║
+// ╚═╝  ╚═╝╚══════╝╚═╝  ╚═╝╚══════╝╚═╝  ╚═╝╚══════╝   ╚═╝
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	hersheyBaseline = 9  // Hershey y of the baseline, y being down
+	hersheyCap      = 21 // height of capitals, from the baseline up to y -12
+)
+
+// LoadHersheyFont reads a single stroke font in the Hershey .jhf format, as distributed with
+//   many plotting programs: a glyph a record, its number, the count of coordinate pairs, then
+//   the pairs as letters offset from 'R', the first pair the left and right sides and " R"
+//   lifting the pen, long records running on over following lines. The glyphs are taken to be
+//   the printable ASCII characters in order from space, as they are in the ASCII font files.
+//   The font is scaled so capitals are 9 high, like Plain.
+func LoadHersheyFont(r io.Reader) (Font, error) {
+	f := make(Font)
+	sc := bufio.NewScanner(r)
+	rec, start, n := "", 0, 0
+	for line := 1; sc.Scan(); line++ {
+		txt := strings.TrimRight(sc.Text(), "\r")
+		if rec == "" {
+			if strings.TrimSpace(txt) == "" {
+				continue
+			}
+			start = line
+		}
+		rec += txt
+		if len(rec) < 8 {
+			return nil, fmt.Errorf("hershey: line %d: record too short", start)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(rec[5:8]))
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("hershey: line %d: bad count of pairs %q", start, rec[5:8])
+		}
+		if len(rec) < 8+2*count {
+			continue // runs on to the next line
+		}
+		l, err := hersheyLetter(rec[8 : 8+2*count])
+		if err != nil {
+			return nil, fmt.Errorf("hershey: line %d: %s", start, err)
+		}
+		f[string(rune(' '+n))] = l
+		rec, n = "", n+1
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if rec != "" {
+		return nil, fmt.Errorf("hershey: line %d: record cut short", start)
+	}
+	return f, nil
+}
+
+// hersheyLetter makes a letter from the coordinate pairs of a glyph
+func hersheyLetter(pairs string) (Letter, error) {
+	k := float64(9) / hersheyCap
+	coord := func(c byte) float64 { return float64(int(c) - int('R')) }
+	left, right := coord(pairs[0]), coord(pairs[1])
+	var lines [][]Vec2
+	var cur []Vec2
+	for i := 2; i+1 < len(pairs); i += 2 {
+		if pairs[i] == ' ' && pairs[i+1] == 'R' { // pen up
+			if len(cur) > 1 {
+				lines = append(lines, cur)
+			}
+			cur = nil
+			continue
+		}
+		if pairs[i] < ' ' || pairs[i+1] < ' ' {
+			return Letter{}, fmt.Errorf("bad coordinate %q", pairs[i:i+2])
+		}
+		cur = append(cur, NewVec2((coord(pairs[i])-left)*k, (hersheyBaseline-coord(pairs[i+1]))*k))
+	}
+	if len(cur) > 1 {
+		lines = append(lines, cur)
+	}
+	return Letter{Width: (right - left) * k, Height: 9, Draw: strokes(lines)}, nil
+}
+
+// Scaled is the font k times the size, drawn with its strokes as straight lines
+func (f Font) Scaled(k float64) Font {
+	g := make(Font)
+	for c, l := range f {
+		t := NewTurtle()
+		t.TurnTo(deg90) // along +x, so the letter's frame is the world's
+		l.Draw(&t)
+		var lines [][]Vec2
+		for _, s := range t.Trail.Segments {
+			lines = append(lines, []Vec2{s.Start.Scale(k), s.End.Scale(k)})
+		}
+		g[c] = Letter{Width: l.Width * k, Height: l.Height * k, Draw: strokes(lines)}
+	}
+	return g
+}
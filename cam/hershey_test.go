@@ -0,0 +1,42 @@
+package cam
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLoadHersheyFont(t *testing.T) {
+
+	// Space, then the simplex A as the next glyph, '!', running on over two lines
+	jhf := "12345  1JZ\n  501  9I[RFJ[ R\nRFZ[ RMTWT\n"
+	f, err := LoadHersheyFont(strings.NewReader(jhf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f) != 2 || math.Abs(f[" "].Width-16*9.0/21) > 1e-9 {
+		t.Fatalf("Expected space and one glyph, got %d", len(f))
+	}
+	a := f["!"]
+	tu := NewTurtle()
+	tu.TurnTo(deg90)
+	a.Draw(&tu)
+	if len(tu.Trail.Segments) != 3 {
+		t.Errorf("A should be 3 strokes, is %d", len(tu.Trail.Segments))
+	}
+	lo, hi := Drawing{Paths: []Path{tu.Trail}}.Bounds()
+	if math.Abs(hi.Y-9) > 1e-9 || math.Abs(lo.Y) > 1e-9 || math.Abs(lo.X-9.0/21) > 1e-9 {
+		t.Errorf("A not 9 high on the baseline: %s %s", lo, hi)
+	}
+
+	big := f.Scaled(2)
+	if math.Abs(big["!"].Width-2*a.Width) > 1e-9 {
+		t.Error("Scaled width wrong")
+	}
+
+	for _, bad := range []string{"12345xx1JZ\n", "  501  9I[RFJ[\n"} {
+		if _, err := LoadHersheyFont(strings.NewReader(bad)); err == nil {
+			t.Errorf("No error loading %q", bad)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package cam
+
+// ██╗███╗   ██╗████████╗███████╗██████╗ ███████╗███████╗ ██████╗████████╗
+// ██║████╗  ██║╚══██╔══╝██╔════╝██╔══██╗██╔════╝██╔════╝██╔════╝╚══██╔══╝
+// ██║██╔██╗ ██║   ██║   █████╗  ██████╔╝███████╗█████╗  ██║        ██║
+// ██║██║╚██╗██║   ██║   ██╔══╝  ██╔══██╗╚════██║██╔══╝  ██║        ██║
+// ██║██║ ╚████║   ██║   ███████╗██║  ██║███████║███████╗╚██████╗   ██║
+// ╚═╝╚═╝  ╚═══╝   ╚═╝   ╚══════╝╚═╝  ╚═╝╚══════╝╚══════╝ ╚═════╝   ╚═╝
+
+import (
+	"math"
+	"sort"
+)
+
+// IntersectTolerance is how close, in mm, lines must come to count as touching
+var IntersectTolerance = 1e-6
+
+// Dot is the dot product
+func (v Vec2) Dot(v2 Vec2) float64 {
+	return v.X*v2.X + v.Y*v2.Y
+}
+
+// Cross is the z part of the cross product, positive if v2 is anticlockwise from v
+func (v Vec2) Cross(v2 Vec2) float64 {
+	return v.X*v2.Y - v.Y*v2.X
+}
+
+// Length is that of the segment
+func (s Segment) Length() float64 {
+	return s.End.Subtract(s.Start).Length()
+}
+
+// At is the point a fraction t of the way along the segment
+func (s Segment) At(t float64) Vec2 {
+	return s.Start.Add(s.End.Subtract(s.Start).Scale(t))
+}
+
+// closest is the fraction of the way along the segment to the point on it closest to p
+func (s Segment) closest(p Vec2) float64 {
+	d := s.End.Subtract(s.Start)
+	l2 := d.Dot(d)
+	if l2 == 0 {
+		return 0
+	}
+	return math.Max(0, math.Min(1, p.Subtract(s.Start).Dot(d)/l2))
+}
+
+// Intersect finds where two segments cross or touch, as fractions t along s and u along o. If
+//   they lie along each other, it is the start of the overlap along s.
+func (s Segment) Intersect(o Segment) (where Vec2, t, u float64, hits bool) {
+	if ov, ok := s.Overlap(o); ok {
+		t, u = s.closest(ov.Start), o.closest(ov.Start)
+		return ov.Start, t, u, true
+	}
+	d, e := s.End.Subtract(s.Start), o.End.Subtract(o.Start)
+	den := d.Cross(e)
+	if math.Abs(den) <= IntersectTolerance*IntersectTolerance {
+		return Origin, 0, 0, false // parallel and apart, or too short to say
+	}
+	r := o.Start.Subtract(s.Start)
+	t, u = r.Cross(e)/den, r.Cross(d)/den
+	tt, tu := IntersectTolerance/d.Length(), IntersectTolerance/e.Length()
+	if t < -tt || t > 1+tt || u < -tu || u > 1+tu {
+		return Origin, 0, 0, false
+	}
+	t, u = math.Max(0, math.Min(1, t)), math.Max(0, math.Min(1, u))
+	return s.At(t), t, u, true
+}
+
+// Overlap is the part of s which o lies along, if they are in line and share more than a point
+func (s Segment) Overlap(o Segment) (Segment, bool) {
+	d := s.End.Subtract(s.Start)
+	l := d.Length()
+	if l <= IntersectTolerance {
+		return Segment{}, false
+	}
+	n := NewVec2(-d.Y/l, d.X/l)
+	if math.Abs(o.Start.Subtract(s.Start).Dot(n)) > IntersectTolerance || math.Abs(o.End.Subtract(s.Start).Dot(n)) > IntersectTolerance {
+		return Segment{}, false
+	}
+	a, b := o.Start.Subtract(s.Start).Dot(d)/(l*l), o.End.Subtract(s.Start).Dot(d)/(l*l)
+	lo, hi := math.Max(0, math.Min(a, b)), math.Min(1, math.Max(a, b))
+	if (hi-lo)*l <= IntersectTolerance {
+		return Segment{}, false
+	}
+	return Segment{Kind: s.Kind, Start: s.At(lo), End: s.At(hi)}, true
+}
+
+// ClosestPoints finds the points on s and o closest to each other, and the distance between them
+func (s Segment) ClosestPoints(o Segment) (a, b Vec2, dist float64) {
+	if w, _, _, ok := s.Intersect(o); ok {
+		return w, w, 0
+	}
+	dist = math.Inf(1)
+	try := func(p, q Vec2) {
+		if l := p.Subtract(q).Length(); l < dist {
+			a, b, dist = p, q, l
+		}
+	}
+	try(s.Start, o.At(o.closest(s.Start)))
+	try(s.End, o.At(o.closest(s.End)))
+	try(s.At(s.closest(o.Start)), o.Start)
+	try(s.At(s.closest(o.End)), o.End)
+	return a, b, dist
+}
+
+// Crossings are where the segment meets the path, in order along the segment. A crossing at a
+//   corner of the path is only counted once.
+func (p Path) Crossings(s Segment) []Vec2 {
+	type hit struct {
+		t     float64
+		where Vec2
+	}
+	hits := []hit{}
+	for _, ps := range p.Segments {
+		if w, t, _, ok := s.Intersect(ps); ok {
+			hits = append(hits, hit{t, w})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].t < hits[j].t })
+	ws := []Vec2{}
+	for _, h := range hits {
+		if len(ws) > 0 && h.where.Subtract(ws[len(ws)-1]).Length() <= IntersectTolerance {
+			continue
+		}
+		ws = append(ws, h.where)
+	}
+	return ws
+}
+
+// SelfIntersections are where the path crosses or runs back along itself, apart from where
+//   each segment joins the next
+func (p Path) SelfIntersections() []Vec2 {
+	n := len(p.Segments)
+	ws := []Vec2{}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			a, b := p.Segments[i], p.Segments[j]
+			next := j == i+1 || (p.Closed && i == 0 && j == n-1)
+			if next {
+				if ov, ok := a.Overlap(b); ok { // doubles back
+					ws = append(ws, ov.Start)
+				}
+				continue
+			}
+			if w, _, _, ok := a.Intersect(b); ok {
+				ws = append(ws, w)
+			}
+		}
+	}
+	return ws
+}
+
+// SelfIntersects is true if the path crosses itself, as a badly flattened panel might
+func (p Path) SelfIntersects() bool {
+	return len(p.SelfIntersections()) > 0
+}
+
+// Distance is the closest any segment of p comes to any of q, 0 if they cross
+func (p Path) Distance(q Path) float64 {
+	d := math.Inf(1)
+	for _, a := range p.Segments {
+		for _, b := range q.Segments {
+			if _, _, l := a.ClosestPoints(b); l < d {
+				d = l
+			}
+		}
+	}
+	return d
+}
@@ -0,0 +1,70 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func near(a, b Vec2) bool {
+	return a.Subtract(b).Length() < 1e-9
+}
+
+func TestSegmentIntersect(t *testing.T) {
+
+	a := Segment{Start: NewVec2(0, 0), End: NewVec2(10, 0)}
+	b := Segment{Start: NewVec2(5, -5), End: NewVec2(5, 5)}
+	if w, ta, tb, ok := a.Intersect(b); !ok || !near(w, NewVec2(5, 0)) || math.Abs(ta-0.5) > 1e-9 || math.Abs(tb-0.5) > 1e-9 {
+		t.Errorf("Crossing segments wrong: %v %s %f %f", ok, w, ta, tb)
+	}
+
+	// Touching at an end, and just missing
+	if w, _, _, ok := a.Intersect(Segment{Start: NewVec2(10, 0), End: NewVec2(12, 3)}); !ok || !near(w, NewVec2(10, 0)) {
+		t.Errorf("Segments touching at the end missed: %s", w)
+	}
+	if _, _, _, ok := a.Intersect(Segment{Start: NewVec2(10.1, 0), End: NewVec2(12, 3)}); ok {
+		t.Errorf("Segments apart hit")
+	}
+
+	// Along each other
+	c := Segment{Start: NewVec2(12, 0), End: NewVec2(6, 0)}
+	ov, ok := a.Overlap(c)
+	if !ok || !near(ov.Start, NewVec2(6, 0)) || !near(ov.End, NewVec2(10, 0)) {
+		t.Errorf("Overlap wrong: %v %s", ok, ov)
+	}
+	if w, _, _, ok := a.Intersect(c); !ok || !near(w, NewVec2(6, 0)) {
+		t.Errorf("Overlapping segments should meet at the start of the overlap: %s", w)
+	}
+	if _, ok := a.Overlap(Segment{Start: NewVec2(0, 1), End: NewVec2(10, 1)}); ok {
+		t.Errorf("Parallel segments overlapped")
+	}
+
+	// Closest points
+	if p, q, d := a.ClosestPoints(Segment{Start: NewVec2(3, 2), End: NewVec2(4, 6)}); math.Abs(d-2) > 1e-9 || !near(p, NewVec2(3, 0)) || !near(q, NewVec2(3, 2)) {
+		t.Errorf("Closest points wrong: %s %s %f", p, q, d)
+	}
+}
+
+func TestPathIntersections(t *testing.T) {
+
+	square := NewPolygonPath([]Vec2{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, EdgePath)
+	xs := square.Crossings(Segment{Start: NewVec2(-5, 5), End: NewVec2(15, 5)})
+	if len(xs) != 2 || !near(xs[0], NewVec2(0, 5)) || !near(xs[1], NewVec2(10, 5)) {
+		t.Errorf("Crossings of the square wrong: %v", xs)
+	}
+	if xs := square.Crossings(Segment{Start: NewVec2(-5, -5), End: NewVec2(5, 5)}); len(xs) != 1 {
+		t.Errorf("Through a corner should cross once, not %d", len(xs))
+	}
+
+	if square.SelfIntersects() {
+		t.Errorf("Square crosses itself: %v", square.SelfIntersections())
+	}
+	bowtie := NewPolygonPath([]Vec2{{0, 0}, {10, 10}, {10, 0}, {0, 10}}, EdgePath)
+	if xs := bowtie.SelfIntersections(); len(xs) != 1 || !near(xs[0], NewVec2(5, 5)) {
+		t.Errorf("Bowtie should cross itself once in the middle: %v", xs)
+	}
+
+	other := NewPolygonPath([]Vec2{{13, 0}, {20, 0}, {20, 10}, {13, 10}}, EdgePath)
+	if d := square.Distance(other); math.Abs(d-3) > 1e-9 {
+		t.Errorf("Squares should be 3 apart, not %f", d)
+	}
+}
@@ -0,0 +1,91 @@
+package cam
+
+// ██╗  ██╗███████╗██████╗ ███████╗
+// ██║ ██╔╝██╔════╝██╔══██╗██╔════╝
+// █████╔╝ █████╗  ██████╔╝█████╗
+// ██╔═██╗ ██╔══╝  ██╔══██╗██╔══╝
+// ██║  ██╗███████╗██║  ██║██║
+// ╚═╝  ╚═╝╚══════╝╚═╝  ╚═╝╚═╝
+
+import "math"
+
+// CutProcess is the way parts are cut out of the sheet
+type CutProcess int
+
+// Values of CutProcess
+const (
+	ProcPlasma   CutProcess = iota // CNC plasma
+	ProcLaser                      // fibre or CO2 laser
+	ProcWaterjet                   // abrasive waterjet
+	ProcRouter                     // CNC router with a milling bit
+)
+
+// String names the process
+func (c CutProcess) String() string {
+	switch c {
+	case ProcPlasma:
+		return "Plasma"
+	case ProcLaser:
+		return "Laser"
+	case ProcWaterjet:
+		return "Waterjet"
+	case ProcRouter:
+		return "Router"
+	}
+	return "Unknown"
+}
+
+// DefaultKerfs are typical kerf widths in m for thin sheet, used if the material has none of its own
+var DefaultKerfs = map[CutProcess]float64{
+	ProcPlasma:   0.0015,
+	ProcLaser:    0.0002,
+	ProcWaterjet: 0.0009,
+	ProcRouter:   0.003, // the bit
+}
+
+// Kerf is the width of the cut made by the process in this material, m
+func (m Material) Kerf(c CutProcess) float64 {
+	if k, ok := m.Kerfs[c]; ok {
+		return k
+	}
+	return DefaultKerfs[c]
+}
+
+// Area is the signed area enclosed by the path, positive if it runs anticlockwise
+func (p Path) Area() float64 {
+	a := 0.0
+	for _, s := range p.Segments {
+		a += s.Start.X*s.End.Y - s.End.X*s.Start.Y
+	}
+	return a / 2
+}
+
+// outline is the index of the closed edge path of largest area, the outside of the part, or -1
+func (d Drawing) outline() int {
+	outer := -1
+	big := 0.0
+	for i, p := range d.Paths {
+		if p.Closed && len(p.Segments) > 0 && p.Segments[0].Kind == EdgePath && math.Abs(p.Area()) > big {
+			outer, big = i, math.Abs(p.Area())
+		}
+	}
+	return outer
+}
+
+// KerfCompensated moves the cut paths so parts come out at their nominal size: the outline (the
+//   closed edge path of largest area) grows by half the kerf, holes shrink by it. Kerf is in mm.
+func (d Drawing) KerfCompensated(kerf float64) Drawing {
+	outer := d.outline()
+	k := Drawing{Name: d.Name, ID: d.ID, Units: d.Units}
+	for i, p := range d.Paths {
+		switch {
+		case !p.Closed || len(p.Segments) == 0 || p.Segments[0].Kind != EdgePath:
+			k.Paths = append(k.Paths, p)
+		case i == outer:
+			k.Paths = append(k.Paths, p.Offset(kerf/2))
+		default:
+			k.Paths = append(k.Paths, p.Offset(-kerf/2))
+		}
+	}
+	return k
+}
@@ -0,0 +1,26 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestKerfCompensated(t *testing.T) {
+
+	d := Drawing{Name: "plate"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {100, 50}, {0, 50}}, EdgePath))
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{10, 10}, {10, 20}, {20, 20}, {20, 10}}, EdgePath)) // clockwise hole
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{50, 0}, {50, 50}}, FoldPath))
+
+	k := d.KerfCompensated(2)
+
+	if a := k.Paths[0].Area(); math.Abs(a-102*52) > 1e-9 {
+		t.Errorf("Outline should grow to 102x52, area is %f", a)
+	}
+	if a := math.Abs(k.Paths[1].Area()); math.Abs(a-8*8) > 1e-9 {
+		t.Errorf("Hole should shrink to 8x8, area is %f", a)
+	}
+	if k.Paths[2].Segments[0] != d.Paths[2].Segments[0] {
+		t.Error("Fold line should not move")
+	}
+}
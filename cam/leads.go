@@ -0,0 +1,155 @@
+package cam
+
+// ██╗     ███████╗ █████╗ ██████╗ ███████╗
+// ██║     ██╔════╝██╔══██╗██╔══██╗██╔════╝
+// ██║     █████╗  ███████║██║  ██║███████╗
+// ██║     ██╔══╝  ██╔══██║██║  ██║╚════██║
+// ███████╗███████╗██║  ██║██████╔╝███████║
+// ╚══════╝╚══════╝╚═╝  ╚═╝╚═════╝ ╚══════╝
+
+import "math"
+
+// LeadStyle is the shape of the leads onto and off a cut
+type LeadStyle int
+
+// Values of LeadStyle
+const (
+	LeadNone LeadStyle = iota // pierce on the cut itself
+	LeadLine                  // straight in, square to the cut
+	LeadArc                   // a quarter circle, meeting the cut tangentially
+)
+
+// String names the style
+func (s LeadStyle) String() string {
+	switch s {
+	case LeadNone:
+		return "None"
+	case LeadLine:
+		return "Line"
+	case LeadArc:
+		return "Arc"
+	}
+	return "Unknown"
+}
+
+// Leads say how a closed cut is started and finished: pierced in the scrap, off the finished
+//   edge, and led onto it, then led off it again at the end. Lengths are mm.
+type Leads struct {
+	Style   LeadStyle
+	In, Out float64 // length of the lead in and lead out, or radius for arcs
+}
+
+// DefaultLeads are typical leads for thin sheet, used if the material has none of its own
+var DefaultLeads = map[CutProcess]Leads{
+	ProcPlasma:   {Style: LeadArc, In: 5, Out: 2},
+	ProcLaser:    {Style: LeadLine, In: 1, Out: 0.5},
+	ProcWaterjet: {Style: LeadArc, In: 3, Out: 1},
+	ProcRouter:   {Style: LeadNone},
+}
+
+// LeadsFor is how the process starts and finishes cuts in this material
+func (m Material) LeadsFor(c CutProcess) Leads {
+	if l, ok := m.Leads[c]; ok {
+		return l
+	}
+	return DefaultLeads[c]
+}
+
+// leadFraction is the most of the smaller side of a hole its leads may take up
+const leadFraction = 0.25
+
+// WithLeads is the closed path opened at the middle of its longest edge segment, away from corners,
+//   with the leads added there on the scrap side: outside if scrapOutside, else inside. It is no
+//   longer closed, since it starts at the pierce point and ends at the end of the lead out.
+func (p Path) WithLeads(l Leads, scrapOutside bool) Path {
+	n := len(p.Segments)
+	if !p.Closed || n == 0 || l.Style == LeadNone {
+		return p
+	}
+	long := 0
+	for i, s := range p.Segments {
+		if s.Kind == EdgePath && (p.Segments[long].Kind != EdgePath || s.Length() > p.Segments[long].Length()) {
+			long = i
+		}
+	}
+	s := p.Segments[long]
+	start := s.At(0.5)
+	along := s.End.Subtract(s.Start).Scale(1 / s.Length())
+	scrap := NewVec2(along.Y, -along.X) // right, which is outside for anticlockwise
+	if (p.Area() > 0) != scrapOutside {
+		scrap = scrap.Scale(-1)
+	}
+	in, out := l.In, l.Out
+	if !scrapOutside { // keep the leads inside the hole
+		lo, hi := Drawing{Paths: []Path{p}}.Bounds()
+		most := leadFraction * math.Min(hi.X-lo.X, hi.Y-lo.Y)
+		in, out = math.Min(in, most), math.Min(out, most)
+	}
+
+	g := Path{}
+	add := func(pts []Vec2) {
+		for i := 0; i+1 < len(pts); i++ {
+			g.Add(Segment{Kind: s.Kind, Start: pts[i], End: pts[i+1]})
+		}
+	}
+	add(leadPoints(l.Style, start, along, scrap, in, true))
+	g.Add(Segment{Kind: s.Kind, Start: start, End: s.End})
+	for k := 1; k < n; k++ {
+		g.Add(p.Segments[(long+k)%n])
+	}
+	g.Add(Segment{Kind: s.Kind, Start: s.Start, End: start})
+	add(leadPoints(l.Style, start, along, scrap, out, false))
+	return g
+}
+
+// leadPoints are the points of a lead of length r, from the pierce point to start if in, else
+//   from start off into the scrap, the cut running along there
+func leadPoints(style LeadStyle, start, along, scrap Vec2, r float64, in bool) []Vec2 {
+	if r <= 0 {
+		return nil
+	}
+	if style == LeadLine {
+		if in {
+			return []Vec2{start.Add(scrap.Scale(r)), start}
+		}
+		return []Vec2{start, start.Add(scrap.Scale(r))}
+	}
+	// A quarter circle round center, tangent to the cut at start
+	center := start.Add(scrap.Scale(r))
+	steps := 2
+	if r > CurveTolerance {
+		steps = int(math.Max(2, math.Ceil(deg90/(2*math.Acos(1-CurveTolerance/r)))))
+	}
+	var pts []Vec2
+	for k := 0; k <= steps; k++ {
+		a := deg90 * float64(k) / float64(steps)
+		if in { // from behind, round to start
+			a = deg90 - a
+			pts = append(pts, center.Add(along.Scale(-r*math.Sin(a))).Add(scrap.Scale(-r*math.Cos(a))))
+		} else { // from start, on round into the scrap
+			pts = append(pts, center.Add(along.Scale(r*math.Sin(a))).Add(scrap.Scale(-r*math.Cos(a))))
+		}
+	}
+	return pts
+}
+
+// WithLeads is the drawing with leads added to its closed edge paths, the outline's outside it
+//   and the holes' inside them. The outline is moved to the end, as it must be cut last.
+func (d Drawing) WithLeads(l Leads) Drawing {
+	outer := d.outline()
+	g := Drawing{Name: d.Name, ID: d.ID, Units: d.Units}
+	for i, p := range d.Paths {
+		switch {
+		case i == outer:
+			continue
+		case p.Closed && len(p.Segments) > 0 && p.Segments[0].Kind == EdgePath:
+			g.Paths = append(g.Paths, p.WithLeads(l, false))
+		default:
+			g.Paths = append(g.Paths, p)
+		}
+	}
+	if outer >= 0 {
+		g.Paths = append(g.Paths, d.Paths[outer].WithLeads(l, true))
+	}
+	return g
+}
@@ -0,0 +1,47 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithLeads(t *testing.T) {
+
+	d := Drawing{Name: "plate"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {100, 50}, {0, 50}}, EdgePath))
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{10, 10}, {10, 20}, {20, 20}, {20, 10}}, EdgePath))
+	l := d.WithLeads(Leads{Style: LeadLine, In: 5, Out: 2})
+
+	outline := l.Paths[1]
+	if outline.Closed || outline.Segments[0].Start != (Vec2{50, -5}) || outline.Segments[0].End != (Vec2{50, 0}) {
+		t.Errorf("Outline lead in wrong: %s", outline)
+	}
+	if end := outline.Segments[len(outline.Segments)-1].End; end != (Vec2{50, -2}) {
+		t.Errorf("Outline lead out wrong: %s", end)
+	}
+	hole := l.Paths[0]
+	if p := hole.Segments[0].Start; p.X <= 10 || p.X >= 20 || p.Y <= 10 || p.Y >= 20 {
+		t.Errorf("Hole pierced outside it: %s", p)
+	}
+	if p := hole.Segments[0].Start; p.Subtract(hole.Segments[0].End).Length() > leadFraction*10+1e-9 {
+		t.Errorf("Hole lead in too long: %s", hole.Segments[0])
+	}
+
+	// The arc meets the cut going the same way
+	a := d.Paths[0].WithLeads(Leads{Style: LeadArc, In: 5, Out: 2}, true)
+	cut := 0
+	for i, s := range a.Segments {
+		if s.Start == (Vec2{50, 0}) {
+			cut = i
+			break
+		}
+	}
+	in := a.Segments[cut-1]
+	dir := in.End.Subtract(in.Start)
+	if cut == 0 || dir.X <= 0 || math.Abs(dir.Y) > 0.5*dir.X || a.Segments[0].Start.Y > -4.9 {
+		t.Errorf("Arc lead in wrong: %s then %s", in, a.Segments[cut])
+	}
+	if d.Paths[0].WithLeads(Leads{}, true).Closed != true {
+		t.Error("No leads should leave the path as it was")
+	}
+}
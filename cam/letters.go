@@ -25,6 +25,18 @@ func (f Font) TypeTo(t *Turtle, txt string, spacing float64) *Turtle {
 	return t
 }
 
+// Width is how long a line of text will be, with spacing between the letters
+func (f Font) Width(txt string, spacing float64) float64 {
+	w := 0.0
+	for _, c := range txt {
+		w += f.GetLetter(string(c)).Width + spacing
+	}
+	if w > 0 {
+		w -= spacing
+	}
+	return w
+}
+
 // GetLetter looks one up
 func (f Font) GetLetter(txt string) Letter {
 	if letter, ok := f[txt]; ok {
@@ -13,7 +13,6 @@ import (
 	"math"
 	"os/exec"
 
-	"github.com/llgcode/draw2d/draw2dpdf"
 	"github.com/llgcode/draw2d/draw2dsvg"
 )
 
@@ -122,7 +121,8 @@ func (k PathKind) String() string {
 // Segment is a straight line portion of a path
 type Segment struct {
 	Kind       PathKind
-	Start, End Vec2 // position vectors of its start and end
+	Start, End Vec2  // position vectors of its start and end
+	Bend       *Bend // how a FoldPath is bent, nil if not known
 }
 
 // String renders a segment in text
@@ -154,6 +154,39 @@ func (p *Path) Close() *Path {
 	return p
 }
 
+// NewPolygonPath makes a closed path through the given points
+func NewPolygonPath(pts []Vec2, kind PathKind) Path {
+	p := Path{}
+	for i := 1; i < len(pts); i++ {
+		p.Add(Segment{Kind: kind, Start: pts[i-1], End: pts[i]})
+	}
+	return *p.Close()
+}
+
+// CircleSides is how many sides a polygon needs to be within CurveTolerance of a circle of the
+//   radius, in mm, at least 8
+func CircleSides(radius float64) int {
+	n := 8
+	if radius > CurveTolerance {
+		n = int(math.Ceil(pi / math.Acos(1-CurveTolerance/radius)))
+	}
+	if n < 8 {
+		n = 8
+	}
+	return n
+}
+
+// NewCirclePath makes a closed polygonal path approximating a circle to within CurveTolerance
+func NewCirclePath(center Vec2, radius float64, kind PathKind) Path {
+	n := CircleSides(radius)
+	pts := []Vec2{}
+	for i := 0; i < n; i++ {
+		a := deg360 * float64(i) / float64(n)
+		pts = append(pts, center.Add(NewVec2(radius*math.Cos(a), radius*math.Sin(a))))
+	}
+	return NewPolygonPath(pts, kind)
+}
+
 // String prints out a path in text
 func (p Path) String() string {
 	s := fmt.Sprintf("Path has %d segments:\n", len(p.Segments))
@@ -387,28 +420,13 @@ func (t *Turtle) Type(txt string) *Turtle {
 	return t
 }
 
-// OutputPDF is
+// OutputPDF is the trail plotted to fit an A4 page, opened in the viewer
 func (t Turtle) OutputPDF() {
-	// Initialize the graphic context on an RGBA image
-	dest := draw2dpdf.NewPdf("L", "mm", "A4")
-	gc := draw2dpdf.NewGraphicContext(dest)
-
-	// Set some properties
-	gc.SetFillColor(color.RGBA{0x44, 0xff, 0x44, 0xff})
-	gc.SetStrokeColor(color.RGBA{0x44, 0x44, 0x44, 0xff})
-	gc.SetLineWidth(0.5)
-
-	// Draw a closed shape
-	gc.MoveTo(0, 0) // should always be called first for a new path
-	for _, s := range t.Trail.Segments {
-		gc.MoveTo(s.Start.X, 200-s.Start.Y)
-		gc.LineTo(s.End.X, 200-s.End.Y)
+	p := NewPlotter(SheetA4).Add(Drawing{Name: "turtle", Paths: []Path{t.Trail}})
+	if err := p.WritePDF("turtle.pdf"); err != nil {
+		fmt.Printf("ERROR: %s\n", err)
+		return
 	}
-	gc.Close()
-	gc.FillStroke()
-
-	// Save to file
-	draw2dpdf.SaveToPdfFile("turtle.pdf", dest)
 	cmd := exec.Command("cmd", "/C start turtle.pdf")
 	cmd.Start()
 }
@@ -453,4 +471,5 @@ type Drawing struct {
 	Name  string
 	ID    int
 	Paths []Path
+	Units Unit // of the coordinates, mm unless converted for output
 }
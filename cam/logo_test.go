@@ -2,6 +2,7 @@ package cam
 
 import (
 	"fmt"
+	"math"
 	"testing"
 )
 
@@ -41,3 +42,15 @@ func TestTurtle(t *testing.T) {
 	mini.OutputSVG()
 
 }
+
+func TestCircleSides(t *testing.T) {
+	if n := CircleSides(CurveTolerance / 2); n != 8 {
+		t.Errorf("Tiny circle has %d sides, want 8", n)
+	}
+	for _, r := range []float64{1, 10, 100, 1000} {
+		n := CircleSides(r)
+		if sag := r * (1 - math.Cos(pi/float64(n))); sag > CurveTolerance*1.0001 {
+			t.Errorf("Circle of radius %g with %d sides is %g off, more than %g", r, n, sag, CurveTolerance)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cam
+
+// defaultMaterials are built in, used unless a materials file is loaded: standard gauges of
+//   mild steel and stainless, and common thicknesses of aluminium in inches, with the sizes
+//   they are usually stocked in. Costs are rough guides only.
+const defaultMaterials = `{"materials": [
+	{"id": "MildSteel", "base": "cold rolled", "specific": "A1008", "name": "Mild steel: cold rolled A1008", "density_kg_m3": 7850, "element": "Fe", "cost_per_kg": 1.2, "min_bend_ratio": 1,
+		"stock": [{"name": "4x8"}, {"name": "4x10"}, {"name": "5x10"}, {"name": "48in coil"}],
+		"gauges": [
+		{"id": "28ga", "thickness_mm": 0.378},
+		{"id": "26ga", "thickness_mm": 0.455},
+		{"id": "24ga", "thickness_mm": 0.607},
+		{"id": "22ga", "thickness_mm": 0.759},
+		{"id": "20ga", "thickness_mm": 0.911},
+		{"id": "18ga", "thickness_mm": 1.214},
+		{"id": "16ga", "thickness_mm": 1.519},
+		{"id": "14ga", "thickness_mm": 1.897},
+		{"id": "12ga", "thickness_mm": 2.657},
+		{"id": "10ga", "thickness_mm": 3.416},
+		{"id": "0000000ga", "thickness_mm": 12.7, "display": "0.5in"},
+		{"id": "00000000ga", "thickness_mm": 25.4, "display": "1in"}]},
+	{"id": "Stainless304", "base": "stainless", "specific": "304", "name": "Stainless steel: 304", "density_kg_m3": 8000, "element": "Fe,Cr", "cost_per_kg": 4.5, "min_bend_ratio": 1,
+		"stock": [{"name": "4x8"}, {"name": "4x10"}, {"name": "5x10"}],
+		"gauges": [
+		{"id": "28ga", "thickness_mm": 0.397},
+		{"id": "26ga", "thickness_mm": 0.476},
+		{"id": "24ga", "thickness_mm": 0.635},
+		{"id": "22ga", "thickness_mm": 0.794},
+		{"id": "20ga", "thickness_mm": 0.953},
+		{"id": "18ga", "thickness_mm": 1.27},
+		{"id": "16ga", "thickness_mm": 1.588},
+		{"id": "14ga", "thickness_mm": 1.984},
+		{"id": "12ga", "thickness_mm": 2.778},
+		{"id": "11ga", "thickness_mm": 3.175}]},
+	{"id": "Aluminium5052", "base": "aluminium", "specific": "5052-H32", "name": "Aluminium: 5052-H32", "density_kg_m3": 2680, "element": "Al", "cost_per_kg": 5.5, "min_bend_ratio": 1.5,
+		"stock": [{"name": "4x8"}, {"name": "4x10"}, {"name": "48in coil"}],
+		"gauges": [
+		{"id": "0.025in", "thickness_mm": 0.635},
+		{"id": "0.032in", "thickness_mm": 0.813},
+		{"id": "0.040in", "thickness_mm": 1.016},
+		{"id": "0.050in", "thickness_mm": 1.27},
+		{"id": "0.063in", "thickness_mm": 1.6},
+		{"id": "0.080in", "thickness_mm": 2.032},
+		{"id": "0.090in", "thickness_mm": 2.286},
+		{"id": "0.125in", "thickness_mm": 3.175}]}
+]}
+`
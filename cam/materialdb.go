@@ -0,0 +1,306 @@
+package cam
+
+// ███╗   ███╗ █████╗ ████████╗███████╗██████╗ ██╗ █████╗ ██╗     ███████╗
+// ████╗ ████║██╔══██╗╚══██╔══╝██╔════╝██╔══██╗██║██╔══██╗██║     ██╔════╝
+// ██╔████╔██║███████║   ██║   █████╗  ██████╔╝██║███████║██║     ███████╗
+// ██║╚██╔╝██║██╔══██║   ██║   ██╔══╝  ██╔══██╗██║██╔══██║██║     ╚════██║
+// ██║ ╚═╝ ██║██║  ██║   ██║   ███████╗██║  ██║██║██║  ██║███████╗███████║
+// ╚═╝     ╚═╝╚═╝  ╚═╝   ╚═╝   ╚══════╝╚═╝  ╚═╝╚═╝╚═╝  ╚═╝╚══════╝╚══════╝
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// materialRecord is a material as written in a materials file, lengths in mm
+type materialRecord struct {
+	ID           string        `json:"id"`
+	Base         string        `json:"base"` // eg stainless, as MaterialBase.String
+	Specific     string        `json:"specific"`
+	Name         string        `json:"name"`
+	Density      float64       `json:"density_kg_m3"`
+	Element      string        `json:"element"`
+	CostPerKg    float64       `json:"cost_per_kg"`
+	MinBendRatio float64       `json:"min_bend_ratio"` // thicknesses, for gauges with no radius of their own
+	Stock        []stockRecord `json:"stock"`          // for gauges with no stock of their own
+	Gauges       []gaugeRecord `json:"gauges"`
+}
+
+// gaugeRecord is one gauge of a material as written in a materials file, lengths in mm
+type gaugeRecord struct {
+	ID            string        `json:"id"`
+	Display       string        `json:"display"`
+	Thickness     float64       `json:"thickness_mm"`
+	MinBendRadius float64       `json:"min_bend_radius_mm"`
+	BendAllowance float64       `json:"bend_allowance_mm"`
+	KFactor       float64       `json:"k_factor"`
+	CostPerM2     float64       `json:"cost_per_m2"`
+	Stock         []stockRecord `json:"stock"`
+}
+
+// stockRecord is a size of stock as written in a materials file: just the name of one of the
+//   StandardStock, or its size in mm with no length for coil, and any price
+type stockRecord struct {
+	Name   string  `json:"name"`
+	Width  float64 `json:"width_mm"`
+	Length float64 `json:"length_mm"`
+	Cost   float64 `json:"cost"`
+}
+
+// stock is the record as a StockSheet
+func (r stockRecord) stock() (StockSheet, error) {
+	s := StockSheet{Name: r.Name, Width: r.Width / 1000, Length: r.Length / 1000, Cost: r.Cost}
+	if r.Width == 0 && r.Length == 0 {
+		std, ok := StandardStock[r.Name]
+		if !ok {
+			return s, fmt.Errorf("Stock %q has no size and is not a standard one", r.Name)
+		}
+		std.Cost = r.Cost
+		return std, nil
+	}
+	if s.Name == "" {
+		s.Name = fmt.Sprintf("%.0fx%.0f", r.Width, r.Length)
+	}
+	return s, nil
+}
+
+// materialsFile is the whole of a JSON materials file
+type materialsFile struct {
+	Materials []materialRecord `json:"materials"`
+}
+
+// material is the record as a Material, with the bend data and areal density of its gauges
+//   filled in where the file leaves them out
+func (r materialRecord) material() (Material, error) {
+	m := Material{ID: MaterialID(r.ID), Specific: r.Specific, DisplayName: r.Name, Density: r.Density,
+		Element: r.Element, CostPerKg: r.CostPerKg, SheetData: GaugeStats{}}
+	if m.DisplayName == "" {
+		m.DisplayName = r.ID
+	}
+	found := false
+	for b, s := range materialBaseNames {
+		if strings.EqualFold(s, strings.TrimSpace(r.Base)) {
+			m.Base, found = b, true
+		}
+	}
+	if !found {
+		return m, fmt.Errorf("Material %q has unknown base %q", r.ID, r.Base)
+	}
+	for _, gr := range r.Gauges {
+		if _, dup := m.SheetData[GaugeID(gr.ID)]; dup {
+			return m, fmt.Errorf("Material %q has gauge %q twice", r.ID, gr.ID)
+		}
+		g := SheetGauge{Display: gr.Display, ID: GaugeID(gr.ID), Thickness: gr.Thickness / 1000,
+			MinBendRadius: gr.MinBendRadius / 1000, BendAllowance: gr.BendAllowance / 1000,
+			KFactor: gr.KFactor, CostPerM2: gr.CostPerM2, ArealDensity: gr.Thickness / 1000 * r.Density}
+		if g.Display == "" {
+			g.Display = gr.ID
+		}
+		srs := gr.Stock
+		if len(srs) == 0 {
+			srs = r.Stock
+		}
+		for _, sr := range srs {
+			s, err := sr.stock()
+			if err != nil {
+				return m, fmt.Errorf("Material %q gauge %q: %s", r.ID, gr.ID, err)
+			}
+			g.Stock = append(g.Stock, s)
+		}
+		m.SheetData[g.ID] = g
+	}
+	ratio := r.MinBendRatio
+	if ratio <= 0 {
+		ratio = 1
+	}
+	withBends(m.SheetData, ratio)
+	return m, m.Validate()
+}
+
+// Validate checks the material makes sense, returning the first thing wrong with it
+func (m Material) Validate() error {
+	switch {
+	case m.ID == "":
+		return fmt.Errorf("Material has no id")
+	case m.Density <= 0:
+		return fmt.Errorf("Material %q has density %g, should be more than 0 kg/m3", m.ID, m.Density)
+	case m.CostPerKg < 0:
+		return fmt.Errorf("Material %q has negative cost", m.ID)
+	case len(m.SheetData) == 0:
+		return fmt.Errorf("Material %q has no gauges", m.ID)
+	}
+	for id, g := range m.SheetData {
+		switch {
+		case id == "" || g.ID != id:
+			return fmt.Errorf("Material %q has a gauge with no id", m.ID)
+		case g.Thickness <= 0 || g.Thickness > 0.1:
+			return fmt.Errorf("Material %q gauge %q is %gmm thick, should be more than 0 and at most 100mm", m.ID, id, g.Thickness*1000)
+		case g.MinBendRadius < 0 || g.BendAllowance < 0:
+			return fmt.Errorf("Material %q gauge %q has negative bend data", m.ID, id)
+		case g.KFactor < 0 || g.KFactor > 0.5:
+			return fmt.Errorf("Material %q gauge %q has K-factor %g, should be from 0 to 0.5", m.ID, id, g.KFactor)
+		case g.CostPerM2 < 0:
+			return fmt.Errorf("Material %q gauge %q has negative cost", m.ID, id)
+		}
+		for _, s := range g.Stock {
+			if s.Width <= 0 || s.Length < 0 || s.Cost < 0 {
+				return fmt.Errorf("Material %q gauge %q has stock %s of the wrong size or cost", m.ID, id, s.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// CostPerM2 is what a square metre of the gauge costs, its own price if it has one, else by
+//   weight, 0 if neither is known
+func (m Material) CostPerM2(g SheetGauge) float64 {
+	if g.CostPerM2 > 0 {
+		return g.CostPerM2
+	}
+	return g.Thickness * m.Density * m.CostPerKg
+}
+
+// materialSet checks the records and makes them into a set
+func materialSet(rs []materialRecord) (MaterialSet, error) {
+	ms := MaterialSet{}
+	for _, r := range rs {
+		m, err := r.material()
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := ms[m.ID]; dup {
+			return nil, fmt.Errorf("Material %q is in the file twice", m.ID)
+		}
+		ms[m.ID] = m
+	}
+	if len(ms) == 0 {
+		return nil, fmt.Errorf("No materials in the file")
+	}
+	return ms, nil
+}
+
+// LoadMaterials reads a JSON materials file: {"materials": [...]}, each material with its
+//   gauges, lengths in mm. See defaultMaterials for an example.
+func LoadMaterials(r io.Reader) (MaterialSet, error) {
+	var f materialsFile
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("Cannot read materials: %s", err)
+	}
+	return materialSet(f.Materials)
+}
+
+// materialColumns are the columns of a CSV materials file, one row per gauge, which may be in
+//   any order. The material's own columns need only be filled in on its first row. Stock is
+//   the names of StandardStock sizes, separated by semicolons.
+var materialColumns = []string{"id", "base", "specific", "name", "density_kg_m3", "element", "cost_per_kg",
+	"min_bend_ratio", "gauge", "display", "thickness_mm", "min_bend_radius_mm", "bend_allowance_mm",
+	"k_factor", "cost_per_m2", "stock"}
+
+// LoadMaterialsCSV reads a CSV materials file with a header row naming the materialColumns
+func LoadMaterialsCSV(r io.Reader) (MaterialSet, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("Cannot read materials: %s", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("No materials in the file")
+	}
+	col := map[string]int{}
+	for i, h := range rows[0] {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, c := range []string{"id", "gauge", "thickness_mm"} {
+		if _, ok := col[c]; !ok {
+			return nil, fmt.Errorf("Materials file has no %s column", c)
+		}
+	}
+	for h := range col {
+		known := false
+		for _, c := range materialColumns {
+			known = known || h == c
+		}
+		if !known {
+			return nil, fmt.Errorf("Materials file has unknown column %q", h)
+		}
+	}
+
+	var rs []materialRecord
+	at := map[string]int{}
+	for n, row := range rows[1:] {
+		get := func(c string) string {
+			if i, ok := col[c]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		var bad error
+		num := func(c string) float64 {
+			s := get(c)
+			if s == "" {
+				return 0
+			}
+			x, err := strconv.ParseFloat(s, 64)
+			if err != nil && bad == nil {
+				bad = fmt.Errorf("Line %d of materials file: %s is %q, not a number", n+2, c, s)
+			}
+			return x
+		}
+		id := get("id")
+		i, seen := at[id]
+		if !seen {
+			rs = append(rs, materialRecord{ID: id, Base: get("base"), Specific: get("specific"), Name: get("name"),
+				Density: num("density_kg_m3"), Element: get("element"), CostPerKg: num("cost_per_kg"),
+				MinBendRatio: num("min_bend_ratio")})
+			i = len(rs) - 1
+			at[id] = i
+		}
+		rs[i].Gauges = append(rs[i].Gauges, gaugeRecord{ID: get("gauge"), Display: get("display"),
+			Thickness: num("thickness_mm"), MinBendRadius: num("min_bend_radius_mm"),
+			BendAllowance: num("bend_allowance_mm"), KFactor: num("k_factor"), CostPerM2: num("cost_per_m2")})
+		if s := get("stock"); s != "" {
+			g := &rs[i].Gauges[len(rs[i].Gauges)-1]
+			for _, name := range strings.Split(s, ";") {
+				g.Stock = append(g.Stock, stockRecord{Name: strings.TrimSpace(name)})
+			}
+		}
+		if bad != nil {
+			return nil, bad
+		}
+	}
+	return materialSet(rs)
+}
+
+// LoadMaterialsFile reads a materials file, CSV if its name ends .csv, else JSON
+func LoadMaterialsFile(fname string) (MaterialSet, error) {
+	f, err := os.Open(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var ms MaterialSet
+	if strings.EqualFold(filepath.Ext(fname), ".csv") {
+		ms, err = LoadMaterialsCSV(f)
+	} else {
+		ms, err = LoadMaterials(f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", fname, err)
+	}
+	return ms, nil
+}
+
+func init() {
+	ms, err := LoadMaterials(strings.NewReader(defaultMaterials))
+	if err != nil {
+		panic(fmt.Sprintf("Built in materials are bad: %s", err))
+	}
+	Materials = ms
+}
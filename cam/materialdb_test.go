@@ -0,0 +1,85 @@
+package cam
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestDefaultMaterials(t *testing.T) {
+	for _, id := range []MaterialID{"MildSteel", "Stainless304", "Aluminium5052"} {
+		m, ok := Materials[id]
+		if !ok {
+			t.Errorf("No %s in the built in materials", id)
+			continue
+		}
+		if err := m.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+	g := Materials["Stainless304"].SheetData["20ga"]
+	if math.Abs(g.Thickness-0.000953) > 1e-9 || g.MinBendRadius != g.Thickness || g.BendAllowance <= 0 {
+		t.Errorf("Stainless 20ga is wrong: %+v", g)
+	}
+	if math.Abs(g.ArealDensity-0.000953*8000) > 1e-9 {
+		t.Errorf("Stainless 20ga areal density is %.3f", g.ArealDensity)
+	}
+	if al := Materials["Aluminium5052"]; al.Base != MatAl || al.SheetData["0.040in"].MinBendRadius != 1.5*al.SheetData["0.040in"].Thickness {
+		t.Errorf("Aluminium is wrong: %+v", al)
+	}
+}
+
+func TestLoadMaterials(t *testing.T) {
+	ms, err := LoadMaterials(strings.NewReader(`{"materials": [{"id": "X", "base": "Copper", "density_kg_m3": 8900,
+		"cost_per_kg": 10, "gauges": [{"id": "1mm", "thickness_mm": 1, "min_bend_radius_mm": 2, "cost_per_m2": 50}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := ms["X"]
+	g := m.SheetData["1mm"]
+	if m.Base != MatCu || g.Display != "1mm" || g.MinBendRadius != 0.002 || g.BendAllowance != g.Allowance(90, 0.002) {
+		t.Errorf("Loaded material is wrong: %+v", m)
+	}
+	if c := m.CostPerM2(g); c != 50 {
+		t.Errorf("Gauge's own cost not used, %.2f", c)
+	}
+	g.CostPerM2 = 0
+	if c := m.CostPerM2(g); math.Abs(c-0.001*8900*10) > 1e-9 {
+		t.Errorf("Cost by weight is %.2f", c)
+	}
+
+	for _, bad := range []string{
+		`{"materials": []}`,
+		`{"materials": [{"id": "X", "base": "cheese", "density_kg_m3": 1, "gauges": [{"id": "a", "thickness_mm": 1}]}]}`,
+		`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 0, "gauges": [{"id": "a", "thickness_mm": 1}]}]}`,
+		`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 1, "gauges": []}]}`,
+		`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 1, "gauges": [{"id": "a", "thickness_mm": -1}]}]}`,
+		`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 1, "gauges": [{"id": "a", "thickness_mm": 1, "k_factor": 0.7}]}]}`,
+		`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 1, "gauges": [{"id": "a", "thickness_mm": 1}, {"id": "a", "thickness_mm": 2}]}]}`,
+		`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 1, "colour": "red", "gauges": [{"id": "a", "thickness_mm": 1}]}]}`,
+	} {
+		if _, err := LoadMaterials(strings.NewReader(bad)); err == nil {
+			t.Errorf("Bad materials loaded: %s", bad)
+		}
+	}
+}
+
+func TestLoadMaterialsCSV(t *testing.T) {
+	ms, err := LoadMaterialsCSV(strings.NewReader(`id,base,density_kg_m3,gauge,thickness_mm,k_factor
+Y,aluminium,2700,a,1,
+Y,,,b,2,0.4
+Z,brass,8500,c,0.5,
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ms) != 2 || len(ms["Y"].SheetData) != 2 || ms["Y"].SheetData["b"].KFactor != 0.4 || ms["Z"].Density != 8500 {
+		t.Errorf("CSV materials loaded wrong: %+v", ms)
+	}
+	if _, err := LoadMaterialsCSV(strings.NewReader("id,base,density_kg_m3,gauge,thickness_mm\nY,aluminium,2700,a,thick\n")); err == nil {
+		t.Error("Bad number in CSV loaded")
+	}
+	if _, err := LoadMaterialsCSV(strings.NewReader("id,gauge\nY,a\n")); err == nil {
+		t.Error("CSV with no thickness column loaded")
+	}
+}
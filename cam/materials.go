@@ -18,6 +18,26 @@ const (
 	MatExotic                         // Maraging steel etc., hardface, carbon fibre, glass, plastic
 )
 
+// materialBaseNames are how each MaterialBase is written in materials files
+var materialBaseNames = map[MaterialBase]string{
+	MatColdRolled: "cold rolled",
+	MatHotRolled:  "hot rolled",
+	MatStainless:  "stainless",
+	MatAl:         "aluminium",
+	MatTi:         "titanium",
+	MatCu:         "copper",
+	MatBrass:      "brass",
+	MatExotic:     "exotic",
+}
+
+// String names the base
+func (b MaterialBase) String() string {
+	if s, ok := materialBaseNames[b]; ok {
+		return s
+	}
+	return "unknown"
+}
+
 // GaugeID is the unique (for this material) gauge name
 type GaugeID string
 
@@ -26,12 +46,15 @@ type GaugeStats map[GaugeID]SheetGauge
 
 // SheetGauge in the info for a particular gauge of a particular material
 type SheetGauge struct {
-	Display       string  // Name to display
-	ID            GaugeID //
-	Thickness     float64 // m
-	ArealDensity  float64 // kg/m2
-	BendAllowance float64 // what length of unbent material does a 90deg bend require
-	MinBendRadius float64 // what is the bend radius imparted by 90deg bend
+	Display       string       // Name to display
+	ID            GaugeID      //
+	Thickness     float64      // m
+	ArealDensity  float64      // kg/m2
+	BendAllowance float64      // m, of unbent material a 90deg bend at MinBendRadius takes up
+	MinBendRadius float64      // m, the tightest inside radius the brake bends it to without cracking
+	KFactor       float64      // where the neutral axis lies, 0 to work it out from the radius
+	CostPerM2     float64      // of sheet bought in this gauge, 0 to work it out from the cost per kg
+	Stock         []StockSheet // sizes it can be bought in, DefaultStock if none
 }
 
 // MaterialID is a unique identifier of a material
@@ -40,12 +63,15 @@ type MaterialID string
 // Material is substance a panel may be made of -- this is as it arrives
 type Material struct {
 	ID          MaterialID
-	Base        MaterialBase // Basic substance
-	Specific    string       // Specific variety e.g. alloy or steel or SS or Al etc.
-	DisplayName string       // Human friendly name
-	Density     float64      // Kg/m3, estimated
-	Element     string       // dominant constituent elements -- chemical symbols
-	SheetData   GaugeStats   // used for display & estimation
+	Base        MaterialBase           // Basic substance
+	Specific    string                 // Specific variety e.g. alloy or steel or SS or Al etc.
+	DisplayName string                 // Human friendly name
+	Density     float64                // Kg/m3, estimated
+	Element     string                 // dominant constituent elements -- chemical symbols
+	SheetData   GaugeStats             // used for display & estimation
+	Kerfs       map[CutProcess]float64 // m, width of cut by each process, DefaultKerfs if missing
+	Leads       map[CutProcess]Leads   // how cuts are started and finished by each process, DefaultLeads if missing
+	CostPerKg   float64                // of sheet, 0 if not known
 }
 
 // MaterialSet is just a map of them
@@ -59,6 +85,7 @@ type InputSheetType struct {
 	ID       InputSheetTypeID // unique id
 	Material MaterialID       // substance its made of
 	Gauge    GaugeID          // what gauge
+	Stock    string           // name of the size of sheet bought, the gauge's first if not one it comes in
 }
 
 // FinishType is the basic variety of finish, more detail given in Specific
@@ -79,31 +106,3 @@ type SurfaceFinish struct {
 	Basic    FinishType // basic type of finish
 	Specific string     // the colour, grade etc. wanted
 }
-
-func init() {
-
-	Materials = make(MaterialSet)
-
-	mildgauges := GaugeStats{
-		"28ga":       SheetGauge{Display: "28ga", ID: "28ga", Thickness: 0.378 / 1000},
-		"24ga":       SheetGauge{Display: "24ga", ID: "24ga", Thickness: 0.607 / 1000},
-		"22ga":       SheetGauge{Display: "22ga", ID: "22ga", Thickness: 0.759 / 1000},
-		"20ga":       SheetGauge{Display: "20ga", ID: "20ga", Thickness: 0.911 / 1000},
-		"18ga":       SheetGauge{Display: "18ga", ID: "18ga", Thickness: 1.214 / 1000},
-		"16ga":       SheetGauge{Display: "16ga", ID: "16ga", Thickness: 1.518 / 1000},
-		"14ga":       SheetGauge{Display: "14ga", ID: "14ga", Thickness: 1.897 / 1000},
-		"0000000ga":  SheetGauge{Display: "0.5in", ID: "0000000ga", Thickness: 12.7 / 1000},
-		"00000000ga": SheetGauge{Display: "1in", ID: "00000000ga", Thickness: 25.5 / 1000},
-	}
-
-	Materials["Stainless304"] = Material{ID: "Stainless304", Base: MatStainless, Specific: "304",
-		DisplayName: "Stainless steel: 304", Density: 8030, Element: "Fe,Cr",
-		SheetData: mildgauges} // TODO WRONG! update properly
-
-	// densities := []density{
-	// 	{display: "Steel", element: "Fe", rho: 7874},
-	// 	{display: "Aluminium", element: "Al", rho: 2700},
-	// 	{display: "Titanium", element: "Ti", rho: 4506},
-	// }
-
-}
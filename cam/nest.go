@@ -0,0 +1,264 @@
+package cam
+
+// ███╗   ██╗███████╗███████╗████████╗
+// ████╗  ██║██╔════╝██╔════╝╚══██╔══╝
+// ██╔██╗ ██║█████╗  ███████╗   ██║
+// ██║╚██╗██║██╔══╝  ╚════██║   ██║
+// ██║ ╚████║███████╗███████║   ██║
+// ╚═╝  ╚═══╝╚══════╝╚══════╝   ╚═╝
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Nesting says how parts are to be nested on sheets. Lengths are mm.
+type Nesting struct {
+	Width, Height float64 // of each sheet
+	Gap           float64 // left between parts and at the edges of the sheet
+	Margin        float64 // left clear at the edges of the sheet for marks, if more than Gap
+	CommonLine    bool    // parts are put right up against each other, so edges in line are cut once for both
+}
+
+// Placed is a part where it has been put on a sheet
+type Placed struct {
+	Drawing         // the part turned and moved into place
+	Part    int     // which of the parts nested it is
+	Angle   float64 // it was turned through anticlockwise about its origin, radians
+	At      Vec2    // it was then moved by
+}
+
+// Nest is the parts placed on one sheet
+type Nest struct {
+	Width, Height float64
+	Placed        []Placed
+}
+
+// Transformed is the drawing turned anticlockwise by a about the origin, then moved by at
+func (d Drawing) Transformed(a float64, at Vec2) Drawing {
+	g := Drawing{Name: d.Name, ID: d.ID, Units: d.Units}
+	for _, p := range d.Paths {
+		q := Path{Closed: p.Closed}
+		for _, s := range p.Segments {
+			q.Add(Segment{Kind: s.Kind, Start: s.Start.Rotate(a).Add(at), End: s.End.Rotate(a).Add(at), Bend: s.Bend})
+		}
+		g.Paths = append(g.Paths, q)
+	}
+	return g
+}
+
+// edges are all the segments of the drawing which are cut
+func (d Drawing) edges() []Segment {
+	var es []Segment
+	for _, p := range d.Paths {
+		for _, s := range p.Segments {
+			if s.Kind == EdgePath {
+				es = append(es, s)
+			}
+		}
+	}
+	return es
+}
+
+// squaredUp is the angle to turn the part so its longest outside edge runs along the bottom
+func (d Drawing) squaredUp() float64 {
+	outer := d.outline()
+	if outer < 0 {
+		return 0
+	}
+	p := d.Paths[outer]
+	var long Segment
+	for _, s := range p.Segments {
+		if s.Length() > long.Length() {
+			long = s
+		}
+	}
+	dir := long.End.Subtract(long.Start)
+	if p.Area() < 0 { // clockwise, so it runs the other way along the bottom
+		dir = dir.Scale(-1)
+	}
+	return -math.Atan2(dir.Y, dir.X)
+}
+
+// rayDistance is how far from v along x, in the direction dir (+1 or -1), the first of the
+//   segments is, infinite if none is that way
+func rayDistance(segs []Segment, v Vec2, dir float64) float64 {
+	d := math.Inf(1)
+	for _, s := range segs {
+		y0, y1 := math.Min(s.Start.Y, s.End.Y), math.Max(s.Start.Y, s.End.Y)
+		if v.Y < y0 || v.Y > y1 || y1-y0 < IntersectTolerance {
+			continue
+		}
+		x := s.Start.X + (s.End.X-s.Start.X)*(v.Y-s.Start.Y)/(s.End.Y-s.Start.Y)
+		if dist := (x - v.X) * dir; dist > -IntersectTolerance {
+			d = math.Min(d, math.Max(0, dist))
+		}
+	}
+	return d
+}
+
+// slideLeft is how far the moving segments can slide left before they touch the fixed ones
+func slideLeft(fixed, moving []Segment) float64 {
+	d := math.Inf(1)
+	for _, m := range moving {
+		d = math.Min(d, math.Min(rayDistance(fixed, m.Start, -1), rayDistance(fixed, m.End, -1)))
+	}
+	for _, f := range fixed {
+		d = math.Min(d, math.Min(rayDistance(moving, f.Start, 1), rayDistance(moving, f.End, 1)))
+	}
+	return d
+}
+
+// NestParts lays the parts out on as many sheets as it takes: each turned with its longest
+//   edge along the bottom, the tallest first, on shelves across the sheet, and slid left up
+//   against the part before, either way up, whichever packs tighter. Returns the sheets and
+//   the indexes of any parts too big for a sheet.
+func NestParts(parts []Drawing, ns Nesting) (nests []Nest, unplaced []int) {
+	gap, edge := ns.Gap, math.Max(ns.Gap, ns.Margin)
+	if ns.CommonLine {
+		gap = 0
+	}
+	type ready struct {
+		i     int
+		angle float64
+		d     Drawing // turned, with its box's bottom left at the origin
+		w, h  float64
+	}
+	var rs []ready
+	for i, d := range parts {
+		a := d.squaredUp()
+		t := d.Transformed(a, Origin)
+		lo, hi := t.Bounds()
+		r := ready{i: i, angle: a, d: t.Transformed(0, lo.Scale(-1)), w: hi.X - lo.X, h: hi.Y - lo.Y}
+		if len(t.edges()) == 0 || r.w > ns.Width-2*edge || r.h > ns.Height-2*edge {
+			unplaced = append(unplaced, i)
+			continue
+		}
+		rs = append(rs, r)
+	}
+	sort.SliceStable(rs, func(a, b int) bool { return rs[a].h > rs[b].h })
+
+	var n *Nest
+	var shelf []Segment // edges of the parts on the current shelf
+	var shelfY, shelfH, shelfRight float64
+	for _, r := range rs {
+		best := Placed{}
+		bestRight := math.Inf(1)
+		for _, flip := range []float64{0, deg180} {
+			d := r.d
+			if flip != 0 {
+				d = d.Transformed(deg180, NewVec2(r.w, r.h))
+			}
+			x := math.Max(edge, shelfRight+gap)
+			if len(shelf) > 0 {
+				moved := d.Transformed(0, NewVec2(x, shelfY))
+				x -= math.Max(0, math.Min(slideLeft(shelf, moved.edges())-gap, x-edge))
+			}
+			if x+r.w < bestRight {
+				at := NewVec2(x, shelfY)
+				best = Placed{Drawing: d.Transformed(0, at), Part: r.i, Angle: r.angle + flip, At: at}
+				bestRight = x + r.w
+			}
+		}
+		if n == nil || bestRight > ns.Width-edge { // start a new shelf
+			shelfY, shelfRight, shelf = shelfY+shelfH+gap, 0, nil
+			shelfH = 0
+			if n == nil || shelfY+r.h > ns.Height-edge { // and a new sheet
+				nests = append(nests, Nest{Width: ns.Width, Height: ns.Height})
+				n = &nests[len(nests)-1]
+				shelfY = edge
+			}
+			at := NewVec2(edge, shelfY)
+			best = Placed{Drawing: r.d.Transformed(0, at), Part: r.i, Angle: r.angle, At: at}
+			bestRight = edge + r.w
+		}
+		n.Placed = append(n.Placed, best)
+		shelf = append(shelf, best.edges()...)
+		shelfRight = math.Max(shelfRight, bestRight)
+		shelfH = math.Max(shelfH, r.h)
+	}
+	return nests, unplaced
+}
+
+// Drawing is all the parts on the sheet as one drawing, in the order they were placed
+func (n Nest) Drawing(name string) Drawing {
+	d := Drawing{Name: name}
+	for _, p := range n.Placed {
+		d.Paths = append(d.Paths, p.Paths...)
+	}
+	return d
+}
+
+// Used is the fraction of the sheet's area inside the parts' outlines
+func (n Nest) Used() float64 {
+	a := 0.0
+	for _, p := range n.Placed {
+		if o := p.outline(); o >= 0 {
+			a += math.Abs(p.Paths[o].Area())
+		}
+	}
+	return a / (n.Width * n.Height)
+}
+
+func (n Nest) String() string {
+	return fmt.Sprintf("%d parts on %.0f x %.0f, %.0f%% used", len(n.Placed), n.Width, n.Height, n.Used()*100)
+}
+
+// ShareCommonLines finds where the edges of parts lie along edges of parts placed before them
+//   and leaves that much of the later part uncut, as a MetaPath, so the line is only cut once.
+//   Returns the length saved, mm.
+func (n *Nest) ShareCommonLines() float64 {
+	saved := 0.0
+	var before []Segment
+	for i := range n.Placed {
+		pl := &n.Placed[i]
+		for k, p := range pl.Paths {
+			q := Path{Closed: p.Closed}
+			for _, s := range p.Segments {
+				pieces := []Segment{s}
+				for _, b := range before {
+					var next []Segment
+					for _, c := range pieces {
+						if c.Kind != EdgePath {
+							next = append(next, c)
+							continue
+						}
+						o, shared := c.Overlap(b)
+						if !shared {
+							next = append(next, c)
+							continue
+						}
+						saved += o.Length()
+						next = append(next, splitAround(c, o)...)
+					}
+					pieces = next
+				}
+				for _, c := range pieces {
+					q.Add(c)
+				}
+			}
+			pl.Paths[k] = q
+		}
+		before = append(before, pl.edges()...)
+	}
+	return saved
+}
+
+// splitAround splits s, which o lies along, into the part before o, o itself uncut, and the
+//   part after it
+func splitAround(s, o Segment) []Segment {
+	a, b := s.closest(o.Start), s.closest(o.End)
+	if a > b {
+		a, b = b, a
+	}
+	var pieces []Segment
+	if a*s.Length() > IntersectTolerance {
+		pieces = append(pieces, Segment{Kind: s.Kind, Start: s.Start, End: s.At(a)})
+	}
+	pieces = append(pieces, Segment{Kind: MetaPath, Start: s.At(a), End: s.At(b)})
+	if (1-b)*s.Length() > IntersectTolerance {
+		pieces = append(pieces, Segment{Kind: s.Kind, Start: s.At(b), End: s.End})
+	}
+	return pieces
+}
@@ -0,0 +1,75 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func rect(w, h float64) Drawing {
+	return Drawing{Name: "rect", Paths: []Path{NewPolygonPath([]Vec2{{0, 0}, {w, 0}, {w, h}, {0, h}}, EdgePath)}}
+}
+
+func TestNestParts(t *testing.T) {
+
+	parts := []Drawing{rect(100, 50), rect(50, 100), rect(100, 50), rect(100, 50), rect(100, 50)}
+	nests, unplaced := NestParts(append(parts, rect(300, 10)), Nesting{Width: 250, Height: 120, CommonLine: true})
+	if len(unplaced) != 1 || unplaced[0] != 5 {
+		t.Errorf("Expected the long part to be left out, got %v", unplaced)
+	}
+	if len(nests) != 2 || len(nests[0].Placed) != 4 || len(nests[1].Placed) != 1 {
+		t.Fatalf("Expected 4 parts on one sheet and 1 on another, got %v", nests)
+	}
+	for _, n := range nests {
+		for _, p := range n.Placed {
+			lo, hi := p.Bounds()
+			if lo.X < -1e-9 || lo.Y < -1e-9 || hi.X > 250+1e-9 || hi.Y > 120+1e-9 {
+				t.Errorf("Part %d off the sheet: %s %s", p.Part, lo, hi)
+			}
+		}
+	}
+	if u := nests[0].Used(); math.Abs(u-4*5000/30000.0) > 1e-9 {
+		t.Errorf("Used wrong: %f", u)
+	}
+
+	// Butted up, each pair on a shelf shares a side, and the shelves share their tops and bottoms
+	if saved := nests[0].ShareCommonLines(); math.Abs(saved-(2*50+200)) > 1e-6 {
+		t.Errorf("Expected 300mm of common line, saved %f", saved)
+	}
+	meta := 0.0
+	for _, p := range nests[0].Placed {
+		for _, pa := range p.Paths {
+			for _, s := range pa.Segments {
+				if s.Kind == MetaPath {
+					meta += s.Length()
+				}
+			}
+		}
+	}
+	if math.Abs(meta-300) > 1e-6 {
+		t.Errorf("Common lines should be left uncut once, %f are", meta)
+	}
+
+	// With a gap nothing touches
+	spaced, _ := NestParts(parts, Nesting{Width: 250, Height: 120, Gap: 5})
+	if saved := spaced[0].ShareCommonLines(); saved != 0 {
+		t.Errorf("Spaced parts share %f", saved)
+	}
+}
+
+func TestNestTriangles(t *testing.T) {
+
+	tri := Drawing{Name: "tri", Paths: []Path{NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {0, 50}}, EdgePath)}}
+	nests, _ := NestParts([]Drawing{tri, tri}, Nesting{Width: 1000, Height: 1000, CommonLine: true})
+	if len(nests) != 1 || len(nests[0].Placed) != 2 {
+		t.Fatalf("Expected both triangles on one sheet")
+	}
+	_, hi := nests[0].Drawing("sheet").Bounds()
+	// Turned over, the second goes in beside the first to make a parallelogram, in by as far as
+	//   the short leg reaches along the long side
+	if hyp := math.Hypot(100, 50); math.Abs(hi.X-(2*hyp-50*50/hyp)) > 1e-6 {
+		t.Errorf("Second triangle not turned over and slid in: %f wide", hi.X)
+	}
+	if saved := nests[0].ShareCommonLines(); saved < 50-1e-6 {
+		t.Errorf("Triangles turned over should share a side, saved %f", saved)
+	}
+}
@@ -0,0 +1,123 @@
+package cam
+
+//  ██████╗ ███████╗███████╗███████╗███████╗████████╗
+// ██╔═══██╗██╔════╝██╔════╝██╔════╝██╔════╝╚══██╔══╝
+// ██║   ██║█████╗  █████╗  ███████╗█████╗     ██║
+// ██║   ██║██╔══╝  ██╔══╝  ╚════██║██╔══╝     ██║
+// ╚██████╔╝██║     ██║     ███████║███████╗   ██║
+//  ╚═════╝ ╚═╝     ╚═╝     ╚══════╝╚══════╝   ╚═╝
+
+import "math"
+
+// Join is how an offset path goes round the outside of a corner
+type Join int
+
+// Values of Join
+const (
+	JoinMiter Join = iota // straight on to a point, unless that would reach too far
+	JoinRound             // round the corner in an arc
+)
+
+// MiterLimit is how far a mitered corner may reach out, in offsets, before it is rounded instead
+var MiterLimit = 4.0
+
+// offsetLine is a segment moved sideways by the offset
+type offsetLine struct {
+	at, along, out Vec2 // start, unit vector along it, unit vector to the side it moved
+	length         float64
+	kind           PathKind
+	corner         Vec2 // where the segment started before it was moved
+}
+
+// Offset is the path moved sideways by d with mitered corners, see OffsetJoin
+func (p Path) Offset(d float64) Path {
+	return p.OffsetJoin(d, JoinMiter)
+}
+
+// OffsetJoin is the parallel path d away: outward for a closed path, whichever way it runs, and
+//   to the right for an open one, with negative d going the other way. Inside corners are
+//   trimmed to where the moved segments cross, outside ones joined as j says. Segments
+//   shorter than the offset round an inside corner will turn inside out.
+func (p Path) OffsetJoin(d float64, j Join) Path {
+	if p.Closed && p.Area() < 0 { // clockwise, so the outward side is the left
+		d = -d
+	}
+	var ls []offsetLine
+	for _, s := range p.Segments {
+		along := s.End.Subtract(s.Start)
+		l := along.Length()
+		if l < IntersectTolerance {
+			continue
+		}
+		along = along.Scale(1 / l)
+		out := NewVec2(along.Y, -along.X)
+		ls = append(ls, offsetLine{at: s.Start.Add(out.Scale(d)), along: along, out: out, length: l, kind: s.Kind, corner: s.Start})
+	}
+	n := len(ls)
+	if n == 0 || d == 0 || (p.Closed && n < 2) {
+		return p
+	}
+
+	var pts []Vec2
+	var kinds []PathKind // of the segment leaving each point
+	if !p.Closed {
+		pts, kinds = append(pts, ls[0].at), append(kinds, ls[0].kind)
+	}
+	for i := range ls {
+		if i == 0 && !p.Closed {
+			continue
+		}
+		b := ls[i]
+		for _, pt := range offsetCorner(ls[(i+n-1)%n], b, d, j) {
+			pts, kinds = append(pts, pt), append(kinds, b.kind)
+		}
+	}
+	if !p.Closed {
+		last := ls[n-1]
+		pts = append(pts, last.at.Add(last.along.Scale(last.length)))
+	}
+
+	g := Path{}
+	for i := 0; i+1 < len(pts); i++ {
+		g.Add(Segment{Kind: kinds[i], Start: pts[i], End: pts[i+1]})
+	}
+	if p.Closed {
+		g.Add(Segment{Kind: kinds[len(pts)-1], Start: pts[len(pts)-1], End: pts[0]})
+		g.Closed = true
+	}
+	return g
+}
+
+// offsetCorner is the points the offset path goes through from the moved segment a to the moved
+//   segment b, round the corner where b started
+func offsetCorner(a, b offsetLine, d float64, j Join) []Vec2 {
+	cross := a.along.Cross(b.along)
+	c := b.corner
+	if math.Abs(cross) < 1e-12 && a.along.Dot(b.along) > 0 { // straight on
+		return []Vec2{b.at}
+	}
+	if math.Abs(cross) >= 1e-12 {
+		t := b.at.Subtract(a.at).Cross(b.along) / cross
+		m := a.at.Add(a.along.Scale(t))
+		inside := cross*d < 0
+		if inside || (j == JoinMiter && m.Subtract(c).Length() <= MiterLimit*math.Abs(d)) {
+			return []Vec2{m}
+		}
+	}
+	// Round the outside of the corner, from the end of a to the start of b
+	r := math.Abs(d)
+	from, to := a.out.Scale(d), b.out.Scale(d)
+	turn := math.Atan2(from.Cross(to), from.Dot(to))
+	if math.Abs(cross) < 1e-12 { // doubling back, go round the outside
+		turn = math.Copysign(pi, d)
+	}
+	steps := 1
+	if r > CurveTolerance {
+		steps = int(math.Ceil(math.Abs(turn) / (2 * math.Acos(1-CurveTolerance/r))))
+	}
+	pts := []Vec2{c.Add(from)}
+	for k := 1; k < steps; k++ {
+		pts = append(pts, c.Add(from.Rotate(turn*float64(k)/float64(steps))))
+	}
+	return append(pts, c.Add(to))
+}
@@ -0,0 +1,48 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOffset(t *testing.T) {
+
+	sq := NewPolygonPath([]Vec2{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, EdgePath)
+	if a := sq.Offset(1).Area(); math.Abs(a-144) > 1e-9 {
+		t.Errorf("Mitered square should be 12x12, area is %f", a)
+	}
+	if a := sq.Offset(-1).Area(); math.Abs(a-64) > 1e-9 {
+		t.Errorf("Inset square should be 8x8, area is %f", a)
+	}
+	round := sq.OffsetJoin(1, JoinRound)
+	if a := round.Area(); math.Abs(a-(100+40+math.Pi)) > 0.2 { // chords cut the arcs a little short
+		t.Errorf("Rounded square area is %f", a)
+	}
+	if len(round.Segments) <= 4 || !round.Closed || round.SelfIntersects() {
+		t.Errorf("Rounded square has no arcs, or is not a proper loop")
+	}
+
+	cw := NewPolygonPath([]Vec2{{0, 0}, {0, 10}, {10, 10}, {10, 0}}, EdgePath)
+	if a := cw.Offset(1).Area(); math.Abs(math.Abs(a)-144) > 1e-9 {
+		t.Errorf("Clockwise square should grow outward too, area is %f", a)
+	}
+
+	// A sharp spike gets rounded rather than mitered a long way out
+	spike := NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {0, 5}}, EdgePath)
+	lo, hi := Drawing{Paths: []Path{spike.Offset(1)}}.Bounds()
+	if hi.X > 100+MiterLimit || lo.X > -1 {
+		t.Errorf("Spike reaches too far: %s %s", lo, hi)
+	}
+
+	// Open paths go to the right
+	p := Path{}
+	p.Add(Segment{Kind: FoldPath, Start: Vec2{0, 0}, End: Vec2{10, 0}})
+	p.Add(Segment{Kind: FoldPath, Start: Vec2{10, 0}, End: Vec2{10, 10}})
+	o := p.Offset(1)
+	if len(o.Segments) != 2 || o.Closed || o.Segments[0].Start != (Vec2{0, -1}) || o.Segments[1].End != (Vec2{11, 10}) {
+		t.Errorf("Open path offset wrong: %s", o)
+	}
+	if o.Segments[0].End != (Vec2{11, -1}) || o.Segments[0].Kind != FoldPath {
+		t.Errorf("Open path corner wrong: %s", o.Segments[0])
+	}
+}
@@ -0,0 +1,230 @@
+package cam
+
+// ██████╗ ██╗      ██████╗ ████████╗
+// ██╔══██╗██║     ██╔═══██╗╚══██╔══╝
+// ██████╔╝██║     ██║   ██║   ██║
+// ██╔═══╝ ██║     ██║   ██║   ██║
+// ██║     ███████╗╚██████╔╝   ██║
+// ╚═╝     ╚══════╝ ╚═════╝    ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// SheetSize is a size of paper, mm, portrait
+type SheetSize struct {
+	Name string
+	W, H float64
+}
+
+// Sheet sizes
+var (
+	SheetA4    = SheetSize{"A4", 210, 297}
+	SheetA3    = SheetSize{"A3", 297, 420}
+	SheetA1    = SheetSize{"A1", 594, 841}
+	SheetArchD = SheetSize{"Arch D", 609.6, 914.4}
+)
+
+// plotColours are the RGB colours of each kind of path on paper
+var plotColours = map[PathKind][3]int{
+	EdgePath: {0, 0, 0},
+	FoldPath: {0, 0, 255},
+	MarkPath: {0, 160, 0},
+	MetaPath: {128, 128, 128},
+}
+
+const (
+	plotTitleBand = 8.0 // mm kept clear at the bottom of each page for the title
+	plotCropMark  = 5.0 // mm, length of the crop marks at the corners of a tile
+	plotCropGap   = 1.0 // mm between a crop mark and the corner it marks
+	plotBendText  = 2.5 // mm, height of bend labels on paper
+)
+
+// Plotter lays drawings out on pages of paper, either each scaled to fit a page, or at a fixed
+//   scale tiled across as many pages as it takes with crop marks to trim and join them by.
+type Plotter struct {
+	Sheet     SheetSize
+	Landscape bool
+	Margin    float64 // mm round the edge of each page
+	Scale     float64 // paper mm per drawing mm when tiling, 0 to fit each drawing to a page
+	Drawings  []Drawing
+}
+
+// Page is one page of a plot, its segments in mm from the bottom left of the page
+type Page struct {
+	Title    string
+	Segments []Segment
+}
+
+// NewPlotter makes one for the sheet, landscape, fitting drawings to pages
+func NewPlotter(sheet SheetSize) *Plotter {
+	return &Plotter{Sheet: sheet, Landscape: true, Margin: 10}
+}
+
+// Add adds a drawing to be plotted, in mm whatever its units
+func (p *Plotter) Add(ds ...Drawing) *Plotter {
+	for _, d := range ds {
+		p.Drawings = append(p.Drawings, d.InUnits(Millimetre))
+	}
+	return p
+}
+
+// PageSize is the width and height of each page as it is laid out
+func (p Plotter) PageSize() (w, h float64) {
+	if p.Landscape {
+		return p.Sheet.H, p.Sheet.W
+	}
+	return p.Sheet.W, p.Sheet.H
+}
+
+// area is the bottom left and top right of the part of a page drawings go in
+func (p Plotter) area() (lo, hi Vec2) {
+	w, h := p.PageSize()
+	return NewVec2(p.Margin, p.Margin+plotTitleBand), NewVec2(w-p.Margin, h-p.Margin)
+}
+
+// scaleName is a scale written the way it is on drawings, eg 1:20
+func scaleName(s float64) string {
+	if s < 1 {
+		return fmt.Sprintf("1:%.3g", 1/s)
+	}
+	return fmt.Sprintf("%.3g:1", s)
+}
+
+// Pages lays out all the drawings
+func (p Plotter) Pages() []Page {
+	var pgs []Page
+	for _, d := range p.Drawings {
+		if p.Scale > 0 {
+			pgs = append(pgs, p.tiles(d)...)
+		} else {
+			pgs = append(pgs, p.fit(d))
+		}
+	}
+	return pgs
+}
+
+// place is the drawing moved from lo and scaled by s, then moved to at, keeping only what is in
+//   the page's area
+func (p Plotter) place(d Drawing, lo Vec2, s float64, at Vec2) []Segment {
+	alo, ahi := p.area()
+	var segs []Segment
+	for _, path := range d.Paths {
+		for _, seg := range path.Segments {
+			seg.Start = seg.Start.Subtract(lo).Scale(s).Add(at)
+			seg.End = seg.End.Subtract(lo).Scale(s).Add(at)
+			if c, in := clipSegment(seg, alo, ahi); in {
+				segs = append(segs, c)
+			}
+		}
+	}
+	return segs
+}
+
+// fit is the drawing scaled to fill the page's area, centered in it
+func (p Plotter) fit(d Drawing) Page {
+	alo, ahi := p.area()
+	aw, ah := ahi.X-alo.X, ahi.Y-alo.Y
+	lo, hi := d.Bounds()
+	w, h := math.Max(hi.X-lo.X, 1e-9), math.Max(hi.Y-lo.Y, 1e-9)
+	s := math.Min(aw/w, ah/h)
+	at := alo.Add(NewVec2((aw-w*s)/2, (ah-h*s)/2))
+	return Page{Title: fmt.Sprintf("%s  %s  %.0f x %.0f mm", d.Name, scaleName(s), w, h),
+		Segments: p.place(d, lo, s, at)}
+}
+
+// tiles is the drawing at the plotter's scale cut into page sized pieces, numbered by row up
+//   from the bottom and column from the left
+func (p Plotter) tiles(d Drawing) []Page {
+	alo, ahi := p.area()
+	aw, ah := ahi.X-alo.X, ahi.Y-alo.Y
+	lo, hi := d.Bounds()
+	cols := int(math.Max(1, math.Ceil((hi.X-lo.X)*p.Scale/aw)))
+	rows := int(math.Max(1, math.Ceil((hi.Y-lo.Y)*p.Scale/ah)))
+	var pgs []Page
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			at := alo.Subtract(NewVec2(float64(c)*aw, float64(r)*ah))
+			pg := Page{Title: fmt.Sprintf("%s  %s  row %d of %d, column %d of %d", d.Name, scaleName(p.Scale), r+1, rows, c+1, cols),
+				Segments: p.place(d, lo, p.Scale, at)}
+			pg.Segments = append(pg.Segments, cropMarks(alo, ahi)...)
+			pgs = append(pgs, pg)
+		}
+	}
+	return pgs
+}
+
+// cropMarks are short lines out from each corner of the box, along its sides
+func cropMarks(lo, hi Vec2) []Segment {
+	var segs []Segment
+	for _, x := range []float64{lo.X, hi.X} {
+		for _, y := range []float64{lo.Y, hi.Y} {
+			dx, dy := math.Copysign(1, x-(lo.X+hi.X)/2), math.Copysign(1, y-(lo.Y+hi.Y)/2)
+			segs = append(segs,
+				Segment{Kind: MetaPath, Start: NewVec2(x+dx*plotCropGap, y), End: NewVec2(x+dx*(plotCropGap+plotCropMark), y)},
+				Segment{Kind: MetaPath, Start: NewVec2(x, y+dy*plotCropGap), End: NewVec2(x, y+dy*(plotCropGap+plotCropMark))})
+		}
+	}
+	return segs
+}
+
+// clipSegment is the part of the segment inside the box, if any
+func clipSegment(s Segment, lo, hi Vec2) (Segment, bool) {
+	t0, t1 := 0.0, 1.0
+	d := s.End.Subtract(s.Start)
+	for _, e := range [][2]float64{{-d.X, s.Start.X - lo.X}, {d.X, hi.X - s.Start.X}, {-d.Y, s.Start.Y - lo.Y}, {d.Y, hi.Y - s.Start.Y}} {
+		p, q := e[0], e[1]
+		if p == 0 {
+			if q < 0 {
+				return s, false
+			}
+			continue
+		}
+		if r := q / p; p < 0 {
+			t0 = math.Max(t0, r)
+		} else {
+			t1 = math.Min(t1, r)
+		}
+		if t0 > t1 {
+			return s, false
+		}
+	}
+	return Segment{Kind: s.Kind, Start: s.At(t0), End: s.At(t1), Bend: s.Bend}, true
+}
+
+// WritePDF writes all the pages to one PDF file, folds dashed and labelled with their bends
+func (p Plotter) WritePDF(fname string) error {
+	w, h := p.PageSize()
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{OrientationStr: "P", UnitStr: "mm", Size: gofpdf.SizeType{Wd: w, Ht: h}})
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // the built in fonts are not UTF-8, for the °
+	for _, pg := range p.Pages() {
+		pdf.AddPage()
+		pdf.SetLineWidth(0.2)
+		for _, s := range pg.Segments {
+			c := plotColours[s.Kind]
+			pdf.SetDrawColor(c[0], c[1], c[2])
+			if s.Kind == FoldPath {
+				pdf.SetDashPattern([]float64{3, 1.5}, 0)
+			}
+			pdf.Line(s.Start.X, h-s.Start.Y, s.End.X, h-s.End.Y) // PDF has y down
+			pdf.SetDashPattern([]float64{}, 0)
+		}
+		c := plotColours[FoldPath]
+		pdf.SetTextColor(c[0], c[1], c[2])
+		pdf.SetFont("Helvetica", "", plotBendText*72/25.4) // points
+		for _, l := range bendLabels(pg.Segments, plotBendText) {
+			x, y, txt := l.At.X, h-l.At.Y, tr(l.Text)
+			pdf.TransformBegin()
+			pdf.TransformRotate(l.Angle/d2r, x, y)
+			pdf.Text(x-pdf.GetStringWidth(txt)/2, y, txt)
+			pdf.TransformEnd()
+		}
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.Text(p.Margin, h-p.Margin-plotTitleBand/3, pg.Title)
+	}
+	return pdf.OutputFileAndClose(fname)
+}
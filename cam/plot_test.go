@@ -0,0 +1,65 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPlotterFit(t *testing.T) {
+
+	d := Drawing{Name: "plate"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {1000, 0}, {1000, 500}, {0, 500}}, EdgePath))
+	p := NewPlotter(SheetA4).Add(d, d)
+	pgs := p.Pages()
+	if len(pgs) != 2 || len(pgs[0].Segments) != 4 {
+		t.Fatalf("Expected 2 pages of 4 segments, got %d", len(pgs))
+	}
+	lo, hi := Drawing{Paths: []Path{{Segments: pgs[0].Segments}}}.Bounds()
+	if math.Abs(hi.X-lo.X-(297-20)) > 1e-9 {
+		t.Errorf("Plate not fitted across the page: %s %s", lo, hi)
+	}
+}
+
+func TestPlotterTiles(t *testing.T) {
+
+	d := Drawing{Name: "plate"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {500, 0}, {500, 100}, {0, 100}}, EdgePath))
+	p := NewPlotter(SheetA4)
+	p.Scale = 1
+	pgs := p.Add(d).Pages()
+	if len(pgs) != 2 { // 277mm across each page
+		t.Fatalf("Expected 2 tiles, got %d", len(pgs))
+	}
+	alo, ahi := p.area()
+	edges := 0.0
+	for _, pg := range pgs {
+		for _, s := range pg.Segments {
+			if s.Kind == EdgePath {
+				edges += s.Length()
+			}
+			for _, v := range []Vec2{s.Start, s.End} {
+				if s.Kind == EdgePath && (v.X < alo.X-1e-9 || v.X > ahi.X+1e-9 || v.Y < alo.Y-1e-9 || v.Y > ahi.Y+1e-9) {
+					t.Errorf("Segment off the tile: %s", s)
+				}
+			}
+		}
+		if len(pg.Segments) < 8 {
+			t.Errorf("Tile has no crop marks")
+		}
+	}
+	if math.Abs(edges-1200) > 1e-9 {
+		t.Errorf("Tiles should share the 1200mm outline between them, have %f", edges)
+	}
+}
+
+func TestClipSegment(t *testing.T) {
+
+	s := Segment{Start: Vec2{-5, 5}, End: Vec2{15, 5}}
+	c, in := clipSegment(s, Vec2{0, 0}, Vec2{10, 10})
+	if !in || c.Start != (Vec2{0, 5}) || c.End != (Vec2{10, 5}) {
+		t.Errorf("Clipped wrong: %s", c)
+	}
+	if _, in := clipSegment(Segment{Start: Vec2{-5, 15}, End: Vec2{15, 15}}, Vec2{0, 0}, Vec2{10, 10}); in {
+		t.Error("Segment above the box kept")
+	}
+}
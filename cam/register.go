@@ -0,0 +1,115 @@
+package cam
+
+// ██████╗ ███████╗ ██████╗ ██╗███████╗████████╗███████╗██████╗
+// ██╔══██╗██╔════╝██╔════╝ ██║██╔════╝╚══██╔══╝██╔════╝██╔══██╗
+// ██████╔╝█████╗  ██║  ███╗██║███████╗   ██║   █████╗  ██████╔╝
+// ██╔══██╗██╔══╝  ██║   ██║██║╚════██║   ██║   ██╔══╝  ██╔══██╗
+// ██║  ██║███████╗╚██████╔╝██║███████║   ██║   ███████╗██║  ██║
+// ╚═╝  ╚═╝╚══════╝ ╚═════╝ ╚═╝╚══════╝   ╚═╝   ╚══════╝╚═╝  ╚═╝
+
+// SheetOutput is a kind of file a nested sheet is written to
+type SheetOutput int
+
+// Values of SheetOutput
+const (
+	OutputGCode SheetOutput = iota
+	OutputDXF
+	OutputSVG
+	OutputPDF
+)
+
+// SheetOutputs lists them in order
+var SheetOutputs = []SheetOutput{OutputGCode, OutputDXF, OutputSVG, OutputPDF}
+
+func (o SheetOutput) String() string {
+	switch o {
+	case OutputGCode:
+		return "G-code"
+	case OutputDXF:
+		return "DXF"
+	case OutputSVG:
+		return "SVG"
+	case OutputPDF:
+		return "PDF"
+	}
+	return "Unknown"
+}
+
+// SheetMarks are the marks put on a nested sheet so it can be put back on the machine, or turned
+//   over, in the same place. The corner marks are the same turned over either way.
+type SheetMarks struct {
+	Corners bool // a registration cross in a circle in each corner
+	ID      bool // the sheet's name along its bottom edge
+	Grain   bool // an arrow along the grain, which runs the length of the sheet
+}
+
+// Any is true if there are any marks at all
+func (m SheetMarks) Any() bool {
+	return m.Corners || m.ID || m.Grain
+}
+
+// MarkSet is the marks put on sheets in each output
+type MarkSet map[SheetOutput]SheetMarks
+
+// DefaultSheetMarks are those put on each output: all of them on drawings, and on the machine
+//   just what is needed to put the sheet back
+var DefaultSheetMarks = MarkSet{
+	OutputGCode: {Corners: true, ID: true},
+	OutputDXF:   {Corners: true, ID: true, Grain: true},
+	OutputSVG:   {Corners: true, ID: true, Grain: true},
+	OutputPDF:   {Corners: true, ID: true, Grain: true},
+}
+
+// Sizes of the marks on sheets, mm
+const (
+	regMarkInset  = 12.0 // from the edges of the sheet to the middle of a corner mark
+	regMarkSize   = 10.0 // across a corner mark
+	regTextHeight = 9.0  // of the sheet's name, the height of Stroke
+	regSpacing    = 1.0  // between its letters
+	grainLength   = 100.0
+	grainHead     = 6.0 // length of the sides of the arrowhead
+)
+
+// SheetMargin is left clear at the edges of a sheet for the marks
+const SheetMargin = regMarkInset + regMarkSize
+
+// regMark is a registration mark centered on c: a cross in a circle
+func regMark(c Vec2) []Path {
+	h := regMarkSize / 2
+	across, up := Path{}, Path{}
+	across.Add(Segment{Kind: MarkPath, Start: c.Subtract(NewVec2(h, 0)), End: c.Add(NewVec2(h, 0))})
+	up.Add(Segment{Kind: MarkPath, Start: c.Subtract(NewVec2(0, h)), End: c.Add(NewVec2(0, h))})
+	return []Path{NewCirclePath(c, h*0.7, MarkPath), across, up}
+}
+
+// grainArrow is an arrow along +X, its point at tip
+func grainArrow(tip Vec2) Path {
+	p := Path{}
+	tail := tip.Subtract(NewVec2(grainLength, 0))
+	p.Add(Segment{Kind: MarkPath, Start: tail, End: tip})
+	p.Add(Segment{Kind: MarkPath, Start: tip, End: tip.Add(NewVec2(-grainHead, grainHead/2))})
+	p.Add(Segment{Kind: MarkPath, Start: tip, End: tip.Add(NewVec2(-grainHead, -grainHead/2))})
+	return p
+}
+
+// Marks are the marks on the sheet, in its margin, with the name as its ID
+func (n Nest) Marks(name string, m SheetMarks) []Path {
+	var ps []Path
+	i := regMarkInset
+	if m.Corners {
+		for _, c := range []Vec2{{i, i}, {n.Width - i, i}, {n.Width - i, n.Height - i}, {i, n.Height - i}} {
+			ps = append(ps, regMark(c)...)
+		}
+	}
+	if m.ID && name != "" {
+		t := NewTurtle()
+		t.SetKind(MarkPath)
+		t.JumpTo(SheetMargin+regMarkInset, i-regTextHeight/2).TurnTo(deg90)
+		t.SetFont(Stroke, regSpacing).Type(name)
+		ps = append(ps, t.Trail)
+	}
+	if m.Grain {
+		ps = append(ps, grainArrow(NewVec2(n.Width-SheetMargin-regMarkInset, i)))
+	}
+	return ps
+}
@@ -0,0 +1,50 @@
+package cam
+
+import (
+	"testing"
+)
+
+func TestSheetMarks(t *testing.T) {
+	parts := []Drawing{{Paths: []Path{NewPolygonPath([]Vec2{{0, 0}, {100, 0}, {100, 50}, {0, 50}}, EdgePath)}}}
+	nests, _ := NestParts(parts, Nesting{Width: 1000, Height: 500, Gap: 5, Margin: SheetMargin})
+	if len(nests) != 1 || nests[0].Placed[0].At != NewVec2(SheetMargin, SheetMargin) {
+		t.Fatalf("Part not placed inside the margin: %+v", nests)
+	}
+	n := nests[0]
+
+	if ps := n.Marks("Sheet 1", SheetMarks{}); len(ps) != 0 {
+		t.Errorf("Got %d marks when none were asked for", len(ps))
+	}
+	ps := n.Marks("Sheet 1", SheetMarks{Corners: true})
+	if len(ps) != 12 {
+		t.Fatalf("Expected a circle and a cross in each corner, got %d paths", len(ps))
+	}
+	// Turned over either way, the corner marks land on each other
+	d := Drawing{Paths: ps}
+	lo, hi := d.Bounds()
+	if lo.X+hi.X != n.Width || lo.Y+hi.Y != n.Height {
+		t.Errorf("Corner marks from %v to %v are not symmetrical on the sheet", lo, hi)
+	}
+
+	all := Drawing{Paths: n.Marks("Sheet 1", DefaultSheetMarks[OutputDXF])}
+	for _, p := range all.Paths {
+		for _, s := range p.Segments {
+			if s.Kind != MarkPath {
+				t.Fatal("Sheet marks are not all marks")
+			}
+		}
+	}
+	if lo, hi := all.Bounds(); lo.X < 0 || lo.Y < 0 || hi.X > n.Width || hi.Y > n.Height {
+		t.Errorf("Marks from %v to %v are off the sheet", lo, hi)
+	}
+	bottom := Drawing{Paths: n.Marks("Sheet 1", SheetMarks{ID: true, Grain: true})}
+	if lo, hi := bottom.Bounds(); lo.Y < 0 || hi.Y > SheetMargin {
+		t.Errorf("Name and grain arrow from %v to %v are not in the margin", lo, hi)
+	}
+	if len(all.Paths) != 14 {
+		t.Errorf("Expected the corners, the name and the grain arrow, got %d paths", len(all.Paths))
+	}
+	if DefaultSheetMarks[OutputGCode].Grain || !DefaultSheetMarks[OutputPDF].Any() {
+		t.Error("Default marks are wrong")
+	}
+}
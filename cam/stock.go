@@ -0,0 +1,100 @@
+package cam
+
+// ███████╗████████╗ ██████╗  ██████╗██╗  ██╗
+// ██╔════╝╚══██╔══╝██╔═══██╗██╔════╝██║ ██╔╝
+// ███████╗   ██║   ██║   ██║██║     █████╔╝
+// ╚════██║   ██║   ██║   ██║██║     ██╔═██╗
+// ███████║   ██║   ╚██████╔╝╚██████╗██║  ██╗
+// ╚══════╝   ╚═╝    ╚═════╝  ╚═════╝╚═╝  ╚═╝
+
+import "fmt"
+
+// StockSheet is a size of sheet, or a width of coil, a gauge can be bought in
+type StockSheet struct {
+	Name   string  // eg 4x8
+	Width  float64 // m
+	Length float64 // m, 0 for coil, which is cut to CoilLength
+	Cost   float64 // of a sheet, or a metre of coil, 0 to price it by the material
+}
+
+// CoilLength is how long the pieces coil is cut into for nesting, m
+const CoilLength = 3.0
+
+const ft = 0.3048 // m
+
+// StandardStock are the usual sizes, by name
+var StandardStock = map[string]StockSheet{
+	"4x8":       {Name: "4x8", Width: 4 * ft, Length: 8 * ft},
+	"4x10":      {Name: "4x10", Width: 4 * ft, Length: 10 * ft},
+	"5x10":      {Name: "5x10", Width: 5 * ft, Length: 10 * ft},
+	"1250x2500": {Name: "1250x2500", Width: 1.25, Length: 2.5},
+	"1500x3000": {Name: "1500x3000", Width: 1.5, Length: 3},
+	"36in coil": {Name: "36in coil", Width: 3 * ft},
+	"48in coil": {Name: "48in coil", Width: 4 * ft},
+}
+
+// DefaultStock are the sizes a gauge comes in if its material does not say
+var DefaultStock = []StockSheet{StandardStock["4x8"], StandardStock["5x10"], StandardStock["48in coil"]}
+
+// Coil is true if it comes off a coil rather than as cut sheets
+func (s StockSheet) Coil() bool {
+	return s.Length <= 0
+}
+
+// Size is the width and length of a sheet, or of a piece cut off the coil, m
+func (s StockSheet) Size() (w, l float64) {
+	if s.Coil() {
+		return s.Width, CoilLength
+	}
+	return s.Width, s.Length
+}
+
+// Count is how many of them, as it would be written on an order, eg 11 sheets of 4x8
+func (s StockSheet) Count(n int) string {
+	if s.Coil() {
+		return fmt.Sprintf("%d %.3gm lengths of %s", n, CoilLength, s.Name)
+	}
+	if n == 1 {
+		return fmt.Sprintf("1 sheet of %s", s.Name)
+	}
+	return fmt.Sprintf("%d sheets of %s", n, s.Name)
+}
+
+func (s StockSheet) String() string {
+	if s.Coil() {
+		return fmt.Sprintf("%s (%.0fmm wide)", s.Name, s.Width*1000)
+	}
+	return fmt.Sprintf("%s (%.0fmm x %.0fmm)", s.Name, s.Width*1000, s.Length*1000)
+}
+
+// StockSizes are the sizes the gauge comes in, DefaultStock if it does not say
+func (g SheetGauge) StockSizes() []StockSheet {
+	if len(g.Stock) > 0 {
+		return g.Stock
+	}
+	return DefaultStock
+}
+
+// FindStock is the size of the gauge with the name, else the first it comes in
+func (g SheetGauge) FindStock(name string) StockSheet {
+	ss := g.StockSizes()
+	for _, s := range ss {
+		if s.Name == name {
+			return s
+		}
+	}
+	return ss[0]
+}
+
+// StockCost is what a sheet of the size, or a CoilLength of coil, costs: its own price if it
+//   has one, else by the area, 0 if that is not known either
+func (m Material) StockCost(g SheetGauge, s StockSheet) float64 {
+	w, l := s.Size()
+	switch {
+	case s.Cost > 0 && s.Coil():
+		return s.Cost * l
+	case s.Cost > 0:
+		return s.Cost
+	}
+	return w * l * m.CostPerM2(g)
+}
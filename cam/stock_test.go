@@ -0,0 +1,76 @@
+package cam
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStockSheet(t *testing.T) {
+	s := StandardStock["4x8"]
+	if w, l := s.Size(); math.Abs(w-1.2192) > 1e-9 || math.Abs(l-2.4384) > 1e-9 {
+		t.Errorf("4x8 is %.4fm x %.4fm", w, l)
+	}
+	if c := s.Count(11); c != "11 sheets of 4x8" {
+		t.Errorf("Count is %q", c)
+	}
+	coil := StandardStock["48in coil"]
+	if w, l := coil.Size(); !coil.Coil() || l != CoilLength || math.Abs(w-1.2192) > 1e-9 {
+		t.Errorf("Coil is %.4fm x %.4fm", w, l)
+	}
+	if c := coil.Count(2); c != "2 3m lengths of 48in coil" {
+		t.Errorf("Coil count is %q", c)
+	}
+
+	g := SheetGauge{Thickness: 0.001}
+	if f := g.FindStock("4x10"); f.Name != "4x8" {
+		t.Errorf("Gauge with no stock should come in the default sizes, found %s", f)
+	}
+	g.Stock = []StockSheet{StandardStock["5x10"], StandardStock["4x10"]}
+	if f := g.FindStock("4x10"); f.Name != "4x10" {
+		t.Errorf("Found %s for 4x10", f)
+	}
+	if f := g.FindStock(""); f.Name != "5x10" {
+		t.Errorf("Found %s for no size, not the first", f)
+	}
+
+	m := Material{Density: 8000, CostPerKg: 2}
+	if c := m.StockCost(g, StandardStock["1250x2500"]); math.Abs(c-1.25*2.5*0.001*8000*2) > 1e-9 {
+		t.Errorf("Sheet priced by weight at %.2f", c)
+	}
+	priced := StockSheet{Name: "roll", Width: 1, Cost: 10}
+	if c := m.StockCost(g, priced); c != 10*CoilLength {
+		t.Errorf("Coil priced by the metre at %.2f", c)
+	}
+}
+
+func TestLoadStock(t *testing.T) {
+	ms, err := LoadMaterials(strings.NewReader(`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 8500,
+		"stock": [{"name": "4x8"}],
+		"gauges": [{"id": "a", "thickness_mm": 1},
+			{"id": "b", "thickness_mm": 2, "stock": [{"width_mm": 1000, "length_mm": 2000, "cost": 90}, {"name": "36in coil"}]}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := ms["X"].SheetData["a"], ms["X"].SheetData["b"]
+	if len(a.Stock) != 1 || a.Stock[0].Name != "4x8" {
+		t.Errorf("Gauge does not get the material's stock: %+v", a.Stock)
+	}
+	if len(b.Stock) != 2 || b.Stock[0].Name != "1000x2000" || b.Stock[0].Cost != 90 || !b.Stock[1].Coil() {
+		t.Errorf("Gauge's own stock is wrong: %+v", b.Stock)
+	}
+	if _, err := LoadMaterials(strings.NewReader(`{"materials": [{"id": "X", "base": "brass", "density_kg_m3": 1,
+		"gauges": [{"id": "a", "thickness_mm": 1, "stock": [{"name": "huge"}]}]}]}`)); err == nil {
+		t.Error("Unknown stock loaded")
+	}
+	ms, err = LoadMaterialsCSV(strings.NewReader("id,base,density_kg_m3,gauge,thickness_mm,stock\nY,brass,8500,a,1,4x10; 5x10\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := ms["Y"].SheetData["a"].Stock; len(s) != 2 || s[1].Name != "5x10" {
+		t.Errorf("CSV stock is wrong: %+v", s)
+	}
+	if s := Materials["Stainless304"].SheetData["16ga"].StockSizes(); len(s) != 3 || s[0].Name != "4x8" {
+		t.Errorf("Built in stainless stock is wrong: %+v", s)
+	}
+}
@@ -0,0 +1,133 @@
+package cam
+
+// ███████╗████████╗██████╗  ██████╗ ██╗  ██╗███████╗
+// ██╔════╝╚══██╔══╝██╔══██╗██╔═══██╗██║ ██╔╝██╔════╝
+// ███████╗   ██║   ██████╔╝██║   ██║█████╔╝ █████╗
+// ╚════██║   ██║   ██╔══██╗██║   ██║██╔═██╗ ██╔══╝
+// ███████║   ██║   ██║  ██║╚██████╔╝██║  ██╗███████╗
+// ╚══════╝   ╚═╝   ╚═╝  ╚═╝ ╚═════╝ ╚═╝  ╚═╝╚══════╝
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Stroke is a single stroke font of capitals, digits and punctuation for engraving and marking,
+//   the same height as Plain's digits. Lower case letters come out as capitals.
+var Stroke Font
+
+// strokeGlyphs are the letters of Stroke, as lines through points "x,y" on a grid 9 high
+//   with the baseline at 0, lines separated by ";", then the width
+var strokeGlyphs = map[string]struct {
+	lines string
+	width float64
+}{
+	"A": {"0,0 0,6 2.5,9 5,6 5,0; 0,4 5,4", 5},
+	"B": {"0,0 0,9 4,9 5,8 5,5.5 4,4.5 0,4.5; 4,4.5 5,3.5 5,1 4,0 0,0", 5},
+	"C": {"5,8 4,9 1,9 0,8 0,1 1,0 4,0 5,1", 5},
+	"D": {"0,0 0,9 3,9 5,7 5,2 3,0 0,0", 5},
+	"E": {"5,9 0,9 0,0 5,0; 0,4.5 3.5,4.5", 5},
+	"F": {"5,9 0,9 0,0; 0,4.5 3.5,4.5", 5},
+	"G": {"5,8 4,9 1,9 0,8 0,1 1,0 4,0 5,1 5,4 3,4", 5},
+	"H": {"0,0 0,9; 5,0 5,9; 0,4.5 5,4.5", 5},
+	"I": {"0,9 3,9; 1.5,9 1.5,0; 0,0 3,0", 3},
+	"J": {"5,9 5,1 4,0 1,0 0,1 0,2", 5},
+	"K": {"0,0 0,9; 5,9 0,3.5; 1.8,5.3 5,0", 5},
+	"L": {"0,9 0,0 5,0", 5},
+	"M": {"0,0 0,9 3,5 6,9 6,0", 6},
+	"N": {"0,0 0,9 5,0 5,9", 5},
+	"O": {"1,0 0,1 0,8 1,9 4,9 5,8 5,1 4,0 1,0", 5},
+	"P": {"0,0 0,9 4,9 5,8 5,5.5 4,4.5 0,4.5", 5},
+	"Q": {"1,0 0,1 0,8 1,9 4,9 5,8 5,1 4,0 1,0; 3,2 5,0", 5},
+	"R": {"0,0 0,9 4,9 5,8 5,5.5 4,4.5 0,4.5; 2,4.5 5,0", 5},
+	"S": {"5,8 4,9 1,9 0,8 0,5.5 1,4.5 4,4.5 5,3.5 5,1 4,0 1,0 0,1", 5},
+	"T": {"0,9 5,9; 2.5,9 2.5,0", 5},
+	"U": {"0,9 0,1 1,0 4,0 5,1 5,9", 5},
+	"V": {"0,9 2.5,0 5,9", 5},
+	"W": {"0,9 1.5,0 3,6 4.5,0 6,9", 6},
+	"X": {"0,0 5,9; 0,9 5,0", 5},
+	"Y": {"0,9 2.5,4.5 5,9; 2.5,4.5 2.5,0", 5},
+	"Z": {"0,9 5,9 0,0 5,0", 5},
+
+	"0": {"1,0 0,1 0,8 1,9 4,9 5,8 5,1 4,0 1,0; 0.5,1.5 4.5,7.5", 5}, // slashed, unlike O
+	"1": {"1,7 3,9 3,0; 1,0 5,0", 5},
+	"2": {"0,8 1,9 4,9 5,8 5,6 0,0 5,0", 5},
+	"3": {"0,8 1,9 4,9 5,8 5,5.5 4,4.5 2,4.5; 4,4.5 5,3.5 5,1 4,0 1,0 0,1", 5},
+	"4": {"4,0 4,9 0,3 5,3", 5},
+	"5": {"5,9 0,9 0,5 4,5 5,4 5,1 4,0 0,0", 5},
+	"6": {"5,8 4,9 1,9 0,8 0,1 1,0 4,0 5,1 5,4 4,5 0,5", 5},
+	"7": {"0,9 5,9 2,0", 5},
+	"8": {"1,4.5 0,5.5 0,8 1,9 4,9 5,8 5,5.5 4,4.5 1,4.5 0,3.5 0,1 1,0 4,0 5,1 5,3.5 4,4.5", 5},
+	"9": {"5,4 1,4 0,5 0,8 1,9 4,9 5,8 5,1 4,0 0,0", 5},
+
+	" ":  {"", 3},
+	"-":  {"0.5,4.5 3.5,4.5", 4},
+	"_":  {"0,0 5,0", 5},
+	"=":  {"0,3 4,3; 0,6 4,6", 4},
+	"+":  {"0,4.5 4,4.5; 2,2.5 2,6.5", 4},
+	".":  {"0,0 0,0.8 0.8,0.8 0.8,0 0,0", 0.8},
+	",":  {"0.8,1 0.8,0 0,-1.5", 0.8},
+	":":  {"0,0 0,0.8 0.8,0.8 0.8,0 0,0; 0,5 0,5.8 0.8,5.8 0.8,5 0,5", 0.8},
+	"'":  {"0.5,9 0.5,7", 1},
+	"!":  {"0.4,9 0.4,2.5; 0,0 0,0.8 0.8,0.8 0.8,0 0,0", 0.8},
+	"?":  {"0,8 1,9 4,9 5,8 5,6 2.5,4 2.5,2.5; 2.1,0 2.1,0.8 2.9,0.8 2.9,0 2.1,0", 5},
+	"/":  {"0,0 4,9", 4},
+	"(":  {"2,9 0,7 0,2 2,0", 2},
+	")":  {"0,9 2,7 2,2 0,0", 2},
+	"#":  {"1,0 2,9; 3,0 4,9; 0,3 5,3; 0,6 5,6", 5},
+	"%":  {"0,0 5,9; 0,9 0,7 1,7 1,9 0,9; 4,0 4,2 5,2 5,0 4,0", 5},
+	"\"": {"0.5,9 0.5,7; 2,9 2,7", 2.5},
+}
+
+// parseStrokes reads lines in the form of strokeGlyphs
+func parseStrokes(s string) [][]Vec2 {
+	var lines [][]Vec2
+	for _, l := range strings.Split(s, ";") {
+		var pts []Vec2
+		for _, p := range strings.Fields(l) {
+			xy := strings.Split(p, ",")
+			if len(xy) != 2 {
+				continue
+			}
+			x, errX := strconv.ParseFloat(xy[0], 64)
+			y, errY := strconv.ParseFloat(xy[1], 64)
+			if errX == nil && errY == nil {
+				pts = append(pts, NewVec2(x, y))
+			}
+		}
+		if len(pts) > 1 {
+			lines = append(lines, pts)
+		}
+	}
+	return lines
+}
+
+// strokes is a letter's Draw drawing the lines, in the letter's own frame: x along the text from
+//   where the turtle starts and y up
+func strokes(lines [][]Vec2) func(t *Turtle) {
+	return func(t *Turtle) {
+		trailing := t.Trailing
+		at := Origin
+		for _, l := range lines {
+			for i, p := range l {
+				d := p.Subtract(at)
+				t.Trailing = trailing && i > 0
+				t.Strafe(d.X, -d.Y)
+				at = p
+			}
+		}
+		t.Trailing = trailing
+	}
+}
+
+func init() {
+	Stroke = make(Font)
+	for k, g := range strokeGlyphs {
+		l := Letter{Width: g.width, Height: 9, Draw: strokes(parseStrokes(g.lines))}
+		Stroke[k] = l
+		if lower := strings.ToLower(k); lower != k {
+			Stroke[lower] = l
+		}
+	}
+	Plain["?"] = Stroke["?"]
+}
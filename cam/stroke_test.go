@@ -0,0 +1,36 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStroke(t *testing.T) {
+
+	for _, c := range "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-.,:/()#?" {
+		if _, ok := Stroke[string(c)]; !ok {
+			t.Errorf("Stroke has no %c", c)
+		}
+	}
+	if len(parseStrokes(strokeGlyphs["H"].lines)) != 3 {
+		t.Error("H should have 3 strokes")
+	}
+
+	// Typing leaves the letters inside their boxes, one after the other, and draws nothing
+	//   between them
+	tu := NewTurtle()
+	tu.TurnTo(deg90).SetFont(Stroke, 1).Type("SEAM A-12")
+	lo, hi := Drawing{Paths: []Path{tu.Trail}}.Bounds()
+	w := Stroke.Width("SEAM A-12", 1)
+	if lo.X < -1e-9 || math.Abs(hi.X-w) > 1e-9 || lo.Y < -1e-9 || hi.Y > 9+1e-9 {
+		t.Errorf("Text %f wide from %s to %s", w, lo, hi)
+	}
+	for _, s := range tu.Trail.Segments {
+		if s.Length() > math.Hypot(6, 9)+1e-9 {
+			t.Errorf("Stroke too long, drawn between letters? %s", s)
+		}
+	}
+	if Stroke.GetLetter("m").Width != Stroke["M"].Width {
+		t.Error("Lower case should come out as capitals")
+	}
+}
@@ -0,0 +1,80 @@
+package cam
+
+// ███████╗██╗   ██╗ ██████╗
+// ██╔════╝██║   ██║██╔════╝
+// ███████╗██║   ██║██║  ███╗
+// ╚════██║╚██╗ ██╔╝██║   ██║
+// ███████║ ╚████╔╝ ╚██████╔╝
+// ╚══════╝  ╚═══╝   ╚═════╝
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// svgColours is the stroke colour of each kind of path
+var svgColours = map[PathKind]string{
+	EdgePath: "black",
+	FoldPath: "blue",
+	MarkPath: "green",
+	MetaPath: "grey",
+}
+
+// Bounds is the bottom left and top right corners of the box round all the segments
+func (d Drawing) Bounds() (Vec2, Vec2) {
+	lo, hi := NewVec2(math.Inf(1), math.Inf(1)), NewVec2(math.Inf(-1), math.Inf(-1))
+	for _, p := range d.Paths {
+		for _, s := range p.Segments {
+			for _, v := range []Vec2{s.Start, s.End} {
+				lo = NewVec2(math.Min(lo.X, v.X), math.Min(lo.Y, v.Y))
+				hi = NewVec2(math.Max(hi.X, v.X), math.Max(hi.Y, v.Y))
+			}
+		}
+	}
+	if lo.X > hi.X {
+		return Origin, Origin
+	}
+	return lo, hi
+}
+
+// SVG renders the drawing as an SVG file, one line per segment grouped by kind, with up as +Y
+//   and a margin round the outside. Folds are dashed and labelled with their bends. Units, and
+//   the margin's, are the drawing's.
+func (d Drawing) SVG(margin float64) string {
+	lo, hi := d.Bounds()
+	lo, hi = lo.Subtract(NewVec2(margin, margin)), hi.Add(NewVec2(margin, margin))
+	w, h := hi.X-lo.X, hi.Y-lo.Y
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.2f%s\" height=\"%.2f%s\" viewBox=\"0 0 %.2f %.2f\">\n", w, d.Units, h, d.Units, w, h)
+	fmt.Fprintf(&b, "<title>%s</title>\n", d.Name)
+	for _, k := range []PathKind{EdgePath, FoldPath, MarkPath, MetaPath} {
+		dash := ""
+		if k == FoldPath {
+			dash = fmt.Sprintf(" stroke-dasharray=\"%.2f %.2f\"", math.Max(w, h)/100, math.Max(w, h)/200)
+		}
+		fmt.Fprintf(&b, "<g id=\"%s\" stroke=\"%s\" stroke-width=\"%.2f\"%s fill=\"none\">\n", k, svgColours[k], math.Max(w, h)/1000, dash)
+		for _, p := range d.Paths {
+			for _, s := range p.Segments {
+				if s.Kind != k {
+					continue
+				}
+				fmt.Fprintf(&b, "<line x1=\"%.3f\" y1=\"%.3f\" x2=\"%.3f\" y2=\"%.3f\"/>\n",
+					s.Start.X-lo.X, hi.Y-s.Start.Y, s.End.X-lo.X, hi.Y-s.End.Y)
+			}
+		}
+		b.WriteString("</g>\n")
+	}
+	th := d.Units.FromMM(bendTextHeight)
+	labels := bendLabels(d.segments(), th)
+	if len(labels) > 0 {
+		fmt.Fprintf(&b, "<g id=\"Bends\" fill=\"%s\" font-family=\"sans-serif\" font-size=\"%.2f\" text-anchor=\"middle\">\n", svgColours[FoldPath], th)
+		for _, l := range labels {
+			x, y := l.At.X-lo.X, hi.Y-l.At.Y
+			fmt.Fprintf(&b, "<text x=\"%.3f\" y=\"%.3f\" transform=\"rotate(%.2f %.3f %.3f)\">%s</text>\n", x, y, -l.Angle/d2r, x, y, l.Text)
+		}
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,23 @@
+package cam
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+
+	d := Drawing{Name: "square"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, EdgePath))
+	s := d.SVG(5)
+
+	if n := strings.Count(s, "<line "); n != 4 {
+		t.Errorf("Expected 4 lines in SVG, got %d", n)
+	}
+	if !strings.Contains(s, "viewBox=\"0 0 20.00 20.00\"") {
+		t.Error("SVG viewBox does not allow for the margin")
+	}
+	if !strings.Contains(s, "x1=\"5.000\" y1=\"15.000\"") {
+		t.Error("SVG does not flip Y")
+	}
+}
@@ -0,0 +1,129 @@
+package cam
+
+// ████████╗ █████╗ ██████╗ ███████╗
+// ╚══██╔══╝██╔══██╗██╔══██╗██╔════╝
+//    ██║   ███████║██████╔╝███████╗
+//    ██║   ██╔══██║██╔══██╗╚════██║
+//    ██║   ██║  ██║██████╔╝███████║
+//    ╚═╝   ╚═╝  ╚═╝╚═════╝ ╚══════╝
+
+import (
+	"math"
+	"sort"
+)
+
+// Tabs say where to leave small uncut bridges, or micro-joints, holding a part in the sheet so
+//   it cannot tip into the slats while it is cut. Lengths are mm.
+type Tabs struct {
+	Spacing float64 // along the cut between tabs, 0 for none
+	Width   float64 // of each tab
+	Clear   float64 // kept between a tab and a corner
+}
+
+// DefaultTabs are typical micro-joints for thin sheet on a plasma table
+var DefaultTabs = Tabs{Spacing: 300, Width: 0.8, Clear: 10}
+
+const (
+	tabCornerAngle = 15 * d2r // a turn sharper than this is a corner to keep tabs from
+	tabSearchStep  = 1.0      // mm, how finely we look for somewhere clear of corners
+)
+
+// WithTabs is the closed path with a tab about every Spacing along it, as far from its corners
+//   as asked, each left as a MetaPath segment so machines do not cut it. The path still goes
+//   all the way round, so stays closed.
+func (p Path) WithTabs(t Tabs) Path {
+	n := len(p.Segments)
+	if !p.Closed || n == 0 || t.Spacing <= 0 || t.Width <= 0 {
+		return p
+	}
+	// Where each segment starts along the path, and where the corners are
+	starts := make([]float64, n+1)
+	var corners []float64
+	for i, s := range p.Segments {
+		starts[i+1] = starts[i] + s.Length()
+		prev := p.Segments[(i+n-1)%n]
+		a, b := prev.End.Subtract(prev.Start), s.End.Subtract(s.Start)
+		if math.Abs(math.Atan2(a.Cross(b), a.Dot(b))) > tabCornerAngle {
+			corners = append(corners, starts[i])
+		}
+	}
+	perim := starts[n]
+	along := func(x float64) float64 { // wrapped onto the path
+		return x - perim*math.Floor(x/perim)
+	}
+	apart := func(x, y float64) float64 { // along the path, whichever way is shorter
+		d := along(x - y)
+		return math.Min(d, perim-d)
+	}
+	clear := func(x float64) bool {
+		for _, c := range corners {
+			if apart(x, c) < t.Clear+t.Width/2 {
+				return false
+			}
+		}
+		return true
+	}
+
+	// Each tab as near as it can be to evenly spaced, and clear of corners and the others
+	count := int(math.Round(perim / t.Spacing))
+	var tabs []float64
+	for k := 0; k < count; k++ {
+		want := perim * (float64(k) + 0.5) / float64(count)
+		for d := 0.0; d < perim/float64(2*count); d += tabSearchStep {
+			at, found := want+d, clear(want+d)
+			if !found {
+				at, found = want-d, clear(want-d)
+			}
+			if found && (len(tabs) == 0 || apart(at, tabs[len(tabs)-1]) > 2*t.Width) {
+				tabs = append(tabs, along(at))
+				break
+			}
+		}
+	}
+	inTab := func(x float64) bool {
+		for _, c := range tabs {
+			if apart(x, c) < t.Width/2 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Split the segments at the ends of the tabs
+	g := Path{Closed: true}
+	for i, s := range p.Segments {
+		l := starts[i+1] - starts[i]
+		if l < IntersectTolerance {
+			g.Add(s)
+			continue
+		}
+		cuts := []float64{0, 1}
+		for _, c := range tabs {
+			for _, e := range []float64{c - t.Width/2, c + t.Width/2} {
+				for _, x := range []float64{e - perim, e, e + perim} {
+					if u := (x - starts[i]) / l; u > 0 && u < 1 {
+						cuts = append(cuts, u)
+					}
+				}
+			}
+		}
+		sort.Float64s(cuts)
+		for k := 0; k+1 < len(cuts); k++ {
+			piece := Segment{Kind: s.Kind, Start: s.At(cuts[k]), End: s.At(cuts[k+1])}
+			if inTab(starts[i] + l*(cuts[k]+cuts[k+1])/2) {
+				piece.Kind = MetaPath
+			}
+			g.Add(piece)
+		}
+	}
+	return g
+}
+
+// WithTabs is the drawing with tabs in its outline, holes being left to fall out
+func (d Drawing) WithTabs(t Tabs) Drawing {
+	g := Drawing{Name: d.Name, ID: d.ID, Units: d.Units, Paths: append([]Path{}, d.Paths...)}
+	if outer := d.outline(); outer >= 0 {
+		g.Paths[outer] = d.Paths[outer].WithTabs(t)
+	}
+	return g
+}
@@ -0,0 +1,48 @@
+package cam
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWithTabs(t *testing.T) {
+
+	d := Drawing{Name: "strip"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {1000, 0}, {1000, 50}, {0, 50}}, EdgePath))
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{10, 10}, {10, 20}, {20, 20}, {20, 10}}, EdgePath))
+	tabs := Tabs{Spacing: 700, Width: 2, Clear: 20}
+	g := d.WithTabs(tabs)
+
+	outline := g.Paths[0]
+	n, cut, uncut := 0, 0.0, 0.0
+	for _, s := range outline.Segments {
+		if s.Kind == MetaPath {
+			n++
+			uncut += s.Length()
+			for _, c := range []Vec2{{0, 0}, {1000, 0}, {1000, 50}, {0, 50}} {
+				if s.Start.Subtract(c).Length() < tabs.Clear || s.End.Subtract(c).Length() < tabs.Clear {
+					t.Errorf("Tab too near a corner: %s", s)
+				}
+			}
+		} else {
+			cut += s.Length()
+		}
+	}
+	if n != 3 || math.Abs(uncut-3*tabs.Width) > 1e-9 || math.Abs(cut+uncut-2100) > 1e-9 {
+		t.Errorf("Expected 3 tabs of 2mm in 2100mm, got %d, %f uncut, %f cut", n, uncut, cut)
+	}
+	if !outline.Closed || math.Abs(outline.Area()-50000) > 1e-6 {
+		t.Error("Tabbed outline should still go all the way round")
+	}
+	if len(g.Paths[1].Segments) != 4 {
+		t.Error("Holes should not get tabs")
+	}
+
+	// The short ends have no room clear of the corners, so a tab wanted there moves along
+	end := NewPolygonPath([]Vec2{{0, 0}, {30, 0}, {30, 200}, {0, 200}}, EdgePath).WithTabs(Tabs{Spacing: 200, Width: 1, Clear: 20})
+	for _, s := range end.Segments {
+		if s.Kind == MetaPath && (s.Start.Y < 20 || s.Start.Y > 180) {
+			t.Errorf("Tab on an end too short for one: %s", s)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package cam
+
+// ██╗   ██╗███╗   ██╗██╗████████╗███████╗
+// ██║   ██║████╗  ██║██║╚══██╔══╝██╔════╝
+// ██║   ██║██╔██╗ ██║██║   ██║   ███████╗
+// ██║   ██║██║╚██╗██║██║   ██║   ╚════██║
+// ╚██████╔╝██║ ╚████║██║   ██║   ███████║
+//  ╚═════╝ ╚═╝  ╚═══╝╚═╝   ╚═╝   ╚══════╝
+
+// Unit is the length unit a drawing's coordinates are in. Drawings are made, nested, kerfed and
+//   led in in mm, so convert them with InUnits only when writing them out.
+type Unit int
+
+// Values of Unit
+const (
+	Millimetre Unit = iota // the zero value, so drawings are mm unless they say otherwise
+	Inch
+)
+
+// Units lists them in order, for the GUI
+var Units = []Unit{Millimetre, Inch}
+
+// MMPerInch is exact
+const MMPerInch = 25.4
+
+func (u Unit) String() string {
+	switch u {
+	case Millimetre:
+		return "mm"
+	case Inch:
+		return "in"
+	}
+	return "Unknown"
+}
+
+// MM is how many mm there are in one of the unit
+func (u Unit) MM() float64 {
+	if u == Inch {
+		return MMPerInch
+	}
+	return 1
+}
+
+// FromMM is a length in mm in the unit
+func (u Unit) FromMM(x float64) float64 {
+	return x / u.MM()
+}
+
+// ToMM is a length in the unit in mm
+func (u Unit) ToMM(x float64) float64 {
+	return x * u.MM()
+}
+
+// dxfInsUnits is the unit's code for the $INSUNITS header variable
+func (u Unit) dxfInsUnits() int {
+	if u == Inch {
+		return 1
+	}
+	return 4
+}
+
+// InUnits is the drawing with its coordinates and bend radii in the unit
+func (d Drawing) InUnits(u Unit) Drawing {
+	if u == d.Units {
+		return d
+	}
+	k := d.Units.MM() / u.MM()
+	g := Drawing{Name: d.Name, ID: d.ID, Units: u}
+	bends := map[*Bend]*Bend{} // segments of a fold share its bend, so keep them sharing it
+	for _, p := range d.Paths {
+		q := Path{Closed: p.Closed}
+		for _, s := range p.Segments {
+			s.Start, s.End = s.Start.Scale(k), s.End.Scale(k)
+			if b := s.Bend; b != nil {
+				if bends[b] == nil {
+					nb := *b
+					nb.Radius *= k
+					bends[b] = &nb
+				}
+				s.Bend = bends[b]
+			}
+			q.Add(s)
+		}
+		g.Paths = append(g.Paths, q)
+	}
+	return g
+}
+
+// InUnits is the machine with its heights, feeds and rapids in the unit, per minute
+func (m Machine) InUnits(u Unit) Machine {
+	k := 1 / u.MM()
+	m.SafeZ, m.PierceZ, m.CutZ = m.SafeZ*k, m.PierceZ*k, m.CutZ*k
+	m.Feed, m.PlungeFeed, m.Rapid = m.Feed*k, m.PlungeFeed*k, m.Rapid*k
+	return m
+}
@@ -0,0 +1,63 @@
+package cam
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestUnits(t *testing.T) {
+	if Inch.FromMM(254) != 10 || Inch.ToMM(1) != 25.4 || Millimetre.FromMM(3) != 3 {
+		t.Error("Conversions are wrong")
+	}
+
+	d := Drawing{Name: "plate"}
+	d.Paths = append(d.Paths, NewPolygonPath([]Vec2{{0, 0}, {254, 0}, {254, 127}, {0, 127}}, EdgePath))
+	d.Paths = append(d.Paths, NewFoldPath(NewVec2(127, 0), NewVec2(127, 127), Bend{Angle: deg90, Radius: 2.54}))
+	in := d.InUnits(Inch)
+	if in.Units != Inch || in.Paths[0].Segments[1].End != NewVec2(10, 5) {
+		t.Errorf("Drawing in inches is %v", in.Paths[0].Segments)
+	}
+	if r := in.Paths[1].Segments[0].Bend.Radius; math.Abs(r-0.1) > 1e-12 || d.Paths[1].Segments[0].Bend.Radius != 2.54 {
+		t.Errorf("Bend radius in inches is %.3f, and was %.3f", r, d.Paths[1].Segments[0].Bend.Radius)
+	}
+	if back := in.InUnits(Millimetre); back.Units != Millimetre || back.Paths[0].Segments[1].End.Subtract(NewVec2(254, 127)).Length() > 1e-9 {
+		t.Error("Drawing does not come back to mm")
+	}
+	if tr := in.Transformed(0, NewVec2(1, 1)); tr.Units != Inch {
+		t.Error("Transformed drawing loses its units")
+	}
+
+	m := Machine{Name: "Test", Torch: true, SafeZ: 25.4, PierceZ: 2.54, CutZ: 2.54, Feed: 2540, PlungeFeed: 254, Cuts: []PathKind{EdgePath}}
+	g := in.GCode(m)
+	if !strings.Contains(g, "G20 G90") || strings.Contains(g, "G21") {
+		t.Error("G-code for a drawing in inches is not in inches")
+	}
+	if !strings.Contains(g, "G1 X10.000 Y0.000") || !strings.Contains(g, "G0 Z1.000") || !strings.Contains(g, "F100\n") {
+		t.Errorf("G-code moves are not in inches:\n%s", g)
+	}
+	if !strings.Contains(d.GCode(m), "G21 G90") {
+		t.Error("G-code for a drawing in mm is not in mm")
+	}
+	if e, ei := d.Estimate(m), in.Estimate(m); math.Abs(e.Length-ei.Length) > 1e-9 || math.Abs(e.Time-ei.Time) > 1e-9 {
+		t.Errorf("Estimate in inches is %s, in mm %s", ei, e)
+	}
+
+	dxf := in.DXF()
+	if !strings.Contains(dxf, "$INSUNITS\n 70\n1\n") {
+		t.Error("DXF in inches does not say so")
+	}
+	r, err := ReadDXF(strings.NewReader(dxf))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, hi := r.Bounds(); math.Abs(hi.X-254) > 1e-3 {
+		t.Errorf("DXF in inches reads back %.3fmm wide", hi.X)
+	}
+	if !strings.Contains(in.SVG(0), "width=\"10.00in\"") || !strings.Contains(d.SVG(0), "width=\"254.00mm\"") {
+		t.Error("SVG sizes are not in the drawing's units")
+	}
+	if p := NewPlotter(SheetA4).Add(in); p.Drawings[0].Units != Millimetre {
+		t.Error("Drawing in inches plotted as it is")
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+//  ██████╗██╗      ██████╗ ███████╗███████╗███████╗████████╗
+// ██╔════╝██║     ██╔═══██╗██╔════╝██╔════╝██╔════╝╚══██╔══╝
+// ██║     ██║     ██║   ██║███████╗█████╗  ███████╗   ██║
+// ██║     ██║     ██║   ██║╚════██║██╔══╝  ╚════██║   ██║
+// ╚██████╗███████╗╚██████╔╝███████║███████╗███████║   ██║
+//  ╚═════╝╚══════╝ ╚═════╝ ╚══════╝╚══════╝╚══════╝   ╚═╝
+
+import (
+	"math"
+
+	v3 "./vec"
+)
+
+// ClosestPoint is the point of the panel, including its inside, closest to pt
+func (p *Panel) ClosestPoint(pt v3.Vec) v3.Vec {
+	return v3.ClosestPointOnTriangle(pt, p.Corners[0].Position, p.Corners[1].Position, p.Corners[2].Position)
+}
+
+// ClosestPointOnPanel finds the closest point to pt on any alive panel, and the panel it is on;
+//   nil if there are no panels
+func (e *EShell) ClosestPointOnPanel(pt v3.Vec) (v3.Vec, *Panel) {
+	dist := func(p *Panel) float64 { return p.ClosestPoint(pt).Subtract(pt).Length() }
+	p, _ := e.panelTree().nearest(pt, dist, nil, math.Inf(1))
+	if p == nil {
+		return nil, nil
+	}
+	return p.ClosestPoint(pt), p
+}
+
+// Clearance is how far pt is from the nearest panel of the shell
+func (e *EShell) Clearance(pt v3.Vec) float64 {
+	c, p := e.ClosestPointOnPanel(pt)
+	if p == nil {
+		return math.Inf(1)
+	}
+	return c.Subtract(pt).Length()
+}
+
+// LeafClearance is how close the top of the leaf's latch edge comes to the shell when it is
+//   opened to deg degrees
+func (d *Door) LeafClearance(l DoorLeaf, deg float64) float64 {
+	c := l.Corners(deg)
+	return math.Min(d.Shell.Clearance(c[2]), d.Shell.Clearance(c[2].Add(c[3]).Scale(0.5)))
+}
@@ -0,0 +1,294 @@
+package main
+
+//  ██████╗ ██████╗ ██╗      ██████╗ ██╗   ██╗██████╗
+// ██╔════╝██╔═══██╗██║     ██╔═══██╗██║   ██║██╔══██╗
+// ██║     ██║   ██║██║     ██║   ██║██║   ██║██████╔╝
+// ██║     ██║   ██║██║     ██║   ██║██║   ██║██╔══██╗
+// ╚██████╗╚██████╔╝███████╗╚██████╔╝╚██████╔╝██║  ██║
+//  ╚═════╝ ╚═════╝ ╚══════╝ ╚═════╝  ╚═════╝ ╚═╝  ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+
+	"github.com/g3n/engine/math32"
+)
+
+// LineColouring is what the colours of the wireframe show
+type LineColouring int
+
+// Wireframe colourings
+const (
+	LinesPlain     LineColouring = iota // all yellow
+	LinesTension                        // tension in each edge from relaxation
+	LinesDeviation                      // how far each edge is from its target length
+	LinesTreatment                      // the treatment of each edge, hems, flanges etc.
+	LinesMaterial                       // the material and gauge of the panels either side
+)
+
+// lengthDeviationFull is the deviation from the target length, as a fraction of it, shown as full red
+const lengthDeviationFull = 0.25
+
+// LineColourings lists them all, in menu order
+var LineColourings = []LineColouring{LinesPlain, LinesTension, LinesDeviation, LinesTreatment, LinesMaterial}
+
+// String names the colouring
+func (c LineColouring) String() string {
+	switch c {
+	case LinesPlain:
+		return "Plain"
+	case LinesTension:
+		return "Tension"
+	case LinesDeviation:
+		return "Deviation"
+	case LinesTreatment:
+		return "Treatment"
+	case LinesMaterial:
+		return "Material"
+	}
+	return "?"
+}
+
+// plainLine is the colour of the wireframe when it shows nothing in particular
+var plainLine = math32.Color{R: 1, G: 1, B: 0}
+
+// blueRed maps t from -1 to 1 onto blue through white to red
+func blueRed(t float64) math32.Color {
+	t = math.Max(-1, math.Min(1, t))
+	if t < 0 {
+		return math32.Color{R: float32(1 + t), G: float32(1 + t), B: 1}
+	}
+	return math32.Color{R: 1, G: float32(1 - t), B: float32(1 - t)}
+}
+
+// greenRed maps t from 0 to 1 onto green through yellow to red
+func greenRed(t float64) math32.Color {
+	t = math.Max(0, math.Min(1, t))
+	if t < 0.5 {
+		return math32.Color{R: float32(2 * t), G: 1, B: 0}
+	}
+	return math32.Color{R: 1, G: float32(2 - 2*t), B: 0}
+}
+
+// LengthDeviation is how far the edge is from the length it should be, the shell's panel size
+//   at its height unless it has its own Target, as a fraction of that length
+func (e *EShell) LengthDeviation(ed *Edge) float64 {
+	a, b := ed.Vertices[0].Position, ed.Vertices[1].Position
+	target := ed.Target
+	if target <= 0 {
+		target = e.PanelSizeAt((a.Z()+b.Z())/2, e.PanelSize)
+	}
+	if target <= 0 {
+		return 0
+	}
+	return math.Abs(b.Subtract(a).Length()-target) / target
+}
+
+// maxTension is the largest tension or compression in any alive edge
+func (e *EShell) maxTension() float64 {
+	m := 0.0
+	for _, ed := range aliveEdges(e.Edges) {
+		m = math.Max(m, math.Abs(ed.Tension))
+	}
+	return m
+}
+
+// lineScale is what the colours of all the edges are worked out from, found once for them all
+type lineScale struct {
+	maxT   float64              // largest tension or compression
+	sheets []cam.InputSheetType // what the panels are cut from, in the order of their colours
+}
+
+// lineScale finds what the shell's Colouring needs
+func (e *EShell) lineScale() lineScale {
+	switch e.Colouring {
+	case LinesTension:
+		return lineScale{maxT: e.maxTension()}
+	case LinesMaterial:
+		return lineScale{sheets: e.SheetTypes(alivePanels(e.Panels))}
+	}
+	return lineScale{}
+}
+
+// EdgeColour is the colour of the edge in the wireframe, according to the shell's Colouring.
+//   Tension is red, compression blue, scaled by the fifth root as the tension goes with
+//   the fifth power of the stretch.
+func (e *EShell) EdgeColour(ed *Edge, ls lineScale) math32.Color {
+	maxT := ls.maxT
+	if ed == nil {
+		return plainLine
+	}
+	switch e.Colouring {
+	case LinesTension:
+		if maxT <= 0 {
+			return blueRed(0)
+		}
+		t := ed.Tension / maxT
+		return blueRed(math.Copysign(math.Pow(math.Abs(t), 0.2), t))
+	case LinesDeviation:
+		return greenRed(e.LengthDeviation(ed) / lengthDeviationFull)
+	case LinesTreatment:
+		return treatmentColours[ed.Treatment]
+	case LinesMaterial:
+		return e.sheetColour(ed, ls.sheets)
+	}
+	return plainLine
+}
+
+// Legend explains the colours of the wireframe
+func (e *EShell) Legend() string {
+	switch e.Colouring {
+	case LinesTension:
+		m := e.maxTension()
+		return fmt.Sprintf("Blue: compression to %.3g\nWhite: none\nRed: tension to %.3g", -m, m)
+	case LinesDeviation:
+		worst := 0.0
+		for _, ed := range aliveEdges(e.Edges) {
+			worst = math.Max(worst, e.LengthDeviation(ed))
+		}
+		return fmt.Sprintf("Green: target length\nYellow: %.0f%% off\nRed: %.0f%% or more off\nWorst: %.0f%%",
+			50*lengthDeviationFull, 100*lengthDeviationFull, 100*worst)
+	case LinesTreatment:
+		return "Grey: as cut\nRed: open hem\nBlue: closed hem\nMagenta: teardrop hem\nWhite: smooth\nGreen: flange"
+	case LinesMaterial:
+		return e.sheetLegend()
+	}
+	return ""
+}
+
+// PanelColouring is what the colours of the panels show
+type PanelColouring int
+
+// Panel colourings
+const (
+	PanelsPlain    PanelColouring = iota // all white, lit
+	PanelsArea                           // smallest green to largest red
+	PanelsMaterial                       // the material and gauge each is cut from
+	PanelsCourse                         // which course each is in
+	PanelsEmit                           // whether each is emitted
+	PanelsDRC                            // those breaking a design rule
+)
+
+// PanelColourings lists them all, in menu order
+var PanelColourings = []PanelColouring{PanelsPlain, PanelsArea, PanelsMaterial, PanelsCourse, PanelsEmit, PanelsDRC}
+
+// String names the colouring
+func (c PanelColouring) String() string {
+	switch c {
+	case PanelsPlain:
+		return "Plain"
+	case PanelsArea:
+		return "Area"
+	case PanelsMaterial:
+		return "Material"
+	case PanelsCourse:
+		return "Course"
+	case PanelsEmit:
+		return "Emit"
+	case PanelsDRC:
+		return "DRC"
+	}
+	return "?"
+}
+
+// Colours of the panels when showing yes or no
+var (
+	panelYes = math32.Color{R: 0, G: 0.8, B: 0}
+	panelNo  = math32.Color{R: 0.4, G: 0.4, B: 0.4}
+	panelBad = math32.Color{R: 1, G: 0, B: 0}
+)
+
+// panelScale is what the colours of all the panels are worked out from, found once for them all
+type panelScale struct {
+	minA, maxA float64              // smallest and largest areas
+	sheets     []cam.InputSheetType // what the panels are cut from, in the order of their colours
+	bad        map[*Panel]bool      // panels breaking a design rule, or either side of an edge which does
+}
+
+// panelScale finds what the shell's PanelColouring needs
+func (e *EShell) panelScale() panelScale {
+	ps := alivePanels(e.Panels)
+	switch e.PanelColouring {
+	case PanelsArea:
+		s := panelScale{minA: math.Inf(1), maxA: math.Inf(-1)}
+		for _, p := range ps {
+			s.minA, s.maxA = math.Min(s.minA, p.Area), math.Max(s.maxA, p.Area)
+		}
+		return s
+	case PanelsMaterial:
+		return panelScale{sheets: e.SheetTypes(ps)}
+	case PanelsDRC:
+		s := panelScale{bad: map[*Panel]bool{}}
+		for _, it := range e.DRC() {
+			if it.Panel != nil {
+				s.bad[it.Panel] = true
+			}
+			if it.Edge != nil {
+				for _, p := range alivePanels(it.Edge.Panels) {
+					s.bad[p] = true
+				}
+			}
+		}
+		return s
+	}
+	return panelScale{}
+}
+
+// PanelColour is the colour of the panel, according to the shell's PanelColouring
+func (e *EShell) PanelColour(p *Panel, ps panelScale) math32.Color {
+	switch e.PanelColouring {
+	case PanelsArea:
+		if ps.maxA <= ps.minA {
+			return greenRed(0)
+		}
+		return greenRed((p.Area - ps.minA) / (ps.maxA - ps.minA))
+	case PanelsMaterial:
+		name := sheetName(p.SheetType())
+		for i, st := range ps.sheets {
+			if sheetName(st) == name {
+				return sheetColours[i%len(sheetColours)]
+			}
+		}
+	case PanelsCourse:
+		return sheetColours[e.Course(p)%len(sheetColours)]
+	case PanelsEmit:
+		if p.Emit {
+			return panelYes
+		}
+		return panelNo
+	case PanelsDRC:
+		if ps.bad[p] {
+			return panelBad
+		}
+		return panelNo
+	}
+	return math32.Color{R: 1, G: 1, B: 1}
+}
+
+// PanelLegend explains the colours of the panels
+func (e *EShell) PanelLegend() string {
+	switch e.PanelColouring {
+	case PanelsArea:
+		s := e.panelScale()
+		return fmt.Sprintf("Green: %.3fsqm\nYellow: %.3fsqm\nRed: %.3fsqm", s.minA, (s.minA+s.maxA)/2, s.maxA)
+	case PanelsMaterial:
+		s := ""
+		for i, st := range e.SheetTypes(alivePanels(e.Panels)) {
+			s += fmt.Sprintf("%s: %s\n", sheetColourNames[i%len(sheetColourNames)], sheetName(st))
+		}
+		return s
+	case PanelsCourse:
+		s := ""
+		for i, n := range sheetColourNames {
+			s += fmt.Sprintf("%s: course %d, %d, ...\n", n, i, i+len(sheetColourNames))
+		}
+		return s
+	case PanelsEmit:
+		return "Green: emitted\nGrey: not"
+	case PanelsDRC:
+		return "Red: breaks a design rule\nGrey: passes"
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package main
+
+//  ██████╗ ██████╗ ███╗   ███╗██████╗  █████╗  ██████╗████████╗
+// ██╔════╝██╔═══██╗████╗ ████║██╔══██╗██╔══██╗██╔════╝╚══██╔══╝
+// ██║     ██║   ██║██╔████╔██║██████╔╝███████║██║        ██║
+// ██║     ██║   ██║██║╚██╔╝██║██╔═══╝ ██╔══██║██║        ██║
+// ╚██████╗╚██████╔╝██║ ╚═╝ ██║██║     ██║  ██║╚██████╗   ██║
+//  ╚═════╝ ╚═════╝ ╚═╝     ╚═╝╚═╝     ╚═╝  ╚═╝ ╚═════╝   ╚═╝
+
+// Compact drops the dead vertices, edges and panels, renumbering the rest in the same order
+//   and removing all references to the dropped ones. Dead panels which alive ones were split
+//   from are kept, so SubPanelOf still leads somewhere. The liner, if any, is compacted to match.
+//   Returns the number of things dropped.
+func (e *EShell) Compact() int {
+	keepV := map[int]bool{}
+	keepE := map[int]bool{}
+	keepP := map[int]bool{}
+	for _, v := range e.Vertices {
+		keepV[v.Serial] = v.Alive
+	}
+	for _, ed := range e.Edges {
+		keepE[ed.Serial] = ed.Alive
+	}
+	for _, p := range e.Panels {
+		if p.Alive {
+			for q := p; q != nil; q = q.SubPanelOf {
+				keepP[q.Serial] = true
+			}
+		}
+	}
+
+	if l := e.Liner; l != nil {
+		if len(l.Vertices) == len(e.Vertices) && len(l.Edges) == len(e.Edges) && len(l.Panels) == len(e.Panels) {
+			l.compactKeeping(keepV, keepE, keepP)
+		} else {
+			console.Warnf("The liner no longer matches the shell, dropping it")
+			e.Liner = nil
+		}
+	}
+	return e.compactKeeping(keepV, keepE, keepP)
+}
+
+// afterCut tidies up after the shell has been cut, compacting it if asked to
+func (e *EShell) afterCut() {
+	if e.AutoCompact {
+		e.Compact()
+	}
+}
+
+// compactKeeping drops everything whose serial is not marked to keep, and renumbers the rest
+func (e *EShell) compactKeeping(keepV, keepE, keepP map[int]bool) int {
+	n := len(e.Vertices) + len(e.Edges) + len(e.Panels)
+
+	vs := []*Vertex{}
+	for _, v := range e.Vertices {
+		if keepV[v.Serial] {
+			vs = append(vs, v)
+		}
+	}
+	es := []*Edge{}
+	for _, ed := range e.Edges {
+		if keepE[ed.Serial] {
+			es = append(es, ed)
+		}
+	}
+	ps := []*Panel{}
+	for _, p := range e.Panels {
+		if keepP[p.Serial] {
+			ps = append(ps, p)
+		}
+	}
+
+	// Work out what is kept before renumbering, as that is by the old serials
+	keptV := map[*Vertex]bool{}
+	for _, v := range vs {
+		keptV[v] = true
+	}
+	keptE := map[*Edge]bool{}
+	for _, ed := range es {
+		keptE[ed] = true
+	}
+	keptP := map[*Panel]bool{}
+	for _, p := range ps {
+		keptP[p] = true
+	}
+
+	for i, v := range vs {
+		v.Serial = i
+		v.Edges = keptEdges(v.Edges, keptE)
+		v.Panels = keptPanels(v.Panels, keptP)
+	}
+	for i, ed := range es {
+		ed.Serial = i
+		ed.Panels = keptPanels(ed.Panels, keptP)
+	}
+	for i, p := range ps {
+		p.Serial = i
+		p.Edges = keptEdges(p.Edges, keptE)
+		var cs []*Vertex
+		for _, c := range p.Corners {
+			if keptV[c] {
+				cs = append(cs, c)
+			}
+		}
+		p.Corners = cs
+	}
+	for ed, p := range e.HemOverrides {
+		if !keptE[ed] || !keptP[p] {
+			delete(e.HemOverrides, ed)
+		}
+	}
+
+	e.Vertices, e.Edges, e.Panels = vs, es, ps
+	e.touch()
+	return n - len(vs) - len(es) - len(ps)
+}
+
+// keptEdges is those in the list which are kept
+func keptEdges(l []*Edge, kept map[*Edge]bool) []*Edge {
+	r := []*Edge{}
+	for _, ed := range l {
+		if kept[ed] {
+			r = append(r, ed)
+		}
+	}
+	return r
+}
+
+// keptPanels is those in the list which are kept
+func keptPanels(l []*Panel, kept map[*Panel]bool) []*Panel {
+	r := []*Panel{}
+	for _, p := range l {
+		if kept[p] {
+			r = append(r, p)
+		}
+	}
+	return r
+}
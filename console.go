@@ -0,0 +1,181 @@
+package main
+
+//  ██████╗ ██████╗ ███╗   ██╗███████╗ ██████╗ ██╗     ███████╗
+// ██╔════╝██╔═══██╗████╗  ██║██╔════╝██╔═══██╗██║     ██╔════╝
+// ██║     ██║   ██║██╔██╗ ██║███████╗██║   ██║██║     █████╗
+// ██║     ██║   ██║██║╚██╗██║╚════██║██║   ██║██║     ██╔══╝
+// ╚██████╗╚██████╔╝██║ ╚████║███████║╚██████╔╝███████╗███████╗
+//  ╚═════╝ ╚═════╝ ╚═╝  ╚═══╝╚══════╝ ╚═════╝ ╚══════╝╚══════╝
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/math32"
+)
+
+// Severity is how bad a message on the console is
+type Severity int
+
+// Values of Severity
+const (
+	Info Severity = iota
+	Warning
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "WARNING"
+	case Error:
+		return "ERROR"
+	}
+	return "INFO"
+}
+
+// Message is one thing reported on the console
+type Message struct {
+	Severity Severity
+	Text     string
+	When     time.Time
+}
+
+func (m Message) String() string {
+	return fmt.Sprintf("%s %s: %s", m.When.Format("15:04:05"), m.Severity, m.Text)
+}
+
+// Console collects the messages from anything going on, including jobs in the background,
+//   for the GUI to show. Each is also printed, as before there was a console.
+type Console struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// console is where everything reports to
+var console = &Console{}
+
+// Add reports a message
+func (c *Console) Add(sev Severity, format string, a ...interface{}) {
+	m := Message{Severity: sev, Text: fmt.Sprintf(format, a...), When: time.Now()}
+	if sev == Info {
+		fmt.Println(m.Text)
+	} else {
+		fmt.Printf("%s: %s\n", sev, m.Text)
+	}
+	c.mu.Lock()
+	c.messages = append(c.messages, m)
+	c.mu.Unlock()
+}
+
+// Errorf reports an error, something which went wrong and was not done
+func (c *Console) Errorf(format string, a ...interface{}) {
+	c.Add(Error, format, a...)
+}
+
+// Warnf reports a warning, something which went wrong but was got round
+func (c *Console) Warnf(format string, a ...interface{}) {
+	c.Add(Warning, format, a...)
+}
+
+// Infof reports something worth knowing
+func (c *Console) Infof(format string, a ...interface{}) {
+	c.Add(Info, format, a...)
+}
+
+// Since is the messages after the first n
+func (c *Console) Since(n int) []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n >= len(c.messages) {
+		return nil
+	}
+	return append([]Message{}, c.messages[n:]...)
+}
+
+// Counts is how many messages there are of each severity
+func (c *Console) Counts() map[Severity]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := map[Severity]int{}
+	for _, m := range c.messages {
+		n[m.Severity]++
+	}
+	return n
+}
+
+// Clear forgets all the messages
+func (c *Console) Clear() {
+	c.mu.Lock()
+	c.messages = nil
+	c.mu.Unlock()
+}
+
+// Sizes of the console panel
+const (
+	consoleWidth  = 600
+	consoleHeight = 160
+)
+
+// Colours of the messages in the console panel
+var severityColours = map[Severity]math32.Color{
+	Info:    {R: 0.85, G: 0.85, B: 0.85},
+	Warning: {R: 1, G: 0.8, B: 0.2},
+	Error:   {R: 1, G: 0.35, B: 0.35},
+}
+
+// ConsolePanel shows the console's messages in a scrolling list, newest at the bottom
+type ConsolePanel struct {
+	Panel  *gui.Panel
+	list   *gui.ItemScroller
+	counts *gui.Label
+	shown  int // messages already in the list
+}
+
+// NewConsolePanel makes one in the parent at x, y
+func NewConsolePanel(parent *gui.Panel, x, y float32) *ConsolePanel {
+	cp := &ConsolePanel{Panel: gui.NewPanel(consoleWidth, consoleHeight)}
+	cp.Panel.SetColor4(&math32.Color4{R: 0.1, G: 0.1, B: 0.1, A: 0.85})
+	cp.Panel.SetPosition(x, y)
+	cp.counts = gui.NewLabel("")
+	cp.counts.SetPosition(5, 4)
+	cp.Panel.Add(cp.counts)
+	clearBtn := gui.NewButton("Clear")
+	clearBtn.SetPosition(consoleWidth-50, 2)
+	clearBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		console.Clear()
+		cp.list.Clear()
+		cp.shown = 0
+		cp.counts.SetText("")
+	})
+	cp.Panel.Add(clearBtn)
+	cp.list = gui.NewVScroller(consoleWidth-10, consoleHeight-30)
+	cp.list.SetPosition(5, 25)
+	cp.Panel.Add(cp.list)
+	parent.Add(cp.Panel)
+	return cp
+}
+
+// Update adds any new messages to the list, scrolled to the newest, and counts them all.
+//   True if any of them are errors.
+func (cp *ConsolePanel) Update() bool {
+	ms := console.Since(cp.shown)
+	if len(ms) == 0 {
+		return false
+	}
+	errs := false
+	for _, m := range ms {
+		errs = errs || m.Severity == Error
+		l := gui.NewLabel(m.String())
+		c := severityColours[m.Severity]
+		l.SetColor(&c)
+		cp.list.Add(l)
+	}
+	cp.shown += len(ms)
+	cp.list.SetFirst(cp.list.Len() - 1)
+	n := console.Counts()
+	cp.counts.SetText(fmt.Sprintf("%d errors, %d warnings", n[Error], n[Warning]))
+	return errs
+}
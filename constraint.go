@@ -0,0 +1,213 @@
+package main
+
+//  ██████╗ ██████╗ ███╗   ██╗███████╗████████╗██████╗  █████╗ ██╗███╗   ██╗████████╗
+// ██╔════╝██╔═══██╗████╗  ██║██╔════╝╚══██╔══╝██╔══██╗██╔══██╗██║████╗  ██║╚══██╔══╝
+// ██║     ██║   ██║██╔██╗ ██║███████╗   ██║   ██████╔╝███████║██║██╔██╗ ██║   ██║
+// ██║     ██║   ██║██║╚██╗██║╚════██║   ██║   ██╔══██╗██╔══██║██║██║╚██╗██║   ██║
+// ╚██████╗╚██████╔╝██║ ╚████║███████║   ██║   ██║  ██║██║  ██║██║██║ ╚████║   ██║
+//  ╚═════╝ ╚═════╝ ╚═╝  ╚═══╝╚══════╝   ╚═╝   ╚═╝  ╚═╝╚═╝  ╚═╝╚═╝╚═╝  ╚═══╝   ╚═╝
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	v3 "./vec"
+)
+
+// Priorities of constraints: those with higher priority are applied later, so have the last word
+const (
+	PrioritySurface = 0  // onto the shell's surface
+	PriorityLine    = 10 // onto a line on the surface, such as a cut
+	PriorityBase    = 20 // onto the floor, which always wins
+)
+
+// ConstraintKind is a registered kind of constraint, made into a Constraint with its arguments
+type ConstraintKind struct {
+	Priority int
+	Args     int // number of arguments it takes
+	Make     func(args []float64) func(e *EShell, p v3.Vec) v3.Vec
+}
+
+// constraintKinds are all the kinds of constraint there are, by name
+var constraintKinds = map[string]ConstraintKind{
+	"OnEllipsoid": {Priority: PrioritySurface, Make: func([]float64) func(e *EShell, p v3.Vec) v3.Vec {
+		return func(e *EShell, p v3.Vec) v3.Vec {
+			return e.surface().Surface(p)
+		}
+	}},
+	"OnBase": {Priority: PriorityBase, Make: func([]float64) func(e *EShell, p v3.Vec) v3.Vec {
+		return func(e *EShell, p v3.Vec) v3.Vec {
+			return p.New(p.X(), p.Y(), e.Base)
+		}
+	}},
+	"OnBaseRing": {Priority: PriorityBase, Make: func([]float64) func(e *EShell, p v3.Vec) v3.Vec {
+		return func(e *EShell, p v3.Vec) v3.Vec {
+			if math.Hypot(p.X(), p.Y()) < v3.PlanckLength {
+				return p.New(p.X(), p.Y(), e.Base)
+			}
+			r := e.surface().SectionAt(e.Base, p)
+			return p.New(r.X(), r.Y(), e.Base)
+		}
+	}},
+	"OnPlane": {Priority: PriorityLine, Args: 6, Make: func(args []float64) func(e *EShell, p v3.Vec) v3.Vec {
+		pl := v3.NewPlane(v3.NewSimVec(args[0], args[1], args[2]), v3.NewSimVec(args[3], args[4], args[5]))
+		return func(e *EShell, p v3.Vec) v3.Vec {
+			for i := 0; i < 20; i++ { // alternate projections, converges quickly unless the plane grazes the surface
+				d := p.Subtract(pl.PointOn).Dot(pl.Normal)
+				if math.Abs(d) < v3.PlanckLength*1000 {
+					break
+				}
+				p = e.surface().Surface(p.Subtract(pl.Normal.Scale(d)))
+			}
+			return p
+		}
+	}},
+}
+
+// RegisterConstraint adds a kind of constraint, replacing any of the same name
+func RegisterConstraint(name string, k ConstraintKind) {
+	constraintKinds[name] = k
+}
+
+// Constraint is a named rule moving a vertex to where it is allowed to be, called during
+//   movement etc.
+type Constraint struct {
+	Name     string
+	Args     []float64
+	Priority int
+	apply    func(e *EShell, p v3.Vec) v3.Vec
+}
+
+// Constraints are the rules a vertex obeys, in the order they are applied
+type Constraints []*Constraint
+
+// NewConstraint makes a constraint of a registered kind
+func NewConstraint(name string, args ...float64) (*Constraint, error) {
+	k, ok := constraintKinds[name]
+	if !ok {
+		return nil, fmt.Errorf("no such constraint as %q", name)
+	}
+	if len(args) != k.Args {
+		return nil, fmt.Errorf("constraint %s takes %d arguments, not %d", name, k.Args, len(args))
+	}
+	return &Constraint{Name: name, Args: args, Priority: k.Priority, apply: k.Make(args)}, nil
+}
+
+// mustConstraint is NewConstraint for those known to be registered
+func mustConstraint(name string, args ...float64) *Constraint {
+	c, err := NewConstraint(name, args...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// The constraints without arguments, shared by every vertex they apply to
+var (
+	OnEllipsoid = mustConstraint("OnEllipsoid") // forces the vertex onto the surface of the ellipsoid
+	OnBase      = mustConstraint("OnBase")      // forces the vertex to the height of the base
+	OnBaseRing  = mustConstraint("OnBaseRing")  // forces it horizontally onto the ring where the surface meets the base
+)
+
+// OnPlane forces the vertex onto the line where the plane cuts the surface
+func OnPlane(pl v3.Plane) *Constraint {
+	a, n := pl.PointOn, pl.Normal
+	return mustConstraint("OnPlane", a.X(), a.Y(), a.Z(), n.X(), n.Y(), n.Z())
+}
+
+// Apply moves the point as the constraint requires
+func (c *Constraint) Apply(e *EShell, p v3.Vec) v3.Vec {
+	return c.apply(e, p)
+}
+
+// String is the name and arguments, from which ParseConstraint makes it again
+func (c *Constraint) String() string {
+	if len(c.Args) == 0 {
+		return c.Name
+	}
+	as := []string{}
+	for _, a := range c.Args {
+		as = append(as, strconv.FormatFloat(a, 'g', -1, 64))
+	}
+	return c.Name + "(" + strings.Join(as, ",") + ")"
+}
+
+// ParseConstraint makes a constraint from its String
+func ParseConstraint(s string) (*Constraint, error) {
+	s = strings.TrimSpace(s)
+	name, args := s, []float64{}
+	if i := strings.Index(s, "("); i >= 0 {
+		if !strings.HasSuffix(s, ")") {
+			return nil, fmt.Errorf("constraint %q has no closing bracket", s)
+		}
+		name = s[:i]
+		for _, a := range strings.Split(s[i+1:len(s)-1], ",") {
+			f, err := strconv.ParseFloat(strings.TrimSpace(a), 64)
+			if err != nil {
+				return nil, fmt.Errorf("constraint %q: %v", s, err)
+			}
+			args = append(args, f)
+		}
+	}
+	for _, c := range []*Constraint{OnEllipsoid, OnBase, OnBaseRing} {
+		if c.Name == name && len(args) == 0 {
+			return c, nil // the shared one
+		}
+	}
+	return NewConstraint(name, args...)
+}
+
+// Apply moves the point by each constraint in turn
+func (cs Constraints) Apply(e *EShell, p v3.Vec) v3.Vec {
+	for _, c := range cs {
+		p = c.Apply(e, p)
+	}
+	return p
+}
+
+// Sorted is the constraints in the order they must be applied, lowest priority first,
+//   without duplicates
+func (cs Constraints) Sorted() Constraints {
+	s := Constraints{}
+	seen := map[string]bool{}
+	for _, c := range cs {
+		if c == nil || seen[c.String()] {
+			continue
+		}
+		seen[c.String()] = true
+		s = append(s, c)
+	}
+	sort.SliceStable(s, func(i, j int) bool { return s[i].Priority < s[j].Priority })
+	return s
+}
+
+// String lists them, separated by spaces
+func (cs Constraints) String() string {
+	ss := []string{}
+	for _, c := range cs {
+		ss = append(ss, c.String())
+	}
+	return strings.Join(ss, " ")
+}
+
+// ParseConstraints makes constraints from their String
+func ParseConstraints(s string) (Constraints, error) {
+	cs := Constraints{}
+	for _, t := range strings.Fields(s) {
+		c, err := ParseConstraint(t)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+	return cs.Sorted(), nil
+}
+
+// Combine does so to two lists of constraints producing a single sensible set: each once,
+//   in priority order
+func Combine(c1, c2 Constraints) Constraints {
+	return append(append(Constraints{}, c1...), c2...).Sorted()
+}
@@ -0,0 +1,99 @@
+package main
+
+//  ██████╗ ██████╗ ███████╗████████╗
+// ██╔════╝██╔═══██╗██╔════╝╚══██╔══╝
+// ██║     ██║   ██║███████╗   ██║
+// ██║     ██║   ██║╚════██║   ██║
+// ╚██████╗╚██████╔╝███████║   ██║
+//  ╚═════╝ ╚═════╝ ╚══════╝   ╚═╝
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+
+	cam "./cam"
+)
+
+// CostReport prices making the emitted panels: the sheets they are nested on, the time on the
+//   machine to cut them and the consumables it uses up
+type CostReport struct {
+	Panels      int // nested, so priced
+	TooBig      int // not nested, so not priced
+	Stocks      []SheetsCost
+	Material    float64 // of all the sheets
+	Machine     cam.Machine
+	Cut         cam.CutEstimate
+	MachineCost float64 // of the time on the machine
+	Consumables float64
+	Total       float64
+}
+
+// SheetsCost is the price of the sheets of one material and gauge
+type SheetsCost struct {
+	Type      cam.InputSheetType
+	Stock     cam.StockSheet
+	Sheets    int
+	SheetCost float64 // of one sheet
+	Cost      float64 // of all of them
+}
+
+// CostReport nests the emitted panels on the stock for their materials and prices cutting them
+//   out with the shell's process, the machine set up for the thickness of each
+func (e *EShell) CostReport() CostReport {
+	c := CostReport{Machine: e.Machine()}
+	for _, sh := range e.Nest() {
+		c.Panels += len(sh.Panels) - len(sh.TooBig)
+		c.TooBig += len(sh.TooBig)
+		mat := cam.Materials[sh.Type.Material]
+		sc := SheetsCost{Type: sh.Type, Stock: sh.Stock, Sheets: len(sh.Nests), SheetCost: mat.StockCost(sh.Gauge, sh.Stock)}
+		sc.Cost = float64(sc.Sheets) * sc.SheetCost
+		c.Stocks = append(c.Stocks, sc)
+		c.Material += sc.Cost
+		m := e.MachineFor(sh.Gauge)
+		cut := sh.Estimate(m)
+		mc, cc := cut.Cost(m)
+		c.Cut = c.Cut.Add(cut)
+		c.MachineCost += mc
+		c.Consumables += cc
+	}
+	c.Total = c.Material + c.MachineCost + c.Consumables
+	return c
+}
+
+func (c CostReport) String() string {
+	s := fmt.Sprintf("Cost of %d panels: %.0f\n", c.Panels, c.Total)
+	for _, sc := range c.Stocks {
+		s += fmt.Sprintf("   Material: %s %s at %.2f, %.0f\n", sheetName(sc.Type), sc.Stock.Count(sc.Sheets), sc.SheetCost, sc.Cost)
+	}
+	s += fmt.Sprintf("   %s: %s, %.0f\n", c.Machine.Name, c.Cut, c.MachineCost)
+	s += fmt.Sprintf("   Consumables: %.0f\n", c.Consumables)
+	for _, u := range c.Cut.Consumables(c.Machine) {
+		s += fmt.Sprintf("      %s\n", u)
+	}
+	if c.TooBig > 0 {
+		s += fmt.Sprintf("ERROR: %d panels too big for a sheet are not priced\n", c.TooBig)
+	}
+	return s
+}
+
+// CSV writes the report, one line per thing paid for
+func (c CostReport) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Item", "Quantity", "Unit", "Unit cost", "Cost"})
+	hours := c.Cut.Time / 3600
+	for _, sc := range c.Stocks {
+		w.Write([]string{fmt.Sprintf("%s %s", sheetName(sc.Type), sc.Stock.Name), fmt.Sprint(sc.Sheets), "sheet",
+			fmt.Sprintf("%.2f", sc.SheetCost), fmt.Sprintf("%.2f", sc.Cost)})
+	}
+	w.Write([]string{c.Machine.Name, fmt.Sprintf("%.2f", hours), "hour",
+		fmt.Sprintf("%.2f", c.Machine.HourlyRate), fmt.Sprintf("%.2f", c.MachineCost)})
+	for _, u := range c.Cut.Consumables(c.Machine) {
+		w.Write([]string{u.Name, fmt.Sprintf("%.2f", u.Used), "each",
+			fmt.Sprintf("%.2f", u.Cost), fmt.Sprintf("%.2f", u.Used*u.Cost)})
+	}
+	w.Write([]string{"Total", "", "", "", fmt.Sprintf("%.2f", c.Total)})
+	w.Flush()
+	return buf.String(), w.Error()
+}
@@ -0,0 +1,63 @@
+package main
+
+// ██████╗ ███████╗███████╗██╗ ██████╗ ███╗   ██╗
+// ██╔══██╗██╔════╝██╔════╝██║██╔════╝ ████╗  ██║
+// ██║  ██║█████╗  ███████╗██║██║  ███╗██╔██╗ ██║
+// ██║  ██║██╔══╝  ╚════██║██║██║   ██║██║╚██╗██║
+// ██████╔╝███████╗███████║██║╚██████╔╝██║ ╚████║
+// ╚═════╝ ╚══════╝╚══════╝╚═╝ ╚═════╝ ╚═╝  ╚═══╝
+
+import (
+	"math"
+
+	cam "./cam"
+	ell "./ellipsoid"
+)
+
+// Design is what a shell is generated from, as typed in the inputs
+type Design struct {
+	Length, Width, Height float64 // m, at the midplane, and top to bottom
+	Headroom              float64 // m, the midplane is raised to give it
+	Panel, BasePanel      float64 // m, desired size of panels at the apex and the base
+	EggTop                float64 // ratio of the semi-height above the midplane to that below, 1 for an ellipsoid
+	VestibuleL            float64 // m, beyond the shell on +X, none if 0
+	VestibuleR            float64 // m
+}
+
+// MinHeight is the lowest the shell can be for the headroom
+func (d Design) MinHeight() float64 {
+	return d.Headroom * 1.25
+}
+
+// NewShell sets up a shell for the design, with the settings of like, ready for MakeMesh
+func (d Design) NewShell(like *EShell, tolerance float64) *EShell {
+	height := math.Max(d.Height, d.MinHeight()) // so the floor, headroom below the top, is inside
+	up, down := height/2, height/2              // semi-heights above and below the midplane
+	if d.EggTop > 0 && d.EggTop != 1 {
+		up, down = height*d.EggTop/(1+d.EggTop), height/(1+d.EggTop)
+	}
+
+	ellip := ell.Ellipsoid{}
+	ellip.Set(d.Width/2, d.Length/2, up)
+	e := &EShell{E: ellip, Process: like.Process, Tabs: like.Tabs, CommonLine: like.CommonLine, SheetMarks: like.SheetMarks,
+		Units: like.Units, Weather: like.Weather, Display: like.Display, Colouring: like.Colouring,
+		PanelColouring: like.PanelColouring, AutoCompact: like.AutoCompact}
+	if up != down {
+		e.Shape = ell.NewOvoid(d.Width/2, d.Length/2, up, down)
+	}
+
+	e.Base = up - d.Headroom
+	if d.VestibuleL > 0 {
+		out := e.surface().SectionAt(e.Base, ell.X).X()
+		e.Shape = ell.Compound{Main: e.surface(), Rule: ell.CompoundBlend, Blend: vestibuleBlend,
+			Extension: ell.Vestibule{Radius: d.VestibuleR, Length: out + d.VestibuleL, Floor: e.Base}}
+	}
+	e.PanelSize = d.Panel
+	if d.BasePanel != d.Panel {
+		e.SizeFunc = LinearPanelSize(e.Base, d.BasePanel, ellip.H, d.Panel)
+	}
+	e.Tolerance = tolerance
+	e.FlangeWidth = 0.05 // 50 mm flanges when doubled over
+	e.Sheet = cam.InputSheetType{Material: "Stainless304", Gauge: "20ga"}
+	return e
+}
@@ -8,11 +8,14 @@ package main
 // ╚═════╝  ╚═════╝  ╚═════╝ ╚═╝  ╚═╝
 
 import (
+	"fmt"
 	"math"
 
 	ell "./ellipsoid"
 	gl "./gl"
 	v3 "./vec"
+
+	"github.com/g3n/engine/math32"
 )
 
 // DoorKind is what basic type of door is it
@@ -27,6 +30,23 @@ const (
 	DoubleSwing
 )
 
+// String names the kind of door
+func (k DoorKind) String() string {
+	switch k {
+	case Hole:
+		return "hole"
+	case Rollup:
+		return "roll-up"
+	case TiltUp:
+		return "tilt-up"
+	case SingleSwing:
+		return "single swing"
+	case DoubleSwing:
+		return "double swing"
+	}
+	return "unknown"
+}
+
 // DoorOpens says how it will open
 type DoorOpens int
 
@@ -53,7 +73,12 @@ type Door struct {
 	Width, Height v3.Meters
 	Opens         DoorOpens
 	Kind          DoorKind
-	Clamps        []Clamp // How is it clamped?
+	Clamps        []Clamp     // How is it clamped?
+	FlangeStyle   FlangeStyle // flange put round the opening when applied
+	Applied       bool        // has the opening been cut in the shell?
+	Opening       []*Edge     // edges around the opening, once applied
+	Tilt          v3.Radians  // leans the top of the opening in towards the middle
+	Depth         float64     // how far the opening goes in; 0 for as far as the middle
 	//	Cutter        v3.Cutter
 	Shell *EShell
 }
@@ -77,11 +102,11 @@ func NewDoor(eshell *EShell, width v3.Meters, height v3.Meters) *Door {
 
 	clps := []Clamp{ClampTangent}
 	d := Door{Width: width, Height: height, Clamps: clps,
-		Kind: Hole, Opens: AlwaysOpen}
+		Kind: Hole, Opens: AlwaysOpen, FlangeStyle: FStyleDoorMk1}
 
 	p := v3.Y.Scale(eshell.E.W + 1).Add(v3.Z.Scale(eshell.Base * 1.3))
 	//	a := v3.Deg90
-	d.Cutter = v3.NewCutter(width, height, p, v3.Y.Scale(-1))
+	d.Cutter = d.makeCutter(p, v3.Y.Scale(-1))
 	d.Shell = eshell
 	// doorPatch = v3.NewPatch(, v3.Y.Scale(-1), doorWide, doorHigh)
 
@@ -89,16 +114,76 @@ func NewDoor(eshell *EShell, width v3.Meters, height v3.Meters) *Door {
 
 }
 
+// String names the clamp
+func (c Clamp) String() string {
+	switch c {
+	case ClampNone:
+		return "None"
+	case ClampFaceX:
+		return "Face X"
+	case ClampFaceY:
+		return "Face Y"
+	case ClampTangent:
+		return "Tangent"
+	case ClampCenter:
+		return "Face Center"
+	case ClampOnX:
+		return "On X Axis"
+	case ClampOnY:
+		return "On Y Axis"
+	}
+	return "Unknown"
+}
+
 // Translate by a vector
 func (d *Door) Translate(v v3.Vec) *Door {
 	//	fmt.Printf("Delta %s\n", v)
-	d.Cutter = v3.NewCutter(d.Width, d.Height, d.Corner.Add(v), d.Normal)
+	d.Cutter = d.makeCutter(d.Corner.Add(v), d.facing())
+	d.DoClamps()
 	return d
 }
 
 // RotateZ rotates about Z axis
 func (d *Door) RotateZ(a v3.Radians) *Door {
-	d.Cutter = v3.NewCutter(d.Width, d.Height, d.Corner, d.Normal.RotateZ(a))
+	d.Cutter = d.makeCutter(d.Corner, d.facing().RotateZ(a))
+	d.DoClamps()
+	return d
+}
+
+// Heading is the level direction the door faces, anticlockwise from +X
+func (d *Door) Heading() v3.Radians {
+	f := d.facing()
+	return v3.Radians(math.Atan2(f.Y(), f.X()))
+}
+
+// Face turns the door about its bottom left corner to face the heading
+func (d *Door) Face(a v3.Radians) *Door {
+	return d.RotateZ(a - d.Heading())
+}
+
+// HasClamp is true if the door is clamped by c
+func (d *Door) HasClamp(c Clamp) bool {
+	for _, dc := range d.Clamps {
+		if dc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// SetClamp turns a clamp on or off, then reapplies the clamps
+func (d *Door) SetClamp(c Clamp, on bool) *Door {
+	cs := []Clamp{}
+	for _, dc := range d.Clamps {
+		if dc != c {
+			cs = append(cs, dc)
+		}
+	}
+	if on {
+		cs = append(cs, c)
+	}
+	d.Clamps = cs
+	d.DoClamps()
 	return d
 }
 
@@ -122,34 +207,254 @@ var clampFuncs = map[Clamp]clampFunc{
 		return pos, v3.Y
 	},
 	ClampTangent: func(e ell.Ellipsoid, pos v3.Vec, norm v3.Vec) (v3.Vec, v3.Vec) {
-		a := v3.Radians(math.Atan(pos.X() / pos.Y()))
-		return pos, e.NormalAt(a)
+		// Inwards normal of the ellipse through the point, at the midplane
+		n := v3.NewSimVec(-pos.X()/e.LL, -pos.Y()/e.WW, 0)
+		if n.Length() < v3.PlanckLength {
+			return pos, norm
+		}
+		return pos, n.Normalized()
+	},
+	ClampCenter: func(e ell.Ellipsoid, pos v3.Vec, norm v3.Vec) (v3.Vec, v3.Vec) {
+		n := v3.NewSimVec(-pos.X(), -pos.Y(), 0)
+		if n.Length() < v3.PlanckLength {
+			return pos, norm
+		}
+		return pos, n.Normalized()
 	},
+	ClampOnX: func(e ell.Ellipsoid, pos v3.Vec, norm v3.Vec) (v3.Vec, v3.Vec) {
+		return v3.NewSimVec(pos.X(), 0, pos.Z()), norm
+	},
+	ClampOnY: func(e ell.Ellipsoid, pos v3.Vec, norm v3.Vec) (v3.Vec, v3.Vec) {
+		return v3.NewSimVec(0, pos.Y(), pos.Z()), norm
+	},
+}
+
+// positionClamp is true for clamps which move the door rather than turn it, these go first
+func positionClamp(c Clamp) bool {
+	return c == ClampOnX || c == ClampOnY
 }
 
-// DoClamps applies the clamps
+// DoClamps applies the clamps to the bottom center of the door, position clamps first
 func (d *Door) DoClamps() {
-	p := d.Cutter.Patch.Corner
-	n := d.Cutter.Normal
-	for _, c := range d.Clamps {
-		p, n = clampFuncs[c](d.Shell.E, p, n)
+	if d.Shell == nil {
+		return
+	}
+	p := d.Cutter.Patch.Corner.Add(d.Wide.Scale(0.5))
+	n := d.facing()
+	for _, first := range []bool{true, false} {
+		for _, c := range d.Clamps {
+			if positionClamp(c) == first {
+				p, n = clampFuncs[c](d.Shell.E, p, n)
+			}
+		}
+	}
+	wide := v3.Z.Cross(n).Scale(-float64(d.Width)) // as NewCutter does
+	d.Cutter = d.makeCutter(p.Subtract(wide.Scale(0.5)), n)
+}
+
+// facing is the level direction the door faces into the shell, whatever its tilt
+func (d *Door) facing() v3.Vec {
+	return v3.Z.Cross(d.Wide.Normalized())
+}
+
+// makeCutter makes the cutter for the door with its bottom left corner at corner, facing the
+//   level direction n: a plain one running to the middle, unless the door is tilted or has a
+//   depth, when it is a box cutter turned about its bottom edge
+func (d *Door) makeCutter(corner, n v3.Vec) *v3.Cutter {
+	if d.Tilt == 0 && d.Depth == 0 {
+		return v3.NewCutter(d.Width, d.Height, corner, n)
+	}
+	n = v3.NewSimVec(n.X(), n.Y(), 0).Normalized()
+	across := n.Cross(v3.Z).Normalized()
+	lean := v3.RotationAbout(across, -d.Tilt)
+	frame := v3.Frame(corner, across, lean.Direction(n), lean.Direction(v3.Z))
+	return v3.NewBoxCutter(d.Width, d.Height, 0, d.depthAt(corner), frame)
+}
+
+// depthAt is how far in the door at corner cuts
+func (d *Door) depthAt(corner v3.Vec) float64 {
+	if d.Depth == 0 {
+		return math.Hypot(corner.X(), corner.Y())
 	}
-	d.Cutter = v3.NewCutter(d.Width, d.Height, p, n)
+	return d.Depth
+}
+
+// SetTilt tilts the door, keeping its place
+func (d *Door) SetTilt(a v3.Radians) *Door {
+	d.Tilt = a
+	d.Cutter = d.makeCutter(d.Corner, d.facing())
+	return d
+}
+
+// SetDepth sets how far in the door cuts, 0 for as far as the middle
+func (d *Door) SetDepth(depth float64) *Door {
+	d.Depth = math.Max(0, depth)
+	d.Cutter = d.makeCutter(d.Corner, d.facing())
+	return d
 }
 
 //pos := v3.NewSimVec(e.W*v3.Sin(a)*1.1, e.L*v3.Cos(a)*1.1, bf).Subtract(c.Wide.Scale(0.5))
 
 // Display generates the lines to display a door
-func (d *Door) Display(e *EShell) []gl.ColourLine {
+func (d *Door) Display(e *EShell, colour math32.Color) []gl.ColourLine {
 
 	ls := []gl.ColourLine{}
 
-	ls = append(ls, gl.LinesForPatch(d.Cutter.Patch, true, gl.Blue)...)
+	ls = append(ls, gl.LinesForPatch(d.Cutter.Patch, true, colour)...)
 
 	for _, p := range d.Cutter.Walls {
-		ls = append(ls, gl.LinesForPatch(p, true, gl.Blue)...)
-		ls = append(ls, e.CutWithPatch(p)...)
+		ls = append(ls, gl.LinesForPatch(p, true, colour)...)
+		ls = append(ls, e.PreviewCutWithPatch(p)...)
+	}
+
+	if d.Kind == Rollup {
+		ls = append(ls, d.RollupLines()...)
+	}
+	if d.Swinging() {
+		ls = append(ls, d.SwingLines()...)
 	}
 
 	return ls
 }
+
+// Orbit moves the door around the Z axis through the origin, turning it to match
+func (d *Door) Orbit(a v3.Radians) *Door {
+	d.Cutter = d.makeCutter(d.Corner.RotateZ(a), d.facing().RotateZ(a))
+	d.DoClamps()
+	return d
+}
+
+// Center is the bottom center of the door
+func (d *Door) Center() v3.Vec {
+	return d.Corner.Add(d.Wide.Scale(0.5))
+}
+
+// DragTo orbits the door so that it is in the direction of p from the Z axis, keeping its
+//   distance and height
+func (d *Door) DragTo(p v3.Vec) *Door {
+	c := d.Center()
+	if math.Hypot(p.X(), p.Y()) < v3.PlanckLength || math.Hypot(c.X(), c.Y()) < v3.PlanckLength {
+		return d
+	}
+	a := math.Atan2(p.Y(), p.X()) - math.Atan2(c.Y(), c.X())
+	return d.Orbit(v3.Radians(a))
+}
+
+// DoorAt finds the door whose face is hit by the segment, nil if none
+func (e *EShell) DoorAt(seg v3.Segment) *Door {
+	for _, d := range e.Doors {
+		if _, hit := d.Cutter.Patch.ParaIntersectSegment(seg); hit {
+			return d
+		}
+	}
+	return nil
+}
+
+// Area is the area of the opening
+func (d *Door) Area() float64 {
+	return float64(d.Width * d.Height)
+}
+
+// AddDoor makes a new door, placed a quarter turn around from the last one
+func (e *EShell) AddDoor(name string, width v3.Meters, height v3.Meters) *Door {
+	d := NewDoor(e, width, height)
+	d.Name = name
+	d.Orbit(v3.Deg90 * v3.Radians(len(e.Doors)))
+	e.Doors = append(e.Doors, d)
+	return d
+}
+
+// RemoveDoor takes a door out of the design
+func (e *EShell) RemoveDoor(d *Door) {
+	var ds []*Door
+	for _, od := range e.Doors {
+		if od != d {
+			ds = append(ds, od)
+		}
+	}
+	e.Doors = ds
+}
+
+// DoorLines makes the display lines for all the doors, highlighting the selected one
+func (e *EShell) DoorLines(selected *Door) []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	for _, d := range e.Doors {
+		colour := gl.Blue
+		if d == selected {
+			colour = gl.Yellow
+		}
+		ls = append(ls, d.Display(e, colour)...)
+	}
+	return ls
+}
+
+// Apply cuts the opening for the door in the shell, dropping the panels inside it, and
+//   flanges the edges around the hole, and the tracks of a roll-up door
+func (d *Door) Apply() []*Edge {
+	if d.Applied {
+		console.Errorf("%s has already been applied", d.Name)
+		return d.Opening
+	}
+	e := d.Shell
+	d.Opening = e.CutWithCutter(d.Cutter)
+	d.Applied = true
+	for _, ed := range d.Opening {
+		if d.FlangeStyle == FStyleNone {
+			ed.Treatment = ETreatSmooth
+			continue
+		}
+		ed.Treatment = ETreatFlange
+		f := Flange{Edge: ed, Style: d.FlangeStyle}
+		e.Flanges = append(e.Flanges, f.Shape(d.Normal))
+	}
+	if d.Kind == Rollup {
+		for _, f := range d.RollupTracks() {
+			if f.Edge != nil {
+				e.Flanges = append(e.Flanges, f)
+			}
+		}
+	}
+	return d.Opening
+}
+
+// CutDoors applies all the doors not yet applied, returning the edges around the new openings
+func (e *EShell) CutDoors() []*Edge {
+	var opening []*Edge
+	for _, d := range e.Doors {
+		if !d.Applied {
+			opening = append(opening, d.Apply()...)
+		}
+	}
+	return opening
+}
+
+// ReapplyDoors cuts the doors which were applied to a previous mesh into a new one
+func (e *EShell) ReapplyDoors() {
+	for _, d := range e.Doors {
+		if d.Applied {
+			d.Applied = false
+			d.Opening = nil
+			d.Apply()
+		}
+	}
+}
+
+// DoorStats describes the doors for the stats panel
+func (e EShell) DoorStats() string {
+	s := fmt.Sprintf("Doors: %d\n", len(e.Doors))
+	for _, d := range e.Doors {
+		s += fmt.Sprintf("   %-12s %-12s %s x %s facing %3.0f°\n", d.Name, d.Kind,
+			e.Display.Len(float64(d.Width)), e.Display.Len(float64(d.Height)),
+			v3.Rad2Deg(v3.Radians(math.Atan2(d.Normal.Y(), d.Normal.X()))))
+		if d.Applied {
+			s += fmt.Sprintf("      applied, %d edges with %s flanges\n", len(d.Opening), d.FlangeStyle)
+		}
+		if d.Kind == Rollup {
+			s += d.RollupStats()
+		}
+		if d.Swinging() {
+			s += d.SwingStats()
+		}
+	}
+	return s
+}
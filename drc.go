@@ -0,0 +1,210 @@
+package main
+
+// ██████╗ ██████╗  ██████╗
+// ██╔══██╗██╔══██╗██╔════╝
+// ██║  ██║██████╔╝██║
+// ██║  ██║██╔══██╗██║
+// ██████╔╝██║  ██║╚██████╗
+// ╚═════╝ ╚═╝  ╚═╝ ╚═════╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	gl "./gl"
+	v3 "./vec"
+)
+
+// Limits the design rules check against
+const (
+	drcMinEdge      = 0.05 // m, shortest panel edge that can be hemmed and handled
+	drcHoleEdgeDias = 1.5  // hole centres at least this many diameters from the edge of a flange
+	drcMarkSize     = 0.05 // m, half the size of the cross marking a point in the viewer
+)
+
+// DRCCategory groups the rules in the report
+type DRCCategory int
+
+// Values of DRCCategory
+const (
+	DRCGeometry    DRCCategory = iota // the shape of the panels
+	DRCFabrication                    // cutting and bending them
+	DRCAccess                         // doors and getting in and out
+)
+
+// DRCCategories lists them in the order they are reported
+var DRCCategories = []DRCCategory{DRCGeometry, DRCFabrication, DRCAccess}
+
+func (c DRCCategory) String() string {
+	switch c {
+	case DRCGeometry:
+		return "Geometry"
+	case DRCFabrication:
+		return "Fabrication"
+	case DRCAccess:
+		return "Access"
+	}
+	return "Unknown"
+}
+
+// DRCRule is one design rule, Check finds everything in the shell which breaks it
+type DRCRule struct {
+	Name     string
+	Category DRCCategory
+	Check    func(e *EShell) []DRCItem
+}
+
+// DRCItem is something which breaks a rule, with whatever geometry it is about
+type DRCItem struct {
+	Rule    *DRCRule
+	Message string
+	Panel   *Panel
+	Edge    *Edge
+	Door    *Door
+	At      v3.Vec // a point, eg a hole, nil if none
+}
+
+// DRCRules are checked by DRC, in order; append to them to add more
+var DRCRules = []*DRCRule{
+	{"Min panel edge", DRCGeometry, drcShortEdges},
+	{"Max sagitta", DRCGeometry, drcSagittas},
+	{"Panel fits stock", DRCFabrication, drcOversize},
+	{"Hole to edge", DRCFabrication, drcHoleEdges},
+	{"Door clearance", DRCAccess, drcDoorClearance},
+}
+
+// drcShortEdges are panel edges shorter than drcMinEdge
+func drcShortEdges(e *EShell) []DRCItem {
+	var is []DRCItem
+	for _, ed := range aliveEdges(e.Edges) {
+		if l := ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position).Length(); l < drcMinEdge {
+			is = append(is, DRCItem{Message: fmt.Sprintf("Edge %d is %.0fmm, under %.0fmm", ed.Serial, l*m2mm, drcMinEdge*m2mm), Edge: ed})
+		}
+	}
+	return is
+}
+
+// drcSagittas are panels which bulge from the shell by more than sagittaTolerance
+func drcSagittas(e *EShell) []DRCItem {
+	var is []DRCItem
+	for _, ps := range e.Sagittas(sagittaTolerance).Over() {
+		is = append(is, DRCItem{Message: fmt.Sprintf("Panel %s has a sagitta of %.1fmm, over %.1fmm", PanelLabel(ps.Panel), ps.Sagitta*m2mm, sagittaTolerance*m2mm),
+			Panel: ps.Panel, At: ps.At})
+	}
+	return is
+}
+
+// drcOversize are panels whose cut patterns will not nest on the stock sheet they are cut from
+func drcOversize(e *EShell) []DRCItem {
+	var is []DRCItem
+	for _, p := range alivePanels(e.Panels) {
+		part := p.CutPattern()
+		ns := e.nesting(p.Stock())
+		if _, big := cam.NestParts([]cam.Drawing{part}, ns); len(big) > 0 {
+			lo, hi := part.Bounds()
+			is = append(is, DRCItem{Message: fmt.Sprintf("Panel %s is %.0f x %.0fmm, too big for %.0f x %.0fmm stock",
+				PanelLabel(p), hi.X-lo.X, hi.Y-lo.Y, ns.Width, ns.Height), Panel: p})
+		}
+	}
+	return is
+}
+
+// drcHoleEdges are holes in flanges closer than drcHoleEdgeDias diameters to an edge of the flange
+func drcHoleEdges(e *EShell) []DRCItem {
+	var is []DRCItem
+	for _, f := range e.Flanges {
+		if !f.Edge.Alive || len(f.Corners) < 3 {
+			continue
+		}
+		for i, h := range f.Holes {
+			d := math.Inf(1)
+			for j := range f.Corners {
+				d = math.Min(d, v3.DistancePointSegment(h, v3.NewSegment2Ends(f.Corners[j], f.Corners[(j+1)%len(f.Corners)])))
+			}
+			if d < drcHoleEdgeDias*f.Dias[i] {
+				is = append(is, DRCItem{Message: fmt.Sprintf("Hole %d of the flange on edge %d is %.1fmm from its edge, under %.1fmm",
+					i+1, f.Edge.Serial, d*m2mm, drcHoleEdgeDias*f.Dias[i]*m2mm), Edge: f.Edge, At: h})
+			}
+		}
+	}
+	return is
+}
+
+// drcDoorClearance are door leaves which hit the shell before they open fully
+func drcDoorClearance(e *EShell) []DRCItem {
+	var is []DRCItem
+	for _, d := range e.Doors {
+		for i, l := range d.Leaves() {
+			if max := d.SwingClearance(l); max < swingOpenAngle {
+				is = append(is, DRCItem{Message: fmt.Sprintf("%s leaf %d opens only %.0f°, not %.0f°", d.Name, i+1, max, swingOpenAngle), Door: d})
+			}
+		}
+	}
+	return is
+}
+
+// DRCReport is everything found by the rules, in the order of the categories
+type DRCReport []DRCItem
+
+// DRC checks the shell against every rule
+func (e *EShell) DRC() DRCReport {
+	var r DRCReport
+	for _, c := range DRCCategories {
+		for _, rule := range DRCRules {
+			if rule.Category != c {
+				continue
+			}
+			for _, it := range rule.Check(e) {
+				it.Rule = rule
+				r = append(r, it)
+			}
+		}
+	}
+	return r
+}
+
+func (it DRCItem) String() string {
+	return fmt.Sprintf("%s: %s", it.Rule.Name, it.Message)
+}
+
+// String lists the items under their categories, and a count of them
+func (r DRCReport) String() string {
+	s := fmt.Sprintf("DRC: %d problems\n", len(r))
+	for _, c := range DRCCategories {
+		n := 0
+		for _, it := range r {
+			if it.Rule.Category == c {
+				if n == 0 {
+					s += fmt.Sprintf("   %s\n", c)
+				}
+				s += fmt.Sprintf("      %s\n", it)
+				n++
+			}
+		}
+	}
+	return s
+}
+
+// Lines highlight the geometry of the item in the viewer: its panel's outline, its edge, its
+//   door, and a cross at its point
+func (it DRCItem) Lines() []gl.ColourLine {
+	var ls []gl.ColourLine
+	if p := it.Panel; p != nil {
+		for i := range p.Corners {
+			ls = append(ls, gl.ColourLine{Start: p.Corners[i].Position, End: p.Corners[(i+1)%len(p.Corners)].Position, Colour: &gl.Yellow})
+		}
+	}
+	if ed := it.Edge; ed != nil {
+		ls = append(ls, gl.ColourLine{Start: ed.Vertices[0].Position, End: ed.Vertices[1].Position, Colour: &gl.Red})
+	}
+	if d := it.Door; d != nil {
+		ls = append(ls, d.Display(d.Shell, gl.Red)...)
+	}
+	if it.At != nil {
+		for _, ax := range []v3.Vec{v3.X, v3.Y, v3.Z} {
+			ls = append(ls, gl.ColourLine{Start: it.At.Subtract(ax.Scale(drcMarkSize)), End: it.At.Add(ax.Scale(drcMarkSize)), Colour: &gl.Fuchsia})
+		}
+	}
+	return ls
+}
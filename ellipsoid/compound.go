@@ -0,0 +1,159 @@
+package ellipsoid
+
+import (
+	"math"
+
+	v3 "../vec"
+)
+
+// How rays are followed to the compound surface
+const (
+	compoundRaySteps     = 400    // along the ray, to find where it crosses the surface
+	compoundRayTolerance = 0.0001 // m, the crossing is then bisected to
+)
+
+// CompoundRule is how the surfaces of a Compound are combined where they meet
+type CompoundRule int
+
+// Compound rules
+const (
+	CompoundUnion CompoundRule = iota // the outer of the two, with a sharp crease where they cross
+	CompoundBlend                     // the outer of the two, with the crease rounded over Blend
+)
+
+// Vestibule is a half cylinder lying on the floor, its axis running horizontally out from
+//   under the Z axis, capped square at its outer end
+type Vestibule struct {
+	Bearing float64 // radians anticlockwise from +X, direction the axis runs out
+	Radius  float64 // of the half cylinder
+	Length  float64 // from the Z axis to the end cap
+	Floor   float64 // height of the axis
+}
+
+// Exit finds how far along the ray from o along unit dir it leaves the vestibule, false if it misses
+func (v Vestibule) Exit(o, dir v3.Vec) (float64, bool) {
+	a := v3.NewSimVec(math.Cos(v.Bearing), math.Sin(v.Bearing), 0)
+	q := o.Subtract(v3.NewSimVec(0, 0, v.Floor))
+	lo, hi := math.Inf(-1), math.Inf(1)
+
+	// Inside the cylinder: |perpendicular part|^2 <= r^2
+	qp := q.Subtract(a.Scale(q.Dot(a)))
+	dp := dir.Subtract(a.Scale(dir.Dot(a)))
+	A, B, C := dp.Dot(dp), 2*qp.Dot(dp), qp.Dot(qp)-v.Radius*v.Radius
+	if A < v3.PlanckLength {
+		if C > 0 {
+			return 0, false
+		}
+	} else {
+		disc := B*B - 4*A*C
+		if disc < 0 {
+			return 0, false
+		}
+		lo = math.Max(lo, (-B-math.Sqrt(disc))/(2*A))
+		hi = math.Min(hi, (-B+math.Sqrt(disc))/(2*A))
+	}
+
+	// Each of the flat faces: f0 + t*f1 >= 0
+	clip := func(f0, f1 float64) {
+		if math.Abs(f1) < v3.PlanckLength {
+			if f0 < 0 {
+				lo, hi = 1, 0
+			}
+			return
+		}
+		t := -f0 / f1
+		if f1 > 0 {
+			lo = math.Max(lo, t)
+		} else {
+			hi = math.Min(hi, t)
+		}
+	}
+	clip(q.Z(), dir.Z())                 // above the floor
+	clip(q.Dot(a), dir.Dot(a))           // out from the Z axis
+	clip(v.Length-q.Dot(a), -dir.Dot(a)) // inside the end cap
+	if lo > hi || hi <= 0 || math.IsInf(hi, 0) {
+		return 0, false
+	}
+	return hi, true
+}
+
+// Compound is a Main surface with a vestibule joined on. Along any ray from the origin the outer
+//   of the two surfaces wins, so the origin must be inside Main.
+type Compound struct {
+	Main      Surfacer
+	Extension Vestibule
+	Rule      CompoundRule
+	Blend     float64 // distance over which CompoundBlend rounds the crease
+}
+
+// combine picks the distance out to the compound surface given the distances to each surface
+func (c Compound) combine(main, ext float64) float64 {
+	if c.Rule != CompoundBlend || c.Blend <= 0 {
+		return math.Max(main, ext)
+	}
+	h := math.Max(c.Blend-math.Abs(main-ext), 0) / c.Blend // polynomial smooth maximum
+	return math.Max(main, ext) + h*h*c.Blend/4
+}
+
+// along finds the compound surface along the ray from o in direction dir, given the point p
+//   on Main along the same ray
+func (c Compound) along(o, dir, p v3.Vec) v3.Vec {
+	d := dir.Normalized()
+	m := p.Subtract(o).Length()
+	ext, ok := c.Extension.Exit(o, d)
+	if !ok {
+		ext = 0
+	}
+	return o.Add(d.Scale(c.combine(m, ext)))
+}
+
+// Surface finds where the vector, assumed to start at the origin, intersects with the compound surface
+func (c Compound) Surface(dir v3.Vec) v3.Vec {
+	return c.along(v3.NewSimVec(0, 0, 0), dir, c.Main.Surface(dir))
+}
+
+// SectionAt finds the point on the compound surface at height z, out from the Z axis along dir horizontally
+func (c Compound) SectionAt(z float64, dir v3.Vec) v3.Vec {
+	h := v3.NewSimVec(dir.X(), dir.Y(), 0)
+	return c.along(v3.NewSimVec(0, 0, z), h, c.Main.SectionAt(z, h))
+}
+
+// PointDistant finds a point on the compound surface along g from p, L from p in a straight line
+func (c Compound) PointDistant(p v3.Vec, g v3.Vec, L float64, tolerance float64) v3.Vec {
+	return pointDistant(c.Surface, p, g, L, tolerance)
+}
+
+// Inside is true for points nearer the origin than the compound surface in their direction
+func (c Compound) Inside(p v3.Vec) bool {
+	l := p.Length()
+	return l < v3.PlanckLength || l < c.Surface(p).Length()
+}
+
+// RayHit finds the first point on the compound surface at or beyond p along dir, stepping
+//   along the ray until it crosses in or out, then bisecting
+func (c Compound) RayHit(p, dir v3.Vec) (v3.Vec, bool) {
+	d := dir.Normalized()
+	reach := c.Extension.Length + c.Extension.Radius + math.Abs(c.Extension.Floor) // beyond all of the surface from the origin
+	for _, a := range []v3.Vec{X, Y, Z, X.Scale(-1), Y.Scale(-1), Z.Scale(-1)} {
+		reach = math.Max(reach, c.Main.Surface(a).Length())
+	}
+	far := p.Length() + reach
+	step := far / compoundRaySteps
+	in := c.Inside(p)
+	for t := step; t <= 2*far; t += step {
+		if c.Inside(p.Add(d.Scale(t))) == in {
+			continue
+		}
+		lo, hi := t-step, t
+		for hi-lo > compoundRayTolerance {
+			mid := (lo + hi) / 2
+			if c.Inside(p.Add(d.Scale(mid))) == in {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return p.Add(d.Scale(hi)), true
+	}
+	return nil, false
+}
@@ -70,6 +70,35 @@ func (e Ellipsoid) Surface(dir v3.Vec) v3.Vec {
 	return v.Scale(k)
 }
 
+// rayRoots are how far along the ray from p in direction dir it meets the ellipsoid, in units
+//   of dir, nearest first; none if it misses
+func (e Ellipsoid) rayRoots(p, dir v3.Vec) []float64 {
+	ax, ay, az := p.X()*e.oLL, p.Y()*e.oWW, p.Z()*e.oHH
+	a := dir.X()*dir.X()*e.oLL + dir.Y()*dir.Y()*e.oWW + dir.Z()*dir.Z()*e.oHH
+	b := 2 * (dir.X()*ax + dir.Y()*ay + dir.Z()*az)
+	c := p.X()*ax + p.Y()*ay + p.Z()*az - 1
+	disc := b*b - 4*a*c
+	if a < v3.PlanckLength || disc < 0 {
+		return nil
+	}
+	return []float64{(-b - math.Sqrt(disc)) / (2 * a), (-b + math.Sqrt(disc)) / (2 * a)}
+}
+
+// RayHit finds the first point on the ellipsoid at or beyond p along dir
+func (e Ellipsoid) RayHit(p, dir v3.Vec) (v3.Vec, bool) {
+	for _, t := range e.rayRoots(p, dir) {
+		if t >= 0 {
+			return p.Add(dir.Scale(t)), true
+		}
+	}
+	return nil, false
+}
+
+// Inside is true for points within the ellipsoid
+func (e Ellipsoid) Inside(p v3.Vec) bool {
+	return p.X()*p.X()*e.oLL+p.Y()*p.Y()*e.oWW+p.Z()*p.Z()*e.oHH < 1
+}
+
 // NormalAt returns a vector length 1 which is normal to the ellipsoid at the midplane point
 //   defined by the angle a from the y axis following mathematical convention -- x axis is
 //   zero angle, angle increases anti-clockwise
@@ -77,8 +106,29 @@ func (e Ellipsoid) NormalAt(a v3.Radians) v3.Vec {
 	return v3.NewSimVec(v3.Cos(a)*e.L*e.AspectRatio, v3.Sin(a)*e.W, 0).Normalized()
 }
 
+// SemiAxesAt gives the semi-axes of the horizontal section through the ellipsoid at height z
+func (e Ellipsoid) SemiAxesAt(z float64) (float64, float64) {
+	r := math.Sqrt(math.Max(0, 1-z*z*e.oHH))
+	return e.L * r, e.W * r
+}
+
+// SectionAt finds the point on the ellipsoid at height z, out from the Z axis along dir horizontally
+func (e Ellipsoid) SectionAt(z float64, dir v3.Vec) v3.Vec {
+	a, b := e.SemiAxesAt(z)
+	k := math.Sqrt(dir.X()*dir.X()/(a*a) + dir.Y()*dir.Y()/(b*b))
+	if k < v3.PlanckLength || math.IsInf(k, 0) || math.IsNaN(k) {
+		return v3.NewSimVec(0, 0, z)
+	}
+	return v3.NewSimVec(dir.X()/k, dir.Y()/k, z)
+}
+
 // PointDistant -- find a point s along the line starting at p defined by g projected onto e that is L from p (straight line) +- no more than l*tolerance
 func (e Ellipsoid) PointDistant(p v3.Vec, g v3.Vec, L float64, tolerance float64) v3.Vec {
+	return pointDistant(e.Surface, p, g, L, tolerance)
+}
+
+// pointDistant does PointDistant for any surface, given its Surface func
+func pointDistant(surface func(v3.Vec) v3.Vec, p v3.Vec, g v3.Vec, L float64, tolerance float64) v3.Vec {
 
 	P := p.Length()
 	PP := P * P
@@ -102,7 +152,7 @@ func (e Ellipsoid) PointDistant(p v3.Vec, g v3.Vec, L float64, tolerance float64
 	// s = p -pN*k + vN*M
 	s := p.Subtract(pN.Scale(K)).Add(vN.Scale(M))
 
-	estimate := e.Surface(s)
+	estimate := surface(s)
 	diff := estimate.Subtract(p)
 	actL := diff.Length()
 
@@ -112,7 +162,7 @@ func (e Ellipsoid) PointDistant(p v3.Vec, g v3.Vec, L float64, tolerance float64
 		//		fmt.Printf("est %s;    Wanted %f got %f (δ %f)\n", estimate, L, actL, delta)
 		diff = estimate.Subtract(p)
 		actL = diff.Length()
-		estimate = e.Surface(p.Add(diff.Scale(L / actL)))
+		estimate = surface(p.Add(diff.Scale(L / actL)))
 		delta = math.Abs(estimate.Subtract(p).Length() - L)
 		tries++
 	}
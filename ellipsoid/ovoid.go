@@ -0,0 +1,78 @@
+package ellipsoid
+
+import (
+	"math"
+
+	v3 "../vec"
+)
+
+// Surfacer is a closed surface round the origin, which a shell can be tessellated over
+type Surfacer interface {
+	Surface(dir v3.Vec) v3.Vec                                            // where the ray from the origin along dir meets it
+	PointDistant(p v3.Vec, g v3.Vec, L float64, tolerance float64) v3.Vec // a point on it L from p in the direction g
+	SectionAt(z float64, dir v3.Vec) v3.Vec                               // the point at height z out from the Z axis along dir, horizontally
+	RayHit(p, dir v3.Vec) (v3.Vec, bool)                                  // the first point on it at or beyond p along dir, if any
+	Inside(p v3.Vec) bool                                                 // is p within it
+}
+
+// Ovoid is an egg: the top half of one ellipsoid on the bottom half of another with the same
+//   midplane section, so the vertical semi-axis differs above and below the midplane
+type Ovoid struct {
+	Upper, Lower Ellipsoid
+}
+
+// NewOvoid makes one with semi-axes l along X and w along Y, up above the midplane and down below it
+func NewOvoid(l, w, up, down float64) Ovoid {
+	o := Ovoid{}
+	o.Upper.Set(l, w, up)
+	o.Lower.Set(l, w, down)
+	return o
+}
+
+// half is the ellipsoid for the half of the ovoid containing points at height z
+func (o Ovoid) half(z float64) Ellipsoid {
+	if z >= 0 {
+		return o.Upper
+	}
+	return o.Lower
+}
+
+// Surface finds where the vector, assumed to start at the origin, intersects with the surface of the ovoid
+func (o Ovoid) Surface(dir v3.Vec) v3.Vec {
+	return o.half(dir.Z()).Surface(dir)
+}
+
+// SectionAt finds the point on the ovoid at height z, out from the Z axis along dir horizontally
+func (o Ovoid) SectionAt(z float64, dir v3.Vec) v3.Vec {
+	return o.half(z).SectionAt(z, dir)
+}
+
+// PointDistant finds a point on the ovoid along g from p, L from p in a straight line, as for Ellipsoid
+func (o Ovoid) PointDistant(p v3.Vec, g v3.Vec, L float64, tolerance float64) v3.Vec {
+	return pointDistant(o.Surface, p, g, L, tolerance)
+}
+
+// RayHit finds the first point on the ovoid at or beyond p along dir, each half's ellipsoid
+//   only counting on its own side of the midplane
+func (o Ovoid) RayHit(p, dir v3.Vec) (v3.Vec, bool) {
+	best := math.Inf(1)
+	for _, t := range o.Upper.rayRoots(p, dir) {
+		if t >= 0 && t < best && p.Z()+t*dir.Z() >= 0 {
+			best = t
+		}
+	}
+	for _, t := range o.Lower.rayRoots(p, dir) {
+		if t >= 0 && t < best && p.Z()+t*dir.Z() < 0 {
+			best = t
+		}
+	}
+	if math.IsInf(best, 1) {
+		return nil, false
+	}
+	return p.Add(dir.Scale(best)), true
+}
+
+// Inside is true for points within the ovoid
+func (o Ovoid) Inside(p v3.Vec) bool {
+	return o.half(p.Z()).Inside(p)
+}
@@ -0,0 +1,160 @@
+package main
+
+// ███████╗███╗   ███╗██╗████████╗
+// ██╔════╝████╗ ████║██║╚══██╔══╝
+// █████╗  ██╔████╔██║██║   ██║
+// ██╔══╝  ██║╚██╔╝██║██║   ██║
+// ███████╗██║ ╚═╝ ██║██║   ██║
+// ╚══════╝╚═╝     ╚═╝╚═╝   ╚═╝
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PanelFilter picks panels, e.g. those to emit in one phase of a build
+type PanelFilter func(p *Panel) bool
+
+// EmitKind is a way of choosing the panels to emit
+type EmitKind int
+
+// EmitKind values
+const (
+	EmitAll     EmitKind = iota // every panel
+	EmitCourses                 // a range of courses, counted up from the base
+	EmitZRange                  // panels whose centers are in a range of heights above the base
+	EmitSet                     // a set of panels by serial number
+)
+
+// EmitKinds lists them in order for the GUI
+var EmitKinds = []EmitKind{EmitAll, EmitCourses, EmitZRange, EmitSet}
+
+// String names the kind
+func (k EmitKind) String() string {
+	switch k {
+	case EmitAll:
+		return "All"
+	case EmitCourses:
+		return "Courses"
+	case EmitZRange:
+		return "Height"
+	case EmitSet:
+		return "Panels"
+	}
+	return "Unknown"
+}
+
+// Course is the number of the course the panel is in, counting up from 0 at the base
+func (e *EShell) Course(p *Panel) int {
+	n := 0
+	for z := e.Base; z < e.E.H; z += e.courseHeight(z) {
+		if p.Center.Z() < z+e.courseHeight(z) {
+			return n
+		}
+		n++
+	}
+	return n
+}
+
+// InCourses picks the panels in courses lo to hi inclusive
+func (e *EShell) InCourses(lo, hi int) PanelFilter {
+	return func(p *Panel) bool {
+		c := e.Course(p)
+		return c >= lo && c <= hi
+	}
+}
+
+// InZRange picks the panels whose centers are from lo to hi above the base, m
+func (e *EShell) InZRange(lo, hi float64) PanelFilter {
+	return func(p *Panel) bool {
+		z := p.Center.Z() - e.Base
+		return z >= lo && z <= hi
+	}
+}
+
+// InSet picks the panels with the given serial numbers
+func InSet(serials []int) PanelFilter {
+	set := map[int]bool{}
+	for _, s := range serials {
+		set[s] = true
+	}
+	return func(p *Panel) bool {
+		return set[p.Serial]
+	}
+}
+
+// EmitFilter makes the filter of the given kind from its argument as typed: courses as "2" or
+//   "1-3", heights in the shell's Display units as "0-6.5", and panels as "12,40,41"
+func (e *EShell) EmitFilter(k EmitKind, arg string) (PanelFilter, error) {
+	arg = strings.TrimSpace(arg)
+	span := func() (float64, float64, error) {
+		parts := strings.SplitN(arg, "-", 2)
+		lo, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s range %q: %v", k, arg, err)
+		}
+		hi := lo
+		if len(parts) == 2 {
+			if hi, err = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err != nil {
+				return 0, 0, fmt.Errorf("%s range %q: %v", k, arg, err)
+			}
+		}
+		return lo, hi, nil
+	}
+	switch k {
+	case EmitAll:
+		return func(p *Panel) bool { return true }, nil
+	case EmitCourses:
+		lo, hi, err := span()
+		if err != nil {
+			return nil, err
+		}
+		return e.InCourses(int(lo), int(hi)), nil
+	case EmitZRange:
+		lo, hi, err := span()
+		if err != nil {
+			return nil, err
+		}
+		return e.InZRange(e.Display.ToM(lo), e.Display.ToM(hi)), nil
+	case EmitSet:
+		var serials []int
+		for _, f := range strings.FieldsFunc(arg, func(r rune) bool { return r == ',' || r == ' ' }) {
+			s, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, fmt.Errorf("panel number %q: %v", f, err)
+			}
+			serials = append(serials, s)
+		}
+		return InSet(serials), nil
+	}
+	return nil, fmt.Errorf("unknown way of choosing panels: %d", k)
+}
+
+// SetEmit marks the alive panels the filter picks to be emitted, and the rest not. Returns
+//   the number to be emitted.
+func (e *EShell) SetEmit(f PanelFilter) int {
+	n := 0
+	for _, p := range e.Panels {
+		if !p.Alive {
+			continue
+		}
+		p.Update(e)
+		p.Emit = f(p)
+		if p.Emit {
+			n++
+		}
+	}
+	return n
+}
+
+// Emitted lists the alive panels to be emitted, for export
+func (e *EShell) Emitted() []*Panel {
+	var ps []*Panel
+	for _, p := range e.Panels {
+		if p.Alive && p.Emit {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
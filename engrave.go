@@ -0,0 +1,120 @@
+package main
+
+// ███████╗███╗   ██╗ ██████╗ ██████╗  █████╗ ██╗   ██╗███████╗
+// ██╔════╝████╗  ██║██╔════╝ ██╔══██╗██╔══██╗██║   ██║██╔════╝
+// █████╗  ██╔██╗ ██║██║  ███╗██████╔╝███████║██║   ██║█████╗
+// ██╔══╝  ██║╚██╗██║██║   ██║██╔══██╗██╔══██║╚██╗ ██╔╝██╔══╝
+// ███████╗██║ ╚████║╚██████╔╝██║  ██║██║  ██║ ╚████╔╝ ███████╗
+// ╚══════╝╚═╝  ╚═══╝ ╚═════╝ ╚═╝  ╚═╝╚═╝  ╚═╝  ╚═══╝  ╚══════╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	v3 "./vec"
+)
+
+// Sizes for drawings imported onto panels
+const (
+	engraveDrop     = 0.6 // of the panel's inradius an engraving is put below the labels
+	engraveFill     = 0.9 // of the circle a drawing is fitted in
+	outlineCutDepth = 0.3 // m, of the prism cutting an outline, half each side of the panel
+)
+
+// level is the direction across a panel which is as horizontal as it allows, as vents are put
+func (p *Panel) level() v3.Vec {
+	x := v3.Z.Cross(p.Normal)
+	if x.Length() < v3.PlanckLength { // panel is flat, at the apex
+		_, x, _ = p.Frame()
+	}
+	return x.Normalized()
+}
+
+// Engrave marks the drawing, in mm, on the panel: scaled to the width, m, centered on at and
+//   level across it, read from outside. Every path of it is a mark, none are cut.
+func (p *Panel) Engrave(d cam.Drawing, at v3.Vec, width float64) {
+	_, x, y := p.Frame()
+	lx := p.level()
+	f := d.Fitted(width*m2mm, cam.Origin).Transformed(math.Atan2(lx.Dot(y), lx.Dot(x)), p.Flat(at))
+	for _, path := range f.Paths {
+		for i := range path.Segments {
+			path.Segments[i].Kind = cam.MarkPath
+		}
+		p.Engravings = append(p.Engravings, path)
+	}
+}
+
+// fitWidth is the width to scale the drawing to for it to fit in a circle of the radius
+func fitWidth(d cam.Drawing, radius float64) float64 {
+	lo, hi := d.Bounds()
+	diag := hi.Subtract(lo).Length()
+	if diag == 0 {
+		return 0
+	}
+	return 2 * radius * engraveFill * (hi.X - lo.X) / diag
+}
+
+// EngraveFitted marks the drawing on the panel below the labels in the middle, as big as fits
+//   in the rest of its incircle there
+func (p *Panel) EngraveFitted(d cam.Drawing) {
+	r := p.Inradius()
+	down := p.level().Cross(p.Normal).Normalized()
+	p.Engrave(d, p.Incenter().Add(down.Scale(r*engraveDrop)), fitWidth(d, r*(1-engraveDrop)))
+}
+
+// OutlinePatch is the outline of the drawing, in mm, scaled to the width, m, centered on center
+//   in the plane square to normal and level across it. It can only be cut by CutWithPrism if it
+//   is convex, so it is an error if it is not, or if the drawing has no closed outline.
+func OutlinePatch(d cam.Drawing, center, normal v3.Vec, width float64) (v3.PolyPatch, error) {
+	pts := d.Fitted(width*m2mm, cam.Origin).Outline()
+	if len(pts) < 3 {
+		return v3.PolyPatch{}, fmt.Errorf("%s has no closed outline", d.Name)
+	}
+	if !convex2(pts) {
+		return v3.PolyPatch{}, fmt.Errorf("Outline of %s is not convex, cannot cut it", d.Name)
+	}
+	x := v3.Z.Cross(normal)
+	if x.Length() < v3.PlanckLength {
+		x, _ = v3.NewPlane(center, normal).Basis()
+	}
+	x = x.Normalized()
+	y := normal.Cross(x).Normalized()
+	var corners []v3.Vec
+	for _, pt := range pts {
+		corners = append(corners, center.Add(x.Scale(pt.X/m2mm)).Add(y.Scale(pt.Y/m2mm)))
+	}
+	return v3.NewPolyPatch(corners...), nil
+}
+
+// convex2 is true if the corners, going round anticlockwise, never turn clockwise
+func convex2(pts []cam.Vec2) bool {
+	n := len(pts)
+	for i := range pts {
+		a, b, c := pts[i], pts[(i+1)%n], pts[(i+2)%n]
+		ab, bc := b.Subtract(a), c.Subtract(b)
+		if ab.X*bc.Y-ab.Y*bc.X < -cam.IntersectTolerance*(ab.Length()+bc.Length()) {
+			return false
+		}
+	}
+	return true
+}
+
+// CutOutline cuts an opening the shape of the drawing's outline, scaled to the width, m, in the
+//   middle of the panel, through the shell there; 0 for as big as fits in the panel. Returns
+//   the edges around the opening.
+func (e *EShell) CutOutline(d cam.Drawing, p *Panel, width float64) ([]*Edge, error) {
+	if !p.Alive {
+		return nil, fmt.Errorf("Panel %d is not alive, cannot cut an opening", p.Serial)
+	}
+	p.Update(e)
+	if width == 0 {
+		width = fitWidth(d, p.Inradius())
+	}
+	c := p.Incenter().Subtract(p.Normal.Scale(outlineCutDepth / 2))
+	pp, err := OutlinePatch(d, c, p.Normal, width)
+	if err != nil {
+		return nil, err
+	}
+	return e.CutWithPrism(pp, outlineCutDepth), nil
+}
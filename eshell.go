@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"math"
 	"sort"
 
@@ -17,8 +16,6 @@ import (
 	"github.com/g3n/engine/material"
 	"github.com/g3n/engine/math32"
 	"github.com/g3n/engine/util/helper"
-
-	"github.com/ztrue/tracerr"
 )
 
 // Global consts
@@ -28,17 +25,74 @@ var (
 
 // EShell is a set of panels covering an ellipsoid from its apex (+Z) to some horizontal plane (Z=base)
 type EShell struct {
-	E           ell.Ellipsoid // Ellipsoid shape on which this is based
-	Base        float64       // Z=base is bottom plane
-	Vertices    []*Vertex     // all of them
-	Edges       []*Edge       // all of them
-	Panels      []*Panel      // all of them
-	PanelSize   float64       // desired panelsize during initial tessellation
-	Tolerance   float64       // tolerance during panel edge length estimation
-	FlangeWidth float64       // normal flange width expected for this design
-	Step        int           //moribund?
-	Cuts        []CutSegment  //TODO
-	DebugLines  []DebugLine   //TODO
+	E              ell.Ellipsoid      // Ellipsoid shape on which this is based
+	Shape          ell.Surfacer       // surface the panels are tessellated over, E if nil
+	Base           float64            // Z=base is bottom plane
+	Vertices       []*Vertex          // all of them
+	Edges          []*Edge            // all of them
+	Panels         []*Panel           // all of them
+	PanelSize      float64            // desired panelsize during initial tessellation
+	SizeFunc       PanelSizeFunc      // if set, overrides PanelSize according to height
+	Tolerance      float64            // tolerance during panel edge length estimation
+	FlangeWidth    float64            // normal flange width expected for this design
+	Sheet          cam.InputSheetType // default sheet the panels are cut from
+	Process        cam.CutProcess     // how the panels are cut out
+	Tabs           cam.Tabs           // micro-joints left in the panel outlines when cut, none if zero
+	CommonLine     bool               // panels are nested butted up, cutting edges in line once for both
+	SheetMarks     cam.MarkSet        // put on the nested sheets in each output, none if nil
+	Units          cam.Unit           // CAM files are written in
+	Step           int                //moribund?
+	Vents          []*Vent            // vent accessories
+	Doors          []*Door            // door openings
+	Blocks         []*Block           // interior layout, standing on the floor
+	Skylight       *Skylight          // opening at the zenith, if any
+	Flanges        []*Flange          // details of edges with ETreatFlange
+	HemOverrides   map[*Edge]*Panel   // panels which get the open hem on a seam, whatever the rule says
+	Weather        LoadCase           // snow and wind for the load report
+	Colouring      LineColouring      // what the colours of the wireframe show
+	PanelColouring PanelColouring     // what the colours of the panels show
+	Display        DisplayUnits       // what the GUI and stats show lengths in
+	Progress       *Progress          // told how MakeMesh and Relax are getting on, nil if no one is watching
+	Liner          *EShell            // insulation liner inside the shell, if made
+	AutoCompact    bool               // drop dead vertices, edges and panels after every cut
+	Oriented       bool               // panels are wound anticlockwise seen from outside, and their normals follow
+	bvh            *panelBVH          // the alive panels by where they are, nil when it must be rebuilt
+	Cuts           []CutSegment       //TODO
+	DebugLines     []DebugLine        //TODO
+}
+
+// surface is what the shell is tessellated over
+func (e *EShell) surface() ell.Surfacer {
+	if e.Shape != nil {
+		return e.Shape
+	}
+	return e.E
+}
+
+// PanelSizeFunc gives the desired panel size at height z
+type PanelSizeFunc func(z float64) float64
+
+// LinearPanelSize returns a PanelSizeFunc that varies linearly from sizeLow at zLow
+//   to sizeHigh at zHigh, and is constant beyond those heights
+func LinearPanelSize(zLow, sizeLow, zHigh, sizeHigh float64) PanelSizeFunc {
+	return func(z float64) float64 {
+		if z <= zLow {
+			return sizeLow
+		}
+		if z >= zHigh {
+			return sizeHigh
+		}
+		return sizeLow + (sizeHigh-sizeLow)*(z-zLow)/(zHigh-zLow)
+	}
+}
+
+// PanelSizeAt returns the desired panel size at height z, falling back to desiredL
+//   if there is no SizeFunc
+func (e *EShell) PanelSizeAt(z float64, desiredL float64) float64 {
+	if e.SizeFunc == nil {
+		return desiredL
+	}
+	return e.SizeFunc(z)
 }
 
 // EShellMesh is just the g3n mesh
@@ -67,12 +121,8 @@ type DebugLine struct {
 //  ╚████╔╝ ███████╗██║  ██║   ██║   ███████╗██╔╝ ██╗
 //   ╚═══╝  ╚══════╝╚═╝  ╚═╝   ╚═╝   ╚══════╝╚═╝  ╚═╝
 
-// Constraint is a function that enforces constraints on vertices
-//type Constraint func(e *EShell, p v3.Vec) v3.Vec
-
-// Constraints are functions that enforce constraints on vertices,
-//   called during movement etc.
-type Constraints []*func(e *EShell, p v3.Vec) v3.Vec
+// Tessellation gives at most 6 panels around a vertex, cuts and collapses may add a couple more
+const maxPanelsPerVertex = 8
 
 // Vertex is a point where panels meet
 type Vertex struct {
@@ -87,24 +137,11 @@ type Vertex struct {
 	Constraints Constraints
 }
 
-// OnEllipsoid forces the vertex to be on the surface of the ellipsoid
-var OnEllipsoid = func(e *EShell, p v3.Vec) v3.Vec {
-	return e.E.Surface(p)
-}
-
-// OnBase forces the vertex to be at the height of the base
-var OnBase = func(e *EShell, p v3.Vec) v3.Vec {
-	p.SetZ(e.Base)
-	return p
-}
-
 // Move moves a vertex to a new position, while respecting contraints. Returns actual new position.
 func (v *Vertex) Move(p v3.Vec) v3.Vec {
-	dest := p
-	for _, cst := range v.Constraints {
-		dest = (*cst)(v.Shell, dest)
-	}
+	dest := v.Constraints.Apply(v.Shell, p)
 	v.Position = dest
+	v.Shell.touch()
 	return dest
 }
 
@@ -119,30 +156,22 @@ func (v *Vertex) ComputeNormal() {
 	v.Normal = tot.Normalized()
 }
 
+// OnBoundary is true iff the vertex is on an edge with only one live panel
+func (v *Vertex) OnBoundary() bool {
+	for _, ed := range aliveEdges(v.Edges) {
+		if len(alivePanels(ed.Panels)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
 // NiceString is a human readable one
 func (v *Vertex) NiceString() string {
 	s := fmt.Sprintf("Vertex %d is at %s", v.Serial, v.Position.String())
 	return s
 }
 
-// Combine does so to two lists of constraints producing a single sensible set
-func Combine(c1, c2 Constraints) Constraints {
-	c3 := c1
-	for _, c := range c2 {
-		found := false
-		for _, d := range c1 {
-			if c != d {
-				found = true
-				break
-			}
-		}
-		if !found {
-			c3 = append(c3, c)
-		}
-	}
-	return c3
-}
-
 // ███████╗██╗      █████╗ ███╗   ██╗ ██████╗ ███████╗
 // ██╔════╝██║     ██╔══██╗████╗  ██║██╔════╝ ██╔════╝
 // █████╗  ██║     ███████║██╔██╗ ██║██║  ███╗█████╗
@@ -155,11 +184,30 @@ type FlangeStyle int
 
 // FlangeStyle values
 const (
-	FStyleNone      FlangeStyle = iota // no flange
-	FStyleGroundMk1                    // Simple ground flanges with holes for bolts or ground anchors, for structures up to 600 sq ft
-	FStyleDoorMk1                      // Simple door flange for smallish doors (up to 8'x8')
+	FStyleNone            FlangeStyle = iota // no flange
+	FStyleGroundMk1                          // Simple ground flanges with holes for bolts or ground anchors, for structures up to 600 sq ft
+	FStyleDoorMk1                            // Simple door flange for smallish doors (up to 8'x8')
+	FStyleRollupTrackMk1                     // Vertical flange carrying the track of a roll-up door
+	FStyleSkylightCurbMk1                    // Vertical curb round a skylight opening
 )
 
+// String names the flange style
+func (s FlangeStyle) String() string {
+	switch s {
+	case FStyleNone:
+		return "None"
+	case FStyleGroundMk1:
+		return "Ground Mk1"
+	case FStyleDoorMk1:
+		return "Door Mk1"
+	case FStyleRollupTrackMk1:
+		return "Roll-up Track Mk1"
+	case FStyleSkylightCurbMk1:
+		return "Skylight Curb Mk1"
+	}
+	return "Unknown"
+}
+
 // Flange is a rectangular flappy thing attached to an edge
 type Flange struct {
 	Edge    *Edge       // which edge this flange is attached to
@@ -171,6 +219,132 @@ type Flange struct {
 	Dias    []float64   // and the diameters of the holes, m
 }
 
+// FlangeSpec gives the sizes for a style of flange
+type FlangeSpec struct {
+	Depth       float64 // m, from the edge to the far side of the flange
+	HolePitch   float64 // m, max spacing of holes along the flange, 0 for no holes
+	HoleDia     float64 // m
+	EndDistance float64 // m, holes start this far from each end, 0 to spread them evenly
+	Vertical    bool    // stands straight up rather than following the panels
+	Flat        bool    // lies horizontal, eg on the slab, rather than following the panels
+}
+
+// FlangeSpecs are the sizes used for each style of flange, change them to suit
+var FlangeSpecs = map[FlangeStyle]FlangeSpec{
+	FStyleGroundMk1:       {Depth: 0.075, HolePitch: groundAnchorPitch, HoleDia: groundAnchorDia, EndDistance: groundAnchorEnd, Flat: true},
+	FStyleDoorMk1:         {Depth: 0.05, HolePitch: 0.2, HoleDia: 0.006},
+	FStyleRollupTrackMk1:  {Depth: 0.075, HolePitch: 0.3, HoleDia: 0.008, Vertical: true},
+	FStyleSkylightCurbMk1: {Depth: 0.1, HolePitch: 0.15, HoleDia: 0.006, Vertical: true},
+}
+
+// Shape works out the corners and holes of the flange, which turns off its edge in the
+//   direction dir, using the sizes for its style
+func (f *Flange) Shape(dir v3.Vec) *Flange {
+	spec := FlangeSpecs[f.Style]
+	a, b := f.Edge.Vertices[0].Position, f.Edge.Vertices[1].Position
+	dir = dir.Normalized()
+	off := dir.Scale(spec.Depth)
+	f.Depth = spec.Depth
+	f.Normal = b.Subtract(a).Cross(dir).Normalized()
+	f.Corners = []v3.Vec{a, b, b.Add(off), a.Add(off)}
+	f.Holes = nil
+	f.Dias = nil
+	run := b.Subtract(a)
+	l := run.Length()
+	ats := pitchPositions(l, spec.HolePitch, spec.EndDistance) // fractions along the edge
+	for _, t := range ats {
+		f.Holes = append(f.Holes, a.Add(run.Scale(t)).Add(off.Scale(0.5)))
+		f.Dias = append(f.Dias, spec.HoleDia)
+	}
+	return f
+}
+
+// Display generates the lines to show the outline of a flange and crosses at its holes
+func (f *Flange) Display() []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	for i := range f.Corners {
+		ls = append(ls, gl.ColourLine{Start: f.Corners[i], End: f.Corners[(i+1)%len(f.Corners)], Colour: &gl.Aqua})
+	}
+	if len(f.Corners) == 4 {
+		across := f.Corners[1].Subtract(f.Corners[0]).Normalized()
+		for i, h := range f.Holes {
+			r := across.Scale(f.Dias[i] / 2)
+			ls = append(ls, gl.ColourLine{Start: h.Subtract(r), End: h.Add(r), Colour: &gl.Aqua})
+		}
+	}
+	return ls
+}
+
+// FlangeOf finds the flange details for an edge, nil if it has none. Roll-up tracks only hang
+//   off their edge, so are not its flange.
+func (e *EShell) FlangeOf(ed *Edge) *Flange {
+	for _, f := range e.Flanges {
+		if f.Edge == ed && f.Style != FStyleRollupTrackMk1 {
+			return f
+		}
+	}
+	return nil
+}
+
+// onBaseTolerance is how close to the base a vertex must be to count as on it
+const onBaseTolerance = 1e-6 // m
+
+// GenerateFlanges makes the flanges for every edge treated with ETreatFlange. Each turns in
+//   towards the origin, square to its panel, or bisecting the two panels of a seam. Edges
+//   without a flange yet get a ground flange on the base or a door flange elsewhere. Roll-up
+//   tracks are kept as they are.
+func (e *EShell) GenerateFlanges() int {
+	var fs []*Flange
+	for _, f := range e.Flanges {
+		if f.Style == FStyleRollupTrackMk1 && f.Edge.Alive { // already shaped by their door
+			fs = append(fs, f)
+		}
+	}
+	for _, ed := range aliveEdges(e.Edges) {
+		if ed.Treatment != ETreatFlange {
+			continue
+		}
+		f := e.FlangeOf(ed)
+		if f == nil {
+			f = &Flange{Edge: ed, Style: FStyleDoorMk1}
+			if e.onBase(ed) {
+				f.Style = FStyleGroundMk1
+			}
+		}
+		var dir v3.Vec = v3.Z
+		mid := ed.Vertices[0].Position.Add(ed.Vertices[1].Position).Scale(0.5)
+		switch spec := FlangeSpecs[f.Style]; {
+		case spec.Flat:
+			dir = ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position).Cross(v3.Z)
+			if dir.Dot(mid) > 0 { // point it inwards
+				dir = dir.Scale(-1)
+			}
+		case !spec.Vertical:
+			ps := alivePanels(ed.Panels)
+			if len(ps) == 0 {
+				continue
+			}
+			dir = v3.NewSimVec(0, 0, 0)
+			for _, p := range ps {
+				p.Update(e)
+				dir = dir.Add(p.Normal)
+			}
+			if dir.Dot(mid) > 0 { // point it inwards
+				dir = dir.Scale(-1)
+			}
+			along := ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position).Normalized()
+			dir = dir.Subtract(along.Scale(dir.Dot(along))) // square to the edge
+			if dir.Length() < v3.PlanckLength {
+				console.Errorf("Edge %d has no direction for a flange", ed.Serial)
+				continue
+			}
+		}
+		fs = append(fs, f.Shape(dir))
+	}
+	e.Flanges = fs
+	return len(fs)
+}
+
 // ███████╗██████╗  ██████╗ ███████╗
 // ██╔════╝██╔══██╗██╔════╝ ██╔════╝
 // █████╗  ██║  ██║██║  ███╗█████╗
@@ -189,6 +363,7 @@ type Edge struct {
 	Along     v3.Vec        // Vector along the edge from vertices[0] to vertices[1], not normalized
 	Length    float64       // length of the edge
 	Tension   float64       // negative is pull, positive is push
+	Target    float64       // length the edge is driven towards during relaxation, 0 = shell default
 	Shell     *EShell       // shell its part of
 	Alive     bool          // still part of display?
 	Treatment EdgeTreatment // what type if edge should it be?
@@ -212,7 +387,9 @@ func (ed *Edge) Update(e *EShell) {
 	if !ed.Alive {
 		return
 	}
-	ed.Along = ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position)
+	along := v3.V3(ed.Vertices[1].Position).Subtract(v3.V3(ed.Vertices[0].Position))
+	ed.Along = along.Sim()
+	ed.Length = along.Length()
 }
 
 // OtherEnd -- finds the vertex of the end other than the one supplied
@@ -229,9 +406,7 @@ func (ed Edge) From(v *Vertex) v3.Vec {
 	fr := ed.Along
 	if ed.Vertices[0].Serial != v.Serial {
 		if ed.Vertices[1].Serial != v.Serial {
-			err := tracerr.Errorf("Geometry error: vertex %d is not on edge %d at all", v.Serial, ed.Serial)
-			tracerr.PrintSourceColor(err, 5, 2)
-			log.Fatal(err)
+			console.Errorf("Geometry: vertex %d is not on edge %d at all", v.Serial, ed.Serial)
 		}
 		fr = fr.Scale(-1)
 	}
@@ -285,6 +460,8 @@ type Panel struct {
 	SubPanelOf  *Panel             // serial number of panel from which this one was derived
 	Kind        PanelType          // is this a simple, or complex, panel to render?
 	Material    *cam.Material      // what material should it be made from?
+	Gauge       cam.GaugeID        // and what gauge of it, empty for the shell's default
+	Engravings  []cam.Path         // marks imported onto it, mm in its Frame
 }
 
 // Types of accessory on a panel
@@ -299,19 +476,21 @@ func (p *Panel) Update(e *EShell) {
 	if !p.Alive {
 		return
 	}
-	crx := p.Edges[0].Along.Cross(p.Edges[1].Along)
+	crx := v3.V3(p.Edges[0].Along).Cross(v3.V3(p.Edges[1].Along))
 	p.Area = crx.Length() / 2
-	if crx.Dot(p.Corners[0].Position) > 0 {
-		p.Normal = crx.Normalized().(v3.SimVec)
+	if e.Oriented && len(p.Corners) == 3 {
+		p.Normal = p.windingNormal3().Sim()
+	} else if crx.Dot(v3.V3(p.Corners[0].Position)) > 0 {
+		p.Normal = crx.Normalized().Sim()
 	} else {
-		p.Normal = crx.Normalized().Scale(-1).(v3.SimVec)
+		p.Normal = crx.Normalized().Scale(-1).Sim()
 	}
 	if len(p.Corners) > 0 {
-		t := v3.Zero
+		var t v3.Vec3
 		for _, c := range p.Corners {
-			t = t.Add(c.Position).(v3.SimVec)
+			t = t.Add(v3.V3(c.Position))
 		}
-		p.Center = t.Scale(1 / float64(len(p.Corners))).(v3.SimVec)
+		p.Center = t.Scale(1 / float64(len(p.Corners))).Sim()
 	}
 
 }
@@ -336,6 +515,147 @@ func (p Panel) STLString() string {
 		p.Corners[2].Position.Stl())
 }
 
+// Frame returns a 2D coordinate system in the plane of the panel: origin at the first corner,
+//   x along the first edge, and y perpendicular to it, in the plane
+func (p *Panel) Frame() (origin, x, y v3.Vec) {
+	pl := p.Plane()
+	x, y = pl.BasisAlong(p.Corners[1].Position.Subtract(pl.PointOn))
+	return pl.PointOn, x, y
+}
+
+// Plane is the one the panel lies in, through its first corner, with its normal
+func (p *Panel) Plane() v3.Plane {
+	return v3.NewPlane(p.Corners[0].Position, p.Normal)
+}
+
+// Flat returns the position of a point, projected onto the panel, in the panel's Frame, in mm
+func (p *Panel) Flat(pt v3.Vec) cam.Vec2 {
+	o, x, y := p.Frame()
+	d := pt.Subtract(o)
+	return cam.NewVec2(d.Dot(x)*m2mm, d.Dot(y)*m2mm)
+}
+
+// Incenter is the center of the largest circle that fits in the panel
+func (p *Panel) Incenter() v3.Vec {
+	a := p.Corners[0].Position
+	b := p.Corners[1].Position
+	c := p.Corners[2].Position
+	la := b.Subtract(c).Length() // lengths of sides opposite each corner
+	lb := c.Subtract(a).Length()
+	lc := a.Subtract(b).Length()
+	return a.Scale(la).Add(b.Scale(lb)).Add(c.Scale(lc)).Scale(1 / (la + lb + lc))
+}
+
+// Inradius is the radius of the largest circle that fits in the panel
+func (p *Panel) Inradius() float64 {
+	perim := 0.0
+	for i := range p.Corners {
+		perim += p.Corners[(i+1)%3].Position.Subtract(p.Corners[i].Position).Length()
+	}
+	return 2 * p.Area / perim
+}
+
+// OtherCorner returns the corner of the panel that is neither a nor b
+func (p *Panel) OtherCorner(a, b *Vertex) *Vertex {
+	for _, c := range p.Corners {
+		if c != a && c != b {
+			return c
+		}
+	}
+	return nil
+}
+
+// HasEdge is true iff the given edge is one of the panel's
+func (p *Panel) HasEdge(ed *Edge) bool {
+	for _, pe := range p.Edges {
+		if pe == ed {
+			return true
+		}
+	}
+	return false
+}
+
+// EdgeBetween returns the edge of the panel joining a and b, if there is one
+func (p *Panel) EdgeBetween(a, b *Vertex) *Edge {
+	for _, ed := range p.Edges {
+		if ed.HasVertex(a) && ed.HasVertex(b) {
+			return ed
+		}
+	}
+	return nil
+}
+
+// complexRoot is the first complex panel this one was split from, if any
+func (p *Panel) complexRoot() *Panel {
+	var root *Panel
+	for q := p.SubPanelOf; q != nil; q = q.SubPanelOf {
+		if q.Kind == PTypeComplex {
+			root = q
+		}
+	}
+	return root
+}
+
+// drawCorners gets the corners of the panel from its edges, as many as there are if it is not
+//   a triangle, which CheckGeometry reports
+func (p *Panel) drawCorners() []*Vertex {
+	vs := []*Vertex{}
+	for _, ed := range p.Edges {
+		vs = appendUniqueVertex(vs, ed.Vertices[0])
+		vs = appendUniqueVertex(vs, ed.Vertices[1])
+	}
+	return vs
+}
+
+// PanelPieces is a panel as it is drawn: a plain panel alone, or the alive pieces a complex
+//   panel (one with a hole or a cut corner) was split into
+type PanelPieces struct {
+	Panel  *Panel
+	Pieces []*Panel
+}
+
+// Internal is true if the edge only joins pieces of the same panel, so is part of the
+//   triangulation rather than the panel's outline
+func (pp PanelPieces) Internal(ed *Edge) bool {
+	if ed == nil || len(pp.Pieces) < 2 {
+		return false
+	}
+	ps := alivePanels(ed.Panels)
+	if len(ps) < 2 {
+		return false
+	}
+	for _, q := range ps {
+		if q.complexRoot() != pp.Panel {
+			return false
+		}
+	}
+	return true
+}
+
+// DrawnPanels groups the alive panels into what is drawn: each complex panel with all its
+//   pieces, and every other panel on its own
+func (e *EShell) DrawnPanels() []PanelPieces {
+	var pps []PanelPieces
+	at := map[*Panel]int{}
+	for _, p := range e.Panels {
+		if !p.Alive {
+			continue
+		}
+		root := p.complexRoot()
+		if root == nil {
+			root = p
+		}
+		i, ok := at[root]
+		if !ok {
+			i = len(pps)
+			at[root] = i
+			pps = append(pps, PanelPieces{Panel: root})
+		}
+		pps[i].Pieces = append(pps[i].Pieces, p)
+	}
+	return pps
+}
+
 // func (p Panel) EdgesWithCorner(c int) ([]int) {
 // 	vNo := p.Corners[c]
 // 	var es []int
@@ -422,19 +742,21 @@ func appendUniqueVertex(l []*Vertex, x *Vertex) []*Vertex {
 	return append(l, x)
 }
 
-// AddPanel adds one to a shell
+// AddPanel adds one to a shell, made of 3 edges. Reports an error and adds nothing, returning
+//   nil, if there are not 3.
 func (e *EShell) AddPanel(es []*Edge) *Panel {
 	if len(es) != 3 {
-		log.Fatal("GEOMETRY ERROR: Trying to make a panel without 3 edges")
+		console.Errorf("Geometry: trying to make a panel from %d edges, not 3", len(es))
+		return nil
 	}
-	p := Panel{Accessory: PAtypePlain} // assume plain to begin with
+	p := Panel{Accessory: PAtypePlain, Emit: true} // assume plain to begin with
 	p.Edges = es
 	crx := es[0].Along.Cross(es[1].Along)
 	p.Area = crx.Length() / 2
-	p.Normal = crx.Normalized().(v3.SimVec)
+	p.Normal = v3.Sim(crx.Normalized())
 	v0 := es[0].Vertices[0].Position
 	if p.Normal.Dot(v0) < 0 { // its pointing inwards so flip it
-		p.Normal = p.Normal.Scale(-1).(v3.SimVec)
+		p.Normal = v3.Sim(p.Normal.Scale(-1))
 	}
 	p.InitNormal = p.Normal
 	p.Shell = e
@@ -447,14 +769,21 @@ func (e *EShell) AddPanel(es []*Edge) *Panel {
 			p.Corners = appendUniqueVertex(p.Corners, v)
 		}
 	}
+	if e.Oriented && len(p.Corners) == 3 {
+		p.windLike()
+		p.Normal = v3.Sim(p.windingNormal())
+		p.InitNormal = p.Normal
+	}
 	e.Panels = append(e.Panels, &p)
+	e.touch()
 	//	fmt.Printf("%s\n", p.NiceString())
 	return &p
 }
 
 // AddVertex adds one to a shell
 func (e *EShell) AddVertex(v v3.Vec, cs Constraints) *Vertex {
-	newV := Vertex{Position: v.(v3.SimVec), Serial: len(e.Vertices), Alive: true}
+	newV := Vertex{Position: v3.Sim(v), Serial: len(e.Vertices), Alive: true, Shell: e, Constraints: cs.Sorted()}
+	newV.Move(v)
 	e.Vertices = append(e.Vertices, &newV)
 	return &newV
 }
@@ -462,6 +791,7 @@ func (e *EShell) AddVertex(v v3.Vec, cs Constraints) *Vertex {
 // RemovePanel removes one from a shell
 func (e *EShell) RemovePanel(p *Panel) {
 	p.Alive = false
+	e.touch()
 }
 
 // RemoveVertex removes one for a shell
@@ -484,6 +814,50 @@ func appendUniqueEdge(l []*Edge, x *Edge) []*Edge {
 	return append(l, x)
 }
 
+// remove an edge from a list
+func removeEdgeFrom(l []*Edge, x *Edge) []*Edge {
+	r := []*Edge{}
+	for _, v := range l {
+		if v != x {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// remove a panel from a list
+func removePanelFrom(l []*Panel, x *Panel) []*Panel {
+	r := []*Panel{}
+	for _, v := range l {
+		if v != x {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// aliveEdges returns only those edges in the list still alive
+func aliveEdges(l []*Edge) []*Edge {
+	r := []*Edge{}
+	for _, v := range l {
+		if v.Alive {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// alivePanels returns only those panels in the list still alive
+func alivePanels(l []*Panel) []*Panel {
+	r := []*Panel{}
+	for _, v := range l {
+		if v.Alive {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
 // AddEdge adds one to a shell, does vertex housekeeping too
 func (e *EShell) AddEdge(vs []*Vertex) *Edge {
 	al := vs[1].Position.Subtract(vs[0].Position)
@@ -539,11 +913,12 @@ func (e *EShell) Spike(desiredL float64, tolerance float64) bool {
 			for _, ep := range p.Edges {
 				if !ep.HasVertex(v) { // the one we want
 					a := ep.From(edge.Vertices[1]).Scale(-1) // other end of this edge
-					newPoint := e.E.PointDistant(v.Position, a, desiredL, tolerance)
+					midZ := (v.Position.Z() + edge.Vertices[1].Position.Z()) / 2
+					newPoint := e.surface().PointDistant(v.Position, a, e.PanelSizeAt(midZ, desiredL), tolerance)
 					if (newPoint.Z() > e.Base) ||
 						(v.Position.Z() > e.Base) ||
 						(edge.Vertices[1].Position.Z() > e.Base) {
-						newV := e.AddVertex(newPoint, Constraints{&OnEllipsoid})
+						newV := e.AddVertex(newPoint, Constraints{OnEllipsoid})
 						//						fmt.Printf("New vertex for spike %s\n", newV.NiceString())
 						edge2 := e.AddEdge([]*Vertex{v, newV})
 						edge3 := e.AddEdge([]*Vertex{newV, edge.Vertices[1]})
@@ -583,8 +958,9 @@ func (e *EShell) FillIn(desiredL float64, tolerance float64) bool {
 					any = true
 				} else { // two tris
 					g := e1.From(me).Add(e2.From(me))
-					p := e.E.PointDistant(vertex.Position, g, desiredL, tolerance) // new position
-					pNo := e.AddVertex(p, Constraints{&OnEllipsoid})
+					l := e.PanelSizeAt(vertex.Position.Z(), desiredL)
+					p := e.surface().PointDistant(vertex.Position, g, l, tolerance) // new position
+					pNo := e.AddVertex(p, Constraints{OnEllipsoid})
 					oe1 := e1.OtherEnd(vertex) // find the other ends
 					oe2 := e2.OtherEnd(vertex)
 					ne1 := e.AddEdge([]*Vertex{oe1, pNo})
@@ -614,47 +990,26 @@ func (e *EShell) PrepLines(mat *material.Basic) *ShellLines {
 	geom := geometry.NewGeometry()
 	buff := math32.NewArrayF32(0, 3*2*6*(len(e.Panels)+len(e.Cuts)+len(showSegs)+len(showTris)))
 
-	appendColour := func() {
-		buff = append(buff, 1.0, 1.0, 0)
+	ls := e.lineScale()
+	appendColour := func(p *Panel, a, b *Vertex) {
+		c := e.EdgeColour(p.EdgeBetween(a, b), ls)
+		buff = append(buff, c.R, c.G, c.B)
 	}
 
-	for _, panel := range e.Panels {
-
-		if panel.Alive {
-
-			if len(panel.Edges) != 3 {
-				fmt.Printf("Geometry error! Panel %d has %d sides\n", panel.Serial, len(panel.Edges))
-			}
-
-			e0 := panel.Edges[0]
-			e1 := panel.Edges[1]
-			e2 := panel.Edges[2]
-
-			vs := []*Vertex{e0.Vertices[0]}
-			vs = appendUniqueVertex(vs, e0.Vertices[1])
-			vs = appendUniqueVertex(vs, e1.Vertices[0])
-			vs = appendUniqueVertex(vs, e1.Vertices[1])
-			vs = appendUniqueVertex(vs, e2.Vertices[0])
-			vs = appendUniqueVertex(vs, e2.Vertices[1])
-
-			if len(vs) != 3 {
-				fmt.Printf("Geometry error! Panel %d has %d edges and %d vertices\n", panel.Serial, len(panel.Edges), len(vs))
+	// Complex panels are drawn by their outlines, without the triangulation inside
+	for _, pp := range e.DrawnPanels() {
+		for _, panel := range pp.Pieces {
+			vs := panel.drawCorners()
+			for i := range vs {
+				a, b := vs[i], vs[(i+1)%len(vs)]
+				if pp.Internal(panel.EdgeBetween(a, b)) {
+					continue
+				}
+				buff = appendXZY(buff, a.Position)
+				appendColour(panel, a, b)
+				buff = appendXZY(buff, b.Position)
+				appendColour(panel, a, b)
 			}
-
-			buff = appendXZY(buff, vs[0].Position)
-			appendColour()
-			buff = appendXZY(buff, vs[1].Position)
-			appendColour()
-
-			buff = appendXZY(buff, vs[1].Position)
-			appendColour()
-			buff = appendXZY(buff, vs[2].Position)
-			appendColour()
-
-			buff = appendXZY(buff, vs[2].Position)
-			appendColour()
-			buff = appendXZY(buff, vs[0].Position)
-			appendColour()
 		}
 	}
 
@@ -694,39 +1049,30 @@ func (e *EShell) PrepLines(mat *material.Basic) *ShellLines {
 }
 
 // Prep makes an OpenGL shellmesh for use in g3n for the eshell
-func (e *EShell) Prep(mat *material.Standard) *EShellMesh {
+func (e *EShell) Prep(mat material.IMaterial) *EShellMesh {
 
 	geom := geometry.NewGeometry()
 	positions := math32.NewArrayF32(0, 2*3*3*len(e.Panels)) //
+	colours := math32.NewArrayF32(0, 3*3*len(e.Panels))
 	indices := math32.NewArrayU32(0, 3*len(e.Panels))
 	var idx uint32 // running index of the vertices
 
-	for _, panel := range e.Panels {
-
-		if panel.Alive {
-
-			if len(panel.Edges) != 3 {
-				fmt.Printf("Geometry error! Panel %d has %d sides\n", panel.Serial, len(panel.Edges))
+	// Complex panels are drawn as the pieces they were split into, which leave out the hole,
+	//   each coloured as the whole panel by the shell's PanelColouring
+	ps := e.panelScale()
+	for _, pp := range e.DrawnPanels() {
+		c := e.PanelColour(pp.Panel, ps)
+		for _, panel := range pp.Pieces {
+			vs := panel.drawCorners()
+			if len(vs) < 3 {
+				continue
 			}
-
-			e0 := panel.Edges[0]
-			e1 := panel.Edges[1]
-			e2 := panel.Edges[2]
-
-			vs := []*Vertex{e0.Vertices[0]}
-			vs = appendUniqueVertex(vs, e0.Vertices[1])
-			vs = appendUniqueVertex(vs, e1.Vertices[0])
-			vs = appendUniqueVertex(vs, e1.Vertices[1])
-			vs = appendUniqueVertex(vs, e2.Vertices[0])
-			vs = appendUniqueVertex(vs, e2.Vertices[1])
-
-			if len(vs) != 3 {
-				fmt.Printf("Geometry error! Panel %d has %d edges and %d vertices\n", panel.Serial, len(panel.Edges), len(vs))
-			}
-
 			positions = appendXZY(positions, vs[0].Position)
 			positions = appendXZY(positions, vs[1].Position)
 			positions = appendXZY(positions, vs[2].Position)
+			for i := 0; i < 3; i++ {
+				colours = append(colours, c.R, c.G, c.B)
+			}
 
 			indices = append(indices, idx, idx+1, idx+2)
 			idx += 3
@@ -735,19 +1081,20 @@ func (e *EShell) Prep(mat *material.Standard) *EShellMesh {
 
 	geom.SetIndices(indices)
 	geom.AddVBO(gls.NewVBO(positions).AddAttrib(gls.VertexPosition))
+	geom.AddVBO(gls.NewVBO(colours).AddAttrib(gls.VertexColor))
 
 	shell := EShellMesh{}
 	shell.Mesh.Init(geom, mat)
 	return &shell
 }
 
-// STLString returns an STL representation of the panels in the shell
+// STLString returns an STL representation of the panels in the shell to be emitted
 func (e EShell) STLString() string {
 	s := "solid Eggstreme\n"
 	// for i := 0; i < 3; i++ {
 	// 	p := e.Panels[i]
 	for _, p := range e.Panels {
-		if p.Alive {
+		if p.Alive && p.Emit {
 			s += p.STLString()
 		}
 	}
@@ -764,7 +1111,6 @@ func (e EShell) STLString() string {
 
 // Stats is
 func (e EShell) Stats(mats cam.MaterialSet) string {
-	area := 0.0
 	nPanels := 0
 	nEdges := 0
 	nSeams := 0
@@ -776,7 +1122,6 @@ func (e EShell) Stats(mats cam.MaterialSet) string {
 			for _, eNo := range p.Edges {
 				perim += eNo.Along.Length()
 			}
-			area += p.Area + perim*2*e.FlangeWidth // doubled over flange
 			totPerim += perim
 			nPanels++
 		}
@@ -795,11 +1140,16 @@ func (e EShell) Stats(mats cam.MaterialSet) string {
 		}
 	}
 
-	s1 := fmt.Sprintf("Panels: %d,  Edges: %d inc %d Seamed,  Vertices: %d\nMidplane: %4.1f'x%4.1f' (%4.1fx%4.1fm)   Area: %4.0fsqft (%4.0fm2)",
-		nPanels, nEdges, nSeams, nVertices,
-		2*e.E.W*m2ft, 2*e.E.L*m2ft, 2*e.E.W, 2*e.E.L, e.E.W*m2ft*e.E.L*m2ft*math.Pi, e.E.W*e.E.L*math.Pi)
+	u := e.Display
+	s1 := fmt.Sprintf("Panels: %d,  Edges: %d inc %d Seamed,  Vertices: %d\nMidplane: %s x %s   Area: %s",
+		nPanels, nEdges, nSeams, nVertices, u.Len(2*e.E.W), u.Len(2*e.E.L), u.Area(e.E.W*e.E.L*math.Pi))
 
-	s := fmt.Sprintf("%s\nMetal area needed: %4.1f sq ft (%4.1f sq m)\n", s1, area*sqM2sqFt, area)
+	bom := e.BOM(mats)
+	s := fmt.Sprintf("%s\nMetal area needed: %s, %s\n", s1, u.Area(bom.Area), bom.Needs())
+	s += e.massFrom(bom).String()
+	if e.Weather.Snow > 0 || e.Weather.Wind > 0 {
+		s += e.loadsFrom(e.Weather, bom.Mass).String()
+	}
 
 	// s += "       "
 	// for _, den := range ds {
@@ -817,16 +1167,64 @@ func (e EShell) Stats(mats cam.MaterialSet) string {
 	l2gal := 0.264172
 	beadVol := 1000 * (totPerim / 2) * 0.004 * 0.004 * math.Pi / 4
 
-	s += fmt.Sprintf("Total panel perimeter: %5.1f' (%5.1fm), 4mm bead volume: %.2gl (%.2ggal)\n", totPerim*m2ft, totPerim, beadVol, beadVol*l2gal)
+	bead := fmt.Sprintf("%.2ggal", beadVol*l2gal)
+	if u == Metric {
+		bead = fmt.Sprintf("%.2gl", beadVol)
+	}
+	s += fmt.Sprintf("Total panel perimeter: %s, 4mm bead volume: %s\n", u.Len(totPerim), bead)
+	s += e.TreatmentSummary()
+	s += e.Fasteners().String()
+	s += e.FitReport()
 	// Floor area calcs
-	floorX := e.E.XGivenYZ(0, e.Base)
-	floorY := e.E.YGivenXZ(0, e.Base)
-	s += fmt.Sprintf("Floor is at %4.1g' (%4.1gm), peak is %4.1f' above it\n   It is %4.1f' x %4.1f' (%4.1fm x %4.1fm)   Area %4.1fsqft (%4.1fsqm)\n",
-		e.Base*m2ft, e.Base, ((e.E.H)-e.Base)*m2ft, floorX*2*m2ft, floorY*2*m2ft, floorX*2, floorY*2, math.Pi*floorX*m2ft*floorY*m2ft, math.Pi*floorX*floorY)
+	floorX := e.surface().SectionAt(e.Base, ell.X).X()
+	floorY := e.surface().SectionAt(e.Base, ell.Y).Y()
+	s += fmt.Sprintf("Floor is at %s, peak is %s above it\n   It is %s x %s   Area %s\n",
+		u.Len(e.Base), u.Len(e.E.H-e.Base), u.Len(floorX*2), u.Len(floorY*2), u.Area(math.Pi*floorX*floorY))
+	if fl := e.FloorPolygon(); len(fl.Points) > 0 {
+		s += fmt.Sprintf("   As meshed, the floor outline has %d sides, area %s\n", len(fl.Points), u.Area(fl.Area()))
+	}
+
+	s += e.HeadroomTable()
+
+	s += e.DoorStats()
+	if e.Skylight != nil {
+		s += e.Skylight.String()
+	}
 
 	return fmt.Sprintf("%s\nStep %d", s, e.Step)
 }
 
+// AccessoryLines shows the vents, the flanges, which include the skylight curb, and the liner
+func (e *EShell) AccessoryLines() []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	for _, v := range e.Vents {
+		ls = append(ls, v.Display()...)
+	}
+	for _, f := range e.Flanges {
+		ls = append(ls, f.Display()...)
+	}
+	return append(ls, e.LinerLines()...)
+}
+
+// Undertaker removes edges which no longer have any live panels, and vertices
+//   which no longer have any live edges. Returns true if it buried anything.
+func (e *EShell) Undertaker() bool {
+	var any bool
+	for _, ed := range e.Edges {
+		if ed.Alive && len(alivePanels(ed.Panels)) == 0 {
+			e.RemoveEdge(ed)
+			any = true
+		}
+	}
+	for _, v := range e.Vertices {
+		if v.Alive && len(aliveEdges(v.Edges)) == 0 {
+			e.RemoveVertex(v)
+			any = true
+		}
+	}
+	return any
+}
+
 // Cleanup makes sure the references are consistent
 // func (e *EShell) Cleanup() {
 // 	a := e.CoupDeGrace()
@@ -851,8 +1249,10 @@ func (e *EShell) AddPanels(el [][]int) {
 	}
 }
 
-// MakeMesh makes the initial mesh
-func (e *EShell) MakeMesh(desiredL float64, tolerance float64) {
+// MakeMesh makes the initial mesh, growing it down from the zenith to the base, reporting
+//   how far down it has got to the shell's Progress. Returns errCancelled, leaving the mesh
+//   part made, if that is cancelled.
+func (e *EShell) MakeMesh(desiredL float64, tolerance float64) error {
 
 	pi := math.Pi
 	cos := math.Cos
@@ -860,12 +1260,13 @@ func (e *EShell) MakeMesh(desiredL float64, tolerance float64) {
 	deg60 := pi / 3
 
 	// Start with a hexagonal patch at the zenith
-	zenith := e.E.Surface(ell.Z)
+	zenith := e.surface().Surface(ell.Z)
+	zenithL := e.PanelSizeAt(zenith.Z(), desiredL)
 	var ang float64
-	e.AddVertex(zenith, Constraints{&OnEllipsoid}) // first vertex at zenith
+	e.AddVertex(zenith, Constraints{OnEllipsoid}) // first vertex at zenith
 	for i := 0; i < 6; i++ {
-		e.AddVertex(e.E.PointDistant(zenith, ell.X.Scale(cos(ang)).Add(ell.Y.Scale(sin(ang))),
-			desiredL, tolerance), Constraints{&OnEllipsoid})
+		e.AddVertex(e.surface().PointDistant(zenith, ell.X.Scale(cos(ang)).Add(ell.Y.Scale(sin(ang))),
+			zenithL, tolerance), Constraints{OnEllipsoid})
 		ang += deg60
 	}
 	e.AddEdges([][]int{{1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 6}, {6, 1},
@@ -873,6 +1274,14 @@ func (e *EShell) MakeMesh(desiredL float64, tolerance float64) {
 	e.AddPanels([][]int{{6, 0, 7}, {7, 1, 8}, {8, 2, 9}, {9, 3, 10}, {10, 4, 11}, {11, 5, 6}})
 	didSomething := true
 	for didSomething {
+		if e.Progress.Cancelled() {
+			return errCancelled
+		}
+		lowest := zenith.Z()
+		for _, v := range e.Vertices {
+			lowest = math.Min(lowest, v.Position.Z())
+		}
+		e.Progress.Set("Tessellating", (zenith.Z()-lowest)/(zenith.Z()-e.Base))
 		a := e.AntiSpike()
 		b := e.FillIn(desiredL, tolerance)
 		// a := true
@@ -887,8 +1296,10 @@ func (e *EShell) MakeMesh(desiredL float64, tolerance float64) {
 		didSomething = a || b || c
 	}
 
+	e.Progress.Set("Cutting the floor", 1)
 	e.CutFloor()
-
+	e.Orient()
+	return nil
 }
 
 // func remove(value int, from []int) []int {
@@ -902,12 +1313,29 @@ func (e *EShell) MakeMesh(desiredL float64, tolerance float64) {
 // }
 
 // CombineVertices transfers all references to v1 onto v0 and moves it to p
-// func (e *EShell) CombineVertices(vNo0, vNo1 int, p v3.Vec) {
-// 	v0 := &vNo0]
-// 	v1 := &vNo1]
-// 	v0.Position = p
-
-// } TODO TODO
+func (e *EShell) CombineVertices(v0, v1 *Vertex, p v3.Vec) {
+	for _, ed := range v1.Edges {
+		for i, v := range ed.Vertices {
+			if v == v1 {
+				ed.Vertices[i] = v0
+			}
+		}
+		v0.Edges = appendUniqueEdge(v0.Edges, ed)
+	}
+	for _, pan := range v1.Panels {
+		for i, c := range pan.Corners {
+			if c == v1 {
+				pan.Corners[i] = v0
+			}
+		}
+		v0.Panels = appendUniquePanel(v0.Panels, pan)
+	}
+	v0.Constraints = Combine(v0.Constraints, v1.Constraints)
+	v1.Edges = nil
+	v1.Panels = nil
+	e.RemoveVertex(v1)
+	v0.Move(p)
+}
 
 type edgeRef struct {
 	serial int
@@ -915,13 +1343,14 @@ type edgeRef struct {
 }
 
 // PruneEdges tries to eliminate very short edges
-func (e *EShell) PruneEdges(lengthLim float64) {
+func (e *EShell) PruneEdges(lengthLim float64) int {
 
 	var shorts []edgeRef
 
 	for _, edi := range e.Edges {
 		ed := edi
 		eNo := ed.Serial
+		ed.Update(e)
 		if ed.Alive && (ed.Length < lengthLim) {
 			shorts = append(shorts, edgeRef{serial: eNo, length: ed.Length})
 		}
@@ -931,8 +1360,288 @@ func (e *EShell) PruneEdges(lengthLim float64) {
 		return shorts[i].length < shorts[j].length
 	})
 
-	//	fmt.Printf("SHORTS: %s\n", shorts)
+	pruned := 0
+	for _, sh := range shorts {
+		ed := e.Edges[sh.serial]
+		if !ed.Alive || ed.Length >= lengthLim { // earlier collapses may have changed things
+			continue
+		}
+		if e.CollapseEdge(ed) {
+			pruned++
+		}
+	}
 
+	console.Infof("Pruned %d of %d short edges", pruned, len(shorts))
+	e.CheckGeometry()
+	return pruned
+}
+
+// CollapseEdge merges the two ends of an edge into one vertex, removing the edge and
+//   the (now degenerate) panels on it. Returns false if the collapse would damage the mesh.
+func (e *EShell) CollapseEdge(ed *Edge) bool {
+
+	a := ed.Vertices[0]
+	b := ed.Vertices[1]
+	dead := alivePanels(ed.Panels)
+
+	// Link condition: the only vertices joined to both ends must be the third
+	//   corners of the panels on this edge, otherwise we would pinch the surface
+	common := 0
+	for _, ea := range aliveEdges(a.Edges) {
+		na := ea.OtherEnd(a)
+		for _, eb := range aliveEdges(b.Edges) {
+			if eb.OtherEnd(b) == na {
+				common++
+			}
+		}
+	}
+	if common != len(dead) {
+		return false
+	}
+	if len(alivePanels(a.Panels))+len(alivePanels(b.Panels))-2*len(dead) > maxPanelsPerVertex {
+		return false
+	}
+
+	// Boundary vertices stay where they are, so the boundary does not wander
+	p := a.Position.Add(b.Position).Scale(0.5)
+	aEdge := a.OnBoundary()
+	bEdge := b.OnBoundary()
+	if aEdge && !bEdge {
+		p = a.Position
+	} else if bEdge && !aEdge {
+		p = b.Position
+	} else if !aEdge && !bEdge {
+		p = e.surface().Surface(p)
+	}
+
+	// None of the surviving panels may be flipped over by the move
+	for _, v := range []*Vertex{a, b} {
+		for _, pan := range alivePanels(v.Panels) {
+			if pan.HasEdge(ed) {
+				continue
+			}
+			var pts []v3.Vec
+			for _, c := range pan.Corners {
+				if c == a || c == b {
+					pts = append(pts, p)
+				} else {
+					pts = append(pts, c.Position)
+				}
+			}
+			n := pts[1].Subtract(pts[0]).Cross(pts[2].Subtract(pts[0]))
+			o := pan.Corners[1].Position.Subtract(pan.Corners[0].Position).Cross(pan.Corners[2].Position.Subtract(pan.Corners[0].Position))
+			if n.Dot(o) <= 0 {
+				return false
+			}
+		}
+	}
+
+	for _, pan := range dead {
+		c := pan.OtherCorner(a, b)
+		ea := pan.EdgeBetween(a, c)
+		eb := pan.EdgeBetween(b, c)
+		if c == nil || ea == nil || eb == nil {
+			console.Errorf("Panel %d is malformed, cannot collapse edge %d", pan.Serial, ed.Serial)
+			return false
+		}
+		for _, q := range eb.Panels { // hand eb's other panel over to ea
+			if q == pan || !q.Alive {
+				continue
+			}
+			for i, qe := range q.Edges {
+				if qe == eb {
+					q.Edges[i] = ea
+				}
+			}
+			ea.Panels = appendUniquePanel(ea.Panels, q)
+		}
+		ea.Panels = removePanelFrom(ea.Panels, pan)
+		b.Edges = removeEdgeFrom(b.Edges, eb)
+		c.Edges = removeEdgeFrom(c.Edges, eb)
+		c.Panels = removePanelFrom(c.Panels, pan)
+		a.Panels = removePanelFrom(a.Panels, pan)
+		b.Panels = removePanelFrom(b.Panels, pan)
+		e.RemoveEdge(eb)
+		e.RemovePanel(pan)
+	}
+	a.Edges = removeEdgeFrom(a.Edges, ed)
+	b.Edges = removeEdgeFrom(b.Edges, ed)
+	e.RemoveEdge(ed)
+
+	e.CombineVertices(a, b, p)
+
+	for _, ae := range a.Edges {
+		ae.Update(e)
+	}
+	for _, ap := range a.Panels {
+		ap.Update(e)
+	}
+	return true
+}
+
+// Decimate collapses the least important edges until there are no more than
+//   targetPanels live panels, or nothing more can be collapsed. The boundary is
+//   left alone. Returns the number of live panels remaining.
+func (e *EShell) Decimate(targetPanels int) int {
+
+	nPanels := len(alivePanels(e.Panels))
+
+	for nPanels > targetPanels {
+
+		var cands []edgeRef
+		for _, ed := range e.Edges {
+			if !ed.Alive {
+				continue
+			}
+			pans := alivePanels(ed.Panels)
+			if len(pans) != 2 || ed.Vertices[0].OnBoundary() || ed.Vertices[1].OnBoundary() {
+				continue
+			}
+			ed.Update(e)
+			flatness := pans[0].Normal.Dot(pans[1].Normal) // 1 = coplanar, so unimportant
+			cands = append(cands, edgeRef{serial: ed.Serial, length: ed.Length * (1 + 10*(1-flatness))})
+		}
+		sort.Slice(cands, func(i, j int) bool {
+			return cands[i].length < cands[j].length
+		})
+
+		// Collapse as many as we can this pass without touching the same vertex twice
+		touched := map[*Vertex]bool{}
+		collapsed := 0
+		for _, c := range cands {
+			if nPanels <= targetPanels {
+				break
+			}
+			ed := e.Edges[c.serial]
+			if !ed.Alive || touched[ed.Vertices[0]] || touched[ed.Vertices[1]] {
+				continue
+			}
+			a := ed.Vertices[0]
+			neighbours := []*Vertex{a, ed.Vertices[1]}
+			for _, v := range neighbours[:2] {
+				for _, ve := range aliveEdges(v.Edges) {
+					neighbours = append(neighbours, ve.OtherEnd(v))
+				}
+			}
+			if e.CollapseEdge(ed) {
+				for _, v := range neighbours {
+					touched[v] = true
+				}
+				nPanels = len(alivePanels(e.Panels))
+				collapsed++
+			}
+		}
+		if collapsed == 0 {
+			break
+		}
+	}
+
+	e.CheckGeometry()
+	console.Infof("Decimated to %d panels (target %d)", nPanels, targetPanels)
+	return nPanels
+}
+
+// minAngle returns the smallest interior angle of the triangle abc, in radians
+func minAngle(a, b, c v3.Vec) float64 {
+	angle := func(p, q, r v3.Vec) float64 { // angle at p
+		u := q.Subtract(p).Normalized()
+		w := r.Subtract(p).Normalized()
+		return math.Acos(math.Max(-1, math.Min(1, u.Dot(w))))
+	}
+	return math.Min(angle(a, b, c), math.Min(angle(b, c, a), angle(c, a, b)))
+}
+
+// FlipEdges swaps the diagonal of pairs of panels wherever that improves the smallest
+//   angle of the pair, repeating until nothing changes. Returns the number of flips.
+func (e *EShell) FlipEdges() int {
+	const minGain = 0.01 // radians, avoids flip-flopping
+	flips := 0
+	for pass := 0; pass < 10; pass++ {
+		any := false
+		for _, ed := range e.Edges {
+			if !ed.Alive {
+				continue
+			}
+			pans := alivePanels(ed.Panels)
+			if len(pans) != 2 {
+				continue
+			}
+			a := ed.Vertices[0]
+			b := ed.Vertices[1]
+			c := pans[0].OtherCorner(a, b)
+			d := pans[1].OtherCorner(a, b)
+			if c == nil || d == nil || c == d || e.EdgeJoining(c, d) != nil {
+				continue
+			}
+			before := math.Min(minAngle(a.Position, b.Position, c.Position), minAngle(a.Position, b.Position, d.Position))
+			after := math.Min(minAngle(a.Position, c.Position, d.Position), minAngle(b.Position, c.Position, d.Position))
+			if after < before+minGain {
+				continue
+			}
+			// Must not fold the surface over
+			nOld := pans[0].Normal.Add(pans[1].Normal)
+			n1 := c.Position.Subtract(a.Position).Cross(d.Position.Subtract(a.Position))
+			n2 := c.Position.Subtract(b.Position).Cross(d.Position.Subtract(b.Position))
+			if n1.Dot(nOld)*n2.Dot(nOld) >= 0 { // new tris facing opposite ways => a or b is outside cd
+				continue
+			}
+			if e.flipEdge(ed, pans[0], pans[1], a, b, c, d) {
+				flips++
+				any = true
+			}
+		}
+		if !any {
+			break
+		}
+	}
+	console.Infof("Flipped %d edges", flips)
+	return flips
+}
+
+// flipEdge turns edge ab, shared by panels p (abc) and q (abd) into edge cd, shared by acd & bcd
+func (e *EShell) flipEdge(ed *Edge, p, q *Panel, a, b, c, d *Vertex) bool {
+	eac := p.EdgeBetween(a, c)
+	ebc := p.EdgeBetween(b, c)
+	ead := q.EdgeBetween(a, d)
+	ebd := q.EdgeBetween(b, d)
+	if eac == nil || ebc == nil || ead == nil || ebd == nil {
+		return false
+	}
+
+	ed.Vertices = []*Vertex{c, d}
+	a.Edges = removeEdgeFrom(a.Edges, ed)
+	b.Edges = removeEdgeFrom(b.Edges, ed)
+	c.Edges = appendUniqueEdge(c.Edges, ed)
+	d.Edges = appendUniqueEdge(d.Edges, ed)
+
+	p.Edges = []*Edge{ed, eac, ead} // p becomes acd
+	p.Corners = []*Vertex{a, c, d}
+	q.Edges = []*Edge{ed, ebc, ebd} // q becomes bcd
+	q.Corners = []*Vertex{b, c, d}
+
+	ead.Panels = appendUniquePanel(removePanelFrom(ead.Panels, q), p)
+	ebc.Panels = appendUniquePanel(removePanelFrom(ebc.Panels, p), q)
+	a.Panels = removePanelFrom(a.Panels, q)
+	b.Panels = removePanelFrom(b.Panels, p)
+	c.Panels = appendUniquePanel(c.Panels, q)
+	d.Panels = appendUniquePanel(d.Panels, p)
+
+	ed.Update(e)
+	p.Update(e)
+	q.Update(e)
+	p.InitNormal = p.Normal
+	q.InitNormal = q.Normal
+	return true
+}
+
+// EdgeJoining returns the live edge between two vertices, if any
+func (e *EShell) EdgeJoining(v0, v1 *Vertex) *Edge {
+	for _, ed := range aliveEdges(v0.Edges) {
+		if ed.OtherEnd(v0) == v1 {
+			return ed
+		}
+	}
+	return nil
 }
 
 // CalcCutPatch computes all the cuts of the panels that intersect the given patch
@@ -958,82 +1667,187 @@ func (e *EShell) PruneEdges(lengthLim float64) {
 // CutFloor cuts off all panels projecting below the floor
 func (e *EShell) CutFloor() {
 	floor := v3.NewPlane(v3.NewSimVec(0, 0, e.Base), v3.NewSimVec(0, 0, 1))
-	//	debug := math32.Color{R: 0.1, G: 0.7, B: 0.4}
-	for _, p := range e.Panels {
-		cutEnds := []v3.Vec{}
-		for _, ed := range p.Edges {
-			v := ed.Vertices[0]
-			f := ed.From(v)
-			l := v3.NewLine(v.Position, f)
-			s := v3.NewSegment(l, 0.0, f.Length())
-			//			e.DebugLines = append(e.DebugLines, DebugLine{Start: s.Start().Scale(1.01), End: s.End().Scale(1.01), Colour: debug})
-			intersect, itsCut := floor.IntersectSegment(s)
-			if itsCut {
-				cutEnds = append(cutEnds, intersect)
-				//		e.DebugLines = append(e.DebugLines, DebugLine{Start: v3.Origin, End: intersect, Colour: DebugPurple})
+	e.CutWithPlane(floor, true)
+}
+
+// CutWithPlane removes everything on one side of the plane, retriangulating the panels
+//   which cross it. The new edges along the cut are returned and recorded in Cuts.
+func (e *EShell) CutWithPlane(pl v3.Plane, keepNormalSide bool) []*Edge {
+
+	const onPlane = 1e-9 // m, vertices this close to the plane are kept
+
+	keep := func(v *Vertex) bool {
+		d := v.Position.Subtract(pl.PointOn).Dot(pl.Normal)
+		if keepNormalSide {
+			return d >= -onPlane
+		}
+		return d <= onPlane
+	}
+
+	// The floor gets the exact base ring constraint, anything else an iterative one
+	cst := OnPlane(pl)
+	if math.Abs(pl.Normal.Z()) > 1-v3.PlanckLength && math.Abs(pl.PointOn.Z()-e.Base) < onPlane {
+		cst = OnBaseRing
+	}
+
+	// Split every edge which crosses the plane, once, so neighbouring panels share the new vertices
+	cutVs := map[*Edge]*Vertex{} // where the edge crosses
+	keptEs := map[*Edge]*Edge{}  // the part of the edge we keep
+	for _, ed := range e.Edges {
+		if !ed.Alive {
+			continue
+		}
+		v0 := ed.Vertices[0]
+		v1 := ed.Vertices[1]
+		if keep(v0) == keep(v1) {
+			continue
+		}
+		where, hits := pl.IntersectSegment(v3.NewSegment2Ends(v0.Position, v1.Position))
+		if !hits {
+			console.Errorf("Edge %d crosses the plane but does not intersect it", ed.Serial)
+			continue
+		}
+		nv := e.AddVertex(where, Constraints{cst})
+		cutVs[ed] = nv
+		if keep(v0) {
+			keptEs[ed] = e.AddEdge([]*Vertex{v0, nv})
+		} else {
+			keptEs[ed] = e.AddEdge([]*Vertex{v1, nv})
+		}
+	}
+
+	var cutEdges []*Edge
+	nPanels := len(e.Panels)
+	for pNo := 0; pNo < nPanels; pNo++ {
+		p := e.Panels[pNo]
+		if !p.Alive {
+			continue
+		}
+		var kept, lost []*Vertex
+		for _, c := range p.Corners {
+			if keep(c) {
+				kept = append(kept, c)
+			} else {
+				lost = append(lost, c)
 			}
 		}
-		if len(cutEnds) == 2 {
-			//			e.Cuts = append(e.Cuts, CutSegment{start: cutEnds[0], end: cutEnds[1]})
-			var aboves []*Vertex
-			for _, vNo := range p.Corners {
-				if vNo.Position.Z() > e.Base {
-					aboves = append(aboves, vNo)
-				}
+		switch len(kept) {
+		case 3: // untouched
+			continue
+		case 1: // one small triangle survives
+			a := kept[0]
+			eab := p.EdgeBetween(a, lost[0])
+			eac := p.EdgeBetween(a, lost[1])
+			if cutVs[eab] == nil || cutVs[eac] == nil {
+				console.Errorf("Panel %d was not cut cleanly", p.Serial)
+				continue
 			}
-			//			fmt.Printf("Panel %d above cut %d\n", pNo, len(aboves))
-			if len(aboves) == 1 { // make one triangle
-				vNew0 := e.AddVertex(e.E.Surface(cutEnds[0]), Constraints{&OnBase, &OnEllipsoid})
-				vNew1 := e.AddVertex(e.E.Surface(cutEnds[1]), Constraints{&OnBase, &OnEllipsoid})
-				eNew0 := e.AddEdge([]*Vertex{vNew0, vNew1})
-				eNew1 := e.AddEdge([]*Vertex{aboves[0], vNew0})
-				eNew2 := e.AddEdge([]*Vertex{aboves[0], vNew1})
-				e.AddPanel([]*Edge{eNew0, eNew1, eNew2})
-			} else if len(aboves) == 2 { // need to make two
-				vNew0 := e.AddVertex(e.E.Surface(cutEnds[0]), Constraints{&OnBase, &OnEllipsoid})
-				vNew1 := e.AddVertex(e.E.Surface(cutEnds[1]), Constraints{&OnBase, &OnEllipsoid})
-				eNew0 := e.AddEdge([]*Vertex{vNew0, vNew1})
-				eNew1 := e.AddEdge([]*Vertex{aboves[0], vNew0})
-				eNew2 := e.AddEdge([]*Vertex{aboves[0], vNew1})
-				eNew3 := e.AddEdge([]*Vertex{aboves[1], vNew1})
-				eNew4 := e.AddEdge([]*Vertex{aboves[1], aboves[0]})
-				e.AddPanel([]*Edge{eNew0, eNew1, eNew2})
-				e.AddPanel([]*Edge{eNew2, eNew3, eNew4})
+			cut := e.AddEdge([]*Vertex{cutVs[eab], cutVs[eac]})
+			e.AddPanel([]*Edge{keptEs[eab], cut, keptEs[eac]})
+			cutEdges = append(cutEdges, cut)
+		case 2: // a quadrilateral survives, make two triangles, using the shorter diagonal
+			a := kept[0]
+			b := kept[1]
+			eab := p.EdgeBetween(a, b)
+			eac := p.EdgeBetween(a, lost[0])
+			ebc := p.EdgeBetween(b, lost[0])
+			if cutVs[eac] == nil || cutVs[ebc] == nil {
+				console.Errorf("Panel %d was not cut cleanly", p.Serial)
+				continue
 			}
-			e.RemovePanel(p)
-		} else {
-			if len(cutEnds) != 0 {
-				fmt.Printf("ERROR: Panel %d has %d cut ends\n", p.Serial, len(cutEnds))
+			ca := cutVs[eac]
+			cb := cutVs[ebc]
+			cut := e.AddEdge([]*Vertex{ca, cb})
+			if a.Position.Subtract(cb.Position).LengthSq() < b.Position.Subtract(ca.Position).LengthSq() {
+				diag := e.AddEdge([]*Vertex{a, cb})
+				e.AddPanel([]*Edge{eab, keptEs[ebc], diag})
+				e.AddPanel([]*Edge{diag, cut, keptEs[eac]})
+			} else {
+				diag := e.AddEdge([]*Vertex{b, ca})
+				e.AddPanel([]*Edge{eab, diag, keptEs[eac]})
+				e.AddPanel([]*Edge{diag, keptEs[ebc], cut})
 			}
+			cutEdges = append(cutEdges, cut)
 		}
+		e.RemovePanel(p)
+	}
+
+	e.Undertaker()
 
+	for _, ed := range cutEdges {
+		e.Cuts = append(e.Cuts, CutSegment{start: ed.Vertices[0].Position, end: ed.Vertices[1].Position})
 	}
+	e.afterCut()
+	return cutEdges
 }
 
-// CalcTensions computes the tension/compression in each edge
+// CalcTensions computes the tension/compression in each edge, driving each towards
+//   its own Target length, or desired if it has none. If the panel size is graded, desired
+//   is for PanelSize, and scaled with the grading.
 func (e *EShell) CalcTensions(desired float64, k float64) {
 	for _, ed := range e.Edges {
 		if ed.Alive {
-			ed.Along = ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position)
-			ed.Tension = k * math.Pow((ed.Along.Length()-desired), 5) // tension = +ve
+			target := desired
+			if e.SizeFunc != nil && e.PanelSize > 0 {
+				midZ := (ed.Vertices[0].Position.Z() + ed.Vertices[1].Position.Z()) / 2
+				target = desired * e.SizeFunc(midZ) / e.PanelSize
+			}
+			if ed.Target > 0 {
+				target = ed.Target
+			}
+			along := v3.V3(ed.Vertices[1].Position).Subtract(v3.V3(ed.Vertices[0].Position))
+			ed.Along = along.Sim()
+			ed.Tension = k * math.Pow((along.Length()-target), 5) // tension = +ve
 		}
 	}
 }
 
-// MoveVertices moves them under action of the edges
-func (e *EShell) MoveVertices(elli ell.Ellipsoid, moveFactor float64, slowFactor float64) {
+// SetTargets sets the Target length of every live edge for which which() is true,
+//   returns how many were set
+func (e *EShell) SetTargets(which func(ed *Edge) bool, l float64) int {
+	n := 0
+	for _, ed := range e.Edges {
+		if ed.Alive && which(ed) {
+			ed.Target = l
+			n++
+		}
+	}
+	return n
+}
+
+// IsBoundary is true iff the edge has only one live panel
+func (ed *Edge) IsBoundary() bool {
+	return len(alivePanels(ed.Panels)) == 1
+}
+
+// MoveVertices moves them under action of the edges, subject to each vertex's constraints.
+//   Returns the largest distance any vertex moved.
+func (e *EShell) MoveVertices(moveFactor float64, slowFactor float64) float64 {
+	maxMove := 0.0
 	for _, v := range e.Vertices {
-		var f v3.SimVec
+		if !v.Alive {
+			continue
+		}
+		var f v3.Vec3 // by value, as this is the inner loop of relaxation
 		for _, ed := range v.Edges {
+			pull := v3.V3(ed.Along).Normalized().Scale(ed.Tension)
 			if v == ed.Vertices[0] {
-				f = f.Add(ed.Along.Normalized().Scale(ed.Tension)).(v3.SimVec)
+				f = f.Add(pull)
 			} else {
-				f = f.Add(ed.Along.Normalized().Scale(-ed.Tension)).(v3.SimVec)
+				f = f.Subtract(pull)
 			}
 		}
-		v.V = v.V.Add(f.Scale(moveFactor)).Scale(slowFactor).(v3.SimVec)
-		v.Position = elli.Surface(v.Position.Add(v.V)).(v3.SimVec)
+		v.V = v.V.V3().Add(f.Scale(moveFactor)).Scale(slowFactor).Sim()
+		was := v3.V3(v.Position)
+		v.Move(was.Add(v.V.V3()).Vec())
+		maxMove = math.Max(maxMove, v3.V3(v.Position).Subtract(was).Length())
 	}
+	e.UpdateAll()
+	return maxMove
+}
+
+// UpdateAll recalculates all the live edges and panels after vertices have moved
+func (e *EShell) UpdateAll() {
 	for _, ed := range e.Edges {
 		if ed.Alive {
 			ed.Update(e)
@@ -1046,130 +1860,294 @@ func (e *EShell) MoveVertices(elli ell.Ellipsoid, moveFactor float64, slowFactor
 	}
 }
 
-// IntersectsPanels find which panels a segment intersects
+// Relaxation parameters
+const (
+	relaxK       = 0.1   // spring constant for CalcTensions
+	relaxStep    = 0.01  // initial moveFactor
+	relaxMaxStep = 0.05  // largest moveFactor we will grow to
+	relaxMinStep = 1e-5  // below this we give up
+	relaxDamping = 0.955 // slowFactor
+	relaxGrowth  = 1.05  // step growth while things are going well
+	relaxShrink  = 0.5   // step reduction when they are not
+	relaxBlowup  = 1.5   // residual growth factor regarded as divergence
+)
+
+// RelaxParams are the knobs of the spring solver which can be tuned
+type RelaxParams struct {
+	K       float64 // spring constant for CalcTensions
+	Damping float64 // slowFactor, the fraction of its speed a vertex keeps each iteration
+	Length  float64 // m, edges without a Target are pulled towards
+}
+
+// DefaultRelaxParams are the usual knobs, for panels of size l
+func DefaultRelaxParams(l float64) RelaxParams {
+	return RelaxParams{K: relaxK, Damping: relaxDamping, Length: l}
+}
+
+// RelaxStats summarises a run of Relax
+type RelaxStats struct {
+	Iterations int     // how many were done
+	Residual   float64 // largest vertex movement in the last iteration, m
+	Step       float64 // final moveFactor
+	Backoffs   int     // how many times the step had to be reduced
+	Flipped    int     // panels flipped relative to InitNormal at the end
+	Converged  bool    // residual got below tolerance
+	Diverged   bool    // gave up because the step became uselessly small
+	Cancelled  bool    // stopped through the shell's Progress
+}
+
+func (rs RelaxStats) String() string {
+	state := "stopped"
+	if rs.Converged {
+		state = "converged"
+	} else if rs.Diverged {
+		state = "diverged"
+	} else if rs.Cancelled {
+		state = "cancelled"
+	}
+	return fmt.Sprintf("Relax %s after %d iterations: residual %.3gm, step %.3g, %d backoffs, %d flipped panels",
+		state, rs.Iterations, rs.Residual, rs.Step, rs.Backoffs, rs.Flipped)
+}
+
+// FlippedPanels counts the live panels whose normals have turned over since they were made
+func (e *EShell) FlippedPanels() int {
+	n := 0
+	for _, p := range e.Panels {
+		if p.Alive && p.Normal.Dot(p.InitNormal) < 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Relaxer runs the spring solver on a shell one iteration at a time, so it can be watched
+//   and its Params changed as it goes
+type Relaxer struct {
+	Params       RelaxParams
+	Stats        RelaxStats
+	shell        *EShell
+	startFlips   int
+	lastResidual float64
+	saved        []v3.Vec
+}
+
+// NewRelaxer starts relaxing the shell with the params
+func (e *EShell) NewRelaxer(p RelaxParams) *Relaxer {
+	return &Relaxer{
+		Params:       p,
+		Stats:        RelaxStats{Step: relaxStep, Flipped: e.FlippedPanels()},
+		shell:        e,
+		startFlips:   e.FlippedPanels(),
+		lastResidual: math.Inf(1),
+	}
+}
+
+// Done is true once the relaxer has converged or given up
+func (r *Relaxer) Done() bool {
+	return r.Stats.Converged || r.Stats.Diverged
+}
+
+// Iterate moves the vertices once, then is Done if no vertex moved more than tolerance, or
+//   the step has become uselessly small. The step size is adapted: grown while the residual
+//   falls, and cut back (undoing the step) when the residual blows up or panels flip over.
+func (r *Relaxer) Iterate(tolerance float64) bool {
+	e, rs := r.shell, &r.Stats
+	if r.Done() {
+		return true
+	}
+	if len(r.saved) != len(e.Vertices) {
+		r.saved = make([]v3.Vec, len(e.Vertices))
+	}
+	for i, v := range e.Vertices {
+		r.saved[i] = v.Position
+	}
+
+	e.CalcTensions(r.Params.Length, r.Params.K)
+	residual := e.MoveVertices(rs.Step, r.Params.Damping)
+	rs.Iterations++
+	rs.Flipped = e.FlippedPanels()
+
+	if rs.Flipped > r.startFlips || residual > r.lastResidual*relaxBlowup {
+		for i, v := range e.Vertices { // undo
+			v.Position = r.saved[i]
+			v.V = v3.SimVec{}
+		}
+		e.touch()
+		e.UpdateAll()
+		rs.Flipped = e.FlippedPanels()
+		rs.Backoffs++
+		rs.Step *= relaxShrink
+		if rs.Step < relaxMinStep {
+			rs.Diverged = true
+		}
+		return r.Done()
+	}
+
+	rs.Residual = residual
+	if residual < tolerance {
+		rs.Converged = true
+		return true
+	}
+	if residual < r.lastResidual {
+		rs.Step = math.Min(rs.Step*relaxGrowth, relaxMaxStep)
+	}
+	r.lastResidual = residual
+	return false
+}
+
+// Relax runs the spring solver with the params until it is Done, or maxIters is reached, or
+//   the shell's Progress is cancelled
+func (e *EShell) Relax(p RelaxParams, maxIters int, tolerance float64) RelaxStats {
+	r := e.NewRelaxer(p)
+	for r.Stats.Iterations < maxIters {
+		if e.Progress.Cancelled() {
+			r.Stats.Cancelled = true
+			break
+		}
+		e.Progress.Set("Relaxing", float64(r.Stats.Iterations)/float64(maxIters))
+		if r.Iterate(tolerance) {
+			break
+		}
+	}
+	console.Infof("%s", r.Stats)
+	return r.Stats
+}
+
+// PanelHit is where a ray hits a panel
+type PanelHit struct {
+	Panel *Panel
+	v3.TriangleHit
+}
+
+// IntersectsPanels find which panels a segment intersects, nearest the start first
 func (e *EShell) IntersectsPanels(seg v3.Segment) (panels []*Panel, wheres []v3.Vec) {
-	dnorm := math32.Color{R: 1, G: 0, B: 0}
-	dsides := math32.Color{R: 0, G: 1, B: 1}
 	showSegs = append(showSegs, seg)
-	for i := 0; i < len(e.Panels); i++ {
-		p := e.Panels[i]
-		ed := p.Edges[0]
-		v := ed.Vertices[0]
-		v1 := ed.Vertices[1]
-		var ed2 *Edge // need to find which edge also has this vertex
-		if p.Edges[1].HasVertex(v) {
-			ed2 = p.Edges[1]
-		} else {
-			ed2 = p.Edges[2]
-		}
-		f0 := ed2.Vertices[0]
-		f1 := ed2.Vertices[1]
-		tri := v3.NewPatch(v.Position, p.Normal, ed.From(v), ed2.From(v))
-		whu, hits := tri.TriIntersectSegment(seg)
-		if hits {
-			panels = append(panels, p)
-			wheres = append(wheres, whu)
-			e.DebugLines = append(e.DebugLines, DebugLine{Start: v.Position, End: whu, Colour: DebugPurple})
-			e.DebugLines = append(e.DebugLines, DebugLine{Start: v.Position, End: v.Position.Add(p.Normal), Colour: dnorm})
-			e.DebugLines = append(e.DebugLines, DebugLine{Start: v.Position, End: v1.Position, Colour: dsides})
-			e.DebugLines = append(e.DebugLines, DebugLine{Start: f0.Position, End: f1.Position, Colour: dsides})
-			showTris = append(showTris, tri)
-		}
+	for _, h := range e.RayHits(seg.Ray(), seg.MaxD-seg.MinD) {
+		panels = append(panels, h.Panel)
+		wheres = append(wheres, h.Where)
+		e.DebugLines = append(e.DebugLines, DebugLine{Start: h.Panel.Corners[0].Position, End: h.Where, Colour: DebugPurple})
 	}
 	return panels, wheres
 }
 
-// CheckGeometry does some basic checks on shell geometry
-func (e *EShell) CheckGeometry() {
+// RayHits lists where the ray hits the alive panels within maxT of its origin, nearest first
+func (e *EShell) RayHits(r v3.Ray, maxT float64) []PanelHit {
+	hits := []PanelHit{}
+	for _, p := range e.PanelsNearSegment(v3.NewSegment(v3.NewLine(r.Origin, r.Dir), 0, maxT)) {
+		h, ok := r.IntersectTriangle(p.Corners[0].Position, p.Corners[1].Position, p.Corners[2].Position)
+		if ok && h.T <= maxT {
+			hits = append(hits, PanelHit{Panel: p, TriangleHit: h})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].T < hits[j].T })
+	return hits
+}
+
+// PickPanel is the nearest panel the ray hits within maxT, if any
+func (e *EShell) PickPanel(r v3.Ray, maxT float64) (PanelHit, bool) {
+	hits := e.RayHits(r, maxT)
+	if len(hits) == 0 {
+		return PanelHit{}, false
+	}
+	return hits[0], true
+}
+
+// CheckGeometry does some basic checks on the live parts of the shell geometry, reporting
+//   each problem on the console. Returns how many there were.
+func (e *EShell) CheckGeometry() int {
+	n := 0
 	for _, v := range e.Vertices {
-		ne := len(v.Edges)
+		if !v.Alive {
+			continue
+		}
+		ne := len(aliveEdges(v.Edges))
 		if ne < 2 {
-			err := tracerr.Errorf("Geometry error: vertex %d is on an incorrect number of edges: %d", v.Serial, ne)
-			tracerr.PrintSourceColor(err, 5, 2)
-			log.Fatal(err)
+			console.Errorf("Geometry: vertex %d is on an incorrect number of edges: %d", v.Serial, ne)
+			n++
 		}
-		np := len(v.Panels)
-		if np > 6 || ne < 1 {
-			err := tracerr.Errorf("Geometry error: vertex %d is on an incorrect number of panels: %d", v.Serial, np)
-			tracerr.PrintSourceColor(err, 5, 2)
-			log.Fatal(err)
+		np := len(alivePanels(v.Panels))
+		if np > maxPanelsPerVertex || ne < 1 {
+			console.Errorf("Geometry: vertex %d is on an incorrect number of panels: %d", v.Serial, np)
+			n++
 		}
 	}
 	for _, ed := range e.Edges {
+		if !ed.Alive {
+			continue
+		}
 		nv := len(ed.Vertices)
 		if nv != 2 {
-			err := tracerr.Errorf("Geometry error: edge %d should have 2 vertices, has %d (%v)", ed.Serial, nv, ed.Vertices)
-			tracerr.PrintSourceColor(err, 5, 2)
-			log.Fatal(err)
+			console.Errorf("Geometry: edge %d should have 2 vertices, has %d (%v)", ed.Serial, nv, ed.Vertices)
+			n++
 		}
-		np := len(ed.Panels)
+		np := len(alivePanels(ed.Panels))
 		if np > 2 || np < 1 {
-			err := tracerr.Errorf("Geometry error: edge %d should be on 1 or 2 panels, is on %d (%v)", ed.Serial, np, ed.Panels)
-			tracerr.PrintSourceColor(err, 5, 2)
-			log.Fatal(err)
+			console.Errorf("Geometry: edge %d should be on 1 or 2 panels, is on %d (%v)", ed.Serial, np, ed.Panels)
+			n++
 		}
 	}
 	for _, p := range e.Panels {
+		if !p.Alive {
+			continue
+		}
 		nv := len(p.Corners)
 		if nv != 3 {
-			err := tracerr.Errorf("Geometry error: Panel %d should have 3 corners, has %d (%v)", p.Serial, nv, p.Corners)
-			tracerr.PrintSourceColor(err, 5, 2)
-			log.Fatal(err)
+			console.Errorf("Geometry: Panel %d should have 3 corners, has %d (%v)", p.Serial, nv, p.Corners)
+			n++
 		}
 		ne := len(p.Edges)
 		if ne != 3 {
-			err := tracerr.Errorf("Geometry error: panel %d should have 3 edges, has %d (%v)", p.Serial, ne, p.Edges)
-			tracerr.PrintSourceColor(err, 5, 2)
-			log.Fatal(err)
+			console.Errorf("Geometry: panel %d should have 3 edges, has %d (%v)", p.Serial, ne, p.Edges)
+			n++
 		}
 	}
-
+	return n
 }
 
-// PrintGeometryProblems is
+// PrintGeometryProblems reports on the console anything wrong with how the vertices, edges
+//   and panels are joined, dead or alive
 func (e *EShell) PrintGeometryProblems() {
 	for _, v := range e.Vertices {
 		ne := len(v.Edges)
 		if ne < 2 {
-			fmt.Printf("Geometry error: vertex %d is on an incorrect number of edges: %d\n", v.Serial, ne)
+			console.Errorf("Geometry: vertex %d is on an incorrect number of edges: %d", v.Serial, ne)
 		}
 		np := len(v.Panels)
 		if np > 6 || ne < 1 {
-			fmt.Printf("Geometry error: vertex %d is on an incorrect number of panels: %d\n", v.Serial, np)
+			console.Errorf("Geometry: vertex %d is on an incorrect number of panels: %d", v.Serial, np)
 		}
 	}
 	for _, ed := range e.Edges {
 		nv := len(ed.Vertices)
 		if nv != 2 {
-			fmt.Printf("Geometry error: edge %d should have 2 vertices, has %d\n", ed.Serial, nv)
+			console.Errorf("Geometry: edge %d should have 2 vertices, has %d", ed.Serial, nv)
 		}
 		np := len(ed.Panels)
 		if np > 2 || np < 1 {
-			fmt.Printf("Geometry error: edge %d should be on 1 or 2 panels, is on %d\n", ed.Serial, np)
+			console.Errorf("Geometry: edge %d should be on 1 or 2 panels, is on %d", ed.Serial, np)
 		}
 	}
 	for _, p := range e.Panels {
 		nv := len(p.Corners)
 		if nv != 3 {
-			fmt.Printf("Geometry error: Panel %d should have 3 corners, has %d\n", p.Serial, nv)
+			console.Errorf("Geometry: panel %d should have 3 corners, has %d", p.Serial, nv)
 		}
 		ne := len(p.Edges)
 		if ne != 3 {
-			fmt.Printf("Geometry error: panel %d should have 3 edges, has %d\n", p.Serial, ne)
+			console.Errorf("Geometry: panel %d should have 3 edges, has %d", p.Serial, ne)
 		}
 	}
-
 }
 
-// CutWithPatch removes the parts of panels in the direction of the normal of the patch
-func (e *EShell) CutWithPatch(pat v3.Patch) []gl.ColourLine {
+// PreviewCutWithPatch returns lines showing where the patch would cut the panels, without cutting
+func (e *EShell) PreviewCutWithPatch(pat v3.Patch) []gl.ColourLine {
 	cuts := []gl.ColourLine{}
-	for _, pan := range e.Panels {
+	for _, pan := range e.PanelsInBox(patchBox(pat)) {
 		if pan.Alive {
 			hits := []v3.Vec{}
 			for _, ed := range pan.Edges {
-				seg := v3.NewSegment(v3.NewLine(ed.Vertices[0].Position, ed.Along), 0, 1)
-				where, hit := pat.ParaIntersectSegment(seg)
+				seg := v3.NewSegment(v3.NewLine(ed.Vertices[0].Position, ed.Along), 0, ed.Along.Length())
+				where, hit := pat.ParaIntersectSegmentTol(seg, v3.CutTolerance)
 				if hit {
 					hits = append(hits, where)
 				}
@@ -1182,6 +2160,206 @@ func (e *EShell) CutWithPatch(pat v3.Patch) []gl.ColourLine {
 	return cuts
 }
 
+// splitEdge is the result of splitting an edge in two at a new vertex
+type splitEdge struct {
+	at     *Vertex
+	halves []*Edge // halves[0] joins at to Vertices[0] of the original, halves[1] to Vertices[1]
+}
+
+// half returns the half of a split edge that reaches v
+func (se splitEdge) half(v *Vertex) *Edge {
+	if se.halves[0].HasVertex(v) {
+		return se.halves[0]
+	}
+	return se.halves[1]
+}
+
+// CutWithPatch splits the panels crossed by the patch along its plane. Both sides are kept:
+//   the pieces become sub-panels of the panel they were cut from. Panels beyond the ends of
+//   the patch that share a split edge are bisected so there are no hanging vertices.
+//   Returns the new edges lying along the cut.
+func (e *EShell) CutWithPatch(pat v3.Patch) []*Edge {
+
+	onPlane := v3.Tolerance{Length: 1e-9, Angle: v3.Exact.Angle} // for which side of the plane vertices are
+
+	side := func(v *Vertex) int {
+		return pat.Plane.SideTol(v.Position, onPlane)
+	}
+	crosses := func(ed *Edge) bool {
+		return side(ed.Vertices[0])*side(ed.Vertices[1]) < 0
+	}
+
+	// Find the edges to split: those of panels whose cut line overlaps the patch
+	toSplit := map[*Edge]bool{}
+	for _, p := range e.PanelsInBox(patchBox(pat)) {
+		var ends []v3.Vec
+		for _, ed := range p.Edges {
+			if crosses(ed) {
+				where, hits := pat.Plane.IntersectSegment(v3.NewSegment2Ends(ed.Vertices[0].Position, ed.Vertices[1].Position))
+				if hits {
+					ends = append(ends, where)
+				}
+			}
+		}
+		for _, c := range p.Corners { // the cut may run through a corner
+			if side(c) == 0 {
+				ends = append(ends, c.Position)
+			}
+		}
+		if len(ends) == 2 && pat.ParaOverlapsSegmentTol(ends[0], ends[1], v3.CutTolerance) {
+			for _, ed := range p.Edges {
+				if crosses(ed) {
+					toSplit[ed] = true
+				}
+			}
+		}
+	}
+	if len(toSplit) == 0 {
+		return nil
+	}
+
+	cst := OnPlane(pat.Plane)
+	splits := map[*Edge]splitEdge{}
+	for _, ed := range e.Edges { // in serial order, so results are repeatable
+		if !toSplit[ed] {
+			continue
+		}
+		v0 := ed.Vertices[0]
+		v1 := ed.Vertices[1]
+		where, _ := pat.Plane.IntersectSegment(v3.NewSegment2Ends(v0.Position, v1.Position))
+		nv := e.AddVertex(where, Constraints{cst})
+		splits[ed] = splitEdge{at: nv, halves: []*Edge{e.AddEdge([]*Vertex{v0, nv}), e.AddEdge([]*Vertex{nv, v1})}}
+		e.RemoveEdge(ed)
+	}
+
+	var cutEdges []*Edge
+	nPanels := len(e.Panels)
+	for pNo := 0; pNo < nPanels; pNo++ {
+		p := e.Panels[pNo]
+		if !p.Alive {
+			continue
+		}
+		var cut []*Edge
+		for _, ed := range p.Edges {
+			if _, ok := splits[ed]; ok {
+				cut = append(cut, ed)
+			}
+		}
+		var kids []*Panel
+		switch len(cut) {
+		case 0:
+			continue
+		case 1: // bisect, from the split point to the opposite corner
+			s := splits[cut[0]]
+			b := cut[0].Vertices[0]
+			c := cut[0].Vertices[1]
+			a := p.OtherCorner(b, c)
+			am := e.AddEdge([]*Vertex{a, s.at})
+			kids = append(kids, e.AddPanel([]*Edge{p.EdgeBetween(a, b), s.half(b), am}))
+			kids = append(kids, e.AddPanel([]*Edge{am, s.half(c), p.EdgeBetween(a, c)}))
+			if side(a) == 0 { // the cut runs through the corner
+				cutEdges = append(cutEdges, am)
+			}
+		case 2: // a small triangle one side, and a quadrilateral the other
+			s0 := splits[cut[0]]
+			s1 := splits[cut[1]]
+			var a *Vertex // the lone corner, shared by both cut edges
+			for _, v := range cut[0].Vertices {
+				if cut[1].HasVertex(v) {
+					a = v
+				}
+			}
+			b := cut[0].OtherEnd(a)
+			c := cut[1].OtherEnd(a)
+			mb := s0.at
+			mc := s1.at
+			line := e.AddEdge([]*Vertex{mb, mc})
+			cutEdges = append(cutEdges, line)
+			kids = append(kids, e.AddPanel([]*Edge{s0.half(a), line, s1.half(a)}))
+			bc := p.EdgeBetween(b, c)
+			if b.Position.Subtract(mc.Position).LengthSq() < c.Position.Subtract(mb.Position).LengthSq() {
+				diag := e.AddEdge([]*Vertex{b, mc})
+				kids = append(kids, e.AddPanel([]*Edge{s0.half(b), line, diag}))
+				kids = append(kids, e.AddPanel([]*Edge{diag, s1.half(c), bc}))
+			} else {
+				diag := e.AddEdge([]*Vertex{c, mb})
+				kids = append(kids, e.AddPanel([]*Edge{s1.half(c), line, diag}))
+				kids = append(kids, e.AddPanel([]*Edge{diag, s0.half(b), bc}))
+			}
+		default:
+			console.Errorf("Panel %d has %d split edges", p.Serial, len(cut))
+			continue
+		}
+		for _, k := range kids {
+			k.SubPanelOf = p
+			k.Material = p.Material
+			k.Emit = p.Emit
+		}
+		p.Kind = PTypeComplex
+		e.RemovePanel(p)
+	}
+
+	return cutEdges
+}
+
+// CutWithCutter cuts the shell along the sides of the cutter and removes all the
+//   panels inside it, making an opening, or a recess for a box cutter not going all the way
+//   through. Returns the edges around the opening.
+func (e *EShell) CutWithCutter(c *v3.Cutter) []*Edge {
+	if len(c.Walls) < len(v3.SidesOnly) {
+		console.Errorf("Cutter has no walls, cannot cut")
+		return nil
+	}
+	var walls []v3.Patch
+	sides := v3.SidesOnly
+	if c.Frame != nil { // a box cutter stops at its ends, so cut there too
+		sides = v3.AllWalls
+	}
+	for _, s := range sides {
+		walls = append(walls, c.Walls[s])
+	}
+	return e.cutOpening(walls, c.Contains)
+}
+
+// CutWithPrism cuts an opening of any convex outline, such as an arched door or a round vent,
+//   along the walls of the prism swept from it along its normal for depth, removing the panels
+//   inside. Returns the edges around the opening.
+func (e *EShell) CutWithPrism(pp v3.PolyPatch, depth float64) []*Edge {
+	if len(pp.Points) < 3 {
+		console.Errorf("Prism has fewer than 3 sides, cannot cut")
+		return nil
+	}
+	return e.cutOpening(pp.Walls(depth), func(p v3.Vec) bool { return pp.PrismContains(p, depth, v3.Exact) })
+}
+
+// cutOpening cuts along the walls, then removes the panels whose centers are inside
+func (e *EShell) cutOpening(walls []v3.Patch, inside func(p v3.Vec) bool) []*Edge {
+	var cutEdges []*Edge
+	for _, w := range walls {
+		cutEdges = append(cutEdges, e.CutWithPatch(w)...)
+	}
+	for _, p := range e.Panels {
+		if p.Alive {
+			p.Update(e)
+			if inside(p.Center) {
+				e.RemovePanel(p)
+			}
+		}
+	}
+	e.Undertaker()
+	var opening []*Edge
+	for _, ed := range cutEdges {
+		if ed.Alive && ed.IsBoundary() {
+			opening = append(opening, ed)
+		}
+	}
+	for _, ed := range opening {
+		e.Cuts = append(e.Cuts, CutSegment{start: ed.Vertices[0].Position, end: ed.Vertices[1].Position})
+	}
+	e.afterCut()
+	return opening
+}
+
 // ██╗   ██╗████████╗██╗██╗     ███████╗
 // ██║   ██║╚══██╔══╝██║██║     ██╔════╝
 // ██║   ██║   ██║   ██║██║     ███████╗
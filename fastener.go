@@ -0,0 +1,193 @@
+package main
+
+// ███████╗ █████╗ ███████╗████████╗███████╗███╗   ██╗
+// ██╔════╝██╔══██╗██╔════╝╚══██╔══╝██╔════╝████╗  ██║
+// █████╗  ███████║███████╗   ██║   █████╗  ██╔██╗ ██║
+// ██╔══╝  ██╔══██║╚════██║   ██║   ██╔══╝  ██║╚██╗██║
+// ██║     ██║  ██║███████║   ██║   ███████╗██║ ╚████║
+// ╚═╝     ╚═╝  ╚═╝╚══════╝   ╚═╝   ╚══════╝╚═╝  ╚═══╝
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"sort"
+
+	v3 "./vec"
+)
+
+// Pitch rules for fastening hemmed seams
+const (
+	seamFastenerPitch = 0.15  // m, max spacing along a seam
+	seamFastenerEnd   = 0.025 // m, from each end of the seam to the first fastener
+)
+
+// FastenerSize is a fastener and the thickest stack of sheet it grips
+type FastenerSize struct {
+	Kind    string
+	Length  float64 // m
+	MaxGrip float64 // m
+}
+
+// FastenerSizes are those used, the shortest that grips a joint is used for it
+var FastenerSizes = []FastenerSize{
+	{"4.8mm blind rivet", 0.008, 0.0032},
+	{"4.8mm blind rivet", 0.010, 0.0048},
+	{"4.8mm blind rivet", 0.012, 0.0064},
+	{"4.8mm blind rivet", 0.016, 0.0095},
+	{"M6 bolt", 0.020, 0.012},
+	{"M6 bolt", 0.030, 0.020},
+}
+
+// fastenerFor is the shortest size which grips the stack of sheet, m, false if none do
+func fastenerFor(stack float64) (FastenerSize, bool) {
+	for _, s := range FastenerSizes {
+		if stack <= s.MaxGrip {
+			return s, true
+		}
+	}
+	return FastenerSizes[len(FastenerSizes)-1], false
+}
+
+// pitchPositions are where holes go along a run of length l, as fractions of it, no more than
+//   pitch apart: the first and last end from the ends, or one in the middle if it is too short;
+//   or with no end distance, spread evenly with half a space at each end. None if no pitch.
+func pitchPositions(l, pitch, end float64) []float64 {
+	var ats []float64
+	switch {
+	case pitch <= 0:
+	case end > 0 && l > 2*end:
+		n := int(math.Ceil((l - 2*end) / pitch))
+		for i := 0; i <= n; i++ {
+			ats = append(ats, (end+(l-2*end)*float64(i)/float64(n))/l)
+		}
+	case end > 0:
+		ats = append(ats, 0.5)
+	default:
+		n := int(math.Ceil(l / pitch))
+		for i := 0; i < n; i++ {
+			ats = append(ats, (float64(i)+0.5)/float64(n))
+		}
+	}
+	return ats
+}
+
+// SeamFastening is the fasteners along one hemmed seam
+type SeamFastening struct {
+	Edge   *Edge
+	Open   *Panel // with the open hem, hooked round the closed one
+	Closed *Panel
+	Stack  float64 // m, of sheet the fasteners go through: both legs of each hem
+	Size   FastenerSize
+	At     []v3.Vec // where each goes, in the middle of the hems
+}
+
+// FastenerCount is how many of one size of fastener there are
+type FastenerCount struct {
+	FastenerSize
+	Count int
+}
+
+// FastenerSchedule is the fasteners of every hemmed seam, and their totals
+type FastenerSchedule struct {
+	Seams      []SeamFastening
+	Counts     []FastenerCount // by kind, then length
+	Unfastened int             // seams with no pair of hems to fasten through
+	TooThick   int             // seams too thick for any fastener, given the longest
+}
+
+// Fasteners works out the schedule: along every seam with an open and a closed hem, fasteners
+//   no more than seamFastenerPitch apart, through the four thicknesses of the hems, halfway down
+//   them
+func (e *EShell) Fasteners() FastenerSchedule {
+	var s FastenerSchedule
+	counts := map[FastenerSize]int{}
+	for _, ed := range aliveEdges(e.Edges) {
+		ps := alivePanels(ed.Panels)
+		if len(ps) != 2 {
+			continue
+		}
+		if ed.Treatment != ETreatOpenHemMk1 && ed.Treatment != ETreatClosedHemMk1 {
+			s.Unfastened++
+			continue
+		}
+		f := SeamFastening{Edge: ed, Open: ps[0], Closed: ps[1]}
+		if ed.HemTreatment(ps[0]) != ETreatOpenHemMk1 {
+			f.Open, f.Closed = ps[1], ps[0]
+		}
+		f.Stack = 2*f.Open.SheetGauge().Thickness + 2*f.Closed.SheetGauge().Thickness
+		ok := false
+		if f.Size, ok = fastenerFor(f.Stack); !ok {
+			s.TooThick++
+		}
+		size := ed.HemSize
+		if size <= 0 {
+			size = hemDefaultSize
+		}
+		a, b := ed.Vertices[0].Position, ed.Vertices[1].Position
+		down := f.Open.Normal.Add(f.Closed.Normal).Normalized().Scale(-size / 2)
+		for _, t := range pitchPositions(b.Subtract(a).Length(), seamFastenerPitch, seamFastenerEnd) {
+			f.At = append(f.At, a.Add(b.Subtract(a).Scale(t)).Add(down))
+		}
+		counts[f.Size] += len(f.At)
+		s.Seams = append(s.Seams, f)
+	}
+	for sz, n := range counts {
+		s.Counts = append(s.Counts, FastenerCount{FastenerSize: sz, Count: n})
+	}
+	sort.Slice(s.Counts, func(i, j int) bool {
+		if s.Counts[i].Kind != s.Counts[j].Kind {
+			return s.Counts[i].Kind < s.Counts[j].Kind
+		}
+		return s.Counts[i].Length < s.Counts[j].Length
+	})
+	return s
+}
+
+// Total is the number of fasteners of every size
+func (s FastenerSchedule) Total() int {
+	n := 0
+	for _, c := range s.Counts {
+		n += c.Count
+	}
+	return n
+}
+
+func (c FastenerCount) String() string {
+	return fmt.Sprintf("%d %s x %.0fmm", c.Count, c.Kind, c.Length*m2mm)
+}
+
+// String is the totals, for the stats
+func (s FastenerSchedule) String() string {
+	str := fmt.Sprintf("Fasteners: %d on %d hemmed seams", s.Total(), len(s.Seams))
+	for _, c := range s.Counts {
+		str += fmt.Sprintf(", %s", c)
+	}
+	str += "\n"
+	if s.Unfastened > 0 {
+		str += fmt.Sprintf("   %d seams not hemmed, so not fastened\n", s.Unfastened)
+	}
+	if s.TooThick > 0 {
+		str += fmt.Sprintf("ERROR: %d seams are too thick for the longest fastener\n", s.TooThick)
+	}
+	return str
+}
+
+// CSV writes the schedule, a line per seam, then the totals of each size
+func (s FastenerSchedule) CSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Edge", "Open hem", "Closed hem", "Length (m)", "Stack (mm)", "Fastener", "Fastener length (mm)", "Count"})
+	for _, f := range s.Seams {
+		a, b := f.Edge.Vertices[0].Position, f.Edge.Vertices[1].Position
+		w.Write([]string{fmt.Sprint(f.Edge.Serial), PanelLabel(f.Open), PanelLabel(f.Closed),
+			fmt.Sprintf("%.3f", b.Subtract(a).Length()), fmt.Sprintf("%.2f", f.Stack*m2mm),
+			f.Size.Kind, fmt.Sprintf("%.0f", f.Size.Length*m2mm), fmt.Sprint(len(f.At))})
+	}
+	for _, c := range s.Counts {
+		w.Write([]string{"Total", "", "", "", "", c.Kind, fmt.Sprintf("%.0f", c.Length*m2mm), fmt.Sprint(c.Count)})
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
@@ -0,0 +1,181 @@
+package main
+
+// ███████╗███████╗ █████╗
+// ██╔════╝██╔════╝██╔══██╗
+// █████╗  █████╗  ███████║
+// ██╔══╝  ██╔══╝  ██╔══██║
+// ██║     ███████╗██║  ██║
+// ╚═╝     ╚══════╝╚═╝  ╚═╝
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	cam "./cam"
+)
+
+// FEAFormat is the input format of a finite element solver
+type FEAFormat int
+
+// FEA formats
+const (
+	FEACalculix FEAFormat = iota // CalculiX / Abaqus .inp
+	FEANastran                   // Nastran bulk data, free field
+)
+
+// String gives the usual file extension for the format
+func (f FEAFormat) String() string {
+	switch f {
+	case FEACalculix:
+		return ".inp"
+	case FEANastran:
+		return ".bdf"
+	}
+	return "?"
+}
+
+// elasticity is Young's modulus (Pa) and Poisson's ratio for each base material, as
+//   typical figures; the materials database does not have them
+var elasticity = map[cam.MaterialBase][2]float64{
+	cam.MatColdRolled: {200e9, 0.29},
+	cam.MatHotRolled:  {200e9, 0.29},
+	cam.MatStainless:  {193e9, 0.29},
+	cam.MatAl:         {68.9e9, 0.33},
+	cam.MatTi:         {114e9, 0.34},
+	cam.MatCu:         {117e9, 0.34},
+	cam.MatBrass:      {100e9, 0.34},
+	cam.MatExotic:     {190e9, 0.30},
+}
+
+// feaSection is a group of elements cut from the same sheet
+type feaSection struct {
+	Name      string
+	Material  cam.Material
+	Thickness float64 // m
+	Elements  [][]int // node numbers of each tria
+}
+
+// feaModel is the shell as nodes and tria elements, numbered from 1, in SI units
+type feaModel struct {
+	Nodes    []*Vertex
+	Sections []*feaSection
+	Base     []int // nodes on the base, to be fixed
+}
+
+// feaMesh makes the nodes from the vertices and the elements from the panels, grouped by
+//   material and gauge. Panels with more than three corners are split into a fan of trias.
+func (e *EShell) feaMesh(mats cam.MaterialSet) feaModel {
+	m := feaModel{}
+	node := map[*Vertex]int{}
+	for _, v := range e.Vertices {
+		if v.Alive {
+			m.Nodes = append(m.Nodes, v)
+			node[v] = len(m.Nodes)
+			if math.Abs(v.Position.Z()-e.Base) < onBaseTolerance {
+				m.Base = append(m.Base, node[v])
+			}
+		}
+	}
+
+	sections := map[string]*feaSection{}
+	for _, p := range e.Panels {
+		if !p.Alive || len(p.Corners) < 3 {
+			continue
+		}
+		g := p.SheetGauge()
+		matID := p.SheetMaterial()
+		name := fmt.Sprintf("%s_%s", matID, g.ID)
+		sec, ok := sections[name]
+		if !ok {
+			mat, found := mats[matID]
+			if !found {
+				console.Warnf("Panel %d material '%s' not known, using mild steel", p.Serial, matID)
+				mat = cam.Material{ID: matID, Base: cam.MatColdRolled, Density: 7850}
+			}
+			sec = &feaSection{Name: name, Material: mat, Thickness: g.Thickness}
+			sections[name] = sec
+		}
+		for i := 1; i < len(p.Corners)-1; i++ {
+			sec.Elements = append(sec.Elements, []int{node[p.Corners[0]], node[p.Corners[i]], node[p.Corners[i+1]]})
+		}
+	}
+	for _, sec := range sections {
+		m.Sections = append(m.Sections, sec)
+	}
+	sort.Slice(m.Sections, func(i, j int) bool { return m.Sections[i].Name < m.Sections[j].Name })
+	return m
+}
+
+// FEAMesh writes the shell as a mesh of shell elements for structural analysis: a node at each
+//   vertex, trias from the panels with the thickness of their gauge, and the base nodes fixed.
+//   Units are m, N, Pa and kg/m3.
+func (e *EShell) FEAMesh(f FEAFormat, mats cam.MaterialSet) (string, error) {
+	m := e.feaMesh(mats)
+	switch f {
+	case FEACalculix:
+		return m.calculix(), nil
+	case FEANastran:
+		return m.nastran(), nil
+	}
+	return "", fmt.Errorf("Unknown FEA format %d", f)
+}
+
+// calculix writes the mesh as a CalculiX input deck, with S3 elements
+func (m feaModel) calculix() string {
+	s := "*HEADING\nEggstreme shell\n*NODE, NSET=NALL\n"
+	for i, v := range m.Nodes {
+		s += fmt.Sprintf("%d, %.6f, %.6f, %.6f\n", i+1, v.Position.X(), v.Position.Y(), v.Position.Z())
+	}
+	eid := 1
+	for _, sec := range m.Sections {
+		s += fmt.Sprintf("*ELEMENT, TYPE=S3, ELSET=%s\n", sec.Name)
+		for _, el := range sec.Elements {
+			s += fmt.Sprintf("%d, %d, %d, %d\n", eid, el[0], el[1], el[2])
+			eid++
+		}
+	}
+	for _, sec := range m.Sections {
+		el := elasticity[sec.Material.Base]
+		s += fmt.Sprintf("*MATERIAL, NAME=M_%s\n*ELASTIC\n%g, %g\n*DENSITY\n%g\n", sec.Name, el[0], el[1], sec.Material.Density)
+		s += fmt.Sprintf("*SHELL SECTION, ELSET=%s, MATERIAL=M_%s\n%g\n", sec.Name, sec.Name, sec.Thickness)
+	}
+	if len(m.Base) > 0 {
+		s += "*NSET, NSET=BASE\n"
+		for i, n := range m.Base {
+			s += fmt.Sprint(n)
+			if i%8 == 7 || i == len(m.Base)-1 {
+				s += "\n"
+			} else {
+				s += ", "
+			}
+		}
+		s += "*BOUNDARY\nBASE, 1, 6\n"
+	}
+	return s
+}
+
+// nastran writes the mesh as Nastran free field bulk data, with CTRIA3 elements
+func (m feaModel) nastran() string {
+	s := "$ Eggstreme shell\nBEGIN BULK\n"
+	for i, v := range m.Nodes {
+		s += fmt.Sprintf("GRID,%d,,%.6f,%.6f,%.6f\n", i+1, v.Position.X(), v.Position.Y(), v.Position.Z())
+	}
+	eid := 1
+	for pid, sec := range m.Sections {
+		s += fmt.Sprintf("$ %s\n", sec.Name)
+		for _, el := range sec.Elements {
+			s += fmt.Sprintf("CTRIA3,%d,%d,%d,%d,%d\n", eid, pid+1, el[0], el[1], el[2])
+			eid++
+		}
+	}
+	for pid, sec := range m.Sections {
+		el := elasticity[sec.Material.Base]
+		s += fmt.Sprintf("PSHELL,%d,%d,%g,%d,,%d\n", pid+1, pid+1, sec.Thickness, pid+1, pid+1)
+		s += fmt.Sprintf("MAT1,%d,%g,,%g,%g\n", pid+1, el[0], el[1], sec.Material.Density)
+	}
+	for _, n := range m.Base {
+		s += fmt.Sprintf("SPC1,1,123456,%d\n", n)
+	}
+	return s + "ENDDATA\n"
+}
@@ -0,0 +1,145 @@
+package main
+
+// ███████╗██╗ ██████╗ ██╗   ██╗██████╗ ███████╗███████╗
+// ██╔════╝██║██╔════╝ ██║   ██║██╔══██╗██╔════╝██╔════╝
+// █████╗  ██║██║  ███╗██║   ██║██████╔╝█████╗  ███████╗
+// ██╔══╝  ██║██║   ██║██║   ██║██╔══██╗██╔══╝  ╚════██║
+// ██║     ██║╚██████╔╝╚██████╔╝██║  ██║███████╗███████║
+// ╚═╝     ╚═╝ ╚═════╝  ╚═════╝ ╚═╝  ╚═╝╚══════╝╚══════╝
+
+import (
+	"math"
+
+	v3 "./vec"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+// Figure is a reference model stood on the floor, to judge the size of the shell by
+type Figure int
+
+// Values of Figure
+const (
+	Human     Figure = iota // 1.8m tall
+	Car                     // a family saloon
+	Workbench               // 1.8m long, 0.9m high
+)
+
+// Figures are in the order their checkboxes are shown
+var Figures = []Figure{Human, Car, Workbench}
+
+func (f Figure) String() string {
+	switch f {
+	case Car:
+		return "Car"
+	case Workbench:
+		return "Workbench"
+	}
+	return "Human"
+}
+
+// Where the figures stand
+const (
+	figureInset = 0.5 // m, a human stands inside a door
+	figureOut   = 3.0 // m, a car is parked beyond the shell
+)
+
+// figureBlock is one block a figure is made of, its bottom centre at X, Y, Z from where the
+//   figure stands, W along X, D along Y and H up. Figures face +X.
+type figureBlock struct {
+	X, Y, Z float64
+	W, D, H float64
+}
+
+// blocks are what the figure is made of
+func (f Figure) blocks() []figureBlock {
+	switch f {
+	case Car:
+		return []figureBlock{
+			{0, 0, 0.3, 4.5, 1.8, 0.7},     // body
+			{-0.2, 0, 1.0, 2.4, 1.6, 0.45}, // cabin
+			{1.4, 0.8, 0, 0.65, 0.25, 0.65},
+			{1.4, -0.8, 0, 0.65, 0.25, 0.65},
+			{-1.4, 0.8, 0, 0.65, 0.25, 0.65},
+			{-1.4, -0.8, 0, 0.65, 0.25, 0.65},
+		}
+	case Workbench:
+		return []figureBlock{
+			{0, 0, 0.85, 1.8, 0.6, 0.05}, // top
+			{0.84, 0.24, 0, 0.06, 0.06, 0.85},
+			{0.84, -0.24, 0, 0.06, 0.06, 0.85},
+			{-0.84, 0.24, 0, 0.06, 0.06, 0.85},
+			{-0.84, -0.24, 0, 0.06, 0.06, 0.85},
+		}
+	}
+	return []figureBlock{
+		{0, 0.1, 0, 0.14, 0.14, 0.85}, // legs
+		{0, -0.1, 0, 0.14, 0.14, 0.85},
+		{0, 0, 0.85, 0.22, 0.4, 0.6}, // torso
+		{0, 0.25, 0.8, 0.09, 0.09, 0.65},
+		{0, -0.25, 0.8, 0.09, 0.09, 0.65},
+		{0, 0, 1.45, 0.1, 0.1, 0.1},   // neck
+		{0, 0, 1.55, 0.22, 0.2, 0.25}, // head, up to 1.8m
+	}
+}
+
+// colour is what the figure is painted
+func (f Figure) colour() math32.Color {
+	switch f {
+	case Car:
+		return math32.Color{R: 0.2, G: 0.35, B: 0.8}
+	case Workbench:
+		return math32.Color{R: 0.6, G: 0.4, B: 0.2}
+	}
+	return math32.Color{R: 0.9, G: 0.7, B: 0.55}
+}
+
+// FigurePlace is where the figure stands on the floor and which way it faces: a human just
+//   inside the first door, looking out through it, a workbench across the floor from them,
+//   and a car parked outside beyond the door
+func (e *EShell) FigurePlace(f Figure) (v3.Vec, v3.Radians) {
+	out := v3.NewSimVec(1, 0, 0)
+	if len(e.Doors) > 0 {
+		c := e.Doors[0].Center()
+		if h := math.Hypot(c.X(), c.Y()); h > v3.PlanckLength {
+			out = v3.NewSimVec(c.X()/h, c.Y()/h, 0)
+		}
+	}
+	heading := v3.Radians(math.Atan2(out.Y(), out.X()))
+	b := e.Bounds()
+	floor := v3.NewSimVec(0, 0, e.Base)
+	switch f {
+	case Car:
+		r := math.Max(math.Max(-b.Min.X(), b.Max.X()), math.Max(-b.Min.Y(), b.Max.Y()))
+		return floor.Add(out.Scale(r + figureOut)), heading + math.Pi/2
+	case Workbench:
+		r := math.Min(math.Min(-b.Min.X(), b.Max.X()), math.Min(-b.Min.Y(), b.Max.Y()))
+		return floor.Add(out.Scale(-r / 2)), heading + math.Pi/2
+	}
+	if len(e.Doors) > 0 {
+		c := e.Doors[0].Center()
+		at := v3.NewSimVec(c.X(), c.Y(), e.Base).Subtract(out.Scale(figureInset))
+		return at, heading
+	}
+	return floor, heading
+}
+
+// PrepFigure makes the figure, stood in its place
+func (e *EShell) PrepFigure(f Figure) *core.Node {
+	n := core.NewNode()
+	c := f.colour()
+	mat := material.NewStandard(&c)
+	for _, b := range f.blocks() { // the viewer has Y up
+		m := graphic.NewMesh(geometry.NewBox(float32(b.W), float32(b.H), float32(b.D)), mat)
+		m.SetPosition(float32(b.X), float32(b.Z+b.H/2), float32(b.Y))
+		n.Add(m)
+	}
+	at, heading := e.FigurePlace(f)
+	n.SetPosition(float32(at.X()), float32(at.Z()), float32(at.Y()))
+	n.SetRotationY(float32(-heading)) // anticlockwise seen from above is clockwise about the viewer's Y
+	return n
+}
@@ -0,0 +1,191 @@
+package main
+
+// ███████╗██╗██╗     ███████╗███████╗
+// ██╔════╝██║██║     ██╔════╝██╔════╝
+// █████╗  ██║██║     █████╗  ███████╗
+// ██╔══╝  ██║██║     ██╔══╝  ╚════██║
+// ██║     ██║███████╗███████╗███████║
+// ╚═╝     ╚═╝╚══════╝╚══════╝╚══════╝
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/math32"
+)
+
+// Sizes of the file dialog
+const (
+	fileDialogWidth  = 320
+	fileDialogHeight = 260
+)
+
+// FileDialog asks for the name of a file to save or open, in a panel over the rest of the GUI,
+//   listing the files in the folder with the extension wanted
+type FileDialog struct {
+	Panel   *gui.Panel
+	title   *gui.Label
+	dir     *gui.Edit
+	name    *gui.Edit
+	files   *gui.List
+	warn    *gui.Label
+	ok      *gui.Button
+	ext     string
+	save    bool
+	confirm string // the file the user has been warned will be overwritten
+	then    func(fname string)
+}
+
+// fileDialog is the one the export buttons use, made with the GUI
+var fileDialog *FileDialog
+
+// NewFileDialog makes a hidden dialog in the parent panel
+func NewFileDialog(parent *gui.Panel) *FileDialog {
+	fd := &FileDialog{Panel: gui.NewPanel(fileDialogWidth, fileDialogHeight)}
+	fd.Panel.SetColor4(&math32.Color4{R: 0.1, G: 0.1, B: 0.2, A: 0.95})
+	fd.Panel.SetPosition(200, 150)
+	fd.Panel.SetVisible(false)
+
+	fd.title = gui.NewLabel("")
+	fd.title.SetPosition(10, 8)
+	fd.Panel.Add(fd.title)
+	dirLabel := gui.NewLabel("Folder")
+	dirLabel.SetPosition(10, 32)
+	fd.Panel.Add(dirLabel)
+	fd.dir = gui.NewEdit(250, "")
+	fd.dir.SetPosition(60, 30)
+	fd.dir.Subscribe(gui.OnChange, func(name string, ev interface{}) { fd.refresh() })
+	fd.Panel.Add(fd.dir)
+
+	fd.files = gui.NewVList(300, 140)
+	fd.files.SetSingle(true)
+	fd.files.SetPosition(10, 55)
+	fd.files.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		if sel := fd.files.Selected(); len(sel) == 1 {
+			if l, ok := sel[0].(*gui.ImageLabel); ok {
+				fd.name.SetText(l.Text())
+				fd.warn.SetText("")
+			}
+		}
+	})
+	fd.Panel.Add(fd.files)
+
+	nameLabel := gui.NewLabel("Name")
+	nameLabel.SetPosition(10, 202)
+	fd.Panel.Add(nameLabel)
+	fd.name = gui.NewEdit(250, "")
+	fd.name.SetPosition(60, 200)
+	fd.name.Subscribe(gui.OnChange, func(name string, ev interface{}) { fd.warn.SetText("") })
+	fd.Panel.Add(fd.name)
+
+	fd.warn = gui.NewLabel("")
+	fd.warn.SetPosition(10, 236)
+	fd.warn.SetColor(&math32.Color{R: 1, G: 0.4, B: 0.4})
+	fd.Panel.Add(fd.warn)
+	fd.ok = gui.NewButton("Save")
+	fd.ok.SetPosition(200, 230)
+	fd.ok.Subscribe(gui.OnClick, func(name string, ev interface{}) { fd.done() })
+	fd.Panel.Add(fd.ok)
+	cancel := gui.NewButton("Cancel")
+	cancel.SetPosition(250, 230)
+	cancel.Subscribe(gui.OnClick, func(name string, ev interface{}) { fd.Panel.SetVisible(false) })
+	fd.Panel.Add(cancel)
+
+	parent.Add(fd.Panel)
+	return fd
+}
+
+// Save asks for a file to save to, then calls then with its name, with ext on the end. If it,
+//   or the files named after it, are already there, the user has to say save twice.
+func (fd *FileDialog) Save(ext string, then func(fname string)) {
+	fd.show("Save as "+ext, ext, true, then)
+}
+
+// Open asks for a file to read, which must be there, then calls then with its name
+func (fd *FileDialog) Open(ext string, then func(fname string)) {
+	fd.show("Open "+ext, ext, false, then)
+}
+
+func (fd *FileDialog) show(title, ext string, save bool, then func(fname string)) {
+	fd.title.SetText(title)
+	fd.ext, fd.save, fd.then, fd.confirm = ext, save, then, ""
+	fd.ok.Label.SetText("Open")
+	if save {
+		fd.ok.Label.SetText("Save")
+	}
+	if fd.dir.Text() == "" {
+		if wd, err := os.Getwd(); err == nil {
+			fd.dir.SetText(wd)
+		}
+	}
+	fd.warn.SetText("")
+	fd.refresh()
+	fd.Panel.SetVisible(true)
+}
+
+// refresh lists the files in the folder with the extension
+func (fd *FileDialog) refresh() {
+	fd.files.Clear()
+	fs, err := ioutil.ReadDir(fd.dir.Text())
+	if err != nil {
+		fd.warn.SetText(err.Error())
+		return
+	}
+	var names []string
+	for _, f := range fs {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), fd.ext) {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fd.files.Add(gui.NewImageLabel(n))
+	}
+}
+
+// done checks the name, asking again before overwriting, then hides the dialog and carries on
+func (fd *FileDialog) done() {
+	n := strings.TrimSpace(fd.name.Text())
+	if n == "" {
+		fd.warn.SetText("Enter a name")
+		return
+	}
+	fname := withExtension(filepath.Join(fd.dir.Text(), n), fd.ext)
+	if fd.save {
+		if ex := existingOutputs(fname, fd.ext); len(ex) > 0 && fd.confirm != fname {
+			fd.confirm = fname
+			fd.warn.SetText(fmt.Sprintf("%d files there, Save to overwrite", len(ex)))
+			return
+		}
+	} else if _, err := os.Stat(fname); err != nil {
+		fd.warn.SetText(fmt.Sprintf("No %s", filepath.Base(fname)))
+		return
+	}
+	fd.Panel.SetVisible(false)
+	fd.then(fname)
+}
+
+// withExtension adds ext to the name if it does not already end with it
+func withExtension(fname, ext string) string {
+	if !strings.HasSuffix(fname, ext) {
+		fname += ext
+	}
+	return fname
+}
+
+// existingOutputs are the files already there which saving to fname would overwrite: it, and
+//   the files named after it, eg shell_sheet1.nc or shell.json for shell.csv
+func existingOutputs(fname, ext string) []string {
+	stem := strings.TrimSuffix(fname, ext)
+	var ex []string
+	for _, pat := range []string{stem + ".*", stem + "_*"} {
+		ms, _ := filepath.Glob(pat)
+		ex = append(ex, ms...)
+	}
+	return ex
+}
@@ -0,0 +1,118 @@
+package main
+
+// ███████╗██╗████████╗
+// ██╔════╝██║╚══██╔══╝
+// █████╗  ██║   ██║
+// ██╔══╝  ██║   ██║
+// ██║     ██║   ██║
+// ╚═╝     ╚═╝   ╚═╝
+
+import (
+	"fmt"
+	"math"
+)
+
+// seamFitTolerance is how much the finished lengths of two mating edges may differ, m
+const seamFitTolerance = 0.0005
+
+// SeamLength is the finished length of the panel along the edge, m, where its flat pattern turns
+//   into the seam: the first bend of the edge's hem or flange, or the cut edge if it has none,
+//   between the sides either side of it as they are moved out by their own hems and bends
+func (p *Panel) SeamLength(ed *Edge) (float64, bool) {
+	if len(p.Corners) < 3 {
+		return 0, false
+	}
+	sides := p.flatSides()
+	n := len(sides)
+	for i, sd := range sides {
+		if sd.edge != ed {
+			continue
+		}
+		off := sd.extension
+		if len(sd.bends) > 0 {
+			off = sd.bends[0].At
+		}
+		at := sd.a.Add(sd.out.Scale(off * m2mm))
+		prev, next := sides[(i+n-1)%n], sides[(i+1)%n]
+		a, ok1 := intersect2(prev.at, prev.along, at, sd.along)
+		b, ok2 := intersect2(at, sd.along, next.at, next.along)
+		if !ok1 || !ok2 {
+			return sd.b.Subtract(sd.a).Length() / m2mm, true
+		}
+		return b.Subtract(a).Length() / m2mm, true
+	}
+	return 0, false
+}
+
+// SeamFit is the finished lengths of the two panels along a seam
+type SeamFit struct {
+	Edge    *Edge
+	Panels  [2]*Panel
+	Lengths [2]float64 // m
+}
+
+// Mismatch is how much longer one panel is along the seam than the other, m
+func (f SeamFit) Mismatch() float64 {
+	return math.Abs(f.Lengths[0] - f.Lengths[1])
+}
+
+func (f SeamFit) String() string {
+	return fmt.Sprintf("seam %d: panel %s is %.1fmm, panel %s %.1fmm, %.1fmm out",
+		f.Edge.Serial, PanelLabel(f.Panels[0]), f.Lengths[0]*m2mm, PanelLabel(f.Panels[1]), f.Lengths[1]*m2mm, f.Mismatch()*m2mm)
+}
+
+// SeamFits compares the finished lengths of the two panels of every seam with one of the panels
+//   in ps, or of every seam if ps is nil
+func (e *EShell) SeamFits(ps []*Panel) []SeamFit {
+	in := map[*Panel]bool{}
+	for _, p := range ps {
+		in[p] = true
+	}
+	var fs []SeamFit
+	for _, ed := range aliveEdges(e.Edges) {
+		pp := alivePanels(ed.Panels)
+		if len(pp) != 2 || (ps != nil && !in[pp[0]] && !in[pp[1]]) {
+			continue
+		}
+		f := SeamFit{Edge: ed, Panels: [2]*Panel{pp[0], pp[1]}}
+		ok := true
+		for i, p := range pp {
+			var found bool
+			f.Lengths[i], found = p.SeamLength(ed)
+			ok = ok && found
+		}
+		if ok {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
+
+// Misfits are the seams of SeamFits whose panels differ in length by more than seamFitTolerance
+func (e *EShell) Misfits(ps []*Panel) []SeamFit {
+	var ms []SeamFit
+	for _, f := range e.SeamFits(ps) {
+		if f.Mismatch() > seamFitTolerance {
+			ms = append(ms, f)
+		}
+	}
+	return ms
+}
+
+// FitReport is the worst mismatch of all the seams, and every one beyond tolerance, for the stats
+func (e *EShell) FitReport() string {
+	fs := e.SeamFits(nil)
+	worst := 0.0
+	var ms []SeamFit
+	for _, f := range fs {
+		worst = math.Max(worst, f.Mismatch())
+		if f.Mismatch() > seamFitTolerance {
+			ms = append(ms, f)
+		}
+	}
+	s := fmt.Sprintf("Fit: %d seams, worst %.2fmm out, %d beyond %.1fmm\n", len(fs), worst*m2mm, len(ms), seamFitTolerance*m2mm)
+	for _, f := range ms {
+		s += fmt.Sprintf("ERROR: %s\n", f)
+	}
+	return s
+}
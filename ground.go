@@ -0,0 +1,203 @@
+package main
+
+//  ██████╗ ██████╗  ██████╗ ██╗   ██╗███╗   ██╗██████╗
+// ██╔════╝ ██╔══██╗██╔═══██╗██║   ██║████╗  ██║██╔══██╗
+// ██║  ███╗██████╔╝██║   ██║██║   ██║██╔██╗ ██║██║  ██║
+// ██║   ██║██╔══██╗██║   ██║██║   ██║██║╚██╗██║██║  ██║
+// ╚██████╔╝██║  ██║╚██████╔╝╚██████╔╝██║ ╚████║██████╔╝
+//  ╚═════╝ ╚═╝  ╚═╝ ╚═════╝  ╚═════╝ ╚═╝  ╚═══╝╚═════╝
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	cam "./cam"
+	v3 "./vec"
+)
+
+// Anchor bolt spacing for the ground flanges, as for sill plates: no more than 6' apart and
+//   within 12" of the end of each piece, 1/2" bolts
+const (
+	groundAnchorPitch = 6 * ft2m         // m, max spacing of anchors
+	groundAnchorEnd   = 12 * in2m        // m, max distance from the end of a flange to its first anchor
+	groundAnchorDia   = 0.5*in2m + 0.002 // m, clearance hole for a 1/2" anchor bolt
+)
+
+// Anchor is a ground anchor bolt position, for marking out the slab
+type Anchor struct {
+	N       int     // number, counting anticlockwise from the +X axis
+	Pos     v3.Vec  // where it is, on the base
+	Dia     float64 // m, of the hole in the flange
+	Bearing float64 // degrees anticlockwise from +X
+	Flange  *Flange // which flange it is in
+}
+
+// onBase is true if the edge lies along the base plane
+func (e *EShell) onBase(ed *Edge) bool {
+	return math.Abs(ed.Vertices[0].Position.Z()-e.Base) < onBaseTolerance &&
+		math.Abs(ed.Vertices[1].Position.Z()-e.Base) < onBaseTolerance
+}
+
+// GroundFlanges gives every boundary edge on the base a FStyleGroundMk1 flange and generates
+//   the flanges. Returns the number of ground flanges.
+func (e *EShell) GroundFlanges() int {
+	n := 0
+	for _, ed := range aliveEdges(e.Edges) {
+		if !ed.IsBoundary() || !e.onBase(ed) {
+			continue
+		}
+		ed.Treatment = ETreatFlange
+		if f := e.FlangeOf(ed); f != nil {
+			f.Style = FStyleGroundMk1
+		} else {
+			e.Flanges = append(e.Flanges, &Flange{Edge: ed, Style: FStyleGroundMk1})
+		}
+		n++
+	}
+	e.GenerateFlanges()
+	return n
+}
+
+// Anchors lists the holes in all the ground flanges, anticlockwise from +X. Flanges meeting at a
+//   vertex each have their own anchors.
+func (e *EShell) Anchors() []Anchor {
+	as := []Anchor{}
+	for _, f := range e.Flanges {
+		if f.Style != FStyleGroundMk1 {
+			continue
+		}
+		for i, h := range f.Holes {
+			b := math.Atan2(h.Y(), h.X()) * 180 / math.Pi
+			if b < 0 {
+				b += 360
+			}
+			as = append(as, Anchor{Pos: h, Dia: f.Dias[i], Bearing: b, Flange: f})
+		}
+	}
+	sort.Slice(as, func(i, j int) bool { return as[i].Bearing < as[j].Bearing })
+	for i := range as {
+		as[i].N = i + 1
+	}
+	return as
+}
+
+// AnchorTable lists the anchor positions as CSV, in mm from the centre of the slab
+func (e *EShell) AnchorTable() string {
+	s := "Anchor,X mm,Y mm,Bearing deg,Radius mm,Hole mm\n"
+	for _, a := range e.Anchors() {
+		s += fmt.Sprintf("%d,%.0f,%.0f,%.2f,%.0f,%.1f\n", a.N, a.Pos.X()*m2mm, a.Pos.Y()*m2mm,
+			a.Bearing, math.Hypot(a.Pos.X(), a.Pos.Y())*m2mm, a.Dia*m2mm)
+	}
+	return s
+}
+
+// AnchorPlan draws the slab plan for marking out: the line of the shell on the slab and the
+//   ground flanges as meta paths, each anchor as a marked circle with a cross, and a cross
+//   at the centre of the slab. Units are mm, origin at the centre of the slab.
+func (e *EShell) AnchorPlan() cam.Drawing {
+	d := cam.Drawing{Name: "Ground anchor plan"}
+	flat := func(p v3.Vec) cam.Vec2 {
+		return cam.NewVec2(p.X()*m2mm, p.Y()*m2mm)
+	}
+	cross := func(c cam.Vec2, r float64) {
+		d.Paths = append(d.Paths, linePath(c.Add(cam.NewVec2(-r, 0)), c.Add(cam.NewVec2(r, 0)), cam.MarkPath))
+		d.Paths = append(d.Paths, linePath(c.Add(cam.NewVec2(0, -r)), c.Add(cam.NewVec2(0, r)), cam.MarkPath))
+	}
+	for _, f := range e.Flanges {
+		if f.Style != FStyleGroundMk1 || len(f.Corners) < 4 {
+			continue
+		}
+		d.Paths = append(d.Paths, linePath(flat(f.Corners[0]), flat(f.Corners[1]), cam.MetaPath))
+		d.Paths = append(d.Paths, linePath(flat(f.Corners[3]), flat(f.Corners[2]), cam.MetaPath))
+	}
+	for _, a := range e.Anchors() {
+		c := flat(a.Pos)
+		d.Paths = append(d.Paths, cam.NewCirclePath(c, a.Dia*m2mm/2, cam.MarkPath))
+		cross(c, a.Dia*m2mm)
+	}
+	cross(cam.Origin, 100)
+	return d
+}
+
+// Sizes for the base plan, mm
+const (
+	basePlanText   = 100.0 // height of the dimension text
+	basePlanOffset = 500.0 // from the ring out to the overall dimension lines
+)
+
+// BaseRing is the vertices on the base, anticlockwise from +X
+func (e *EShell) BaseRing() []*Vertex {
+	vs := []*Vertex{}
+	for _, v := range e.Vertices {
+		if v.Alive && math.Abs(v.Position.Z()-e.Base) < onBaseTolerance {
+			vs = append(vs, v)
+		}
+	}
+	bearing := func(v *Vertex) float64 {
+		return math.Atan2(v.Position.Y(), v.Position.X())
+	}
+	sort.Slice(vs, func(i, j int) bool { return bearing(vs[i]) < bearing(vs[j]) })
+	return vs
+}
+
+// textAt is labelAt scaled to make text h high
+func textAt(txt string, c, up cam.Vec2, h float64) cam.Path {
+	p := labelAt(txt, cam.Plain, c, up)
+	k := h / labelHeight
+	for i, s := range p.Segments {
+		p.Segments[i].Start = c.Add(s.Start.Subtract(c).Scale(k))
+		p.Segments[i].End = c.Add(s.End.Subtract(c).Scale(k))
+	}
+	return p
+}
+
+// dimension draws a dimension between a and b, with the dimension line moved out by off,
+//   ticks at the ends and the length in mm beyond the middle, reading from a to b
+func dimension(a, b, off cam.Vec2) []cam.Path {
+	a2, b2 := a.Add(off), b.Add(off)
+	out := off.Scale(1 / off.Length())
+	along := b.Subtract(a).Scale(1 / b.Subtract(a).Length())
+	up := cam.NewVec2(-along.Y, along.X) // so the text reads along from a to b
+	tick := out.Add(along).Scale(basePlanText / 2)
+	return []cam.Path{
+		linePath(a, a2.Add(out.Scale(basePlanText/2)), cam.MetaPath),
+		linePath(b, b2.Add(out.Scale(basePlanText/2)), cam.MetaPath),
+		linePath(a2, b2, cam.MetaPath),
+		linePath(a2.Subtract(tick), a2.Add(tick), cam.MetaPath),
+		linePath(b2.Subtract(tick), b2.Add(tick), cam.MetaPath),
+		textAt(fmt.Sprintf("%.0f", b.Subtract(a).Length()), a2.Add(b2).Scale(0.5).Add(out.Scale(basePlanText)), up, basePlanText),
+	}
+}
+
+// BasePlan draws the foundation layout: the anchor plan with the ring of base vertices marked
+//   round it, the length of each side of the ring and the overall length and width. Units
+//   are mm, origin at the centre of the slab.
+func (e *EShell) BasePlan() cam.Drawing {
+	d := e.AnchorPlan()
+	d.Name = "Base ring plan"
+	ring := e.BaseRing()
+	if len(ring) < 3 {
+		return d
+	}
+	var pts []cam.Vec2
+	lo, hi := cam.NewVec2(math.Inf(1), math.Inf(1)), cam.NewVec2(math.Inf(-1), math.Inf(-1))
+	for _, v := range ring {
+		p := cam.NewVec2(v.Position.X()*m2mm, v.Position.Y()*m2mm)
+		pts = append(pts, p)
+		lo = cam.NewVec2(math.Min(lo.X, p.X), math.Min(lo.Y, p.Y))
+		hi = cam.NewVec2(math.Max(hi.X, p.X), math.Max(hi.Y, p.Y))
+	}
+	d.Paths = append(d.Paths, cam.NewPolygonPath(pts, cam.MarkPath))
+
+	for i, a := range pts {
+		b := pts[(i+1)%len(pts)]
+		mid := a.Add(b).Scale(0.5)
+		out := mid.Scale(1 / mid.Length())
+		d.Paths = append(d.Paths, textAt(fmt.Sprintf("%.0f", b.Subtract(a).Length()), mid.Add(out.Scale(basePlanText)), out, basePlanText))
+	}
+
+	d.Paths = append(d.Paths, dimension(cam.NewVec2(lo.X, lo.Y), cam.NewVec2(hi.X, lo.Y), cam.NewVec2(0, -basePlanOffset))...)
+	d.Paths = append(d.Paths, dimension(cam.NewVec2(hi.X, lo.Y), cam.NewVec2(hi.X, hi.Y), cam.NewVec2(basePlanOffset, 0))...)
+	return d
+}
@@ -0,0 +1,409 @@
+package main
+
+// ██╗  ██╗███████╗███╗   ███╗
+// ██║  ██║██╔════╝████╗ ████║
+// ███████║█████╗  ██╔████╔██║
+// ██╔══██║██╔══╝  ██║╚██╔╝██║
+// ██║  ██║███████╗██║ ╚═╝ ██║
+// ╚═╝  ╚═╝╚══════╝╚═╝     ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+)
+
+// Sizes for hems
+const (
+	hemDefaultSize      = 0.025    // m, outer face to bottom of the hem, if the edge has no HemSize
+	hemClearance        = 0.0005   // m, gap left between the mating hems
+	hemDefaultThickness = 0.000911 // m, 20ga, if the panel's gauge is not known
+	teardropPreBend     = 135.0    // degrees, the brake bends a teardrop hem to before it is closed
+	teardropArcPoints   = 12       // in the section of a teardrop's bulb
+)
+
+// HemBend is one bend in a hem, as seen in the flat pattern
+type HemBend struct {
+	Angle  float64 // degrees
+	Radius float64 // m, inside radius
+	At     float64 // m, from the edge line to the center line of the bend, out from the panel
+}
+
+// Bend is the bend as the brake operator sees it on the flat pattern, which is drawn looking
+//   at the outer face, so hems and flanges fold down, away from them
+func (bd HemBend) Bend() cam.Bend {
+	return cam.Bend{Angle: bd.Angle * math.Pi / 180, Up: false, Radius: bd.Radius * m2mm}
+}
+
+// HemProfile is the shape of the hem, flange etc. on one panel's side of an edge
+type HemProfile struct {
+	Panel     *Panel
+	Edge      *Edge
+	Treatment EdgeTreatment // what this panel does at the edge
+	Size      float64       // m, outer face to bottom of the hem
+	Thickness float64       // m, of the panel's material
+	Extension float64       // m, the flat pattern extends this far beyond the edge line
+	Bends     []HemBend     // the bends, from the panel outwards
+	Section   []cam.Vec2    // outer face of the hem in section, mm, seam plane at x=0 and the
+	//   panel's outer face at y=0. Panels[0] is on the -x side.
+}
+
+// SheetGauge is the gauge of sheet the panel is cut from, the shell's default if not set
+func (p *Panel) SheetGauge() cam.SheetGauge {
+	mat := p.Material
+	gauge := p.Gauge
+	if p.Shell != nil {
+		if mat == nil {
+			if m, ok := cam.Materials[p.Shell.Sheet.Material]; ok {
+				mat = &m
+			}
+		}
+		if gauge == "" {
+			gauge = p.Shell.Sheet.Gauge
+		}
+	}
+	if mat != nil {
+		if g, ok := mat.SheetData[gauge]; ok {
+			return g
+		}
+	}
+	return cam.SheetGauge{Display: "default", Thickness: hemDefaultThickness}
+}
+
+// SheetMaterial is the material the panel is cut from, the shell's default if not set
+func (p *Panel) SheetMaterial() cam.MaterialID {
+	if p.Material != nil {
+		return p.Material.ID
+	}
+	if p.Shell != nil {
+		return p.Shell.Sheet.Material
+	}
+	return ""
+}
+
+// HemTreatment is the treatment the panel gets along the edge: a seam with an open hem on
+//   one panel has a closed hem on the other. The Treatment of the edge is what Panels[0] gets.
+func (ed *Edge) HemTreatment(p *Panel) EdgeTreatment {
+	ps := alivePanels(ed.Panels)
+	if len(ps) < 2 || ps[0] == p {
+		return ed.Treatment
+	}
+	switch ed.Treatment {
+	case ETreatOpenHemMk1:
+		return ETreatClosedHemMk1
+	case ETreatClosedHemMk1:
+		return ETreatOpenHemMk1
+	}
+	return ed.Treatment
+}
+
+// SetOpenHem makes the panel get the open hem on the seam, overriding the usual rule
+func (e *EShell) SetOpenHem(ed *Edge, p *Panel) {
+	if e.HemOverrides == nil {
+		e.HemOverrides = map[*Edge]*Panel{}
+	}
+	e.HemOverrides[ed] = p
+}
+
+// AssignSeamTreatments gives every seam an open hem on one panel and a closed hem on the other,
+//   so the two always mate. The panel with the lower serial gets the open hem unless
+//   overridden. Seams already flanged, smoothed etc. are left alone. Returns the number of seams hemmed.
+func (e *EShell) AssignSeamTreatments() int {
+	n := 0
+	for _, ed := range aliveEdges(e.Edges) {
+		ps := alivePanels(ed.Panels)
+		if len(ps) != 2 {
+			continue
+		}
+		switch ed.Treatment {
+		case ETreatAsCut, ETreatOpenHemMk1, ETreatClosedHemMk1:
+		default:
+			continue
+		}
+		open := ps[0]
+		if ps[1].Serial < ps[0].Serial {
+			open = ps[1]
+		}
+		if o, ok := e.HemOverrides[ed]; ok && (o == ps[0] || o == ps[1]) {
+			open = o
+		}
+		ed.Treatment = ETreatOpenHemMk1 // the Treatment is what ps[0] gets
+		if open != ps[0] {
+			ed.Treatment = ETreatClosedHemMk1
+		}
+		if ed.HemSize <= 0 {
+			ed.HemSize = hemDefaultSize
+		}
+		n++
+	}
+	return n
+}
+
+// UnhemmedSeams finds seams which have no pair of hems
+func (e *EShell) UnhemmedSeams() []*Edge {
+	var es []*Edge
+	for _, ed := range aliveEdges(e.Edges) {
+		if len(alivePanels(ed.Panels)) == 2 && ed.Treatment != ETreatOpenHemMk1 && ed.Treatment != ETreatClosedHemMk1 {
+			es = append(es, ed)
+		}
+	}
+	return es
+}
+
+// SeamBend is the angle in degrees through which each panel bends to turn into the plane
+//   bisecting the seam, 90 for a flat seam and less where the shell is convex
+func (ed *Edge) SeamBend() float64 {
+	ps := alivePanels(ed.Panels)
+	if len(ps) < 2 {
+		return 90
+	}
+	c := math.Max(-1, math.Min(1, ps[0].Normal.Dot(ps[1].Normal)))
+	return 90 - math.Acos(c)*180/math.Pi/2
+}
+
+// HemProfile works out the hem of the panel along the edge, allowing for the thickness of
+//   the material and the bends, so the flat patterns of the two panels nest when folded. The
+//   open hem hooks round the closed hem, which is doubled over on itself. A teardrop hem
+//   stands alone, rolling under an exposed edge.
+func (p *Panel) HemProfile(ed *Edge) (HemProfile, error) {
+	g := p.SheetGauge()
+	t := g.Thickness
+	r := g.BendRadius()
+	size := ed.HemSize
+	if size <= 0 {
+		size = hemDefaultSize
+	}
+	h := HemProfile{Panel: p, Edge: ed, Treatment: ed.HemTreatment(p), Size: size, Thickness: t}
+
+	b1 := ed.SeamBend()
+	setback := g.OutsideSetback(b1, r) // from the edge line to the start of the first bend
+	ba1 := g.Allowance(b1, r)
+	mm := func(x, y float64) cam.Vec2 {
+		return cam.NewVec2(x*m2mm, y*m2mm)
+	}
+	side := -1.0 // which side of the seam plane the panel is on
+	if ps := alivePanels(ed.Panels); len(ps) > 1 && ps[1] == p {
+		side = 1
+	}
+
+	var leg, ri2, ret float64 // straight down the seam, inside radius of the fold, return leg
+	switch h.Treatment {
+	case ETreatTeardropHem:
+		return p.teardropProfile(h, side), nil
+	case ETreatOpenHemMk1: // hooks round the doubled closed hem, with clearance
+		ri2 = (2*t + hemClearance) / 2
+		leg = size - setback - (ri2 + t)
+		ret = size / 2
+		x := 2*ri2 + 2*t
+		h.Section = []cam.Vec2{mm(side*size, 0), mm(0, 0), mm(0, -size), mm(x, -size), mm(x, -size+ret)}
+	case ETreatClosedHemMk1: // tucks inside the open hem, folded flat
+		depth := size - t - hemClearance
+		leg = depth - setback - t
+		ret = size/2 - t
+		h.Section = []cam.Vec2{mm(side*size, 0), mm(0, 0), mm(0, -depth), mm(2*t, -depth), mm(2*t, -depth+ret)}
+	default:
+		return h, fmt.Errorf("Edge %d has no hem for panel %d", ed.Serial, p.Serial)
+	}
+	if leg <= 0 {
+		return h, fmt.Errorf("Hem of %.1fmm on edge %d is too small for %.2fmm material", size*m2mm, ed.Serial, t*m2mm)
+	}
+
+	ba2 := g.Allowance(180, ri2)
+	h.Bends = []HemBend{
+		{Angle: b1, Radius: r, At: -setback + ba1/2},
+		{Angle: 180, Radius: ri2, At: -setback + ba1 + leg + ba2/2}}
+	h.Extension = -setback + ba1 + leg + ba2 + ret
+	return h, nil
+}
+
+// teardropProfile is a teardrop safety hem on an exposed edge: the sheet is rolled under, round
+//   the tightest bend the gauge allows, so the bulb is the edge, and its return leg closed back
+//   until it touches the underside of the panel Size in from the edge. The brake bends it to
+//   teardropPreBend, then it is closed in the hemming die.
+func (p *Panel) teardropProfile(h HemProfile, side float64) HemProfile {
+	g := p.SheetGauge()
+	t := g.Thickness
+	ri := g.BendRadius()
+	ro := ri + t // outer radius of the bulb
+	// the leg slopes up from the bottom of the bulb to touch the panel's inner face
+	slope := math.Atan2(2*ri, math.Max(h.Size-ro, ri))
+	angle := 180 + slope*180/math.Pi
+	ba := g.Allowance(angle, ri)
+	leg := math.Max(0, h.Size-ro) / math.Cos(slope)
+	h.Bends = []HemBend{{Angle: angle, Radius: ri, At: -ro + ba/2}}
+	h.Extension = -ro + ba + leg
+
+	// outer face: along the panel, round the bulb and back along the leg, mm
+	mm := func(x, y float64) cam.Vec2 {
+		return cam.NewVec2(side*x*m2mm, y*m2mm)
+	}
+	c := cam.NewVec2(-ro, -ro) // center of the bulb, mirrored by side
+	h.Section = []cam.Vec2{mm(-h.Size, 0)}
+	for i := 0; i <= teardropArcPoints; i++ {
+		a := math.Pi/2 - (math.Pi+slope)*float64(i)/teardropArcPoints
+		h.Section = append(h.Section, mm(c.X+ro*math.Cos(a), c.Y+ro*math.Sin(a)))
+	}
+	h.Section = append(h.Section, mm(-h.Size, -2*t))
+	return h
+}
+
+// Sequence is how the brake operator makes the hem, a step for each bend, the outermost first
+func (h HemProfile) Sequence() []string {
+	var ss []string
+	for i := len(h.Bends) - 1; i >= 0; i-- {
+		bd := h.Bends[i]
+		at := fmt.Sprintf("%.1fmm out from the edge line", bd.At*m2mm)
+		if h.Treatment == ETreatTeardropHem {
+			ss = append(ss, fmt.Sprintf("Bend DOWN %.0f° R%.3g %s", teardropPreBend, bd.Radius*m2mm, at),
+				fmt.Sprintf("Close in the hemming die to %.0f°, leaving a %.1fmm teardrop", bd.Angle, 2*(bd.Radius+h.Thickness)*m2mm))
+			continue
+		}
+		ss = append(ss, fmt.Sprintf("Bend DOWN %.0f° R%.3g %s", bd.Angle, bd.Radius*m2mm, at))
+	}
+	for i := range ss {
+		ss[i] = fmt.Sprintf("%d. %s", i+1, ss[i])
+	}
+	return ss
+}
+
+// EdgeExtension is how far the flat pattern of the panel extends beyond the edge line, with the
+//   bends in it, according to the treatment of the edge
+func (p *Panel) EdgeExtension(ed *Edge) (float64, []HemBend) {
+	switch ed.HemTreatment(p) {
+	case ETreatOpenHemMk1, ETreatClosedHemMk1, ETreatTeardropHem:
+		h, err := p.HemProfile(ed)
+		if err != nil {
+			console.Errorf("%s", err)
+			return 0, nil
+		}
+		return h.Extension, h.Bends
+	case ETreatFlange:
+		f := p.Shell.FlangeOf(ed)
+		if f == nil || FlangeSpecs[f.Style].Depth <= 0 {
+			return 0, nil
+		}
+		g := p.SheetGauge()
+		r := g.BendRadius()
+		setback := g.OutsideSetback(90, r)
+		return FlangeSpecs[f.Style].Depth - g.Deduction(90, r), []HemBend{{Angle: 90, Radius: r, At: -setback + g.Allowance(90, r)/2}}
+	}
+	return 0, nil
+}
+
+// intersect2 finds where the lines through a along da and through b along db cross
+func intersect2(a, da, b, db cam.Vec2) (cam.Vec2, bool) {
+	den := da.X*db.Y - da.Y*db.X
+	if math.Abs(den) < 1e-12 {
+		return a, false
+	}
+	t := ((b.X-a.X)*db.Y - (b.Y-a.Y)*db.X) / den
+	return a.Add(da.Scale(t)), true
+}
+
+// flatSide is one side of a panel's flat pattern, moved out by the extension of its edge
+type flatSide struct {
+	a, b      cam.Vec2 // ends of the edge line, mm in the panel's Frame
+	at        cam.Vec2 // on the side, moved out
+	along     cam.Vec2 // unit, from a to b
+	out       cam.Vec2 // unit, away from the panel
+	edge      *Edge    // nil if the corners have no edge between them
+	extension float64  // m
+	bends     []HemBend
+}
+
+// flatSides are the sides of the flat pattern, the ith from corner i to the next
+func (p *Panel) flatSides() []flatSide {
+	n := len(p.Corners)
+	var inner []cam.Vec2
+	for _, c := range p.Corners {
+		inner = append(inner, p.Flat(c.Position))
+	}
+	var sides []flatSide
+	for i := range p.Corners {
+		a, b := inner[i], inner[(i+1)%n]
+		along := b.Subtract(a)
+		along = along.Scale(1 / along.Length())
+		out := cam.NewVec2(along.Y, -along.X)
+		if inner[(i+2)%n].Subtract(a).X*out.X+inner[(i+2)%n].Subtract(a).Y*out.Y > 0 {
+			out = out.Scale(-1)
+		}
+		sd := flatSide{a: a, b: b, along: along, out: out, edge: p.EdgeBetween(p.Corners[i], p.Corners[(i+1)%n])}
+		if sd.edge != nil {
+			sd.extension, sd.bends = p.EdgeExtension(sd.edge)
+		}
+		sd.at = a.Add(out.Scale(sd.extension * m2mm))
+		sides = append(sides, sd)
+	}
+	return sides
+}
+
+// FlatPattern is the panel unfolded, mm in the panel's Frame: the outline with every edge moved
+//   out by its extension, fold lines at the bends, the labels, any engravings and any vents
+func (p *Panel) FlatPattern() cam.Drawing {
+	d := cam.Drawing{Name: fmt.Sprintf("Panel %d", p.Serial), ID: p.Serial}
+	n := len(p.Corners)
+	if n < 3 {
+		return d
+	}
+	sides := p.flatSides()
+	for _, sd := range sides {
+		for _, bd := range sd.bends {
+			o := sd.out.Scale(bd.At * m2mm)
+			d.Paths = append(d.Paths, cam.NewFoldPath(sd.a.Add(o), sd.b.Add(o), bd.Bend()))
+		}
+	}
+
+	var outline []cam.Vec2
+	for i := range sides {
+		prev := sides[(i+n-1)%n]
+		pt, ok := intersect2(prev.at, prev.along, sides[i].at, sides[i].along)
+		if !ok {
+			pt = sides[i].at
+		}
+		outline = append(outline, pt)
+	}
+	d.Paths = append([]cam.Path{cam.NewPolygonPath(outline, cam.EdgePath)}, d.Paths...)
+	d.Paths = append(d.Paths, p.Labels()...)
+	d.Paths = append(d.Paths, p.Engravings...)
+	if p.Shell != nil {
+		for _, v := range p.Shell.VentsIn(p) {
+			d.Paths = append(d.Paths, v.Drawing().Paths...)
+		}
+	}
+	return d
+}
+
+// cutMaterial is the panel's own material, else the shell's sheet material, nil if unknown
+func (p *Panel) cutMaterial() *cam.Material {
+	mat := p.Material
+	if mat == nil && p.Shell != nil {
+		if m, ok := cam.Materials[p.Shell.Sheet.Material]; ok {
+			mat = &m
+		}
+	}
+	return mat
+}
+
+// Kerf is the width of the cut made in the panel's material by the shell's cutting process, m
+func (p *Panel) Kerf() float64 {
+	mat := p.cutMaterial()
+	if mat == nil {
+		return cam.DefaultKerfs[p.Shell.Process]
+	}
+	return mat.Kerf(p.Shell.Process)
+}
+
+// Leads are how the shell's cutting process starts and finishes cuts in the panel's material
+func (p *Panel) Leads() cam.Leads {
+	mat := p.cutMaterial()
+	if mat == nil {
+		return cam.DefaultLeads[p.Shell.Process]
+	}
+	return mat.LeadsFor(p.Shell.Process)
+}
+
+// CutPattern is the flat pattern with the cuts moved to allow for the kerf, ready to cut
+func (p *Panel) CutPattern() cam.Drawing {
+	return p.FlatPattern().KerfCompensated(p.Kerf() * m2mm)
+}
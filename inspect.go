@@ -0,0 +1,235 @@
+package main
+
+// ██╗███╗   ██╗███████╗██████╗ ███████╗ ██████╗████████╗
+// ██║████╗  ██║██╔════╝██╔══██╗██╔════╝██╔════╝╚══██╔══╝
+// ██║██╔██╗ ██║███████╗██████╔╝█████╗  ██║        ██║
+// ██║██║╚██╗██║╚════██║██╔═══╝ ██╔══╝  ██║        ██║
+// ██║██║ ╚████║███████║██║     ███████╗╚██████╗   ██║
+// ╚═╝╚═╝  ╚═══╝╚══════╝╚═╝     ╚══════╝ ╚═════╝   ╚═╝
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cam "./cam"
+
+	"github.com/g3n/engine/gui"
+	"github.com/g3n/engine/math32"
+)
+
+// Property is one field of the thing selected, shown and perhaps edited in the inspector
+type Property struct {
+	Name  string
+	Value string
+	Set   func(s string) error // nil if it can only be looked at
+}
+
+// Properties are those of the selected vertex, else edge, else the last panel picked
+func (e *EShell) Properties(s *Selection) (string, []Property) {
+	switch {
+	case s.Vertex != nil:
+		return fmt.Sprintf("Vertex %d", s.Vertex.Serial), e.VertexProperties(s.Vertex)
+	case s.Edge != nil:
+		return fmt.Sprintf("Edge %d", s.Edge.Serial), e.EdgeProperties(s.Edge)
+	case len(s.Panels) > 0:
+		p := s.Panels[len(s.Panels)-1]
+		return fmt.Sprintf("Panel %s", PanelLabel(p)), e.PanelProperties(p)
+	}
+	return "Nothing selected", nil
+}
+
+// parseBool takes yes and no as well as what strconv does
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	return strconv.ParseBool(strings.TrimSpace(s))
+}
+
+// parseLength reads a length in the unit, giving it in m
+func parseLength(s string, unit float64) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("Not a number: %q", s)
+	}
+	return f / unit, nil
+}
+
+// PanelProperties are its serial and area, its material and gauge, blank for the shell's, and
+//   whether it is emitted
+func (e *EShell) PanelProperties(p *Panel) []Property {
+	mat := ""
+	if p.Material != nil {
+		mat = sheetName(p.SheetType())
+	}
+	return []Property{
+		{Name: "Serial", Value: fmt.Sprint(p.Serial)},
+		{Name: "Area", Value: e.Display.Area(p.Area)},
+		{Name: "Sheet", Value: mat, Set: func(s string) error {
+			fs := strings.Fields(s)
+			switch len(fs) {
+			case 0:
+				return e.SetSheet([]*Panel{p}, "", "")
+			case 2:
+				return e.SetSheet([]*Panel{p}, cam.MaterialID(fs[0]), cam.GaugeID(fs[1]))
+			}
+			return fmt.Errorf("Sheet is a material and a gauge, eg %q, or blank for the shell's", sheetName(e.Sheet))
+		}},
+		{Name: "Emit", Value: fmt.Sprint(p.Emit), Set: func(s string) error {
+			b, err := parseBool(s)
+			p.Emit = b
+			return err
+		}},
+	}
+}
+
+// EdgeProperties are its serial and length, its treatment, hem size and target length, in the
+//   shell's Display units
+func (e *EShell) EdgeProperties(ed *Edge) []Property {
+	return []Property{
+		{Name: "Serial", Value: fmt.Sprint(ed.Serial)},
+		{Name: "Length", Value: e.Display.Len(ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position).Length())},
+		{Name: "Treatment", Value: ed.Treatment.String(), Set: func(s string) error {
+			for _, t := range EdgeTreatments {
+				if strings.EqualFold(t.String(), strings.TrimSpace(s)) {
+					e.SetTreatment(ed, t)
+					return nil
+				}
+			}
+			return fmt.Errorf("No treatment %q", s)
+		}},
+		{Name: "Hem mm", Value: fmt.Sprintf("%.1f", ed.HemSize*m2mm), Set: func(s string) error {
+			l, err := parseLength(s, m2mm)
+			if err == nil {
+				ed.HemSize = l
+			}
+			return err
+		}},
+		{Name: "Target " + e.Display.LengthUnit(), Value: fmt.Sprintf("%.3f", e.Display.FromM(ed.Target)), Set: func(s string) error {
+			l, err := parseLength(s, e.Display.FromM(1))
+			if err == nil {
+				ed.Target = l
+			}
+			return err
+		}},
+	}
+}
+
+// VertexProperties are its serial and position, and its constraints, which it is moved to obey
+//   when they are changed
+func (e *EShell) VertexProperties(v *Vertex) []Property {
+	return []Property{
+		{Name: "Serial", Value: fmt.Sprint(v.Serial)},
+		{Name: "Position", Value: fmt.Sprintf("%s, %s, %s", e.Display.Len(v.Position.X()), e.Display.Len(v.Position.Y()), e.Display.Len(v.Position.Z()))},
+		{Name: "Constraints", Value: v.Constraints.String(), Set: func(s string) error {
+			cs, err := ParseConstraints(s)
+			if err != nil {
+				return err
+			}
+			v.Constraints = cs.Sorted()
+			v.Move(v.Position)
+			return nil
+		}},
+	}
+}
+
+// Sizes of the inspector
+const (
+	inspectorRows   = 6 // most properties anything has
+	inspectorWidth  = 300
+	inspectorRowGap = 22
+)
+
+// Inspector shows the properties of the selection, in a panel docked to either side of the GUI
+type Inspector struct {
+	Panel  *gui.Panel
+	title  *gui.Label
+	names  []*gui.Label
+	values []*gui.Edit
+	props  []Property
+	Right  bool // docked on the right rather than the left
+	left   float32
+	right  float32
+	top    float32
+}
+
+// NewInspector makes one in the parent, docked at left or right, top down
+func NewInspector(parent *gui.Panel, left, right, top float32, apply func()) *Inspector {
+	in := &Inspector{Panel: gui.NewPanel(inspectorWidth, inspectorRowGap*(inspectorRows+2)+10), left: left, right: right, top: top}
+	in.Panel.SetColor4(&math32.Color4{R: 0.1, G: 0.1, B: 0.2, A: 0.8})
+	in.title = gui.NewLabel("")
+	in.title.SetPosition(5, 5)
+	in.Panel.Add(in.title)
+	for i := 0; i < inspectorRows; i++ {
+		y := float32(5 + inspectorRowGap*(i+1))
+		l := gui.NewLabel("")
+		l.SetPosition(5, y+2)
+		in.Panel.Add(l)
+		v := gui.NewEdit(200, "")
+		v.SetPosition(90, y)
+		in.Panel.Add(v)
+		in.names, in.values = append(in.names, l), append(in.values, v)
+	}
+	y := float32(5 + inspectorRowGap*(inspectorRows+1))
+	applyBtn := gui.NewButton("Apply")
+	applyBtn.SetPosition(5, y)
+	applyBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		in.Apply()
+		apply()
+	})
+	in.Panel.Add(applyBtn)
+	dockBtn := gui.NewButton("Dock other side")
+	dockBtn.SetPosition(60, y)
+	dockBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		in.Right = !in.Right
+		in.dock()
+	})
+	in.Panel.Add(dockBtn)
+	in.dock()
+	parent.Add(in.Panel)
+	return in
+}
+
+// dock puts it on its side
+func (in *Inspector) dock() {
+	x := in.left
+	if in.Right {
+		x = in.right
+	}
+	in.Panel.SetPosition(x, in.top)
+}
+
+// Show fills it with the properties, those which cannot be set shown but not editable
+func (in *Inspector) Show(title string, props []Property) {
+	in.title.SetText(title)
+	in.props = props
+	for i := range in.names {
+		on := i < len(props)
+		in.names[i].SetVisible(on)
+		in.values[i].SetVisible(on)
+		if !on {
+			continue
+		}
+		in.names[i].SetText(props[i].Name)
+		in.values[i].SetText(props[i].Value)
+		in.values[i].SetEnabled(props[i].Set != nil)
+	}
+}
+
+// Apply sets every property which has been changed, reporting those it can't
+func (in *Inspector) Apply() {
+	for i, p := range in.props {
+		if i >= len(in.values) || p.Set == nil {
+			continue
+		}
+		if s := in.values[i].Text(); s != p.Value {
+			if err := p.Set(s); err != nil {
+				console.Errorf("%s: %s", p.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+// ██╗███╗   ██╗████████╗███████╗██████╗ ██╗ ██████╗ ██████╗
+// ██║████╗  ██║╚══██╔══╝██╔════╝██╔══██╗██║██╔═══██╗██╔══██╗
+// ██║██╔██╗ ██║   ██║   █████╗  ██████╔╝██║██║   ██║██████╔╝
+// ██║██║╚██╗██║   ██║   ██╔══╝  ██╔══██╗██║██║   ██║██╔══██╗
+// ██║██║ ╚████║   ██║   ███████╗██║  ██║██║╚██████╔╝██║  ██║
+// ╚═╝╚═╝  ╚═══╝   ╚═╝   ╚══════╝╚═╝  ╚═╝╚═╝ ╚═════╝ ╚═╝  ╚═╝
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	gl "./gl"
+	v3 "./vec"
+)
+
+// BlockKind is what an interior block stands for
+type BlockKind int
+
+// Values of BlockKind
+const (
+	Bed BlockKind = iota
+	Bench
+	Machine
+)
+
+// BlockKinds are in the order their buttons are shown
+var BlockKinds = []BlockKind{Bed, Bench, Machine}
+
+func (k BlockKind) String() string {
+	switch k {
+	case Bench:
+		return "Bench"
+	case Machine:
+		return "Machine"
+	}
+	return "Bed"
+}
+
+// size is the usual width, depth and height of the kind of block, in m
+func (k BlockKind) size() (w, d, h float64) {
+	switch k {
+	case Bench:
+		return 1.8, 0.6, 0.9
+	case Machine:
+		return 1.2, 1.2, 1.5
+	}
+	return 2.0, 1.5, 0.6 // a double bed
+}
+
+// Block is a box standing on the floor for laying out the inside of the shell: a bed, a bench
+//   or the footprint of a machine
+type Block struct {
+	Name    string
+	Kind    BlockKind
+	At      v3.Vec     // centre of its footprint, on the floor
+	Heading v3.Radians // its width is along, anticlockwise from +X
+	W, D, H float64    // m, width, depth and height
+}
+
+// AddBlock puts a new block of the usual size for its kind in the middle of the floor
+func (e *EShell) AddBlock(k BlockKind) *Block {
+	n := 1
+	for _, b := range e.Blocks {
+		if b.Kind == k {
+			n++
+		}
+	}
+	b := &Block{Name: fmt.Sprintf("%s %d", k, n), Kind: k, At: v3.NewSimVec(0, 0, e.Base)}
+	b.W, b.D, b.H = k.size()
+	e.Blocks = append(e.Blocks, b)
+	return b
+}
+
+// RemoveBlock takes a block out of the layout
+func (e *EShell) RemoveBlock(b *Block) {
+	var bs []*Block
+	for _, ob := range e.Blocks {
+		if ob != b {
+			bs = append(bs, ob)
+		}
+	}
+	e.Blocks = bs
+}
+
+// corners are those of the block at height z above its footprint, anticlockwise from above
+func (b *Block) corners(z float64) []v3.Vec {
+	var cs []v3.Vec
+	for _, s := range [][2]float64{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}} {
+		c := v3.NewSimVec(s[0]*b.W/2, s[1]*b.D/2, 0).RotateZ(b.Heading)
+		cs = append(cs, b.At.Add(c).Add(v3.Z.Scale(z)))
+	}
+	return cs
+}
+
+// TopCorners are the corners of the top of the block
+func (b *Block) TopCorners() []v3.Vec {
+	return b.corners(b.H)
+}
+
+// Covers is true if the point is over or under the footprint of the block
+func (b *Block) Covers(p v3.Vec) bool {
+	d := p.Subtract(b.At).RotateZ(-b.Heading)
+	return math.Abs(d.X()) <= b.W/2 && math.Abs(d.Y()) <= b.D/2
+}
+
+// BlockAt is a block whose top the line passes through, nil if none
+func (e *EShell) BlockAt(l v3.Line) *Block {
+	for _, b := range e.Blocks {
+		if at, hit := v3.NewPlane(b.At.Add(v3.Z.Scale(b.H)), v3.Z).IntersectLine(l); hit && b.Covers(at) {
+			return b
+		}
+	}
+	return nil
+}
+
+// Clashes are the top corners of the block the shell does not cover, where it pokes through
+//   the shell or stands outside it
+func (e *EShell) Clashes(b *Block) []v3.Vec {
+	var cs []v3.Vec
+	for _, c := range b.TopCorners() {
+		if _, ok := e.PickPanel(v3.NewRay(c, v3.Z), walkFar); !ok {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// BlockLines are the edges of all the blocks, the selected one highlighted and any clashing
+//   with the shell in red, with their clashing corners marked
+func (e *EShell) BlockLines(selected *Block) []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	for _, b := range e.Blocks {
+		clashes := e.Clashes(b)
+		colour := &gl.Aqua
+		if b == selected {
+			colour = &gl.Yellow
+		}
+		if len(clashes) > 0 {
+			colour = &gl.Red
+		}
+		bottom, top := b.corners(0), b.TopCorners()
+		for i := range bottom {
+			j := (i + 1) % len(bottom)
+			ls = append(ls,
+				gl.ColourLine{Start: bottom[i], End: bottom[j], Colour: colour},
+				gl.ColourLine{Start: top[i], End: top[j], Colour: colour},
+				gl.ColourLine{Start: bottom[i], End: top[i], Colour: colour})
+		}
+		for _, c := range clashes {
+			ls = append(ls, measureCross(c)...)
+		}
+	}
+	return ls
+}
+
+// BlockInfo says where each block is and which clash with the shell
+func (e *EShell) BlockInfo(u DisplayUnits) string {
+	var ss []string
+	for _, b := range e.Blocks {
+		s := fmt.Sprintf("%s %s x %s x %s at %s, %s", b.Name, u.Len(b.W), u.Len(b.D), u.Len(b.H), u.Len(b.At.X()), u.Len(b.At.Y()))
+		if n := len(e.Clashes(b)); n > 0 {
+			s += fmt.Sprintf(", CLASHES at %d corners", n)
+		}
+		ss = append(ss, s)
+	}
+	return strings.Join(ss, "\n")
+}
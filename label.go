@@ -0,0 +1,87 @@
+package main
+
+// ██╗      █████╗ ██████╗ ███████╗██╗
+// ██║     ██╔══██╗██╔══██╗██╔════╝██║
+// ██║     ███████║██████╔╝█████╗  ██║
+// ██║     ██╔══██║██╔══██╗██╔══╝  ██║
+// ███████╗██║  ██║██████╔╝███████╗███████╗
+// ╚══════╝╚═╝  ╚═╝╚═════╝ ╚══════╝╚══════╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+)
+
+// Sizes for labels engraved on the flat parts, mm
+const (
+	labelSpacing   = 1.0  // between letters
+	labelHeight    = 9.0  // of the tallest letters in cam.Plain and cam.Stroke
+	labelEdgeInset = 5.0  // from an edge to the top of the label naming the panel beyond it
+	labelLineGap   = 14.0 // between lines of the center label
+)
+
+// PanelLabel is the name engraved on a panel for its serial
+func PanelLabel(p *Panel) string {
+	return fmt.Sprintf("P%d", p.Serial)
+}
+
+// labelAt types the text in the font centered on c, with up pointing along up, as a mark path
+func labelAt(txt string, f cam.Font, c, up cam.Vec2) cam.Path {
+	along := cam.NewVec2(up.Y, -up.X) // up is to the left of the direction of the text
+	w := f.Width(txt, labelSpacing)
+	start := c.Subtract(along.Scale(w / 2)).Subtract(up.Scale(labelHeight / 2))
+	t := cam.NewTurtle()
+	t.SetKind(cam.MarkPath)
+	t.JumpTo(start.X, start.Y).TurnTo(math.Atan2(along.X, along.Y))
+	t.SetFont(f, labelSpacing).Type(txt)
+	return t.Trail
+}
+
+// Labels engraves the panel, in its flat coords: its serial and sheet in the middle, and
+//   the serial of the panel beyond each seam just inside that edge, reading outwards. Serials
+//   are in Plain, with its panel symbol, the sheet in Stroke.
+func (p *Panel) Labels() []cam.Path {
+	n := len(p.Corners)
+	if n < 3 {
+		return nil
+	}
+	var pts []cam.Vec2
+	for _, c := range p.Corners {
+		pts = append(pts, p.Flat(c.Position))
+	}
+	ls := []cam.Path{}
+
+	c := p.Flat(p.Incenter())
+	g := p.SheetGauge()
+	sheet := g.Display
+	if p.Material != nil {
+		sheet += " " + string(p.Material.ID)
+	} else if p.Shell != nil {
+		sheet += " " + string(p.Shell.Sheet.Material)
+	}
+	ls = append(ls, labelAt(PanelLabel(p), cam.Plain, c.Add(cam.NewVec2(0, labelLineGap/2)), cam.NewVec2(0, 1)))
+	ls = append(ls, labelAt(sheet, cam.Stroke, c.Subtract(cam.NewVec2(0, labelLineGap/2)), cam.NewVec2(0, 1)))
+
+	for i := range p.Corners {
+		ed := p.EdgeBetween(p.Corners[i], p.Corners[(i+1)%n])
+		if ed == nil {
+			continue
+		}
+		for _, other := range alivePanels(ed.Panels) {
+			if other == p {
+				continue
+			}
+			a, b := pts[i], pts[(i+1)%n]
+			along := b.Subtract(a)
+			out := cam.NewVec2(along.Y, -along.X).Scale(1 / along.Length())
+			if pts[(i+2)%n].Subtract(a).X*out.X+pts[(i+2)%n].Subtract(a).Y*out.Y > 0 {
+				out = out.Scale(-1)
+			}
+			mid := a.Add(along.Scale(0.5)).Subtract(out.Scale(labelEdgeInset + labelHeight/2))
+			ls = append(ls, labelAt(PanelLabel(other), cam.Plain, mid, out))
+		}
+	}
+	return ls
+}
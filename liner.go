@@ -0,0 +1,97 @@
+package main
+
+// ██╗     ██╗███╗   ██╗███████╗██████╗
+// ██║     ██║████╗  ██║██╔════╝██╔══██╗
+// ██║     ██║██╔██╗ ██║█████╗  ██████╔╝
+// ██║     ██║██║╚██╗██║██╔══╝  ██╔══██╗
+// ███████╗██║██║ ╚████║███████╗██║  ██║
+// ╚══════╝╚═╝╚═╝  ╚═══╝╚══════╝╚═╝  ╚═╝
+
+import (
+	gl "./gl"
+)
+
+// linerDepth is how far inside the shell the liner goes by default, m
+const linerDepth = 0.1
+
+// Offset makes a liner: a copy of the shell with every vertex moved in by d along its normal.
+//   Vertices on the base stay on the base. The liner has the same vertices, edges and panels
+//   in the same order, alive or not, so each liner panel pairs with the shell panel of the same
+//   Serial. Its vertices are unconstrained, as it is not on the ellipsoid.
+func (e *EShell) Offset(d float64) *EShell {
+	l := &EShell{E: e.E, Shape: e.Shape, Base: e.Base, PanelSize: e.PanelSize, SizeFunc: e.SizeFunc,
+		Tolerance: e.Tolerance, FlangeWidth: e.FlangeWidth, Sheet: e.Sheet, Process: e.Process, Tabs: e.Tabs, CommonLine: e.CommonLine, Oriented: e.Oriented}
+
+	for _, p := range e.Panels {
+		p.Update(e)
+	}
+	vs := map[*Vertex]*Vertex{}
+	for _, v := range e.Vertices {
+		nv := &Vertex{Serial: v.Serial, Position: v.Position, Shell: l, Alive: v.Alive, Constraints: Constraints{}}
+		if v.Alive && len(alivePanels(v.Panels)) > 0 {
+			v.ComputeNormal()
+			nv.Position = v.Position.Subtract(v.Normal.Scale(d))
+			if v.Position.Z()-e.Base < onBaseTolerance {
+				nv.Position = nv.Position.New(nv.Position.X(), nv.Position.Y(), e.Base)
+				nv.Constraints = Constraints{OnBase}
+			}
+		}
+		vs[v] = nv
+		l.Vertices = append(l.Vertices, nv)
+	}
+
+	es := map[*Edge]*Edge{}
+	for _, ed := range e.Edges {
+		ne := &Edge{Serial: ed.Serial, Vertices: []*Vertex{vs[ed.Vertices[0]], vs[ed.Vertices[1]]}, Target: ed.Target,
+			Shell: l, Alive: ed.Alive, Treatment: ed.Treatment, HemSize: ed.HemSize}
+		ne.Along = ne.Vertices[1].Position.Subtract(ne.Vertices[0].Position)
+		ne.Length = ne.Along.Length()
+		for _, v := range ne.Vertices {
+			v.Edges = append(v.Edges, ne)
+		}
+		es[ed] = ne
+		l.Edges = append(l.Edges, ne)
+	}
+
+	for _, p := range e.Panels {
+		np := &Panel{Serial: p.Serial, Shell: l, Alive: p.Alive, Emit: p.Emit, Accessory: p.Accessory,
+			Kind: p.Kind, Material: p.Material, Gauge: p.Gauge}
+		for _, c := range p.Corners {
+			np.Corners = append(np.Corners, vs[c])
+			vs[c].Panels = append(vs[c].Panels, np)
+		}
+		for _, ed := range p.Edges {
+			np.Edges = append(np.Edges, es[ed])
+			es[ed].Panels = append(es[ed].Panels, np)
+		}
+		np.Update(l)
+		np.InitNormal = np.Normal
+		l.Panels = append(l.Panels, np)
+	}
+	for _, p := range e.Panels {
+		if p.SubPanelOf != nil {
+			l.Panels[p.Serial].SubPanelOf = l.Panels[p.SubPanelOf.Serial]
+		}
+	}
+	return l
+}
+
+// LinerPanel is the panel of the liner paired with the shell panel p
+func (e *EShell) LinerPanel(p *Panel) *Panel {
+	if e.Liner == nil || p.Serial >= len(e.Liner.Panels) {
+		return nil
+	}
+	return e.Liner.Panels[p.Serial]
+}
+
+// LinerLines shows the edges of the liner
+func (e *EShell) LinerLines() []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	if e.Liner == nil {
+		return ls
+	}
+	for _, ed := range aliveEdges(e.Liner.Edges) {
+		ls = append(ls, gl.ColourLine{Start: ed.Vertices[0].Position, End: ed.Vertices[1].Position, Colour: &gl.Grey})
+	}
+	return ls
+}
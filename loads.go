@@ -0,0 +1,144 @@
+package main
+
+// ██╗      ██████╗  █████╗ ██████╗ ███████╗
+// ██║     ██╔═══██╗██╔══██╗██╔══██╗██╔════╝
+// ██║     ██║   ██║███████║██║  ██║███████╗
+// ██║     ██║   ██║██╔══██║██║  ██║╚════██║
+// ███████╗╚██████╔╝██║  ██║██████╔╝███████║
+// ╚══════╝ ╚═════╝ ╚═╝  ╚═╝╚═════╝ ╚══════╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	v3 "./vec"
+)
+
+// Constants for estimating loads, rough figures for a smooth dome
+const (
+	airDensity = 1.225 // kg/m3, at sea level
+	windDrag   = 0.5   // drag coefficient of the shell, on its projected area
+	gravity    = 9.81  // m/s2
+)
+
+// LoadCase is the weather the shell has to stand up to
+type LoadCase struct {
+	Snow    float64 // kPa, on the horizontal projection of the roof
+	Wind    float64 // m/s
+	WindDir float64 // degrees anticlockwise from +X, the direction the wind blows towards
+}
+
+// AnchorReaction is the force on one ground anchor
+type AnchorReaction struct {
+	Anchor Anchor
+	Down   float64 // N, pushing down on the slab, negative is uplift
+	Shear  float64 // N, along the wind
+}
+
+// LoadReport is the estimated loads on the shell and how they reach the ground
+type LoadReport struct {
+	Case        LoadCase
+	Dead        float64 // N, weight of the panels
+	Snow        float64 // N, vertical
+	Wind        float64 // N, lateral
+	SnowArea    float64 // m2, horizontal projection of the upward facing panels
+	WindArea    float64 // m2, projection of the windward panels across the wind
+	WindHeight  float64 // m, above the base, where the wind acts
+	Overturning float64 // Nm, about the base
+	Reactions   []AnchorReaction
+}
+
+// Loads estimates the snow, wind and dead loads and the reactions at the ground anchors
+func (e *EShell) Loads(lc LoadCase, mats cam.MaterialSet) LoadReport {
+	return e.loadsFrom(lc, e.BOM(mats).Mass)
+}
+
+// loadsFrom estimates the loads given the mass of the shell
+func (e *EShell) loadsFrom(lc LoadCase, mass float64) LoadReport {
+	r := LoadReport{Case: lc, Dead: mass * gravity}
+	a := lc.WindDir * math.Pi / 180
+	w := v3.NewSimVec(math.Cos(a), math.Sin(a), 0)
+
+	moment := 0.0 // of the windward projected area about the base
+	for _, p := range e.Panels {
+		if !p.Alive {
+			continue
+		}
+		if p.Normal.Z() > 0 {
+			r.SnowArea += p.Area * p.Normal.Z()
+		}
+		if facing := -p.Normal.Dot(w); facing > 0 {
+			r.WindArea += p.Area * facing
+			moment += p.Area * facing * (p.Center.Z() - e.Base)
+		}
+	}
+	r.Snow = r.SnowArea * lc.Snow * 1000
+	r.Wind = 0.5 * airDensity * lc.Wind * lc.Wind * windDrag * r.WindArea
+	if r.WindArea > 0 {
+		r.WindHeight = moment / r.WindArea
+	}
+	r.Overturning = r.Wind * r.WindHeight
+
+	// Share the vertical load evenly and resist the overturning by the anchors' lever arms
+	//   along the wind about their centroid, as for a bolt group
+	as := e.Anchors()
+	if len(as) == 0 {
+		return r
+	}
+	var cx, cy float64
+	for _, an := range as {
+		cx += an.Pos.X()
+		cy += an.Pos.Y()
+	}
+	cx, cy = cx/float64(len(as)), cy/float64(len(as))
+	arm := func(an Anchor) float64 {
+		return (an.Pos.X()-cx)*w.X() + (an.Pos.Y()-cy)*w.Y()
+	}
+	sumD2 := 0.0
+	for _, an := range as {
+		sumD2 += arm(an) * arm(an)
+	}
+	n := float64(len(as))
+	for _, an := range as {
+		ar := AnchorReaction{Anchor: an, Down: (r.Dead + r.Snow) / n, Shear: r.Wind / n}
+		if sumD2 > 0 {
+			ar.Down += r.Overturning * arm(an) / sumD2
+		}
+		r.Reactions = append(r.Reactions, ar)
+	}
+	return r
+}
+
+// String summarises the loads, with the worst anchor
+func (r LoadReport) String() string {
+	s := fmt.Sprintf("Loads: snow %3.1fkPa, wind %2.0fm/s (%2.0fmph) towards %3.0f deg\n",
+		r.Case.Snow, r.Case.Wind, r.Case.Wind*2.23694, r.Case.WindDir)
+	s += fmt.Sprintf("   Dead %5.1fkN, snow %5.1fkN on %4.1fm2, wind %5.1fkN on %4.1fm2 at %3.1fm\n",
+		r.Dead/1000, r.Snow/1000, r.SnowArea, r.Wind/1000, r.WindArea, r.WindHeight)
+	if len(r.Reactions) == 0 {
+		return s + "   No ground anchors\n"
+	}
+	lo, hi := r.Reactions[0], r.Reactions[0]
+	for _, ar := range r.Reactions {
+		if ar.Down < lo.Down {
+			lo = ar
+		}
+		if ar.Down > hi.Down {
+			hi = ar
+		}
+	}
+	return s + fmt.Sprintf("   %d anchors: max down %4.2fkN (#%d), min %4.2fkN (#%d), shear %4.2fkN each\n",
+		len(r.Reactions), hi.Down/1000, hi.Anchor.N, lo.Down/1000, lo.Anchor.N, hi.Shear/1000)
+}
+
+// CSV lists the totals and the reaction at each anchor
+func (r LoadReport) CSV() string {
+	s := fmt.Sprintf("Snow kPa,%.2f\nWind m/s,%.1f\nWind towards deg,%.1f\n", r.Case.Snow, r.Case.Wind, r.Case.WindDir)
+	s += fmt.Sprintf("Dead N,%.0f\nSnow N,%.0f\nWind N,%.0f\nOverturning Nm,%.0f\n", r.Dead, r.Snow, r.Wind, r.Overturning)
+	s += "Anchor,X mm,Y mm,Down N,Shear N\n"
+	for _, ar := range r.Reactions {
+		s += fmt.Sprintf("%d,%.0f,%.0f,%.0f,%.0f\n", ar.Anchor.N, ar.Anchor.Pos.X()*m2mm, ar.Anchor.Pos.Y()*m2mm, ar.Down, ar.Shear)
+	}
+	return s
+}
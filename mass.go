@@ -0,0 +1,97 @@
+package main
+
+// ███╗   ███╗ █████╗ ███████╗███████╗
+// ████╗ ████║██╔══██╗██╔════╝██╔════╝
+// ██╔████╔██║███████║███████╗███████╗
+// ██║╚██╔╝██║██╔══██║╚════██║╚════██║
+// ██║ ╚═╝ ██║██║  ██║███████║███████║
+// ╚═╝     ╚═╝╚═╝  ╚═╝╚══════╝╚══════╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	v3 "./vec"
+)
+
+// MassCourse is a horizontal band of panels, about one panel high
+type MassCourse struct {
+	Bottom, Top float64 // m, heights of the band
+	Panels      int     // number of panels with their centres in the band
+	Mass        float64 // kg
+}
+
+// MassProps is the mass of the shell and where it acts, for transport and lifting
+type MassProps struct {
+	Mass    float64 // kg, total of all the panels
+	CG      v3.Vec  // centre of gravity
+	Courses []MassCourse
+}
+
+// MassProperties works out the mass and centre of gravity of the shell from the materials and
+//   gauges of its panels
+func (e *EShell) MassProperties(mats cam.MaterialSet) MassProps {
+	return e.massFrom(e.BOM(mats))
+}
+
+// courseHeight is the height of a course of panels starting at z
+func (e *EShell) courseHeight(z float64) float64 {
+	h := e.PanelSizeAt(z, e.PanelSize) * math.Sqrt(3) / 2 // a row of equilateral triangles
+	if h <= 0 {
+		return 1
+	}
+	return h
+}
+
+// massFrom works out the mass properties using the masses in the bill of materials
+func (e *EShell) massFrom(b BOM) MassProps {
+	bySerial := map[int]*Panel{}
+	for _, p := range e.Panels {
+		if p.Alive {
+			bySerial[p.Serial] = p
+		}
+	}
+
+	mp := MassProps{CG: v3.NewSimVec(0, 0, 0)}
+	for z := e.Base; z < e.E.H; z += e.courseHeight(z) {
+		mp.Courses = append(mp.Courses, MassCourse{Bottom: z, Top: math.Min(z+e.courseHeight(z), e.E.H)})
+	}
+
+	var mx, my, mz float64
+	for _, it := range b.Items {
+		p, ok := bySerial[it.Panel]
+		if !ok {
+			continue
+		}
+		mp.Mass += it.Mass
+		mx += it.Mass * p.Center.X()
+		my += it.Mass * p.Center.Y()
+		mz += it.Mass * p.Center.Z()
+		for i := range mp.Courses {
+			c := &mp.Courses[i]
+			if p.Center.Z() >= c.Bottom && (p.Center.Z() < c.Top || i == len(mp.Courses)-1) {
+				c.Panels++
+				c.Mass += it.Mass
+				break
+			}
+		}
+	}
+	if mp.Mass > 0 {
+		mp.CG = v3.NewSimVec(mx/mp.Mass, my/mp.Mass, mz/mp.Mass)
+	}
+	return mp
+}
+
+// String describes the mass properties, course by course from the bottom
+func (mp MassProps) String() string {
+	s := fmt.Sprintf("Mass: %4.0flb (%4.0fkg), CG at (%4.2f, %4.2f, %4.2f)m\n",
+		mp.Mass*kg2lb, mp.Mass, mp.CG.X(), mp.CG.Y(), mp.CG.Z())
+	for i, c := range mp.Courses {
+		if c.Panels == 0 {
+			continue
+		}
+		s += fmt.Sprintf("   Course %2d: %4.2f-%4.2fm, %3d panels, %4.0fkg\n", i+1, c.Bottom, c.Top, c.Panels, c.Mass)
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package main
+
+// ███╗   ███╗ █████╗ ████████╗███████╗██████╗ ██╗ █████╗ ██╗
+// ████╗ ████║██╔══██╗╚══██╔══╝██╔════╝██╔══██╗██║██╔══██╗██║
+// ██╔████╔██║███████║   ██║   █████╗  ██████╔╝██║███████║██║
+// ██║╚██╔╝██║██╔══██║   ██║   ██╔══╝  ██╔══██╗██║██╔══██║██║
+// ██║ ╚═╝ ██║██║  ██║   ██║   ███████╗██║  ██║██║██║  ██║███████╗
+// ╚═╝     ╚═╝╚═╝  ╚═╝   ╚═╝   ╚══════╝╚═╝  ╚═╝╚═╝╚═╝  ╚═╝╚══════╝
+
+import (
+	"fmt"
+	"sort"
+
+	cam "./cam"
+
+	"github.com/g3n/engine/math32"
+)
+
+// SheetType is what the panel is cut from: its own material and gauge, each the shell's if
+//   it has none, in the shell's size of stock
+func (p *Panel) SheetType() cam.InputSheetType {
+	st := cam.InputSheetType{Material: p.SheetMaterial(), Gauge: p.Gauge}
+	if p.Shell != nil {
+		st.Stock = p.Shell.Sheet.Stock
+		if st.Gauge == "" {
+			st.Gauge = p.Shell.Sheet.Gauge
+		}
+	}
+	return st
+}
+
+// sheetName is how the material and gauge are shown, eg "Stainless304 20ga"
+func sheetName(st cam.InputSheetType) string {
+	return fmt.Sprintf("%s %s", st.Material, st.Gauge)
+}
+
+// SetSheet gives the panels the material and gauge, which it must come in. An empty material
+//   puts them back to the shell's.
+func (e *EShell) SetSheet(ps []*Panel, mat cam.MaterialID, gauge cam.GaugeID) error {
+	if mat == "" {
+		for _, p := range ps {
+			p.Material, p.Gauge = nil, ""
+		}
+		return nil
+	}
+	m, ok := cam.Materials[mat]
+	if !ok {
+		return fmt.Errorf("No material %q", mat)
+	}
+	if _, ok := m.SheetData[gauge]; !ok {
+		return fmt.Errorf("%s does not come in %s", mat, gauge)
+	}
+	for _, p := range ps {
+		p.Material, p.Gauge = &m, gauge
+	}
+	return nil
+}
+
+// SheetChoices are every material and gauge there is, by name, for the GUI
+func SheetChoices(mats cam.MaterialSet) []cam.InputSheetType {
+	var sts []cam.InputSheetType
+	for id, m := range mats {
+		for g := range m.SheetData {
+			sts = append(sts, cam.InputSheetType{Material: id, Gauge: g})
+		}
+	}
+	sort.Slice(sts, func(i, j int) bool { return sheetName(sts[i]) < sheetName(sts[j]) })
+	return sts
+}
+
+// SheetTypes are the different ones the panels are cut from, the shell's first, then by name
+func (e *EShell) SheetTypes(ps []*Panel) []cam.InputSheetType {
+	seen := map[string]bool{}
+	var sts []cam.InputSheetType
+	for _, p := range ps {
+		st := p.SheetType()
+		if !seen[sheetName(st)] {
+			seen[sheetName(st)] = true
+			sts = append(sts, st)
+		}
+	}
+	shell := sheetName(e.Sheet)
+	sort.Slice(sts, func(i, j int) bool {
+		a, b := sheetName(sts[i]), sheetName(sts[j])
+		if (a == shell) != (b == shell) {
+			return a == shell
+		}
+		return a < b
+	})
+	return sts
+}
+
+// sheetColours show which material and gauge the panels either side of an edge are cut from,
+//   in the order of SheetTypes, going round again if there are more
+var sheetColours = []math32.Color{
+	{R: 0.5, G: 0.5, B: 0.5}, // grey, the shell's own
+	{R: 1, G: 0.5, B: 0},     // orange
+	{R: 0, G: 0.8, B: 1},     // sky blue
+	{R: 1, G: 0, B: 1},       // magenta
+	{R: 0, G: 1, B: 0},       // green
+	{R: 1, G: 1, B: 0},       // yellow
+}
+
+// sheetColourNames are the names of sheetColours, for the legend
+var sheetColourNames = []string{"Grey", "Orange", "Sky blue", "Magenta", "Green", "Yellow"}
+
+// sheetColour is the colour of the edge when showing materials: that of the panels' material
+//   and gauge, or white where panels cut from different ones meet
+func (e *EShell) sheetColour(ed *Edge, sts []cam.InputSheetType) math32.Color {
+	ps := alivePanels(ed.Panels)
+	if len(ps) == 0 {
+		return plainLine
+	}
+	name := sheetName(ps[0].SheetType())
+	for _, p := range ps[1:] {
+		if sheetName(p.SheetType()) != name {
+			return math32.Color{R: 1, G: 1, B: 1}
+		}
+	}
+	for i, st := range sts {
+		if sheetName(st) == name {
+			return sheetColours[i%len(sheetColours)]
+		}
+	}
+	return plainLine
+}
+
+// sheetLegend lists the colour of each material and gauge
+func (e *EShell) sheetLegend() string {
+	s := ""
+	for i, st := range e.SheetTypes(alivePanels(e.Panels)) {
+		s += fmt.Sprintf("%s: %s\n", sheetColourNames[i%len(sheetColourNames)], sheetName(st))
+	}
+	return s + "White: where they meet"
+}
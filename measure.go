@@ -0,0 +1,149 @@
+package main
+
+// ███╗   ███╗███████╗ █████╗ ███████╗██╗   ██╗██████╗ ███████╗
+// ████╗ ████║██╔════╝██╔══██╗██╔════╝██║   ██║██╔══██╗██╔════╝
+// ██╔████╔██║█████╗  ███████║███████╗██║   ██║██████╔╝█████╗
+// ██║╚██╔╝██║██╔══╝  ██╔══██║╚════██║██║   ██║██╔══██╗██╔══╝
+// ██║ ╚═╝ ██║███████╗██║  ██║███████║╚██████╔╝██║  ██║███████╗
+// ╚═╝     ╚═╝╚══════╝╚═╝  ╚═╝╚══════╝ ╚═════╝ ╚═╝  ╚═╝╚══════╝
+
+import (
+	"fmt"
+	"math"
+
+	gl "./gl"
+	v3 "./vec"
+)
+
+// measureSnap is how near a corner a click must be to measure from the corner rather than the
+//   nearest edge, as a fraction of the panel's size
+const measureSnap = 0.25
+
+// measureMark is half the size of the cross at each end of a measurement
+const measureMark = 0.03 // m
+
+// MeasurePoint is one end of a measurement, on a vertex or on an edge of the shell
+type MeasurePoint struct {
+	At     v3.Vec
+	Vertex *Vertex // nil if on an edge
+	Edge   *Edge
+}
+
+// SnapMeasure is the point where the ray hits the shell, snapped to the nearest corner of the
+//   panel hit if near it, otherwise to the nearest point on its edges
+func (e *EShell) SnapMeasure(r v3.Ray, maxT float64) (MeasurePoint, bool) {
+	h, ok := e.PickPanel(r, maxT)
+	if !ok {
+		return MeasurePoint{}, false
+	}
+	p := h.Panel
+	size := math.Sqrt(p.Area)
+	var mp MeasurePoint
+	best := math.Inf(1)
+	for _, v := range p.Corners {
+		if d := v.Position.Subtract(h.Where).Length(); d < best {
+			mp, best = MeasurePoint{At: v.Position, Vertex: v}, d
+		}
+	}
+	if best < measureSnap*size {
+		return mp, true
+	}
+	best = math.Inf(1)
+	for _, ed := range aliveEdges(p.Edges) {
+		seg := v3.NewSegment2Ends(ed.Vertices[0].Position, ed.Vertices[1].Position)
+		at := seg.ClosestPoint(h.Where)
+		if d := at.Subtract(h.Where).Length(); d < best {
+			mp, best = MeasurePoint{At: at, Edge: ed}, d
+		}
+	}
+	return mp, true
+}
+
+// ends are the vertices a measurement can leave the point by, and how far each is from it
+func (mp MeasurePoint) ends() map[*Vertex]float64 {
+	if mp.Vertex != nil {
+		return map[*Vertex]float64{mp.Vertex: 0}
+	}
+	return map[*Vertex]float64{
+		mp.Edge.Vertices[0]: mp.At.Subtract(mp.Edge.Vertices[0].Position).Length(),
+		mp.Edge.Vertices[1]: mp.At.Subtract(mp.Edge.Vertices[1].Position).Length(),
+	}
+}
+
+// SurfaceDistance is the shortest way from one point to the other over the shell, going along
+//   its edges, so a little more than the true geodesic; infinite if they are not connected
+func (e *EShell) SurfaceDistance(from, to MeasurePoint) float64 {
+	best := math.Inf(1)
+	if from.Edge != nil && from.Edge == to.Edge {
+		best = from.At.Subtract(to.At).Length()
+	}
+	dist := from.ends()
+	done := map[*Vertex]bool{}
+	targets := to.ends()
+	for {
+		var v *Vertex // nearest not yet done
+		for u, d := range dist {
+			if !done[u] && (v == nil || d < dist[v]) {
+				v = u
+			}
+		}
+		if v == nil || dist[v] >= best {
+			return best
+		}
+		done[v] = true
+		if off, ok := targets[v]; ok {
+			best = math.Min(best, dist[v]+off)
+		}
+		for _, ed := range aliveEdges(v.Edges) {
+			u := ed.Vertices[0]
+			if u == v {
+				u = ed.Vertices[1]
+			}
+			d := dist[v] + u.Position.Subtract(v.Position).Length()
+			if old, ok := dist[u]; !ok || d < old {
+				dist[u] = d
+			}
+		}
+	}
+}
+
+// Measurement is between two points on the shell
+type Measurement struct {
+	From, To MeasurePoint
+	Surface  float64 // m, over the shell
+}
+
+// Measure measures between the points
+func (e *EShell) Measure(from, to MeasurePoint) Measurement {
+	return Measurement{From: from, To: to, Surface: e.SurfaceDistance(from, to)}
+}
+
+// Straight is the distance between the points, through the air
+func (m Measurement) Straight() float64 {
+	return m.To.At.Subtract(m.From.At).Length()
+}
+
+// Text describes the measurement in the units
+func (m Measurement) Text(u DisplayUnits) string {
+	d := m.To.At.Subtract(m.From.At)
+	return fmt.Sprintf("%s straight, %s over the shell, dX %s dY %s dZ %s",
+		u.Len(m.Straight()), u.Len(m.Surface), u.Len(d.X()), u.Len(d.Y()), u.Len(d.Z()))
+}
+
+// Lines show the measurement in the viewer: a line between crosses at its ends
+func (m Measurement) Lines() []gl.ColourLine {
+	ls := []gl.ColourLine{{Start: m.From.At, End: m.To.At, Colour: &gl.Fuchsia}}
+	for _, at := range []v3.Vec{m.From.At, m.To.At} {
+		ls = append(ls, measureCross(at)...)
+	}
+	return ls
+}
+
+// measureCross marks a point clicked
+func measureCross(at v3.Vec) []gl.ColourLine {
+	var ls []gl.ColourLine
+	for _, ax := range []v3.Vec{v3.X, v3.Y, v3.Z} {
+		ls = append(ls, gl.ColourLine{Start: at.Subtract(ax.Scale(measureMark)), End: at.Add(ax.Scale(measureMark)), Colour: &gl.Green})
+	}
+	return ls
+}
@@ -0,0 +1,192 @@
+package main
+
+// ███╗   ██╗███████╗███████╗████████╗
+// ████╗  ██║██╔════╝██╔════╝╚══██╔══╝
+// ██╔██╗ ██║█████╗  ███████╗   ██║
+// ██║╚██╗██║██╔══╝  ╚════██║   ██║
+// ██║ ╚████║███████╗███████║   ██║
+// ╚═╝  ╚═══╝╚══════╝╚══════╝   ╚═╝
+
+import (
+	"fmt"
+	"strings"
+
+	cam "./cam"
+)
+
+// nestGap is left between parts and round the edge of the sheet, unless cutting on common lines, mm
+const nestGap = 5.0
+
+// Sheets are the emitted panels of one material and gauge nested on sheets, ready to cut
+type Sheets struct {
+	Nests  []cam.Nest
+	Panels []*Panel  // the panels nested, by their part number in the nests
+	TooBig []*Panel  // panels which would not fit on a sheet
+	Misfit []SeamFit // seams of the panels whose mating edges do not agree in length
+	Shared float64   // mm cut once for two panels on common lines
+	Type   cam.InputSheetType
+	Gauge  cam.SheetGauge
+	Stock  cam.StockSheet
+	Tag    string // names the sheets apart from those of other materials, empty if there are none
+	Marks  cam.MarkSet
+	Units  cam.Unit // the sheets are written out in
+}
+
+// Nest lays out the cut patterns of the emitted panels, with the shell's tabs, on sheets: a set
+//   of sheets for each material and gauge they are cut from, the shell's first. If the shell
+//   cuts on common lines they are butted up and edges in line are only cut once. If it marks
+//   the sheets, a margin is left clear round them for the marks. Seams of the panels which will
+//   not fit are found first, so they are reported before anything is cut, each with the sheets
+//   of its first emitted panel.
+func (e *EShell) Nest() []Sheets {
+	emitted := e.Emitted()
+	types := e.SheetTypes(emitted)
+	var shs []Sheets
+	for _, st := range types {
+		var ps []*Panel
+		for _, p := range emitted {
+			if sheetName(p.SheetType()) == sheetName(st) {
+				ps = append(ps, p)
+			}
+		}
+		sh := e.nestPanels(ps, st)
+		if len(types) > 1 {
+			sh.Tag = sheetName(st)
+		}
+		shs = append(shs, sh)
+	}
+	for _, f := range e.Misfits(emitted) {
+		p := f.Panels[0]
+		if !p.Emit {
+			p = f.Panels[1]
+		}
+		for i := range shs {
+			if sheetName(p.SheetType()) == sheetName(shs[i].Type) {
+				shs[i].Misfit = append(shs[i].Misfit, f)
+			}
+		}
+	}
+	return shs
+}
+
+// nestPanels nests the panels, all cut from the sheet type, on its stock
+func (e *EShell) nestPanels(ps []*Panel, st cam.InputSheetType) Sheets {
+	sh := Sheets{Panels: ps, Type: st, Marks: e.SheetMarks, Units: e.Units}
+	if len(ps) > 0 {
+		sh.Gauge, sh.Stock = ps[0].SheetGauge(), ps[0].Stock()
+	}
+	parts := make([]cam.Drawing, len(sh.Panels))
+	for i, p := range sh.Panels {
+		parts[i] = p.CutPattern().WithTabs(e.Tabs)
+	}
+	nests, big := cam.NestParts(parts, e.nesting(sh.Stock))
+	for _, i := range big {
+		sh.TooBig = append(sh.TooBig, sh.Panels[i])
+	}
+	if e.CommonLine {
+		for i := range nests {
+			sh.Shared += nests[i].ShareCommonLines()
+		}
+	}
+	sh.Nests = nests
+	return sh
+}
+
+// nesting is how the shell's panels are nested on the stock: the sheet's length along X, with
+//   the margin for marks if there are any
+func (e *EShell) nesting(stock cam.StockSheet) cam.Nesting {
+	w, l := stock.Size()
+	ns := cam.Nesting{Width: l * m2mm, Height: w * m2mm, Gap: nestGap, CommonLine: e.CommonLine}
+	for _, m := range e.SheetMarks {
+		if m.Any() {
+			ns.Margin = cam.SheetMargin
+		}
+	}
+	return ns
+}
+
+// Cuts are what is cut on the nth sheet, each panel led in and out as its material needs
+func (sh Sheets) Cuts(n int) cam.Drawing {
+	d := cam.Drawing{Name: fmt.Sprintf("Sheet %d of %d", n+1, len(sh.Nests))}
+	if sh.Tag != "" {
+		d.Name = sh.Tag + " " + d.Name
+	}
+	for _, pl := range sh.Nests[n].Placed {
+		d.Paths = append(d.Paths, pl.WithLeads(sh.Panels[pl.Part].Leads()).Paths...)
+	}
+	return d
+}
+
+// Sheet is the nth sheet as written to the output: its cuts and the marks put on it there, in
+//   the units the sheets are written in
+func (sh Sheets) Sheet(n int, out cam.SheetOutput) cam.Drawing {
+	d := sh.Cuts(n)
+	d.Paths = append(d.Paths, sh.Nests[n].Marks(d.Name, sh.Marks[out])...)
+	return d.InUnits(sh.Units)
+}
+
+// FileStem is the start of the names of the files the sheets are written to, with their Tag
+//   if they have one
+func (sh Sheets) FileStem(fname string) string {
+	if sh.Tag == "" {
+		return fname
+	}
+	return fname + "_" + strings.Replace(sh.Tag, " ", "_", -1)
+}
+
+// GCode is the program to cut the nth sheet on the machine
+func (sh Sheets) GCode(n int, m cam.Machine) string {
+	return sh.Sheet(n, cam.OutputGCode).GCode(m)
+}
+
+// Machine is the machine for the shell's process, set up for the thickness of its sheet
+func (e *EShell) Machine() cam.Machine {
+	return e.MachineFor(cam.Materials[e.Sheet.Material].SheetData[e.Sheet.Gauge])
+}
+
+// MachineFor is the machine for the shell's process, set up for the thickness of the gauge
+func (e *EShell) MachineFor(g cam.SheetGauge) cam.Machine {
+	t := g.Thickness
+	if t <= 0 {
+		t = hemDefaultThickness
+	}
+	return cam.Machines[e.Process].ForThickness(t)
+}
+
+// Estimate is how long the machine takes to cut all the sheets
+func (sh Sheets) Estimate(m cam.Machine) cam.CutEstimate {
+	var e cam.CutEstimate
+	for i := range sh.Nests {
+		e = e.Add(sh.Sheet(i, cam.OutputGCode).Estimate(m))
+	}
+	return e
+}
+
+// Report is the nesting with how long the machine takes to cut it and the consumables it uses
+func (sh Sheets) Report(m cam.Machine) string {
+	e := sh.Estimate(m)
+	s := sh.String()
+	s += fmt.Sprintf("%s at %.0fmm/min: %s, %.0fmin cutting\n", m.Name, m.Feed, e, e.ArcTime(m)/60)
+	for _, u := range e.Consumables(m) {
+		s += fmt.Sprintf("   %s\n", u)
+	}
+	return s
+}
+
+func (sh Sheets) String() string {
+	s := fmt.Sprintf("%d panels of %s nested on %s", len(sh.Panels)-len(sh.TooBig), sheetName(sh.Type), sh.Stock.Count(len(sh.Nests)))
+	if sh.Shared > 0 {
+		s += fmt.Sprintf(", %.1fm cut on common lines", sh.Shared/m2mm)
+	}
+	s += "\n"
+	for i, n := range sh.Nests {
+		s += fmt.Sprintf("   Sheet %d: %s\n", i+1, n)
+	}
+	for _, p := range sh.TooBig {
+		s += fmt.Sprintf("ERROR: panel %s is too big for a sheet\n", PanelLabel(p))
+	}
+	for _, f := range sh.Misfit {
+		s += fmt.Sprintf("ERROR: %s, beyond %.1fmm\n", f, seamFitTolerance*m2mm)
+	}
+	return s
+}
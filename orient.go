@@ -0,0 +1,122 @@
+package main
+
+//  ██████╗ ██████╗ ██╗███████╗███╗   ██╗████████╗
+// ██╔═══██╗██╔══██╗██║██╔════╝████╗  ██║╚══██╔══╝
+// ██║   ██║██████╔╝██║█████╗  ██╔██╗ ██║   ██║
+// ██║   ██║██╔══██╗██║██╔══╝  ██║╚██╗██║   ██║
+// ╚██████╔╝██║  ██║██║███████╗██║ ╚████║   ██║
+//  ╚═════╝ ╚═╝  ╚═╝╚═╝╚══════╝╚═╝  ╚═══╝   ╚═╝
+
+import (
+	v3 "./vec"
+)
+
+// windingNormal is the unit normal by the right hand rule round the corners, as they are ordered
+func (p *Panel) windingNormal() v3.Vec {
+	return p.windingNormal3().Vec()
+}
+
+// windingNormal3 is windingNormal by value, for Update
+func (p *Panel) windingNormal3() v3.Vec3 {
+	a, b, c := v3.V3(p.Corners[0].Position), v3.V3(p.Corners[1].Position), v3.V3(p.Corners[2].Position)
+	return b.Subtract(a).Cross(c.Subtract(a)).Normalized()
+}
+
+// reverse turns the panel's corners round the other way
+func (p *Panel) reverse() {
+	p.Corners[1], p.Corners[2] = p.Corners[2], p.Corners[1]
+}
+
+// windLike orders the corners of a new panel to go round the opposite way to an alive
+//   neighbour along their shared edge, so they wind the same way seen from outside. Panels on
+//   the same side of the edge, such as one it is being split from, are not neighbours. With no
+//   neighbour, it winds round the outward normal as it was guessed.
+func (p *Panel) windLike() {
+	for _, ed := range p.Edges {
+		a, b := ed.Vertices[0], ed.Vertices[1]
+		side := func(r *Panel) v3.Vec {
+			ab := b.Position.Subtract(a.Position)
+			return ab.Cross(r.OtherCorner(a, b).Position.Subtract(a.Position))
+		}
+		for _, q := range alivePanels(ed.Panels) {
+			if q == p || len(q.Corners) != 3 || side(p).Dot(side(q)) > 0 {
+				continue
+			}
+			if p.winds(a, b)*q.winds(a, b) > 0 {
+				p.reverse()
+			}
+			return
+		}
+	}
+	if p.windingNormal().Dot(p.Normal) < 0 {
+		p.reverse()
+	}
+}
+
+// Orient winds every alive panel the same way as its neighbours, anticlockwise seen from outside,
+//   by going out from a seed panel in each connected piece of the shell. The seed is the panel
+//   furthest from the middle of the shell, which must face away from it. Normals are then set
+//   from the winding, and follow it from now on. Returns the number of panels turned round.
+func (e *EShell) Orient() int {
+	ps := []*Panel{}
+	mid := v3.Vec(v3.Zero)
+	for _, p := range alivePanels(e.Panels) {
+		if len(p.Corners) == 3 {
+			p.Update(e)
+			ps = append(ps, p)
+			mid = mid.Add(p.Center)
+		}
+	}
+	if len(ps) == 0 {
+		return 0
+	}
+	mid = mid.Scale(1 / float64(len(ps)))
+
+	n := 0
+	done := map[*Panel]bool{}
+	for {
+		var seed *Panel
+		for _, p := range ps {
+			if !done[p] && (seed == nil || p.Center.Subtract(mid).Length() > seed.Center.Subtract(mid).Length()) {
+				seed = p
+			}
+		}
+		if seed == nil {
+			break
+		}
+		if seed.windingNormal().Dot(seed.Center.Subtract(mid)) < 0 {
+			seed.reverse()
+			n++
+		}
+		done[seed] = true
+		queue := []*Panel{seed}
+		for len(queue) > 0 {
+			p := queue[0]
+			queue = queue[1:]
+			for _, ed := range p.Edges {
+				if !ed.Alive {
+					continue
+				}
+				a, b := ed.Vertices[0], ed.Vertices[1]
+				for _, q := range alivePanels(ed.Panels) {
+					if done[q] || len(q.Corners) != 3 {
+						continue
+					}
+					if p.winds(a, b)*q.winds(a, b) > 0 {
+						q.reverse()
+						n++
+					}
+					done[q] = true
+					queue = append(queue, q)
+				}
+			}
+		}
+	}
+
+	e.Oriented = true
+	for _, p := range ps {
+		p.Update(e)
+		p.InitNormal = p.Normal
+	}
+	return n
+}
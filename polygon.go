@@ -0,0 +1,117 @@
+package main
+
+// ██████╗  ██████╗ ██╗  ██╗   ██╗ ██████╗  ██████╗ ███╗   ██╗
+// ██╔══██╗██╔═══██╗██║  ╚██╗ ██╔╝██╔════╝ ██╔═══██╗████╗  ██║
+// ██████╔╝██║   ██║██║   ╚████╔╝ ██║  ███╗██║   ██║██╔██╗ ██║
+// ██╔═══╝ ██║   ██║██║    ╚██╔╝  ██║   ██║██║   ██║██║╚██╗██║
+// ██║     ╚██████╔╝███████╗██║   ╚██████╔╝╚██████╔╝██║ ╚████║
+// ╚═╝      ╚═════╝ ╚══════╝╚═╝    ╚═════╝  ╚═════╝ ╚═╝  ╚═══╝
+
+import (
+	v3 "./vec"
+)
+
+// loops chains the edges into closed loops of vertices, leaving out any which do not close
+func loops(eds []*Edge) [][]*Vertex {
+	at := map[*Vertex][]*Edge{}
+	for _, ed := range eds {
+		at[ed.Vertices[0]] = append(at[ed.Vertices[0]], ed)
+		at[ed.Vertices[1]] = append(at[ed.Vertices[1]], ed)
+	}
+	used := map[*Edge]bool{}
+	var ls [][]*Vertex
+	for _, ed := range eds {
+		if used[ed] {
+			continue
+		}
+		used[ed] = true
+		start, v := ed.Vertices[0], ed.Vertices[1]
+		l := []*Vertex{start}
+		for v != start {
+			l = append(l, v)
+			var next *Edge
+			for _, o := range at[v] {
+				if !used[o] {
+					next = o
+					break
+				}
+			}
+			if next == nil {
+				break
+			}
+			used[next] = true
+			v = next.OtherEnd(v)
+		}
+		if v == start && len(l) > 2 {
+			ls = append(ls, l)
+		}
+	}
+	return ls
+}
+
+// polygonOf makes the loops into a polygon: the largest is the outline, going round anticlockwise
+//   seen from the side up points to, and the rest are holes in it
+func polygonOf(ls [][]*Vertex, up v3.Vec) v3.Polygon {
+	var pg v3.Polygon
+	if len(ls) == 0 {
+		return pg
+	}
+	ps := make([][]v3.Vec, len(ls))
+	outer := 0
+	for i, l := range ls {
+		for _, v := range l {
+			ps[i] = append(ps[i], v.Position)
+		}
+		if v3.NewPolygon(ps[i]...).Area() > v3.NewPolygon(ps[outer]...).Area() {
+			outer = i
+		}
+	}
+	pg.Points = ps[outer]
+	if pg.Normal().Dot(up) < 0 {
+		for i, j := 0, len(pg.Points)-1; i < j; i, j = i+1, j-1 {
+			pg.Points[i], pg.Points[j] = pg.Points[j], pg.Points[i]
+		}
+	}
+	for i := range ps {
+		if i != outer {
+			pg.AddHole(ps[i]...)
+		}
+	}
+	return pg
+}
+
+// Polygon is the panel as flat geometry: the outline of its pieces, with any holes in them,
+//   wound round its outward normal. Only really flat for a plain panel.
+func (pp PanelPieces) Polygon() v3.Polygon {
+	count := map[*Edge]int{}
+	var eds []*Edge
+	up := v3.Vec(v3.Zero)
+	for _, p := range pp.Pieces {
+		up = up.Add(p.Normal)
+		for _, ed := range p.Edges {
+			if count[ed] == 0 {
+				eds = append(eds, ed)
+			}
+			count[ed]++
+		}
+	}
+	var outline []*Edge
+	for _, ed := range eds {
+		if count[ed] == 1 {
+			outline = append(outline, ed)
+		}
+	}
+	return polygonOf(loops(outline), up)
+}
+
+// FloorPolygon is the outline of the shell where it meets the floor, going round anticlockwise
+//   seen from above, with any holes in it
+func (e *EShell) FloorPolygon() v3.Polygon {
+	var eds []*Edge
+	for _, ed := range aliveEdges(e.Edges) {
+		if ed.IsBoundary() && e.onBase(ed) {
+			eds = append(eds, ed)
+		}
+	}
+	return polygonOf(loops(eds), v3.Z)
+}
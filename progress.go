@@ -0,0 +1,170 @@
+package main
+
+// ██████╗ ██████╗  ██████╗  ██████╗ ██████╗ ███████╗███████╗███████╗
+// ██╔══██╗██╔══██╗██╔═══██╗██╔════╝ ██╔══██╗██╔════╝██╔════╝██╔════╝
+// ██████╔╝██████╔╝██║   ██║██║  ███╗██████╔╝█████╗  ███████╗███████╗
+// ██╔═══╝ ██╔══██╗██║   ██║██║   ██║██╔══██╗██╔══╝  ╚════██║╚════██║
+// ██║     ██║  ██║╚██████╔╝╚██████╔╝██║  ██║███████╗███████║███████║
+// ╚═╝     ╚═╝  ╚═╝ ╚═════╝  ╚═════╝ ╚═╝  ╚═╝╚══════╝╚══════╝╚══════╝
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	v3 "./vec"
+)
+
+// errCancelled is returned by a job on the shell which was cancelled part way
+var errCancelled = errors.New("Cancelled")
+
+// Progress is how far a long job on the shell has got, set by the goroutine doing it and read
+//   by the GUI, which can cancel it. A nil Progress is watched by no one and never cancelled.
+type Progress struct {
+	mu        sync.Mutex
+	stage     string
+	done      float64 // 0 to 1
+	cancelled bool
+}
+
+// Set says what the job is doing and how far through it is, 0 to 1
+func (p *Progress) Set(stage string, done float64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.stage, p.done = stage, done
+	p.mu.Unlock()
+}
+
+// Get is what the job is doing and how far through it is
+func (p *Progress) Get() (string, float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stage, p.done
+}
+
+// Cancel asks the job to stop at the next chance it gets
+func (p *Progress) Cancel() {
+	p.mu.Lock()
+	p.cancelled = true
+	p.mu.Unlock()
+}
+
+// Cancelled is true if the job should stop
+func (p *Progress) Cancelled() bool {
+	if p == nil {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cancelled
+}
+
+// rehome points everything in the shell back at it, after it has been copied into place
+func (e *EShell) rehome() {
+	for _, v := range e.Vertices {
+		v.Shell = e
+	}
+	for _, ed := range e.Edges {
+		ed.Shell = e
+	}
+	for _, p := range e.Panels {
+		p.Shell = e
+	}
+	for _, d := range e.Doors {
+		d.Shell = e
+	}
+	for _, b := range e.Blocks { // on its floor, wherever that now is
+		b.At = v3.NewSimVec(b.At.X(), b.At.Y(), e.Base)
+	}
+	e.touch()
+}
+
+// meshCopy is a copy of the shell with vertices, edges and panels of its own, for a job to move
+//   about in the background while the GUI goes on using the shell. The rest is shared.
+func (e *EShell) meshCopy() *EShell {
+	c := *e
+	c.bvh = nil
+	vs := make(map[*Vertex]*Vertex, len(e.Vertices))
+	eds := make(map[*Edge]*Edge, len(e.Edges))
+	ps := make(map[*Panel]*Panel, len(e.Panels))
+	c.Vertices = make([]*Vertex, len(e.Vertices))
+	for i, v := range e.Vertices {
+		nv := *v
+		nv.Shell = &c
+		c.Vertices[i], vs[v] = &nv, &nv
+	}
+	c.Edges = make([]*Edge, len(e.Edges))
+	for i, ed := range e.Edges {
+		ned := *ed
+		ned.Shell = &c
+		c.Edges[i], eds[ed] = &ned, &ned
+	}
+	c.Panels = make([]*Panel, len(e.Panels))
+	for i, p := range e.Panels {
+		np := *p
+		np.Shell = &c
+		c.Panels[i], ps[p] = &np, &np
+	}
+	for _, v := range c.Vertices {
+		v.Edges = mapEdges(v.Edges, eds)
+		v.Panels = mapPanels(v.Panels, ps)
+	}
+	for _, ed := range c.Edges {
+		ed.Vertices = mapVertices(ed.Vertices, vs)
+		ed.Panels = mapPanels(ed.Panels, ps)
+	}
+	for _, p := range c.Panels {
+		p.Corners = mapVertices(p.Corners, vs)
+		p.Edges = mapEdges(p.Edges, eds)
+		if p.SubPanelOf != nil {
+			p.SubPanelOf = ps[p.SubPanelOf]
+		}
+	}
+	return &c
+}
+
+// mapVertices are the copies of the vertices
+func mapVertices(vs []*Vertex, m map[*Vertex]*Vertex) []*Vertex {
+	out := make([]*Vertex, len(vs))
+	for i, v := range vs {
+		out[i] = m[v]
+	}
+	return out
+}
+
+// mapEdges are the copies of the edges
+func mapEdges(eds []*Edge, m map[*Edge]*Edge) []*Edge {
+	out := make([]*Edge, len(eds))
+	for i, ed := range eds {
+		out[i] = m[ed]
+	}
+	return out
+}
+
+// mapPanels are the copies of the panels
+func mapPanels(ps []*Panel, m map[*Panel]*Panel) []*Panel {
+	out := make([]*Panel, len(ps))
+	for i, p := range ps {
+		out[i] = m[p]
+	}
+	return out
+}
+
+// takePositions moves the vertices to where they are in c, a meshCopy of the shell whose
+//   vertices have been moved, and takes the tensions found there
+func (e *EShell) takePositions(c *EShell) error {
+	if len(c.Vertices) != len(e.Vertices) || len(c.Edges) != len(e.Edges) {
+		return fmt.Errorf("The shell's mesh changed while it was being moved")
+	}
+	for i, v := range e.Vertices {
+		v.Position, v.V = c.Vertices[i].Position, c.Vertices[i].V
+	}
+	for i, ed := range e.Edges {
+		ed.Tension = c.Edges[i].Tension
+	}
+	e.touch()
+	e.UpdateAll()
+	return nil
+}
@@ -0,0 +1,242 @@
+package main
+
+// ██████╗  ██████╗ ██╗     ██╗     ██╗   ██╗██████╗
+// ██╔══██╗██╔═══██╗██║     ██║     ██║   ██║██╔══██╗
+// ██████╔╝██║   ██║██║     ██║     ██║   ██║██████╔╝
+// ██╔══██╗██║   ██║██║     ██║     ██║   ██║██╔═══╝
+// ██║  ██║╚██████╔╝███████╗███████╗╚██████╔╝██║
+// ╚═╝  ╚═╝ ╚═════╝ ╚══════╝╚══════╝ ╚═════╝ ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	gl "./gl"
+	v3 "./vec"
+
+	"github.com/g3n/engine/math32"
+)
+
+// Typical dimensions for a small commercial roll-up door
+const (
+	rollupCoilDia    = 0.35  // m, diameter of the rolled-up curtain & its drum
+	rollupTrackDepth = 0.075 // m, how far the tracks stand in from the shell
+	rollupTrackWidth = 0.05  // m, width of each track, outside the opening
+	rollupBoltPitch  = 0.3   // m, max spacing of bolts fixing the tracks
+	rollupBoltDia    = 0.008 // m, M8
+)
+
+// box is a rough rectangular volume used for interference checks
+type box struct {
+	Origin  v3.Vec // one corner
+	X, Y, Z v3.Vec // edges from the origin, assumed orthogonal
+}
+
+// Contains returns true iff the point is within the box
+func (b box) Contains(p v3.Vec) bool {
+	d := p.Subtract(b.Origin)
+	for _, ax := range []v3.Vec{b.X, b.Y, b.Z} {
+		t := d.Dot(ax) / ax.Dot(ax)
+		if t < 0 || t > 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Lines are the twelve edges of the box
+func (b box) Lines(colour math32.Color) []gl.ColourLine {
+	c := []v3.Vec{b.Origin, b.Origin.Add(b.X), b.Origin.Add(b.X).Add(b.Y), b.Origin.Add(b.Y)}
+	ls := []gl.ColourLine{}
+	for i := range c {
+		j := (i + 1) % len(c)
+		top, nextTop := c[i].Add(b.Z), c[j].Add(b.Z)
+		ls = append(ls,
+			gl.ColourLine{Start: c[i], End: c[j], Colour: &colour},
+			gl.ColourLine{Start: top, End: nextTop, Colour: &colour},
+			gl.ColourLine{Start: c[i], End: top, Colour: &colour})
+	}
+	return ls
+}
+
+// jambs finds where the bottom and top of each side of the opening meet the shell, left then right
+func (d *Door) jambs() (bl, tl, br, tr v3.Vec, ok bool) {
+	s := d.Shell.surface()
+	var hits [4]bool
+	bl, hits[0] = s.RayHit(d.Corner, d.Normal)
+	tl, hits[1] = s.RayHit(d.Corner.Add(d.High), d.Normal)
+	br, hits[2] = s.RayHit(d.Corner.Add(d.Wide), d.Normal)
+	tr, hits[3] = s.RayHit(d.Corner.Add(d.Wide).Add(d.High), d.Normal)
+	ok = hits[0] && hits[1] && hits[2] && hits[3]
+	return
+}
+
+// innerCorner is the bottom left corner of the opening moved in to the innermost of the jambs,
+//   so a flat thing there is clear of the shell's edges
+func innerCorner(bl, tl, br, tr, wide, n v3.Vec) v3.Vec {
+	inner := bl
+	for _, p := range []v3.Vec{tl, br.Subtract(wide), tr.Subtract(wide)} {
+		if p.Subtract(inner).Dot(n) > 0 {
+			inner = p
+		}
+	}
+	return v3.NewSimVec(inner.X(), inner.Y(), bl.Z())
+}
+
+// RollupCoil is the rough volume of the rolled-up curtain, sitting behind the header
+func (d *Door) RollupCoil() (box, bool) {
+	_, tl, _, tr, ok := d.jambs()
+	if !ok {
+		return box{}, false
+	}
+	// The header is the innermost of the top corners, the coil sits behind it
+	header := tl
+	if tr.Subtract(tl).Dot(d.Normal) > 0 {
+		header = tr.Subtract(d.Wide)
+	}
+	wn := d.Wide.Normalized()
+	origin := header.Subtract(wn.Scale(rollupTrackWidth))
+	return box{Origin: origin,
+		X: d.Wide.Add(wn.Scale(2 * rollupTrackWidth)),
+		Y: d.Normal.Normalized().Scale(rollupCoilDia),
+		Z: v3.Z.Scale(rollupCoilDia)}, true
+}
+
+// RollupCurtain is the rough volume of the curtain when closed, running down the tracks
+func (d *Door) RollupCurtain() (box, bool) {
+	bl, tl, br, tr, ok := d.jambs()
+	if !ok {
+		return box{}, false
+	}
+	// Curtain hangs just inside the innermost point of the jambs
+	n := d.Normal.Normalized()
+	return box{Origin: innerCorner(bl, tl, br, tr, d.Wide, n), X: d.Wide, Y: n.Scale(rollupTrackDepth), Z: d.High}, true
+}
+
+// HeaderClearance returns how far the top of the coil is below the shell, negative means
+//   the shell gets in the way
+func (d *Door) HeaderClearance() (float64, bool) {
+	coil, ok := d.RollupCoil()
+	if !ok {
+		return 0, false
+	}
+	clear := math.Inf(1)
+	top := coil.Origin.Add(coil.Z)
+	for _, p := range []v3.Vec{top, top.Add(coil.X), top.Add(coil.Y), top.Add(coil.X).Add(coil.Y)} {
+		e := d.Shell.E
+		sz := e.ZGivenXY(p.X(), p.Y())
+		if math.IsNaN(sz) { // outside the footprint altogether
+			return math.Inf(-1), true
+		}
+		clear = math.Min(clear, sz-p.Z())
+	}
+	return clear, true
+}
+
+// RollupTracks makes the vertical flanges carrying the curtain tracks, one on each side of the
+//   opening, attached to the nearest edge of the panels beside it
+func (d *Door) RollupTracks() []*Flange {
+	bl, tl, br, tr, ok := d.jambs()
+	if !ok {
+		return nil
+	}
+	n := d.Normal.Normalized()
+	wn := d.Wide.Normalized()
+	tracks := []*Flange{}
+	for _, side := range [][3]v3.Vec{{bl, tl, wn.Scale(-rollupTrackWidth)}, {br, tr, wn.Scale(rollupTrackWidth)}} {
+		b, t := side[0].Add(side[2]), side[1].Add(side[2])
+		t = t.Add(v3.Z.Scale(rollupCoilDia)) // run up to the coil
+		f := Flange{Style: FStyleRollupTrackMk1, Depth: rollupTrackDepth, Normal: wn,
+			Edge:    d.Shell.NearestEdge(b.Add(t).Scale(0.5)),
+			Corners: []v3.Vec{b, t, t.Add(n.Scale(rollupTrackDepth)), b.Add(n.Scale(rollupTrackDepth))}}
+		run := t.Subtract(b)
+		nBolts := int(math.Ceil(run.Length()/rollupBoltPitch)) + 1
+		for i := 0; i < nBolts; i++ {
+			at := b.Add(run.Scale(float64(i) / float64(nBolts-1))).Add(n.Scale(rollupTrackDepth * 0.5))
+			f.Holes = append(f.Holes, at)
+			f.Dias = append(f.Dias, rollupBoltDia)
+		}
+		tracks = append(tracks, &f)
+	}
+	return tracks
+}
+
+// NearestEdge finds the alive edge whose midpoint is closest to p
+func (e *EShell) NearestEdge(p v3.Vec) *Edge {
+	var best *Edge
+	bestD := math.Inf(1)
+	for _, ed := range aliveEdges(e.Edges) {
+		mid := ed.Vertices[0].Position.Add(ed.Vertices[1].Position).Scale(0.5)
+		if dd := mid.Subtract(p).Length(); dd < bestD {
+			best, bestD = ed, dd
+		}
+	}
+	return best
+}
+
+// RollupInterference finds the shell vertices which are inside the coil or curtain volumes
+func (d *Door) RollupInterference() []*Vertex {
+	vols := []box{}
+	if coil, ok := d.RollupCoil(); ok {
+		vols = append(vols, coil)
+	}
+	if curtain, ok := d.RollupCurtain(); ok {
+		vols = append(vols, curtain)
+	}
+	hits := []*Vertex{}
+	for _, v := range d.Shell.Vertices {
+		if !v.Alive {
+			continue
+		}
+		for _, b := range vols {
+			if b.Contains(v.Position) {
+				hits = append(hits, v)
+				break
+			}
+		}
+	}
+	return hits
+}
+
+// RollupLines displays the coil, curtain and, until the door is applied, tracks, red if they
+//   interfere with the shell
+func (d *Door) RollupLines() []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	colour := gl.Green
+	clear, ok := d.HeaderClearance()
+	if !ok || clear < 0 || len(d.RollupInterference()) > 0 {
+		colour = gl.Red
+	}
+	if coil, ok := d.RollupCoil(); ok {
+		ls = append(ls, coil.Lines(colour)...)
+	}
+	if curtain, ok := d.RollupCurtain(); ok {
+		ls = append(ls, curtain.Lines(colour)...)
+	}
+	if d.Applied { // the tracks are among the shell's flanges now
+		return ls
+	}
+	trackColour := gl.Aqua
+	for _, f := range d.RollupTracks() {
+		for i := range f.Corners {
+			ls = append(ls, gl.ColourLine{Start: f.Corners[i], End: f.Corners[(i+1)%len(f.Corners)], Colour: &trackColour})
+		}
+	}
+	return ls
+}
+
+// RollupStats describes the fit of a roll-up door
+func (d *Door) RollupStats() string {
+	clear, ok := d.HeaderClearance()
+	if !ok {
+		return "      roll-up: opening misses the shell\n"
+	}
+	s := fmt.Sprintf("      roll-up: header clearance %4.0f mm", clear*1000)
+	if clear < 0 {
+		s += " -- TOO LOW"
+	}
+	if n := len(d.RollupInterference()); n > 0 {
+		s += fmt.Sprintf(", %d vertices in the way", n)
+	}
+	return s + "\n"
+}
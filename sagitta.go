@@ -0,0 +1,168 @@
+package main
+
+// ███████╗ █████╗  ██████╗ ██╗████████╗████████╗ █████╗
+// ██╔════╝██╔══██╗██╔════╝ ██║╚══██╔══╝╚══██╔══╝██╔══██╗
+// ███████╗███████║██║  ███╗██║   ██║      ██║   ███████║
+// ╚════██║██╔══██║██║   ██║██║   ██║      ██║   ██╔══██║
+// ███████║██║  ██║╚██████╔╝██║   ██║      ██║   ██║  ██║
+// ╚══════╝╚═╝  ╚═╝ ╚═════╝ ╚═╝   ╚═╝      ╚═╝   ╚═╝  ╚═╝
+
+import (
+	"fmt"
+	"sort"
+
+	v3 "./vec"
+)
+
+// Panel curvature checking
+const (
+	sagittaTolerance = 0.003 // m, how far the shell may bulge from a flat panel before it looks faceted
+	sagittaSamples   = 8     // divisions along each side of a panel when searching for the sagitta
+	sagittaPasses    = 4     // most times SplitCurvedPanels goes round
+)
+
+// PanelSagitta is how far the ellipsoid bulges from a flat panel
+type PanelSagitta struct {
+	Panel   *Panel
+	Sagitta float64 // m, greatest distance from the panel out to the ellipsoid along its normal
+	At      v3.Vec  // the point on the panel where it is greatest
+	Over    bool    // more than the tolerance
+}
+
+// SagittaReport lists the panels, most curved first
+type SagittaReport []PanelSagitta
+
+// Sagitta finds the greatest distance from the flat panel out to the surface, along the normal,
+//   by sampling a triangular grid over the panel
+func (p *Panel) Sagitta() (float64, v3.Vec) {
+	if len(p.Corners) != 3 || p.Shell == nil {
+		return 0, nil
+	}
+	a, b, c := p.Corners[0].Position, p.Corners[1].Position, p.Corners[2].Position
+	worst, at := 0.0, v3.Vec(nil)
+	for i := 0; i <= sagittaSamples; i++ {
+		for j := 0; i+j <= sagittaSamples; j++ {
+			u, v := float64(i)/sagittaSamples, float64(j)/sagittaSamples
+			pt := a.Scale(1 - u - v).Add(b.Scale(u)).Add(c.Scale(v))
+			hit, ok := p.Shell.surface().RayHit(pt, p.Normal)
+			if !ok {
+				continue
+			}
+			if s := hit.Subtract(pt).Length(); s > worst {
+				worst, at = s, pt
+			}
+		}
+	}
+	return worst, at
+}
+
+// Sagittas works out the sagitta of every alive panel, flagging those over tol
+func (e *EShell) Sagittas(tol float64) SagittaReport {
+	r := SagittaReport{}
+	for _, p := range e.Panels {
+		if !p.Alive {
+			continue
+		}
+		p.Update(e)
+		s, at := p.Sagitta()
+		r = append(r, PanelSagitta{Panel: p, Sagitta: s, At: at, Over: s > tol})
+	}
+	sort.Slice(r, func(i, j int) bool { return r[i].Sagitta > r[j].Sagitta })
+	return r
+}
+
+// Over lists just the panels over the tolerance
+func (r SagittaReport) Over() SagittaReport {
+	o := SagittaReport{}
+	for _, ps := range r {
+		if ps.Over {
+			o = append(o, ps)
+		}
+	}
+	return o
+}
+
+// String summarises the report
+func (r SagittaReport) String() string {
+	if len(r) == 0 {
+		return "Sagitta: no panels\n"
+	}
+	return fmt.Sprintf("Sagitta: worst %.1fmm (panel %d), %d of %d panels over tolerance\n",
+		r[0].Sagitta*m2mm, r[0].Panel.Serial, len(r.Over()), len(r))
+}
+
+// CSV lists every panel's sagitta
+func (r SagittaReport) CSV() string {
+	s := "Panel,Sagitta mm,Over,X,Y,Z\n"
+	for _, ps := range r {
+		x, y, z := 0.0, 0.0, 0.0
+		if ps.At != nil {
+			x, y, z = ps.At.X(), ps.At.Y(), ps.At.Z()
+		}
+		s += fmt.Sprintf("%d,%.2f,%t,%.3f,%.3f,%.3f\n", ps.Panel.Serial, ps.Sagitta*m2mm, ps.Over, x, y, z)
+	}
+	return s
+}
+
+// SplitEdge splits the edge at its midpoint, and each panel on it in two. The new vertex goes
+//   onto the ellipsoid, or stays on the base or the line of a cut if the edge is there.
+func (e *EShell) SplitEdge(ed *Edge) *Vertex {
+	a, b := ed.Vertices[0], ed.Vertices[1]
+	mid := a.Position.Add(b.Position).Scale(0.5)
+	m := e.AddVertex(mid, e.edgeConstraints(ed))
+	am := e.AddEdge([]*Vertex{a, m})
+	mb := e.AddEdge([]*Vertex{m, b})
+	am.Treatment, mb.Treatment = ed.Treatment, ed.Treatment
+	am.HemSize, mb.HemSize = ed.HemSize, ed.HemSize
+
+	for _, p := range alivePanels(ed.Panels) {
+		var c *Vertex
+		for _, v := range p.Corners {
+			if v != a && v != b {
+				c = v
+			}
+		}
+		ac, bc := p.EdgeBetween(a, c), p.EdgeBetween(b, c)
+		if c == nil || ac == nil || bc == nil {
+			console.Errorf("Panel %d is not a triangle on edge %d, not split", p.Serial, ed.Serial)
+			continue
+		}
+		e.RemovePanel(p)
+		mc := e.AddEdge([]*Vertex{m, c})
+		for _, np := range []*Panel{e.AddPanel([]*Edge{ac, am, mc}), e.AddPanel([]*Edge{bc, mb, mc})} {
+			np.SubPanelOf = p
+			np.Material, np.Gauge, np.Emit = p.Material, p.Gauge, p.Emit
+		}
+	}
+	e.RemoveEdge(ed)
+	e.Undertaker()
+	return m
+}
+
+// SplitCurvedPanels splits the longest edge of each panel whose sagitta is over tol, going
+//   round until none are or it gives up. Returns the number of edges split.
+func (e *EShell) SplitCurvedPanels(tol float64) int {
+	n := 0
+	for pass := 0; pass < sagittaPasses; pass++ {
+		over := e.Sagittas(tol).Over()
+		if len(over) == 0 {
+			break
+		}
+		for _, ps := range over {
+			if !ps.Panel.Alive {
+				continue // a neighbour was split already this pass
+			}
+			var longest *Edge
+			for _, ed := range ps.Panel.Edges {
+				if ed.Alive && (longest == nil || ed.Along.Length() > longest.Along.Length()) {
+					longest = ed
+				}
+			}
+			if longest != nil {
+				e.SplitEdge(longest)
+				n++
+			}
+		}
+	}
+	return n
+}
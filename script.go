@@ -0,0 +1,356 @@
+package main
+
+// ███████╗ ██████╗██████╗ ██╗██████╗ ████████╗
+// ██╔════╝██╔════╝██╔══██╗██║██╔══██╗╚══██╔══╝
+// ███████╗██║     ██████╔╝██║██████╔╝   ██║
+// ╚════██║██║     ██╔══██╗██║██╔═══╝    ██║
+// ███████║╚██████╗██║  ██║██║██║        ██║
+// ╚══════╝ ╚═════╝╚═╝  ╚═╝╚═╝╚═╝        ╚═╝
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+
+	v3 "./vec"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/gui"
+)
+
+// scriptExt is what design scripts are saved as
+const scriptExt = ".shelly"
+
+// scriptFilename answers the next file dialog instead of asking, when a script presses a
+//   button which saves or opens a file; "" if the dialog should ask
+var scriptFilename string
+
+// ScriptCommand is one line of a design script: a command and its arguments. A script drives
+//   the GUI the same way as clicking it does, one line a frame, waiting while a job runs:
+//
+//   # a comment
+//   choose Metric                    pick the item in whichever dropdown has it
+//   set Length 3.6                   type in the input, in the units chosen
+//   press Regenerate                 click the button, the first with the label
+//   press "Export STL" shell.stl     click it, and save or open the file rather than ask
+//   check Serials on                 tick or untick the checkbox
+//   door "Door 1" 90 100 apply       add the door if there isn't one of the name, put it that
+//                                    many degrees round from +X, facing the heading if given,
+//                                    and cut it in the shell if apply is given
+//   block Bed 1.2 -0.5 90 2 1.5 0.6  add a block at x, y, turned to the heading, sized w d h
+type ScriptCommand struct {
+	Line int // from 1
+	Name string
+	Args []string
+}
+
+// ScriptInput is a named input the script can set
+type ScriptInput struct {
+	Name string
+	Edit *gui.Edit
+}
+
+// Script is a design script being run
+type Script struct {
+	Commands []ScriptCommand
+	Shell    *EShell       // doors and blocks are placed in
+	Root     *gui.Panel    // buttons, checkboxes and dropdowns are found in
+	Inputs   []ScriptInput // set by name
+	Redraw   func()        // after a door or block has been placed, or a door cut
+	next     int
+}
+
+// splitScriptLine splits a line into words, keeping those in double quotes together and
+//   dropping any comment
+func splitScriptLine(l string) ([]string, error) {
+	var ws []string
+	var w strings.Builder
+	quoted, inWord := false, false
+	for _, r := range l {
+		switch {
+		case r == '"':
+			if quoted {
+				ws = append(ws, w.String())
+				w.Reset()
+				inWord = false
+			}
+			quoted = !quoted
+		case quoted:
+			w.WriteRune(r)
+		case r == '#':
+			if inWord {
+				ws = append(ws, w.String())
+			}
+			return ws, nil
+		case r == ' ' || r == '\t':
+			if inWord {
+				ws = append(ws, w.String())
+				w.Reset()
+				inWord = false
+			}
+		default:
+			w.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("Unclosed quote")
+	}
+	if inWord {
+		ws = append(ws, w.String())
+	}
+	return ws, nil
+}
+
+// ParseScript splits a script into its commands, checking each has the right number of
+//   arguments
+func ParseScript(src string) ([]ScriptCommand, error) {
+	counts := map[string][2]int{"choose": {1, 1}, "set": {2, 2}, "press": {1, 2}, "check": {2, 2}, "door": {2, 4}, "block": {3, 7}}
+	var cs []ScriptCommand
+	for i, l := range strings.Split(src, "\n") {
+		ws, err := splitScriptLine(l)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %s", i+1, err)
+		}
+		if len(ws) == 0 {
+			continue
+		}
+		c := ScriptCommand{Line: i + 1, Name: strings.ToLower(ws[0]), Args: ws[1:]}
+		n, ok := counts[c.Name]
+		if !ok {
+			return nil, fmt.Errorf("Line %d: Unknown command %q", c.Line, ws[0])
+		}
+		if len(c.Args) < n[0] || len(c.Args) > n[1] {
+			return nil, fmt.Errorf("Line %d: %s takes %d to %d arguments, not %d", c.Line, c.Name, n[0], n[1], len(c.Args))
+		}
+		cs = append(cs, c)
+	}
+	return cs, nil
+}
+
+// LoadScript reads and parses a script file
+func LoadScript(fname string) ([]ScriptCommand, error) {
+	b, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScript(string(b))
+}
+
+// findWidget is the first thing under n, depth first, which matches
+func findWidget(n core.INode, match func(c core.INode) bool) core.INode {
+	for _, c := range n.GetNode().Children() {
+		if match(c) {
+			return c
+		}
+		if w := findWidget(c, match); w != nil {
+			return w
+		}
+	}
+	return nil
+}
+
+// sameLabel is true if the labels are the same but for case
+func sameLabel(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// Done is true once every command has been run
+func (s *Script) Done() bool {
+	return s.next >= len(s.Commands)
+}
+
+// Step runs the next command, with the error saying which line it is on
+func (s *Script) Step() error {
+	if s.Done() {
+		return nil
+	}
+	c := s.Commands[s.next]
+	s.next++
+	if err := s.run(c); err != nil {
+		return fmt.Errorf("Line %d: %s: %s", c.Line, c.Name, err)
+	}
+	return nil
+}
+
+// run does a command
+func (s *Script) run(c ScriptCommand) error {
+	switch c.Name {
+	case "choose":
+		w := findWidget(s.Root, func(n core.INode) bool {
+			dd, ok := n.(*gui.DropDown)
+			return ok && dropDownPos(dd, c.Args[0]) >= 0
+		})
+		if w == nil {
+			return fmt.Errorf("No dropdown has %q", c.Args[0])
+		}
+		dd := w.(*gui.DropDown)
+		dd.SelectPos(dropDownPos(dd, c.Args[0]))
+	case "set":
+		for _, in := range s.Inputs {
+			if sameLabel(in.Name, c.Args[0]) {
+				in.Edit.SetText(c.Args[1])
+				return nil
+			}
+		}
+		return fmt.Errorf("No input %q", c.Args[0])
+	case "press":
+		w := findWidget(s.Root, func(n core.INode) bool {
+			b, ok := n.(*gui.Button)
+			return ok && b.Visible() && sameLabel(b.Label.Text(), c.Args[0])
+		})
+		if w == nil {
+			return fmt.Errorf("No button %q", c.Args[0])
+		}
+		if len(c.Args) > 1 {
+			scriptFilename = c.Args[1]
+			defer func() { scriptFilename = "" }() // if the button didn't ask
+		}
+		w.(*gui.Button).Dispatch(gui.OnClick, nil)
+	case "check":
+		w := findWidget(s.Root, func(n core.INode) bool {
+			cb, ok := n.(*gui.CheckBox)
+			return ok && sameLabel(cb.Label.Text(), c.Args[0])
+		})
+		if w == nil {
+			return fmt.Errorf("No checkbox %q", c.Args[0])
+		}
+		on, err := parseBool(c.Args[1])
+		if err != nil {
+			return err
+		}
+		if cb := w.(*gui.CheckBox); cb.Value() != on {
+			cb.SetValue(on)
+		}
+	case "door":
+		args := c.Args[1:]
+		apply := sameLabel(args[len(args)-1], "apply")
+		if apply {
+			args = args[:len(args)-1]
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("No position for %q", c.Args[0])
+		}
+		var fs []float64
+		for _, a := range args {
+			f, err := strconv.ParseFloat(a, 64)
+			if err != nil {
+				return err
+			}
+			fs = append(fs, f)
+		}
+		d := s.Shell.PlaceDoor(c.Args[0], v3.Degrees(fs[0]))
+		if len(fs) > 1 {
+			d.Face(v3.Deg2Rad(v3.Degrees(fs[1])))
+		}
+		if apply && !d.Applied {
+			d.Apply()
+		}
+		s.Redraw()
+	case "block":
+		if err := s.Shell.PlaceBlock(c.Args[0], c.Args[1:]); err != nil {
+			return err
+		}
+		s.Redraw()
+	}
+	return nil
+}
+
+// dropDownPos is where the item is in the dropdown, -1 if it isn't
+func dropDownPos(dd *gui.DropDown, item string) int {
+	for i := 0; i < dd.Len(); i++ {
+		if sameLabel(dd.ItemAt(i).Text(), item) {
+			return i
+		}
+	}
+	return -1
+}
+
+// PlaceDoor puts the door of the name, added if there isn't one, deg round from +X
+func (e *EShell) PlaceDoor(name string, deg v3.Degrees) *Door {
+	var d *Door
+	for _, od := range e.Doors {
+		if od.Name == name {
+			d = od
+		}
+	}
+	if d == nil {
+		d = e.AddDoor(name, 8*ft2m, 8*ft2m)
+	}
+	a := float64(v3.Deg2Rad(deg))
+	return d.DragTo(v3.NewSimVec(math.Cos(a), math.Sin(a), 0))
+}
+
+// PlaceBlock adds a block of the kind, with x, y, heading and then w, d and h, any left out
+//   as they are for a new one. Lengths are in the shell's Display units, headings in degrees.
+func (e *EShell) PlaceBlock(kind string, args []string) error {
+	for _, k := range BlockKinds {
+		if !sameLabel(k.String(), kind) {
+			continue
+		}
+		b := e.AddBlock(k)
+		var fs []float64
+		for _, a := range args {
+			f, err := strconv.ParseFloat(a, 64)
+			if err != nil {
+				e.RemoveBlock(b)
+				return err
+			}
+			fs = append(fs, f)
+		}
+		for len(fs) < 6 {
+			fs = append(fs, math.NaN())
+		}
+		x, y := b.At.X(), b.At.Y()
+		for i, p := range []*float64{&x, &y, nil, &b.W, &b.D, &b.H} {
+			if math.IsNaN(fs[i]) {
+				continue
+			}
+			if p == nil {
+				b.Heading = v3.Deg2Rad(v3.Degrees(fs[i]))
+			} else {
+				*p = e.Display.ToM(fs[i])
+			}
+		}
+		b.At = v3.NewSimVec(x, y, e.Base)
+		return nil
+	}
+	return fmt.Errorf("No kind of block %q", kind)
+}
+
+// quoteScript quotes a word for a script if it has spaces in it
+func quoteScript(w string) string {
+	if strings.ContainsAny(w, " \t#") {
+		return `"` + w + `"`
+	}
+	return w
+}
+
+// ScriptFor is a script which makes the shell again: the units, the inputs as they are,
+//   regeneration, then the doors, cutting those which have been, and the blocks. Other edits
+//   to the mesh, like skylights, vents and relaxing, are not in it.
+func (e *EShell) ScriptFor(inputs []ScriptInput) string {
+	var b strings.Builder
+	u := e.Display
+	fmt.Fprintf(&b, "# Design script, run it with Run Script or as shelly <script>\n")
+	fmt.Fprintf(&b, "choose %s\n", u)
+	for _, in := range inputs {
+		fmt.Fprintf(&b, "set %s %s\n", quoteScript(in.Name), strings.TrimSpace(in.Edit.Text()))
+	}
+	fmt.Fprintf(&b, "press Regenerate\n")
+	for _, d := range e.Doors {
+		c := d.Center()
+		apply := ""
+		if d.Applied {
+			apply = " apply"
+		}
+		fmt.Fprintf(&b, "door %s %.1f %.1f%s\n", quoteScript(d.Name), v3.Rad2Deg(v3.Radians(math.Atan2(c.Y(), c.X()))), v3.Rad2Deg(d.Heading()), apply)
+	}
+	for _, bl := range e.Blocks {
+		fmt.Fprintf(&b, "block %s %.3f %.3f %.1f %.3f %.3f %.3f\n", bl.Kind, u.FromM(bl.At.X()), u.FromM(bl.At.Y()),
+			v3.Rad2Deg(bl.Heading), u.FromM(bl.W), u.FromM(bl.D), u.FromM(bl.H))
+	}
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package main
+
+// ███████╗███████╗██╗     ███████╗ ██████╗████████╗
+// ██╔════╝██╔════╝██║     ██╔════╝██╔════╝╚══██╔══╝
+// ███████╗█████╗  ██║     █████╗  ██║        ██║
+// ╚════██║██╔══╝  ██║     ██╔══╝  ██║        ██║
+// ███████║███████╗███████╗███████╗╚██████╗   ██║
+// ╚══════╝╚══════╝╚══════╝╚══════╝ ╚═════╝   ╚═╝
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	gl "./gl"
+	v3 "./vec"
+
+	"github.com/g3n/engine/geometry"
+	"github.com/g3n/engine/gls"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+)
+
+// selectLift is how far out the highlight is drawn over the selected panels, so it is not lost in
+//   the shell's own faces
+const selectLift = 0.005 // m
+
+// selectMark is half the size of the cross marking the selected vertex
+const selectMark = 0.05 // m
+
+// Selection is the panels picked in the viewer, in the order they were picked, or an edge or a
+//   vertex
+type Selection struct {
+	Panels []*Panel
+	Edge   *Edge
+	Vertex *Vertex
+}
+
+// Has is true if the panel is selected
+func (s *Selection) Has(p *Panel) bool {
+	for _, q := range s.Panels {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Set selects just the panel
+func (s *Selection) Set(p *Panel) {
+	s.Clear()
+	s.Panels = []*Panel{p}
+}
+
+// SetEdge selects just the edge
+func (s *Selection) SetEdge(ed *Edge) {
+	s.Clear()
+	s.Edge = ed
+}
+
+// SetVertex selects just the vertex
+func (s *Selection) SetVertex(v *Vertex) {
+	s.Clear()
+	s.Vertex = v
+}
+
+// Toggle adds the panel to the selection, or takes it out if it is already in it
+func (s *Selection) Toggle(p *Panel) {
+	s.Edge, s.Vertex = nil, nil
+	for i, q := range s.Panels {
+		if q == p {
+			s.Panels = append(s.Panels[:i], s.Panels[i+1:]...)
+			return
+		}
+	}
+	s.Panels = append(s.Panels, p)
+}
+
+// Clear selects nothing
+func (s *Selection) Clear() {
+	s.Panels, s.Edge, s.Vertex = nil, nil, nil
+}
+
+// PickVertex is the corner nearest where the ray first hits the shell, within maxT, if it hits it
+func (e *EShell) PickVertex(r v3.Ray, maxT float64) (*Vertex, bool) {
+	h, ok := e.PickPanel(r, maxT)
+	if !ok {
+		return nil, false
+	}
+	var best *Vertex
+	for _, v := range h.Panel.Corners {
+		if best == nil || v.Position.Subtract(h.Where).Length() < best.Position.Subtract(h.Where).Length() {
+			best = v
+		}
+	}
+	return best, best != nil
+}
+
+// Lines show the selected edge or vertex
+func (s *Selection) Lines() []gl.ColourLine {
+	var ls []gl.ColourLine
+	if ed := s.Edge; ed != nil && ed.Alive {
+		ls = append(ls, gl.ColourLine{Start: ed.Vertices[0].Position, End: ed.Vertices[1].Position, Colour: &gl.Aqua})
+	}
+	if v := s.Vertex; v != nil && v.Alive {
+		for _, ax := range []v3.Vec{v3.X, v3.Y, v3.Z} {
+			ls = append(ls, gl.ColourLine{Start: v.Position.Subtract(ax.Scale(selectMark)), End: v.Position.Add(ax.Scale(selectMark)), Colour: &gl.Aqua})
+		}
+	}
+	return ls
+}
+
+// Neighbours are the alive panels across the panel's alive edges, by serial
+func (p *Panel) Neighbours() []*Panel {
+	var ns []*Panel
+	for _, ed := range aliveEdges(p.Edges) {
+		for _, q := range alivePanels(ed.Panels) {
+			if q != p {
+				ns = append(ns, q)
+			}
+		}
+	}
+	sort.Slice(ns, func(i, j int) bool { return ns[i].Serial < ns[j].Serial })
+	return ns
+}
+
+// Info describes each selected panel: its serial, area, material and neighbours; and their total
+//   area if there are several, in the units
+func (s *Selection) Info(u DisplayUnits) string {
+	switch {
+	case s.Edge != nil:
+		return fmt.Sprintf("Edge %d selected\n", s.Edge.Serial)
+	case s.Vertex != nil:
+		return fmt.Sprintf("Vertex %d selected\n", s.Vertex.Serial)
+	case len(s.Panels) == 0:
+		return "Nothing selected, click a panel, shift-click to add more,\n   ctrl-click an edge, alt-click a vertex"
+	}
+	str := ""
+	total := 0.0
+	for _, p := range s.Panels {
+		if !p.Alive {
+			continue
+		}
+		g := p.SheetGauge()
+		var ns []string
+		for _, q := range p.Neighbours() {
+			ns = append(ns, PanelLabel(q))
+		}
+		str += fmt.Sprintf("Panel %s (%d): %s, %s %s\n   Neighbours %s\n",
+			PanelLabel(p), p.Serial, u.Area(p.Area), p.SheetMaterial(), g.Display, strings.Join(ns, ", "))
+		total += p.Area
+	}
+	if len(s.Panels) > 1 {
+		str += fmt.Sprintf("%d panels, %s\n", len(s.Panels), u.Area(total))
+	}
+	return str
+}
+
+// PrepSelected makes the mesh highlighting the selected panels, just outside them
+func (e *EShell) PrepSelected(s *Selection, mat *material.Standard) *EShellMesh {
+	geom := geometry.NewGeometry()
+	positions := math32.NewArrayF32(0, 3*3*len(s.Panels))
+	indices := math32.NewArrayU32(0, 3*len(s.Panels))
+	var idx uint32
+	for _, p := range s.Panels {
+		if !p.Alive {
+			continue
+		}
+		vs := p.drawCorners()
+		if len(vs) < 3 {
+			continue
+		}
+		lift := p.Normal.Scale(selectLift)
+		for _, v := range vs[:3] {
+			positions = appendXZY(positions, v.Position.Add(lift))
+		}
+		indices = append(indices, idx, idx+1, idx+2)
+		idx += 3
+	}
+	geom.SetIndices(indices)
+	geom.AddVBO(gls.NewVBO(positions).AddAttrib(gls.VertexPosition))
+
+	sel := EShellMesh{}
+	sel.Mesh.Init(geom, mat)
+	return &sel
+}
@@ -0,0 +1,54 @@
+package main
+
+// ███████╗███████╗██████╗ ██╗ █████╗ ██╗     ███████╗
+// ██╔════╝██╔════╝██╔══██╗██║██╔══██╗██║     ██╔════╝
+// ███████╗█████╗  ██████╔╝██║███████║██║     ███████╗
+// ╚════██║██╔══╝  ██╔══██╗██║██╔══██║██║     ╚════██║
+// ███████║███████╗██║  ██║██║██║  ██║███████╗███████║
+// ╚══════╝╚══════╝╚═╝  ╚═╝╚═╝╚═╝  ╚═╝╚══════╝╚══════╝
+
+import (
+	"fmt"
+
+	"github.com/g3n/engine/core"
+	"github.com/g3n/engine/graphic"
+	"github.com/g3n/engine/material"
+	"github.com/g3n/engine/math32"
+	"github.com/g3n/engine/text"
+	"github.com/g3n/engine/texture"
+)
+
+// Sizes of the serials shown over the panels in the viewer
+const (
+	serialHeight = 0.08 // m
+	serialLift   = 0.03 // m, out from the panel, so it is not lost in it
+)
+
+// PanelTag is what is shown over the panel in the viewer: the label engraved on it, and its
+//   course if wanted
+func (e *EShell) PanelTag(p *Panel, course bool) string {
+	if course {
+		return fmt.Sprintf("%s c%d", PanelLabel(p), e.Course(p))
+	}
+	return PanelLabel(p)
+}
+
+// PrepSerials makes a billboard over the centre of each alive panel, always facing the camera,
+//   showing its tag in the font
+func (e *EShell) PrepSerials(font *text.Font, course bool) *core.Node {
+	n := core.NewNode()
+	font.SetFgColor(&math32.Color4{R: 1, G: 1, B: 0.6, A: 1})
+	font.SetBgColor(&math32.Color4{R: 0, G: 0, B: 0, A: 0})
+	for _, p := range alivePanels(e.Panels) {
+		img := font.DrawText(e.PanelTag(p, course))
+		mat := material.NewStandard(&math32.Color{R: 1, G: 1, B: 1})
+		mat.AddTexture(texture.NewTexture2DFromRGBA(img))
+		mat.SetTransparent(true)
+		w := serialHeight * float32(img.Bounds().Dx()) / float32(img.Bounds().Dy())
+		s := graphic.NewSprite(w, serialHeight, mat)
+		at := p.Incenter().Add(p.Normal.Scale(serialLift))
+		s.SetPosition(float32(at.X()), float32(at.Z()), float32(at.Y()))
+		n.Add(s)
+	}
+	return n
+}
@@ -42,15 +42,22 @@ import (
 )
 
 const (
-	m2ft     = 3.28084     // 1m in ft
-	ft2m     = 1 / 3.28084 // 1' in m
-	m2mm     = 1000.0      // 1m in mm
-	mm2m     = 0.001       // 1mm in m
-	sqM2sqFt = 10.7639     // 1 sq m to 1 sq ft
-	sqFt2sqM = 1 / 10.7639 // other way
-	deg90    = math.Pi / 2
+	m2ft           = 3.28084     // 1m in ft
+	ft2m           = 1 / 3.28084 // 1' in m
+	m2mm           = 1000.0      // 1m in mm
+	mm2m           = 0.001       // 1mm in m
+	in2m           = 0.0254      // 1" in m
+	sqM2sqFt       = 10.7639     // 1 sq m to 1 sq ft
+	sqFt2sqM       = 1 / 10.7639 // other way
+	kg2lb          = 2.20462     // 1kg in lb
+	vestibuleBlend = 0.3         // m, radius of the blend where a vestibule meets the shell
+	deg90          = math.Pi / 2
+	regenDelay     = 750 * time.Millisecond // after an input changes, before regenerating automatically
 )
 
+// materialsFiles are looked for at startup, the first found replacing the built in materials
+var materialsFiles = []string{"materials.json", "materials.csv"}
+
 var showTris []v3.Patch
 var showSegs []v3.Segment
 
@@ -77,6 +84,7 @@ func main() {
 	}
 
 	desiredL := 1.1     // desired size of panels
+	baseL := desiredL   // desired size of panels at the base, graded up to desiredL at the apex
 	tolerance := 0.0001 // tolerance in length approximations = 1/10th mm
 
 	headroom := 12 * ft2m
@@ -88,6 +96,8 @@ func main() {
 	semiLength := midLength / 2
 	semiHeight := midHeight / 2
 	midplaneRaised := headroom - semiHeight
+	eggTop := 1.0                         // ratio of the semi-height above the midplane to that below, 1 for an ellipsoid
+	vestibuleL, vestibuleR := 0.0, 4*ft2m // vestibule beyond the shell on +X, none if no length
 
 	// Display shell as wireframe and/or shell
 	wire := true
@@ -110,6 +120,28 @@ func main() {
 	eshell.PanelSize = desiredL
 	eshell.Tolerance = tolerance
 	eshell.FlangeWidth = 0.05 // 50 mm flanges when doubled over
+	for _, fname := range materialsFiles {
+		if _, err := os.Stat(fname); err != nil {
+			continue
+		}
+		if ms, err := cam.LoadMaterialsFile(fname); err != nil {
+			console.Warnf("%s, using the built in materials", err)
+		} else {
+			cam.Materials = ms
+		}
+		break
+	}
+	eshell.Sheet = cam.InputSheetType{Material: "Stainless304", Gauge: "20ga"}
+	if _, ok := cam.Materials[eshell.Sheet.Material].SheetData[eshell.Sheet.Gauge]; !ok {
+		console.Warnf("No %s %s in the materials, masses and bends will be rough", eshell.Sheet.Material, eshell.Sheet.Gauge)
+	}
+	eshell.Weather = LoadCase{Snow: 1.0, Wind: 45}
+
+	// shelly -sweep ... compares designs without the GUI
+	if len(os.Args) > 1 && os.Args[1] == "-sweep" {
+		os.Exit(RunSweepArgs(os.Args[2:], Design{Length: midLength, Width: midWidth, Height: midHeight, Headroom: headroom,
+			Panel: desiredL, BasePanel: baseL, EggTop: eggTop, VestibuleL: vestibuleL, VestibuleR: vestibuleR}, &eshell, tolerance))
+	}
 
 	wireframe := &ShellLines{}
 
@@ -166,14 +198,23 @@ func main() {
 	fontData, err := ioutil.ReadAll(r)
 	statsFont, err := text.NewFontFromData(fontData)
 	if err != nil {
-		fmt.Printf("Could not load font from %s\n", fontFile)
+		console.Errorf("Could not load font from %s", fontFile)
 	}
 
 	stats := gui.NewLabel("")
 	stats.SetFont(statsFont)
-	stats.SetPosition(col1, 400)
 	mygui.Add(stats)
 
+	legend := gui.NewLabel("") // explains the wireframe and panel colours
+	mygui.Add(legend)
+
+	selInfo := gui.NewLabel("") // describes the selected panels
+	selInfo.SetFont(statsFont)
+	mygui.Add(selInfo)
+
+	var inputChanged func() // schedules a regen if regenerating automatically, set up with the regen button
+
+	var scriptInputs []ScriptInput // those a script can set, by their labels
 	inpFn := func(panel *gui.Panel, lab string, init string, unit string) *gui.Edit {
 		lab1 := gui.NewLabel(lab)
 		lab1.SetPosition(col1, row)
@@ -181,20 +222,56 @@ func main() {
 		inp = gui.NewEdit(50, init)
 		inp.SetText(init)
 		inp.SetPosition(col2, row)
+		inp.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+			if inputChanged != nil {
+				inputChanged()
+			}
+		})
 		lab2 := gui.NewLabel(unit)
 		lab2.SetPosition(col3, row)
 		row += 22.0
 		panel.Add(lab1)
 		panel.Add(lab2)
 		panel.Add(inp)
+		scriptInputs = append(scriptInputs, ScriptInput{Name: lab, Edit: inp})
 		return inp
 	}
 
-	lengthInput := inpFn(mygui, "Length", fmt.Sprintf("%4.1f", midLength*m2ft), "ft")
-	widthInput := inpFn(mygui, "Width", fmt.Sprintf("%4.1f", midWidth*m2ft), "ft")
-	heightInput := inpFn(mygui, "Height", fmt.Sprintf("%4.1f", midHeight*m2ft), "ft")
-	headroomInput := inpFn(mygui, "Headroom", fmt.Sprintf("%4.1f", headroom*m2ft), "ft")
-	panelInput := inpFn(mygui, "Panel", fmt.Sprintf("%4.1f", desiredL), "m")
+	// lengths are typed in the shell's Display units, and converted and relabelled when they change
+	type lengthBox struct {
+		edit   *gui.Edit
+		unit   *gui.Label
+		suffix string // after the unit, eg "out"
+	}
+	var lengthInputs []lengthBox
+	lenFn := func(lab string, m float64, suffix string) *gui.Edit {
+		unit := gui.NewLabel(strings.TrimSpace(eshell.Display.LengthUnit() + " " + suffix))
+		unit.SetPosition(col3, row)
+		mygui.Add(unit)
+		inp := inpFn(mygui, lab, eshell.Display.Input(m), "")
+		lengthInputs = append(lengthInputs, lengthBox{edit: inp, unit: unit, suffix: suffix})
+		return inp
+	}
+	// lengthIn reads a length input, in m
+	lengthIn := func(ed *gui.Edit, old float64) float64 {
+		return eshell.Display.ToM(floatIn(ed, eshell.Display.FromM(old)))
+	}
+
+	lengthInput := lenFn("Length", midLength, "")
+	widthInput := lenFn("Width", midWidth, "")
+	heightInput := lenFn("Height", midHeight, "")
+	headroomInput := lenFn("Headroom", headroom, "")
+	panelInput := lenFn("Panel", desiredL, "")
+	basePanelInput := lenFn("Base Panel", baseL, "")
+	maxPanelsInput := inpFn(mygui, "Max Panels", "120", "")
+	eggInput := inpFn(mygui, "Egg Top", "1.0", "x bottom")
+	vestibuleLInput := lenFn("Vestibule", vestibuleL, "out")
+	vestibuleRInput := lenFn("Vestibule R", vestibuleR, "")
+	snowInput := inpFn(mygui, "Snow", "1.0", "kPa")
+	windInput := inpFn(mygui, "Wind", "45", "m/s")
+	windDirInput := inpFn(mygui, "Wind Dir", "0", "deg")
+	displayRow := row // for the units the lengths are in, set up with the rest of the GUI
+	row += 22
 
 	// ███████╗███████╗████████╗██╗   ██╗██████╗
 	// ██╔════╝██╔════╝╚══██╔══╝██║   ██║██╔══██╗
@@ -204,18 +281,45 @@ func main() {
 	// ╚══════╝╚══════╝   ╚═╝    ╚═════╝ ╚═╝
 
 	var shellmesh *EShellMesh // the actual shell
+	var selMesh *EShellMesh   // highlights the selected panels
+	var selection Selection
+	var selLines *gl.LineSet // shows the selected edge or vertex
+	var inspector *Inspector // edits what is selected
+	selMat := material.NewStandard(&math32.Color{R: 1, G: 0.8, B: 0})
+	selMat.SetEmissiveColor(&math32.Color{R: 0.6, G: 0.4, B: 0})
+	selMat.SetSide(material.SideDouble)
 
 	smat := material.NewStandard(&math32.Color{R: 1, G: 1, B: 1})
 	smat.SetLineWidth(1)
 	smat.SetWireframe(false)
 	smat.SetSide(material.SideDouble)
 
+	staleMat := material.NewStandard(&math32.Color{R: 0.35, G: 0.35, B: 0.35}) // the old shell, until it is regenerated
+	staleMat.SetSide(material.SideDouble)
+	var regenDue time.Time          // when to regenerate automatically, zero if not waiting to
+	var job *Progress               // tessellating or relaxing in the background, nil if not
+	jobDone := make(chan func(), 1) // what to do back in the GUI when the job is over
+
+	colourMat := material.NewBasic() // for the panels when coloured by something
+	colourMat.SetSide(material.SideDouble)
+	shellMat := func() material.IMaterial {
+		if eshell.PanelColouring == PanelsPlain {
+			return smat
+		}
+		return colourMat
+	}
+
 	wiremat := material.NewBasic() // for the wireframe
 	wiremat.SetLineWidth(2)
 	wiremat.SetWireframe(true)
 	//wiremat.SetSide(material.SideDouble)
 
 	var normals *gl.LineSet
+	var accessories *gl.LineSet
+	var drcLines *gl.LineSet // highlights the DRC item selected
+	var drcList *gui.List
+	var drcReport DRCReport
+	skylightR, skylightN := 0.0, 0 // skylight to cut after each regen, none if no sides
 
 	// ██████╗  ██████╗  ██████╗ ██████╗
 	// ██╔══██╗██╔═══██╗██╔═══██╗██╔══██╗
@@ -232,7 +336,28 @@ func main() {
 	var doorHeight v3.Meters = 8 * ft2m
 	// var doorWide = v3.X.Scale(8 * ft2m)
 	// var doorHigh = v3.Z.Scale(8 * ft2m)
-	var doorA *Door
+	var selDoor *Door   // the one being moved about
+	var selBlock *Block // the interior block being moved about, nil if none
+	var blockLines *gl.LineSet
+	var pickEdges bool            // clicking the shell sets the treatment of edges, not picks panels
+	var measuring bool            // clicking the shell measures between two points, not picks panels
+	var measureFrom *MeasurePoint // the first point clicked, nil until then
+	var measurements []Measurement
+	var setView func(v View)      // moves the camera to a standard view, set up with the camera
+	var setOrtho func(ortho bool) // switches between orthographic and perspective projection
+	var walker *Walker            // walking about inside the shell, nil if orbiting it
+	var serials *core.Node        // each panel's serial, over it
+	var showSerials, showCourses bool
+	figures := map[Figure]*core.Node{} // those shown, for scale
+	var assembly AssemblyPlan          // found when first shown after each regen
+	var assembling, playing bool       // showing the panels as they go up, one step at a time
+	var assemblyN int                  // steps shown
+	var assemblyMesh *EShellMesh
+	var playTime float64        // s, the step has been shown for
+	var setWalk func(walk bool) // starts or stops walking, set up with the camera
+	var measureLines *gl.LineSet
+	var relaxer *Relaxer // stepping the solver from the GUI, nil until the first step
+	var relaxing bool    // stepping it every frame
 
 	// ███████╗███████╗████████╗██╗   ██╗██████╗
 	// ██╔════╝██╔════╝╚══██╔══╝██║   ██║██╔══██╗
@@ -247,14 +372,156 @@ func main() {
 	// }
 	// mls := gl.NewLineSet(mylines)
 
+	var doorList *gui.List
+	var doorName *gui.Edit
+	var redrawDoors func()
+
+	// Make the door list match the doors, selecting the selected one
+	refreshDoorList := func() {
+		if doorList == nil {
+			return
+		}
+		doorList.Clear()
+		for i, d := range eshell.Doors {
+			doorList.Add(gui.NewImageLabel(d.Name))
+			if d == selDoor {
+				doorList.SelectPos(i, true)
+			}
+		}
+		if selDoor != nil && doorName != nil {
+			doorName.SetText(selDoor.Name)
+		}
+	}
+
+	// Show the measurements, and the first point of the one being made
+	measureInfo := gui.NewLabel("")
+	measureInfo.SetFont(statsFont)
+	mygui.Add(measureInfo)
+	redrawMeasures := func() {
+		if measureLines != nil {
+			scene.Remove(measureLines)
+		}
+		var ls []gl.ColourLine
+		for _, m := range measurements {
+			ls = append(ls, m.Lines()...)
+		}
+		if measureFrom != nil {
+			ls = append(ls, measureCross(measureFrom.At)...)
+		}
+		measureLines = gl.NewLineSet(ls, 2)
+		scene.Add(measureLines)
+		switch {
+		case measureFrom != nil:
+			measureInfo.SetText("Click the second point")
+		case len(measurements) > 0:
+			measureInfo.SetText(fmt.Sprintf("%d: %s", len(measurements), measurements[len(measurements)-1].Text(eshell.Display)))
+		case measuring:
+			measureInfo.SetText("Click the first point")
+		default:
+			measureInfo.SetText("")
+		}
+	}
+
+	// Label each panel with its serial, and course if wanted, if they are shown
+	redrawSerials := func() {
+		if serials != nil {
+			scene.Remove(serials)
+			serials = nil
+		}
+		if showSerials {
+			serials = eshell.PrepSerials(statsFont, showCourses)
+			scene.Add(serials)
+		}
+	}
+
+	// Put the figures shown back in their places, after the shell or its doors have changed
+	redrawFigures := func() {
+		for f, n := range figures {
+			scene.Remove(n)
+			figures[f] = eshell.PrepFigure(f)
+			scene.Add(figures[f])
+		}
+	}
+
+	// Redraw the interior blocks, after one has moved or the shell has changed, showing the
+	//   selected one's size in its inputs
+	blockInfo := gui.NewLabel("")
+	blockInfo.SetFont(statsFont)
+	mygui.Add(blockInfo)
+	var blockInputs []*gui.Edit // width, depth and height
+	redrawBlocks := func() {
+		if blockLines != nil {
+			scene.Remove(blockLines)
+		}
+		blockLines = gl.NewLineSet(eshell.BlockLines(selBlock), 2)
+		scene.Add(blockLines)
+		blockInfo.SetText(eshell.BlockInfo(eshell.Display))
+		if selBlock != nil {
+			for i, m := range []float64{selBlock.W, selBlock.D, selBlock.H} {
+				blockInputs[i].SetText(fmt.Sprintf("%.2f", eshell.Display.FromM(m)))
+			}
+		}
+	}
+
+	// Show the panels up after the steps of the assembly, instead of the shell, if assembling
+	assemblyInfo := gui.NewLabel("")
+	assemblyInfo.SetFont(statsFont)
+	mygui.Add(assemblyInfo)
+	redrawAssembly := func() {
+		if assemblyMesh != nil {
+			scene.Remove(assemblyMesh)
+			assemblyMesh = nil
+		}
+		if !assembling {
+			shellmesh.SetVisible(shell)
+			assemblyInfo.SetText("")
+			return
+		}
+		if assembly.Steps == nil {
+			assembly = eshell.AssemblySequence()
+			fmt.Print(assembly)
+		}
+		shellmesh.SetVisible(false)
+		assemblyMesh = eshell.PrepAssembly(assembly, assemblyN, colourMat)
+		scene.Add(assemblyMesh)
+		if assemblyN == 0 {
+			assemblyInfo.SetText(fmt.Sprintf("%d steps, press > or Play", len(assembly.Steps)))
+		} else {
+			assemblyInfo.SetText(assembly.Steps[assemblyN-1].String())
+		}
+	}
+
+	// Highlight the selected panels, edge or vertex, describe them, and show them in the inspector
+	redrawSelection := func() {
+		if selMesh != nil {
+			scene.Remove(selMesh)
+		}
+		selMesh = eshell.PrepSelected(&selection, selMat)
+		scene.Add(selMesh)
+		if selLines != nil {
+			scene.Remove(selLines)
+		}
+		selLines = gl.NewLineSet(selection.Lines(), 3)
+		scene.Add(selLines)
+		selInfo.SetText(selection.Info(eshell.Display))
+		if inspector != nil {
+			inspector.Show(eshell.Properties(&selection))
+		}
+	}
+
 	setupFunc := func() {
 
 		// mls.SetVisible(true)
 		// scene.Add(mls)
 
-		eshell.MakeMesh(desiredL, tolerance) // compute the tris
+		eshell.ReapplyDoors()
+		if skylightN > 0 { // and the skylight
+			if _, err := eshell.AddSkylight(skylightR, skylightN); err != nil {
+				console.Errorf("%s", err)
+			}
+		}
 		smat.SetWireframe(false)
-		shellmesh = eshell.Prep(smat) // convert to opengl tris
+		shellmesh = eshell.Prep(shellMat()) // convert to opengl tris
 		shellmesh.SetVisible(shell)
 		scene.Add(shellmesh)
 
@@ -283,13 +550,19 @@ func main() {
 		wireframe.SetVisible(wire)
 		scene.Add(wireframe)
 
+		accessories = gl.NewLineSet(eshell.AccessoryLines(), 2)
+		scene.Add(accessories)
+
 		eloid = ellipsoid.LatLong(60, 60, 100, wht)
 		eloid.SetVisible(ellipy)
 		scene.Add(eloid)
 
-		// Door tool 1
-		doorA = NewDoor(&eshell, doorWidth, doorHeight)
-		door = gl.NewLineSet(doorA.Display(&eshell), 3)
+		// Doors, keep any we already had
+		if len(eshell.Doors) == 0 {
+			selDoor = eshell.AddDoor("Door 1", doorWidth, doorHeight)
+			refreshDoorList()
+		}
+		door = gl.NewLineSet(eshell.DoorLines(selDoor), 3)
 
 		// doorPatch = v3.NewPatch(v3.Y.Scale(eshell.E.W+1).Add(v3.Z.Scale(eshell.Base)), v3.Y.Scale(-1), doorWide, doorHigh)
 		// doorLines = gl.LinesForPatch(doorPatch, true, doorColour)
@@ -326,6 +599,11 @@ func main() {
 		scene.Add(ground)
 
 		stats.SetText(eshell.Stats(cam.Materials))
+		redrawSelection()
+		redrawSerials()
+		redrawAssembly()
+		redrawFigures()
+		redrawBlocks()
 
 	}
 
@@ -336,32 +614,60 @@ func main() {
 	// ██║  ██║███████╗╚██████╔╝███████╗██║ ╚████║
 	// ╚═╝  ╚═╝╚══════╝ ╚═════╝ ╚══════╝╚═╝  ╚═══╝
 
+	var swapIn func(next *EShell)
+
 	// Regenerate the scene after the shell itself is changed
 	regenFunc := func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
 
-		desiredL = floatIn(panelInput, desiredL)
-		midLength = floatIn(lengthInput, midLength) * ft2m
-		midWidth = floatIn(widthInput, midWidth) * ft2m
-		headroom = floatIn(headroomInput, headroom) * ft2m
-		midHeight = math.Max(floatIn(heightInput, midHeight)*ft2m, headroom*1.25) // >headroom
-		heightInput.SetText(fmt.Sprintf("%4.1f", midHeight*m2ft))
-
-		semiWidth := midWidth / 2
-		semiLength := midLength / 2
-		semiHeight := midHeight / 2
-		midplaneRaised := headroom - semiHeight
-
-		oldDebugs := eshell.DebugLines // preserve the debugs
-
-		ellipsoid = ell.Ellipsoid{}
-		ellipsoid.Set(semiWidth, semiLength, semiHeight)
-		eshell = EShell{E: ellipsoid, DebugLines: oldDebugs}
-
-		eshell.Base = -midplaneRaised
-		eshell.PanelSize = desiredL
-		eshell.Tolerance = tolerance
-		eshell.FlangeWidth = 0.05 // 50 mm flanges when doubled over
+		desiredL = lengthIn(panelInput, desiredL)
+		baseL = lengthIn(basePanelInput, baseL)
+		midLength = lengthIn(lengthInput, midLength)
+		midWidth = lengthIn(widthInput, midWidth)
+		headroom = lengthIn(headroomInput, headroom)
+		midHeight = math.Max(lengthIn(heightInput, midHeight), headroom*1.25) // >headroom
+		heightInput.SetText(eshell.Display.Input(midHeight))
+		eshell.Weather = LoadCase{Snow: floatIn(snowInput, eshell.Weather.Snow), Wind: floatIn(windInput, eshell.Weather.Wind),
+			WindDir: floatIn(windDirInput, eshell.Weather.WindDir)}
+
+		eggTop = floatIn(eggInput, eggTop)
+		vestibuleL = lengthIn(vestibuleLInput, vestibuleL)
+		vestibuleR = lengthIn(vestibuleRInput, vestibuleR)
+		d := Design{Length: midLength, Width: midWidth, Height: midHeight, Headroom: headroom, Panel: desiredL, BasePanel: baseL,
+			EggTop: eggTop, VestibuleL: vestibuleL, VestibuleR: vestibuleR}
+		next := d.NewShell(&eshell, tolerance)
+		next.DebugLines = eshell.DebugLines // preserve the debugs
+		next.Doors = eshell.Doors           // and the doors
+		next.Blocks = eshell.Blocks         // and the layout inside
+
+		// Tessellate in the background, the old shell staying until the new one is swapped in
+		job = &Progress{}
+		next.Progress = job
+		regenDue = time.Time{} // done, whatever it set in the inputs
+		l := desiredL
+		go func() {
+			err := next.MakeMesh(l, tolerance)
+			jobDone <- func() {
+				if err == errCancelled {
+					console.Infof("Regeneration cancelled")
+				} else if err != nil {
+					console.Errorf("Regeneration: %s", err)
+				}
+				if err != nil {
+					shellmesh.SetMaterial(shellMat()) // the old shell, no longer stale
+					shellmesh.SetVisible(shell)
+					wireframe.SetVisible(wire)
+					return
+				}
+				swapIn(next)
+			}
+		}()
+	}
 
+	// Put the shell just tessellated in place of the old one, and set up the scene for it
+	swapIn = func(next *EShell) {
 		scene.Remove(shellmesh)
 		scene.Remove(wireframe)
 		scene.Remove(shellmesh.normals)
@@ -370,11 +676,693 @@ func main() {
 		scene.Remove(grid)
 		scene.Remove(door)
 		scene.Remove(normals)
-
+		scene.Remove(accessories)
+		if drcLines != nil { // the shell it highlighted is gone
+			scene.Remove(drcLines)
+			drcLines = nil
+		}
+		drcReport = nil
+		if drcList != nil {
+			drcList.Clear()
+		}
+		selection.Clear() // and so are the panels selected
+		assembly, assemblyN = AssemblyPlan{}, 0
+		relaxer = nil
+
+		ellipsoid = next.E
+		eshell = *next
+		eshell.Progress = nil
+		eshell.rehome()
 		setupFunc()
+	}
 
+	// Redraw the shell after its geometry has changed, without retessellating
+	// Redraw just the shell and its wireframe, quick enough to do every frame
+	redrawMesh := func() {
+		scene.Remove(shellmesh)
+		scene.Remove(wireframe)
+		shellmesh = eshell.Prep(shellMat())
+		shellmesh.SetVisible(shell)
+		scene.Add(shellmesh)
+		wireframe = eshell.PrepLines(wiremat)
+		wireframe.SetVisible(wire)
+		scene.Add(wireframe)
 	}
 
+	redrawFunc := func() {
+		redrawMesh()
+		redrawBlocks() // they may clash with it now
+		scene.Remove(accessories)
+		accessories = gl.NewLineSet(eshell.AccessoryLines(), 2)
+		scene.Add(accessories)
+		stats.SetText(eshell.Stats(cam.Materials))
+		legend.SetText(strings.TrimSpace(eshell.Legend() + "\n" + eshell.PanelLegend()))
+		redrawSelection()
+		redrawSerials()
+		redrawAssembly()
+	}
+
+	// Redraw the doors after one has changed
+	redrawDoors = func() {
+		scene.Remove(door)
+		door = gl.NewLineSet(eshell.DoorLines(selDoor), 3)
+		scene.Add(door)
+		stats.SetText(eshell.Stats(cam.Materials))
+		redrawFigures()
+	}
+
+	// Door list, in the second column
+	col4 := float32(350)
+	row2 := float32(40)
+	doorsLabel := gui.NewLabel("Doors")
+	doorsLabel.SetPosition(col4, row2)
+	mygui.Add(doorsLabel)
+	row2 += 20
+	clampChecks := map[Clamp]*gui.CheckBox{}
+	doorList = gui.NewVList(200, 120)
+	doorList.SetSingle(true)
+	doorList.SetPosition(col4, row2)
+	doorList.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		sel := doorList.Selected()
+		if len(sel) == 1 {
+			pos := doorList.ItemPosition(sel[0])
+			if pos >= 0 && pos < len(eshell.Doors) {
+				selDoor = eshell.Doors[pos]
+				doorName.SetText(selDoor.Name)
+				for c, cb := range clampChecks {
+					cb.SetValue(selDoor.HasClamp(c))
+				}
+				redrawDoors()
+			}
+		}
+	})
+	mygui.Add(doorList)
+	row2 += 130
+	doorName = gui.NewEdit(200, "")
+	doorName.SetPosition(col4, row2)
+	mygui.Add(doorName)
+	row2 += 25
+
+	addDoorBtn := gui.NewButton("Add Door")
+	addDoorBtn.SetPosition(col4, row2)
+	addDoorBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		selDoor = eshell.AddDoor(fmt.Sprintf("Door %d", len(eshell.Doors)+1), doorWidth, doorHeight)
+		refreshDoorList()
+		redrawDoors()
+	})
+	mygui.Add(addDoorBtn)
+
+	removeDoorBtn := gui.NewButton("Remove")
+	removeDoorBtn.SetPosition(col4+75, row2)
+	removeDoorBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if selDoor == nil {
+			return
+		}
+		eshell.RemoveDoor(selDoor)
+		selDoor = nil
+		if len(eshell.Doors) > 0 {
+			selDoor = eshell.Doors[len(eshell.Doors)-1]
+		}
+		refreshDoorList()
+		redrawDoors()
+	})
+	mygui.Add(removeDoorBtn)
+
+	renameDoorBtn := gui.NewButton("Rename")
+	renameDoorBtn.SetPosition(col4+140, row2)
+	renameDoorBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if selDoor == nil {
+			return
+		}
+		selDoor.Name = strings.TrimSpace(doorName.Text())
+		refreshDoorList()
+		redrawDoors()
+	})
+	mygui.Add(renameDoorBtn)
+	row2 += 25
+
+	doorFlange := gui.NewDropDown(130, gui.NewImageLabel(FStyleDoorMk1.String()))
+	for _, fs := range []FlangeStyle{FStyleNone, FStyleDoorMk1} {
+		doorFlange.Add(gui.NewImageLabel(fs.String()))
+	}
+	doorFlange.SelectPos(1)
+	doorFlange.SetPosition(col4, row2)
+	doorFlange.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		if selDoor != nil && !selDoor.Applied {
+			selDoor.FlangeStyle = []FlangeStyle{FStyleNone, FStyleDoorMk1}[doorFlange.SelectedPos()]
+		}
+	})
+	mygui.Add(doorFlange)
+
+	applyDoorBtn := gui.NewButton("Apply Door")
+	applyDoorBtn.SetPosition(col4+140, row2)
+	applyDoorBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil || selDoor == nil || selDoor.Applied {
+			return
+		}
+		selDoor.Apply()
+		redrawFunc()
+		redrawDoors()
+	})
+	mygui.Add(applyDoorBtn)
+
+	applyAllBtn := gui.NewButton("Apply All")
+	applyAllBtn.SetPosition(col4+230, row2)
+	applyAllBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		console.Infof("Cut %d edges round the doors", len(eshell.CutDoors()))
+		redrawFunc()
+		redrawDoors()
+	})
+	mygui.Add(applyAllBtn)
+	row2 += 30
+
+	skylightBtn := gui.NewButton("Skylight")
+	skylightBtn.SetPosition(col4, row2)
+	skylightBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil || eshell.Skylight != nil {
+			return
+		}
+		if _, err := eshell.AddSkylight(skylightRadius, skylightSides); err != nil {
+			console.Errorf("%s", err)
+			return
+		}
+		skylightR, skylightN = skylightRadius, skylightSides
+		redrawFunc()
+	})
+	mygui.Add(skylightBtn)
+
+	flangesBtn := gui.NewButton("Flanges")
+	flangesBtn.SetPosition(col4+75, row2)
+	flangesBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		n := eshell.GenerateFlanges()
+		fmt.Printf("Generated %d flanges\n", n)
+		redrawFunc()
+	})
+	mygui.Add(flangesBtn)
+
+	hemsBtn := gui.NewButton("Hem Seams")
+	hemsBtn.SetPosition(col4+140, row2)
+	hemsBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		n := eshell.AssignSeamTreatments()
+		fmt.Printf("Hemmed %d seams, %d left unhemmed\n", n, len(eshell.UnhemmedSeams()))
+		redrawFunc()
+	})
+	mygui.Add(hemsBtn)
+	hemsRow := row2
+	row2 += 30
+
+	sagittaBtn := gui.NewButton("Sagitta")
+	sagittaBtn.SetPosition(col4, row2)
+	sagittaBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		r := eshell.Sagittas(sagittaTolerance)
+		fmt.Print(r.String())
+		askFilename(".csv", func(fname string) {
+			saveText(fname, r.CSV())
+		})
+	})
+	mygui.Add(sagittaBtn)
+
+	splitBtn := gui.NewButton("Split Curved")
+	splitBtn.SetPosition(col4+75, row2)
+	splitBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		n := eshell.SplitCurvedPanels(sagittaTolerance)
+		fmt.Printf("Split %d edges. %s", n, eshell.Sagittas(sagittaTolerance).String())
+		redrawFunc()
+	})
+	mygui.Add(splitBtn)
+
+	linerBtn := gui.NewButton("Liner")
+	linerBtn.SetPosition(col4+170, row2)
+	linerBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if eshell.Liner != nil {
+			eshell.Liner = nil
+		} else {
+			eshell.Liner = eshell.Offset(linerDepth)
+		}
+		redrawFunc()
+	})
+	mygui.Add(linerBtn)
+
+	compactBtn := gui.NewButton("Compact")
+	compactBtn.SetPosition(col4+225, row2)
+	compactBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		fmt.Printf("Dropped %d dead vertices, edges and panels\n", eshell.Compact())
+		redrawFunc()
+	})
+	mygui.Add(compactBtn)
+	row2 += 30
+
+	processDD := gui.NewDropDown(70, gui.NewImageLabel(cam.ProcPlasma.String()))
+	procs := []cam.CutProcess{cam.ProcPlasma, cam.ProcLaser, cam.ProcWaterjet, cam.ProcRouter}
+	for _, pr := range procs {
+		processDD.Add(gui.NewImageLabel(pr.String()))
+	}
+	processDD.SelectPos(0)
+	processDD.SetPosition(col4, row2)
+	processDD.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		eshell.Process = procs[processDD.SelectedPos()]
+	})
+	mygui.Add(processDD)
+
+	colouringDD := gui.NewDropDown(70, gui.NewImageLabel(LinesPlain.String()))
+	for _, c := range LineColourings {
+		colouringDD.Add(gui.NewImageLabel(c.String()))
+	}
+	colouringDD.SelectPos(0)
+	colouringDD.SetPosition(col4+80, row2)
+	colouringDD.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		eshell.Colouring = LineColourings[colouringDD.SelectedPos()]
+		redrawFunc()
+	})
+	mygui.Add(colouringDD)
+
+	// colouring the panels, unlit so the colours are true
+	panelColouringDD := gui.NewDropDown(70, gui.NewImageLabel(PanelsPlain.String()))
+	for _, c := range PanelColourings {
+		panelColouringDD.Add(gui.NewImageLabel(c.String()))
+	}
+	panelColouringDD.SelectPos(0)
+	panelColouringDD.SetPosition(col4+160, row2)
+	panelColouringDD.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		eshell.PanelColouring = PanelColourings[panelColouringDD.SelectedPos()]
+		redrawFunc()
+	})
+	mygui.Add(panelColouringDD)
+	legend.SetPosition(col4+240, row2)
+
+	// picking edges: clicking one gives it the next treatment, shift-click the one before,
+	//   shown on the wireframe
+	pickEdgesCheck := gui.NewCheckBox("Pick edges")
+	pickEdgesCheck.SetPosition(col4+225, hemsRow)
+	pickEdgesCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		pickEdges = pickEdgesCheck.Value()
+		if pickEdges {
+			eshell.Colouring = LinesTreatment
+			for i, c := range LineColourings {
+				if c == LinesTreatment {
+					colouringDD.SelectPos(i)
+				}
+			}
+			redrawFunc()
+		}
+	})
+	mygui.Add(pickEdgesCheck)
+	row2 += 30
+
+	// Measuring: click two points, each snapped to a corner or edge, kept until cleared
+	measureCheck := gui.NewCheckBox("Measure")
+	measureCheck.SetPosition(col4, row2)
+	measureCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		measuring = measureCheck.Value()
+		measureFrom = nil
+		redrawMeasures()
+	})
+	mygui.Add(measureCheck)
+	measureClear := gui.NewButton("Clear measures")
+	measureClear.SetPosition(col4+90, row2)
+	measureClear.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		measurements, measureFrom = nil, nil
+		redrawMeasures()
+	})
+	mygui.Add(measureClear)
+
+	// Serials over the panels, matching those engraved on them, with their courses if wanted
+	serialsCheck := gui.NewCheckBox("Serials")
+	serialsCheck.SetPosition(col4+225, row2)
+	serialsCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		showSerials = serialsCheck.Value()
+		redrawSerials()
+	})
+	mygui.Add(serialsCheck)
+	coursesCheck := gui.NewCheckBox("Courses")
+	coursesCheck.SetPosition(col4+295, row2)
+	coursesCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		showCourses = coursesCheck.Value()
+		redrawSerials()
+	})
+	mygui.Add(coursesCheck)
+	row2 += 25
+	measureInfo.SetPosition(col4, row2)
+	row2 += 25
+
+	// Figures on the floor, to judge the size of the doors and the room inside by
+	for i, f := range Figures {
+		f := f
+		figureCheck := gui.NewCheckBox(f.String())
+		figureCheck.SetPosition(col4+float32(i)*80, row2)
+		figureCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+			if n, ok := figures[f]; ok {
+				scene.Remove(n)
+				delete(figures, f)
+			}
+			if figureCheck.Value() {
+				figures[f] = eshell.PrepFigure(f)
+				scene.Add(figures[f])
+			}
+		})
+		mygui.Add(figureCheck)
+	}
+	row2 += 25
+
+	// Snapping where doors and blocks are dragged to, and how they are turned, ctrl-drag to
+	//   do the opposite
+	snap := DefaultSnap
+	snapCheck := gui.NewCheckBox("Snap")
+	snapCheck.SetValue(snap.On)
+	snapCheck.SetPosition(col4, row2)
+	snapCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) { snap.On = snapCheck.Value() })
+	mygui.Add(snapCheck)
+	snapGridInput := gui.NewEdit(45, eshell.Display.Input(snap.Grid))
+	snapGridInput.SetPosition(col4+60, row2)
+	mygui.Add(snapGridInput)
+	snapGridUnit := gui.NewLabel(eshell.Display.LengthUnit())
+	snapGridUnit.SetPosition(col4+110, row2+2)
+	mygui.Add(snapGridUnit)
+	snapAngleInput := gui.NewEdit(45, fmt.Sprintf("%.0f", float64(snap.Angle)))
+	snapAngleInput.SetPosition(col4+140, row2)
+	mygui.Add(snapAngleInput)
+	snapAngleUnit := gui.NewLabel("°, ctrl-drag to not snap")
+	snapAngleUnit.SetPosition(col4+190, row2+2)
+	mygui.Add(snapAngleUnit)
+	row2 += 25
+
+	// Interior blocks, added in the middle of the floor, dragged about like the doors and sized
+	//   in the inputs
+	for i, k := range BlockKinds {
+		k := k
+		addBlockBtn := gui.NewButton(k.String())
+		addBlockBtn.SetPosition(col4+float32(i)*65, row2)
+		addBlockBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+			selBlock = eshell.AddBlock(k)
+			redrawBlocks()
+		})
+		mygui.Add(addBlockBtn)
+	}
+	removeBlockBtn := gui.NewButton("Remove")
+	removeBlockBtn.SetPosition(col4+float32(len(BlockKinds))*65, row2)
+	removeBlockBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if selBlock != nil {
+			eshell.RemoveBlock(selBlock)
+			selBlock = nil
+			redrawBlocks()
+		}
+	})
+	mygui.Add(removeBlockBtn)
+	row2 += 25
+	for i, lab := range []string{"W", "D", "H"} {
+		l := gui.NewLabel(lab)
+		l.SetPosition(col4+float32(i)*65, row2+2)
+		mygui.Add(l)
+		inp := gui.NewEdit(45, "")
+		inp.SetPosition(col4+float32(i)*65+15, row2)
+		mygui.Add(inp)
+		blockInputs = append(blockInputs, inp)
+	}
+	setBlockBtn := gui.NewButton("Size")
+	setBlockBtn.SetPosition(col4+float32(len(blockInputs))*65, row2)
+	setBlockBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if selBlock == nil {
+			return
+		}
+		for i, m := range []*float64{&selBlock.W, &selBlock.D, &selBlock.H} {
+			if l := lengthIn(blockInputs[i], *m); l > 0 {
+				*m = l
+			}
+		}
+		redrawBlocks()
+	})
+	mygui.Add(setBlockBtn)
+	row2 += 25
+	blockInfo.SetPosition(col4, row2)
+	row2 += 50
+
+	// Standard views, also keys 1 to 4, and orthographic projection, also key 5, for checking
+	//   dimensions against the grid
+	for i, v := range Views {
+		v := v
+		viewBtn := gui.NewButton(v.String())
+		viewBtn.SetPosition(col4+float32(i)*50, row2)
+		viewBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) { setView(v) })
+		mygui.Add(viewBtn)
+	}
+	orthoCheck := gui.NewCheckBox("Ortho")
+	orthoCheck.SetPosition(col4+float32(len(Views))*50+10, row2)
+	orthoCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) { setOrtho(orthoCheck.Value()) })
+	mygui.Add(orthoCheck)
+	walkCheck := gui.NewCheckBox("Walk")
+	walkCheck.SetPosition(col4+float32(len(Views))*50+70, row2)
+	walkCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) { setWalk(walkCheck.Value()) })
+	mygui.Add(walkCheck)
+	row2 += 30
+
+	// Assembly guide: the panels put up one step at a time, stepped through or played
+	assemblyCheck := gui.NewCheckBox("Assembly")
+	assemblyCheck.SetPosition(col4, row2)
+	assemblyCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		assembling, playing = assemblyCheck.Value(), false
+		redrawAssembly()
+	})
+	mygui.Add(assemblyCheck)
+	for i, d := range []int{-1, 1} {
+		d := d
+		label := "<"
+		if d > 0 {
+			label = ">"
+		}
+		stepBtn := gui.NewButton(label)
+		stepBtn.SetPosition(col4+90+float32(i)*30, row2)
+		stepBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+			if !assembling {
+				return
+			}
+			if n := assemblyN + d; n >= 0 && n <= len(assembly.Steps) {
+				assemblyN = n
+			}
+			redrawAssembly()
+		})
+		mygui.Add(stepBtn)
+	}
+	playCheck := gui.NewCheckBox("Play")
+	playCheck.SetPosition(col4+160, row2)
+	playCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		playing = playCheck.Value() && assembling
+		if playing && assemblyN >= len(assembly.Steps) {
+			assemblyN = 0
+		}
+		playTime = 0
+	})
+	mygui.Add(playCheck)
+	row2 += 25
+	assemblyInfo.SetPosition(col4, row2)
+	row2 += 25
+
+	// Choosing the panels to emit, for building in phases
+	emitDD := gui.NewDropDown(70, gui.NewImageLabel(EmitAll.String()))
+	for _, k := range EmitKinds {
+		emitDD.Add(gui.NewImageLabel(k.String()))
+	}
+	emitDD.SelectPos(0)
+	emitDD.SetPosition(col4, row2)
+	mygui.Add(emitDD)
+	emitArg := gui.NewEdit(80, "")
+	emitArg.SetPosition(col4+80, row2)
+	mygui.Add(emitArg)
+	emitBtn := gui.NewButton("Emit")
+	emitBtn.SetPosition(col4+170, row2)
+	emitBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		f, err := eshell.EmitFilter(EmitKinds[emitDD.SelectedPos()], emitArg.Text())
+		if err != nil {
+			console.Errorf("%s", err)
+			return
+		}
+		fmt.Printf("Emitting %d of %d panels\n", eshell.SetEmit(f), len(alivePanels(eshell.Panels)))
+	})
+	mygui.Add(emitBtn)
+	row2 += 30
+
+	// Importing DXF outlines, engraved on or cut through the panels typed, as "12,40,41"
+	dxfArg := gui.NewEdit(70, "")
+	dxfArg.SetPosition(col4, row2)
+	mygui.Add(dxfArg)
+	dxfPanels := func(then func(ps []*Panel, d cam.Drawing)) {
+		f, err := eshell.EmitFilter(EmitSet, dxfArg.Text())
+		if err != nil {
+			console.Errorf("%s", err)
+			return
+		}
+		var ps []*Panel
+		for _, p := range alivePanels(eshell.Panels) {
+			if f(p) {
+				ps = append(ps, p)
+			}
+		}
+		if len(ps) == 0 {
+			console.Errorf("No panels %q", dxfArg.Text())
+			return
+		}
+		openFilename(".dxf", func(fname string) {
+			d, err := cam.LoadDXF(fname)
+			if err != nil {
+				console.Errorf("%s", err)
+				return
+			}
+			then(ps, d)
+		})
+	}
+	engraveBtn := gui.NewButton("Engrave DXF")
+	engraveBtn.SetPosition(col4+80, row2)
+	engraveBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		dxfPanels(func(ps []*Panel, d cam.Drawing) {
+			for _, p := range ps {
+				p.EngraveFitted(d)
+			}
+			fmt.Printf("Engraved %s on %d panels\n", d.Name, len(ps))
+		})
+	})
+	mygui.Add(engraveBtn)
+	cutDXFBtn := gui.NewButton("Cut DXF")
+	cutDXFBtn.SetPosition(col4+170, row2)
+	cutDXFBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		dxfPanels(func(ps []*Panel, d cam.Drawing) {
+			for _, p := range ps {
+				if _, err := eshell.CutOutline(d, p, 0); err != nil {
+					console.Errorf("%s", err)
+				}
+			}
+			redrawFunc()
+		})
+	})
+	mygui.Add(cutDXFBtn)
+	row2 += 30
+
+	// Clamps for the selected door
+	for _, c := range []Clamp{ClampTangent, ClampCenter, ClampFaceX, ClampFaceY, ClampOnX, ClampOnY} {
+		c := c
+		cb := gui.NewCheckBox(c.String())
+		cb.SetPosition(col4, row2)
+		cb.SetValue(selDoor != nil && selDoor.HasClamp(c))
+		cb.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+			if selDoor != nil && selDoor.HasClamp(c) != cb.Value() {
+				selDoor.SetClamp(c, cb.Value())
+				redrawDoors()
+			}
+		})
+		mygui.Add(cb)
+		clampChecks[c] = cb
+		row2 += 20
+	}
+
+	// Design rules check, click an item to highlight what it is about
+	drcBtn := gui.NewButton("DRC")
+	drcBtn.SetPosition(col4, row2)
+	drcBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		drcReport = eshell.DRC()
+		fmt.Print(drcReport.String())
+		drcList.Clear()
+		for _, it := range drcReport {
+			drcList.Add(gui.NewImageLabel(it.String()))
+		}
+	})
+	mygui.Add(drcBtn)
+	row2 += 25
+	drcList = gui.NewVList(300, 120)
+	drcList.SetSingle(true)
+	drcList.SetPosition(col4, row2)
+	drcList.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		sel := drcList.Selected()
+		if len(sel) != 1 {
+			return
+		}
+		pos := drcList.ItemPosition(sel[0])
+		if pos < 0 || pos >= len(drcReport) {
+			return
+		}
+		if drcLines != nil {
+			scene.Remove(drcLines)
+		}
+		drcLines = gl.NewLineSet(drcReport[pos].Lines(), 3)
+		scene.Add(drcLines)
+		fmt.Println(drcReport[pos])
+	})
+	mygui.Add(drcList)
+	row2 += 125
+
+	// Material and gauge for the selected panels, the first choice puts them back to the shell's
+	sheetChoices := SheetChoices(cam.Materials)
+	sheetDD := gui.NewDropDown(150, gui.NewImageLabel("Shell's"))
+	sheetDD.Add(gui.NewImageLabel("Shell's"))
+	for _, st := range sheetChoices {
+		sheetDD.Add(gui.NewImageLabel(sheetName(st)))
+	}
+	sheetDD.SelectPos(0)
+	sheetDD.SetPosition(col4, row2)
+	mygui.Add(sheetDD)
+	sheetBtn := gui.NewButton("Assign")
+	sheetBtn.SetPosition(col4+160, row2)
+	sheetBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if len(selection.Panels) == 0 {
+			console.Errorf("No panels selected")
+			return
+		}
+		var st cam.InputSheetType
+		if pos := sheetDD.SelectedPos(); pos > 0 {
+			st = sheetChoices[pos-1]
+		}
+		if err := eshell.SetSheet(selection.Panels, st.Material, st.Gauge); err != nil {
+			console.Errorf("%s", err)
+			return
+		}
+		fmt.Printf("%d panels cut from %s\n", len(selection.Panels), sheetName(eshell.SheetTypes(selection.Panels)[0]))
+		redrawFunc()
+	})
+	mygui.Add(sheetBtn)
+	row2 += 30
+
+	// Vents for commercial units, one in the middle of each selected panel
+	for _, vs := range []VentShape{VentRound, VentRect} {
+		vs := vs
+		ventBtn := gui.NewButton(vs.String() + " Vent")
+		ventBtn.SetPosition(col4+float32(vs)*90, row2)
+		ventBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+			if job != nil {
+				return
+			}
+			if len(selection.Panels) == 0 {
+				console.Errorf("No panels selected")
+				return
+			}
+			for _, p := range selection.Panels {
+				if _, err := eshell.AddVent(p, vs, ventFreeArea); err != nil {
+					console.Errorf("%s", err)
+				}
+			}
+			redrawFunc()
+		})
+		mygui.Add(ventBtn)
+	}
+	row2 += 30
+	selInfo.SetPosition(col4, row2)
+
+	// Properties of the selection, edited in the inspector, docked at the left or right
+	inspector = NewInspector(mygui, 5, mygui.Width()-inspectorWidth-5, 200, func() { redrawFunc() })
+	inspector.Panel.SetVisible(false)
+	inspectCheck := gui.NewCheckBox("Properties")
+	inspectCheck.SetPosition(col4+225, row2-60)
+	inspectCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		inspector.Panel.SetVisible(inspectCheck.Value())
+		redrawSelection()
+	})
+	mygui.Add(inspectCheck)
+
 	row += 15
 
 	// wireframe button
@@ -410,11 +1398,57 @@ func main() {
 	regenBtn.Subscribe(gui.OnClick, regenFunc)
 	mygui.Add(regenBtn)
 
+	// regenerating automatically a moment after an input is changed, the old shell greyed until then
+	autoRegenCheck := gui.NewCheckBox("Auto")
+	autoRegenCheck.SetPosition(col1+90, row)
+	mygui.Add(autoRegenCheck)
+
+	// how the job in the background is getting on, and a button to stop it, shown while it runs
+	jobPanel := gui.NewPanel(250, 20)
+	jobPanel.SetPosition(col1+150, row)
+	jobPanel.SetVisible(false)
+	jobBar := gui.NewPanel(0, 6)
+	jobBar.SetPosition(0, 14)
+	jobBar.SetColor4(&math32.Color4{R: 0.2, G: 0.8, B: 0.2, A: 1})
+	jobPanel.Add(jobBar)
+	jobLabel := gui.NewLabel("")
+	jobPanel.Add(jobLabel)
+	jobCancel := gui.NewButton("Cancel")
+	jobCancel.SetPosition(180, 0)
+	jobCancel.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			job.Cancel()
+		}
+	})
+	jobPanel.Add(jobCancel)
+	mygui.Add(jobPanel)
+	showJob := func() {
+		jobPanel.SetVisible(job != nil)
+		if job != nil {
+			stage, done := job.Get()
+			jobLabel.SetText(fmt.Sprintf("%s %.0f%%", stage, 100*done))
+			jobBar.SetWidth(float32(170 * math.Max(0, math.Min(1, done))))
+		}
+	}
+	inputChanged = func() {
+		if !autoRegenCheck.Value() {
+			return
+		}
+		regenDue = time.Now().Add(regenDelay)
+		shellmesh.SetMaterial(staleMat)
+		shellmesh.SetVisible(true)
+		wireframe.SetVisible(false)
+	}
+
 	row += 25
 
 	// Cull edges button
 	cullFunc := func(name string, ev interface{}) {
-		//eshell.PruneEdges(desiredL * 0.1)
+		if job != nil {
+			return
+		}
+		eshell.PruneEdges(desiredL * 0.1)
+		redrawFunc()
 		// _, _, err := dlgs.FileMulti("Select files", "")
 		// if err != nil {
 		// 	panic(err)
@@ -426,6 +1460,152 @@ func main() {
 	cullBtn.Subscribe(gui.OnClick, cullFunc)
 	mygui.Add(cullBtn)
 
+	row += 25
+
+	// Flip edges button
+	flipBtn := gui.NewButton("Flip Edges")
+	flipBtn.SetPosition(col1, row)
+	flipBtn.SetSize(40, 18)
+	flipBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		eshell.FlipEdges()
+		redrawFunc()
+	})
+	mygui.Add(flipBtn)
+
+	row += 25
+
+	// Decimate button
+	decBtn := gui.NewButton("Decimate")
+	decBtn.SetPosition(col1, row)
+	decBtn.SetSize(40, 18)
+	decBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		eshell.Decimate(int(floatIn(maxPanelsInput, 120)))
+		redrawFunc()
+	})
+	mygui.Add(decBtn)
+
+	row += 25
+
+	// Relax button
+	relaxBtn := gui.NewButton("Relax")
+	relaxBtn.SetPosition(col1, row)
+	relaxBtn.SetSize(40, 18)
+	mygui.Add(relaxBtn)
+
+	// Relaxation controls: step through the solver or play it, tuning its knobs as it goes
+	relaxStepBtn := gui.NewButton("Step")
+	relaxStepBtn.SetPosition(col1+50, row)
+	relaxStepBtn.SetSize(40, 18)
+	mygui.Add(relaxStepBtn)
+	relaxCheck := gui.NewCheckBox("Play")
+	relaxCheck.SetPosition(col1+100, row)
+	mygui.Add(relaxCheck)
+	relaxRow := row
+	row += 25
+	kSlider := gui.NewHSlider(100, 20)
+	kSlider.SetPosition(col1, row)
+	kSlider.SetValue(0.5)
+	mygui.Add(kSlider)
+	dampingSlider := gui.NewHSlider(100, 20)
+	dampingSlider.SetPosition(col1+110, row)
+	dampingSlider.SetValue(float32((relaxDamping - 0.8) / 0.2))
+	mygui.Add(dampingSlider)
+	relaxLSlider := gui.NewHSlider(100, 20)
+	relaxLSlider.SetPosition(col1+220, row)
+	relaxLSlider.SetValue(0.5)
+	mygui.Add(relaxLSlider)
+	row += 25
+	relaxInfo := gui.NewLabel("")
+	relaxInfo.SetPosition(col1, row)
+	mygui.Add(relaxInfo)
+
+	// relaxParams reads the sliders: k from a tenth to ten times the usual, damping from 0.8
+	//   to 1, and length from half to one and a half times the panel size
+	relaxParams := func() RelaxParams {
+		p := RelaxParams{
+			K:       relaxK * math.Pow(10, 2*float64(kSlider.Value())-1),
+			Damping: 0.8 + 0.2*float64(dampingSlider.Value()),
+			Length:  eshell.PanelSize * (0.5 + float64(relaxLSlider.Value())),
+		}
+		kSlider.SetText(fmt.Sprintf("k %.3g", p.K))
+		dampingSlider.SetText(fmt.Sprintf("damping %.3f", p.Damping))
+		relaxLSlider.SetText(eshell.Display.Len(p.Length))
+		return p
+	}
+	for _, s := range []*gui.Slider{kSlider, dampingSlider, relaxLSlider} {
+		s.Subscribe(gui.OnChange, func(name string, ev interface{}) { relaxParams() })
+	}
+	relaxParams()
+
+	// relaxOnce does an iteration with the knobs as they are now, true once the solver is done
+	relaxOnce := func() bool {
+		if relaxer == nil {
+			relaxer = eshell.NewRelaxer(relaxParams())
+		}
+		relaxer.Params = relaxParams()
+		done := relaxer.Iterate(tolerance)
+		relaxInfo.SetText(relaxer.Stats.String())
+		if done {
+			relaxer = nil // the next step starts again
+		}
+		return done
+	}
+	relaxStepBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		relaxOnce()
+		redrawFunc()
+	})
+	// Drive the boundary edges, round the base and any openings, to the length on the slider
+	targetBtn := gui.NewButton("Target Boundary")
+	targetBtn.SetPosition(col1+160, relaxRow)
+	targetBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		l := relaxParams().Length
+		console.Infof("Boundary edges driven to %s: %d", eshell.Display.Len(l), eshell.SetTargets((*Edge).IsBoundary, l))
+		redrawFunc()
+	})
+	mygui.Add(targetBtn)
+	relaxCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		relaxing = relaxCheck.Value()
+		if !relaxing {
+			redrawFunc()
+		}
+	})
+
+	relaxBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		if job != nil {
+			return
+		}
+		relaxCheck.SetValue(false)
+		relaxer = nil
+		// Relax a copy in the background, the shell taking where its vertices end up
+		next := eshell.meshCopy()
+		job = &Progress{}
+		next.Progress = job
+		p := relaxParams()
+		go func() {
+			stats := next.Relax(p, 500, tolerance)
+			jobDone <- func() {
+				relaxInfo.SetText(stats.String())
+				if err := eshell.takePositions(next); err != nil {
+					console.Errorf("Relax: %s", err)
+					return
+				}
+				redrawFunc()
+			}
+		}()
+	})
+
 	row += 40
 
 	// normals button
@@ -457,35 +1637,339 @@ func main() {
 	stlBtn.SetPosition(col1, row)
 	stlBtn.SetSize(40, 18)
 	stlBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		r := eshell.Watertight()
+		fmt.Print(r.String())
+		if !r.OK() {
+			fmt.Print("Repaired. ", eshell.RepairWatertight().String())
+		}
+		askFilename(".stl", func(fname string) {
+			saveText(fname, eshell.STLString())
+		})
+	})
+	mygui.Add(stlBtn)
+
+	// export ground anchor plan button
+	anchorBtn := gui.NewButton("Export Anchors")
+	anchorBtn.SetPosition(col1+90, row)
+	anchorBtn.SetSize(40, 18)
+	anchorBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		n := eshell.GroundFlanges()
+		fmt.Printf("%d ground flanges, %d anchors\n", n, len(eshell.Anchors()))
+		redrawFunc()
+		askFilename(".csv", func(path string) {
+			fname := strings.TrimSuffix(path, ".csv")
+			saveText(fname+".csv", eshell.AnchorTable())
+			u := eshell.Units
+			saveText(fname+".dxf", eshell.AnchorPlan().InUnits(u).DXF())
+			plan := eshell.BasePlan().InUnits(u)
+			saveText(fname+"_base.dxf", plan.DXF())
+			saveText(fname+"_base.svg", plan.SVG(u.FromMM(2*basePlanOffset)))
+		})
+	})
+	mygui.Add(anchorBtn)
+
+	// export bill of materials button
+	bomBtn := gui.NewButton("Export BOM")
+	bomBtn.SetPosition(col1+200, row)
+	bomBtn.SetSize(40, 18)
+	bomBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		bom := eshell.EmittedBOM(cam.Materials)
+		askFilename(".csv", func(path string) {
+			fname := strings.TrimSuffix(path, ".csv")
+			c, err := bom.CSV()
+			if err != nil {
+				console.Errorf("%s", err)
+				return
+			}
+			j, err := bom.JSON()
+			if err != nil {
+				console.Errorf("%s", err)
+				return
+			}
+			f, err := eshell.Fasteners().CSV()
+			if err != nil {
+				console.Errorf("%s", err)
+				return
+			}
+			as, err := eshell.AssemblySequence().CSV()
+			if err != nil {
+				console.Errorf("%s", err)
+				return
+			}
+			saveText(fname+".csv", c)
+			saveText(fname+".json", j)
+			saveText(fname+"_fasteners.csv", f)
+			saveText(fname+"_assembly.csv", as)
+		})
+	})
+	mygui.Add(bomBtn)
+
+	row += 25
 
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Print("Enter filename: ")
-		fname, _ := reader.ReadString('\n')
-		fname = strings.TrimSpace(fname)
-		if !strings.HasSuffix(fname, ".stl") {
-			fname = fname + ".stl"
+	// export load report button
+	loadsBtn := gui.NewButton("Export Loads")
+	loadsBtn.SetPosition(col1, row)
+	loadsBtn.SetSize(40, 18)
+	loadsBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		askFilename(".csv", func(fname string) {
+			saveText(fname, eshell.Loads(eshell.Weather, cam.Materials).CSV())
+		})
+	})
+	mygui.Add(loadsBtn)
+
+	// export FE mesh button
+	feaBtn := gui.NewButton("Export FEA")
+	feaBtn.SetPosition(col1+90, row)
+	feaBtn.SetSize(40, 18)
+	feaBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		askFilename(FEACalculix.String(), func(path string) {
+			fname := strings.TrimSuffix(path, FEACalculix.String())
+			for _, f := range []FEAFormat{FEACalculix, FEANastran} {
+				s, err := eshell.FEAMesh(f, cam.Materials)
+				if err != nil {
+					console.Errorf("%s", err)
+					continue
+				}
+				saveText(fname+f.String(), s)
+			}
+		})
+	})
+	mygui.Add(feaBtn)
+
+	// export base ring member button
+	memberBtn := gui.NewButton("Base Member")
+	memberBtn.SetPosition(col1+180, row)
+	memberBtn.SetSize(40, 18)
+	memberBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		askFilename(".dxf", func(path string) {
+			fname := strings.TrimSuffix(path, ".dxf")
+			ms := eshell.BaseMembers()
+			for _, m := range ms {
+				saveText(fmt.Sprintf("%s_%d_rolled.dxf", fname, m.N), m.RolledDrawing().InUnits(eshell.Units).DXF())
+				saveText(fmt.Sprintf("%s_%d_web.dxf", fname, m.N), m.FlatDrawing().InUnits(eshell.Units).DXF())
+			}
+			saveText(fname+"_splice.dxf", SplicePlate().InUnits(eshell.Units).DXF())
+			fmt.Printf("%d base members and joints, 2 splice plates per joint\n", len(ms))
+		})
+	})
+	mygui.Add(memberBtn)
+
+	// export the flat parts of the swing doors' leaves and frames, a file each
+	doorPartsBtn := gui.NewButton("Door Parts")
+	doorPartsBtn.SetPosition(col1+280, row)
+	doorPartsBtn.SetSize(40, 18)
+	doorPartsBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		parts := eshell.DoorParts()
+		if len(parts) == 0 {
+			console.Errorf("No swing doors to make parts for")
+			return
 		}
-		fmt.Printf("Will save in %s\n", fname)
+		askFilename(".dxf", func(path string) {
+			fname := strings.TrimSuffix(path, ".dxf")
+			for _, d := range parts {
+				saveText(fmt.Sprintf("%s_%s.dxf", fname, strings.Replace(d.Name, " ", "_", -1)), d.InUnits(eshell.Units).DXF())
+			}
+		})
+	})
+	mygui.Add(doorPartsBtn)
+
+	row += 25
+
+	// export G-code button, the emitted panels nested on sheets, a program per sheet for the
+	//   shell's cutting process
+	gcodeBtn := gui.NewButton("Export G-code")
+	gcodeBtn.SetPosition(col1, row)
+	gcodeBtn.SetSize(40, 18)
+	gcodeBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		askFilename(".nc", func(path string) {
+			for _, sh := range eshell.Nest() {
+				fname := sh.FileStem(strings.TrimSuffix(path, ".nc"))
+				m := eshell.MachineFor(sh.Gauge)
+				for i := range sh.Nests {
+					saveText(fmt.Sprintf("%s_sheet%d.nc", fname, i+1), sh.GCode(i, m))
+					saveText(fmt.Sprintf("%s_sheet%d.dxf", fname, i+1), sh.Sheet(i, cam.OutputDXF).DXF())
+					saveText(fmt.Sprintf("%s_sheet%d.svg", fname, i+1), sh.Sheet(i, cam.OutputSVG).SVG(sh.Units.FromMM(nestGap)))
+				}
+				fmt.Print(sh.Report(m))
+			}
+		})
+	})
+	mygui.Add(gcodeBtn)
+
+	// export fabrication drawings button: the emitted panels each fitted to an A4 page, at full
+	//   size tiled over A1 sheets, and the nested sheets each fitted to an A4 page
+	pdfBtn := gui.NewButton("Export PDF")
+	pdfBtn.SetPosition(col1+110, row)
+	pdfBtn.SetSize(40, 18)
+	pdfBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		askFilename(".pdf", func(path string) {
+			fname := strings.TrimSuffix(path, ".pdf")
+			fit, full, sheets := cam.NewPlotter(cam.SheetA4), cam.NewPlotter(cam.SheetA1), cam.NewPlotter(cam.SheetA4)
+			full.Scale = 1
+			for _, p := range eshell.Emitted() {
+				fit.Add(p.CutPattern())
+				full.Add(p.CutPattern())
+			}
+			for _, sh := range eshell.Nest() {
+				for i := range sh.Nests {
+					sheets.Add(sh.Sheet(i, cam.OutputPDF))
+				}
+			}
+			for f, p := range map[string]*cam.Plotter{fname + ".pdf": fit, fname + "_1to1.pdf": full, fname + "_sheets.pdf": sheets} {
+				if err := p.WritePDF(f); err != nil {
+					console.Errorf("%s", err)
+					continue
+				}
+				fmt.Printf("Wrote %d pages to %s\n", len(p.Pages()), f)
+			}
+		})
+	})
+	mygui.Add(pdfBtn)
+
+	tabsCheck := gui.NewCheckBox("Tabs")
+	tabsCheck.SetPosition(col1+200, row)
+	tabsCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		eshell.Tabs = cam.Tabs{}
+		if tabsCheck.Value() {
+			eshell.Tabs = cam.DefaultTabs
+		}
+	})
+	mygui.Add(tabsCheck)
 
-		f, err := os.Create(fname)
+	commonCheck := gui.NewCheckBox("Common line")
+	commonCheck.SetPosition(col1+250, row)
+	commonCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		eshell.CommonLine = commonCheck.Value()
+	})
+	mygui.Add(commonCheck)
+
+	// price button: nests the emitted panels and prices the sheets, machine time and
+	//   consumables, shown beside the stats
+	costs := gui.NewLabel("")
+	costs.SetFont(statsFont)
+	mygui.Add(costs)
+	priceBtn := gui.NewButton("Price")
+	priceBtn.SetPosition(col1+360, row)
+	priceBtn.SetSize(40, 18)
+	priceBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		costs.SetText(eshell.CostReport().String())
+	})
+	mygui.Add(priceBtn)
+
+	// export cost report button
+	costBtn := gui.NewButton("Export Cost")
+	costBtn.SetPosition(col1+410, row)
+	costBtn.SetSize(40, 18)
+	costBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		c := eshell.CostReport()
+		costs.SetText(c.String())
+		s, err := c.CSV()
 		if err != nil {
-			fmt.Printf("Error creating %s: %s\n", fname, err.Error())
+			console.Errorf("%s", err)
 			return
 		}
-		defer f.Close()
+		askFilename(".csv", func(fname string) {
+			saveText(strings.TrimSuffix(fname, ".csv")+"_cost.csv", s)
+		})
+	})
+	mygui.Add(costBtn)
+
+	// registration marks, sheet names and grain arrows on the nested sheets, as usual for each output
+	marksCheck := gui.NewCheckBox("Marks")
+	marksCheck.SetPosition(col1+500, row)
+	marksCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		eshell.SheetMarks = nil
+		if marksCheck.Value() {
+			eshell.SheetMarks = cam.DefaultSheetMarks
+		}
+	})
+	mygui.Add(marksCheck)
 
-		w := bufio.NewWriter(f)
-		n, err := w.WriteString(eshell.STLString())
+	// units the CAM files are written in
+	unitsDD := gui.NewDropDown(40, gui.NewImageLabel(cam.Millimetre.String()))
+	for _, u := range cam.Units {
+		unitsDD.Add(gui.NewImageLabel(u.String()))
+	}
+	unitsDD.SelectPos(0)
+	unitsDD.SetPosition(col1+560, row)
+	unitsDD.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		eshell.Units = cam.Units[unitsDD.SelectedPos()]
+	})
+	mygui.Add(unitsDD)
+
+	// Design scripts: run one, driving the GUI a line a frame, or save one which makes the
+	//   shell as it is
+	var script *Script
+	runScript := func(fname string) {
+		cmds, err := LoadScript(fname)
 		if err != nil {
-			fmt.Printf("Error writing %s: %s\n", fname, err.Error())
+			console.Errorf("Script %s: %s", fname, err)
 			return
 		}
-		w.Flush()
-		fmt.Printf("Wrote %d bytes to %s\n", n, fname)
+		console.Infof("Running %s, %d commands", fname, len(cmds))
+		script = &Script{Commands: cmds, Shell: &eshell, Root: mygui, Inputs: scriptInputs, Redraw: func() {
+			refreshDoorList()
+			redrawFunc() // in case a door was cut
+			redrawDoors()
+		}}
+	}
+	row += 25
+	runScriptBtn := gui.NewButton("Run Script")
+	runScriptBtn.SetPosition(col1, row)
+	runScriptBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		openFilename(scriptExt, runScript)
+	})
+	mygui.Add(runScriptBtn)
+	saveScriptBtn := gui.NewButton("Save Script")
+	saveScriptBtn.SetPosition(col1+90, row)
+	saveScriptBtn.Subscribe(gui.OnClick, func(name string, ev interface{}) {
+		askFilename(scriptExt, func(fname string) {
+			saveText(fname, eshell.ScriptFor(scriptInputs))
+		})
+	})
+	mygui.Add(saveScriptBtn)
+
+	// units the GUI and stats show, the length inputs converted to them
+	displayLabel := gui.NewLabel("Units")
+	displayLabel.SetPosition(col1, displayRow)
+	mygui.Add(displayLabel)
+	displayDD := gui.NewDropDown(70, gui.NewImageLabel(eshell.Display.String()))
+	for _, u := range AllDisplayUnits {
+		displayDD.Add(gui.NewImageLabel(u.String()))
+	}
+	displayDD.SelectPos(int(eshell.Display))
+	displayDD.SetPosition(col2, displayRow)
+	displayDD.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		was, u := eshell.Display, AllDisplayUnits[displayDD.SelectedPos()]
+		for _, li := range lengthInputs {
+			li.edit.SetText(u.Input(was.ToM(floatIn(li.edit, 0))))
+			li.unit.SetText(strings.TrimSpace(u.LengthUnit() + " " + li.suffix))
+		}
+		snapGridInput.SetText(u.Input(was.ToM(floatIn(snapGridInput, 0))))
+		snapGridUnit.SetText(u.LengthUnit())
+		eshell.Display = u
+		redrawFunc()
+		redrawMeasures()
+	})
+	mygui.Add(displayDD)
+
+	row += 40
 
+	// Errors and warnings, shown over the stats, and whenever there is a new error
+	consolePanel := NewConsolePanel(mygui, col1, mygui.Height()-consoleHeight-10)
+	consolePanel.Panel.SetVisible(false)
+	consoleCheck := gui.NewCheckBox("Console")
+	consoleCheck.SetPosition(col1, row)
+	consoleCheck.Subscribe(gui.OnChange, func(name string, ev interface{}) {
+		consolePanel.Panel.SetVisible(consoleCheck.Value())
 	})
-	mygui.Add(stlBtn)
+	mygui.Add(consoleCheck)
+	row += 25
+
+	stats.SetPosition(col1, row) // below all the controls
+	costs.SetPosition(col1+400, row)
 
+	fileDialog = NewFileDialog(mygui) // last, so it is over all the controls
 	scene.Add(mygui)
 
 	// ███████╗ ██████╗███████╗███╗   ██╗███████╗
@@ -504,7 +1988,45 @@ func main() {
 	camA.LookAt(&orig, &zaxis)
 
 	// Set up orbit control for the camera
-	camera.NewOrbitControl(camA)
+	orbit := camera.NewOrbitControl(camA)
+
+	setView = func(v View) {
+		eye, up := v.Pose(eshell.Size())
+		camA.SetPosition(eye.X, eye.Y, eye.Z)
+		camA.LookAt(&orig, &up)
+		camA.SetSize(OrthoSize(eshell.Size()))
+	}
+	setOrtho = func(ortho bool) {
+		camA.SetSize(OrthoSize(eshell.Size()))
+		if ortho {
+			camA.SetProjection(camera.Orthographic)
+		} else {
+			camA.SetProjection(camera.Perspective)
+		}
+		if orthoCheck.Value() != ortho { // the key, not the box
+			orthoCheck.SetValue(ortho)
+		}
+	}
+
+	// Walking: the camera at eye height inside the shell, moved by the keys, not orbiting
+	walkTo := func() {
+		eye, target := walker.Pose()
+		camA.SetPosition(eye.X, eye.Y, eye.Z)
+		camA.LookAt(&target, &zaxis)
+		measureInfo.SetText(walker.Info(&eshell))
+	}
+	setWalk = func(walk bool) {
+		orbit.Enabled = !walk
+		if !walk {
+			walker = nil
+			measureInfo.SetText("")
+			setView(ViewIso)
+			return
+		}
+		setOrtho(false)
+		walker = eshell.NewWalker()
+		walkTo()
+	}
 
 	// Scene setup
 	onResize := func(evname string, ev interface{}) {
@@ -517,20 +2039,15 @@ func main() {
 
 	rc := collision.NewRaycaster(&math32.Vector3{}, &math32.Vector3{})
 
-	onMouseDown := func(evname string, ev interface{}) {
-
-		mev := ev.(*window.MouseEvent)
-		if mev.Button != 1 {
-			return
-		}
-
+	// mouseRay finds the ray under the mouse in shell coords
+	mouseRay := func(x, y float32) v3.Line {
 		matrixWorld := (*shellmesh).MatrixWorld()
 		var inverseMatrix math32.Matrix4
 		inverseMatrix.GetInverse(&matrixWorld)
 
 		width, height := a.GetSize()
-		rcx := 2*(mev.Xpos/float32(width)) - 1
-		rcy := -2*(mev.Ypos/float32(height)) + 1
+		rcx := 2*(x/float32(width)) - 1
+		rcy := -2*(y/float32(height)) + 1
 		rc.SetFromCamera(camA, rcx, rcy)
 
 		var ray math32.Ray
@@ -538,22 +2055,170 @@ func main() {
 
 		rayOn := v3.NewSimVec(float64(ray.Origin().X), float64(ray.Origin().Z), float64(ray.Origin().Y))
 		rayDir := v3.NewSimVec(float64(ray.Direction().X), float64(ray.Direction().Z), float64(ray.Direction().Y))
+		return v3.NewLine(rayOn, rayDir)
+	}
 
-		seg := v3.NewSegment(v3.NewLine(rayOn, rayDir), 0.0, 50.0)
+	// Door and block dragging: plain drag moves round the ring, or over the floor, shift-drag
+	//   rotates
+	var dragging *Door
+	var draggingBlock *Block
+	var dragPlane v3.Plane // horizontal, through the bottom of the door or block being dragged
+	var dragRotate bool
+	var dragX float32
+	var dragSnap bool                 // snapping this drag
+	var dragFrom, dragTurn v3.Radians // heading when the drag started, and turn since
+	startDrag := func(mev *window.MouseEvent, at v3.Vec, heading v3.Radians) {
+		dragPlane = v3.NewPlane(at, v3.Z)
+		dragRotate = mev.Mods&window.ModShift != 0
+		dragX = mev.Xpos
+		dragFrom, dragTurn = heading, 0
+		snap.Grid = lengthIn(snapGridInput, snap.Grid)
+		snap.Angle = v3.Degrees(floatIn(snapAngleInput, float64(snap.Angle)))
+		dragSnap = snap.Active(mev.Mods&window.ModControl != 0)
+		orbit.Enabled = false
+	}
+	// dragHeading is where a shift-drag has turned to
+	dragHeading := func(cev *window.CursorEvent) v3.Radians {
+		dragTurn += v3.Radians(float64(cev.Xpos-dragX) * 0.01)
+		dragX = cev.Xpos
+		if dragSnap {
+			return snap.Heading(dragFrom + dragTurn)
+		}
+		return dragFrom + dragTurn
+	}
+	// dragPoint is where a drag has got to on the floor
+	dragPoint := func(cev *window.CursorEvent) (v3.Vec, bool) {
+		at, hit := dragPlane.IntersectLine(mouseRay(cev.Xpos, cev.Ypos))
+		if hit && dragSnap {
+			at = snap.Point(at)
+		}
+		return at, hit
+	}
+
+	onMouseDown := func(evname string, ev interface{}) {
+		if job != nil { // the shell is being changed in the background
+			return
+		}
+
+		mev := ev.(*window.MouseEvent)
+		if mev.Button != 1 {
+			return
+		}
+
+		seg := v3.NewSegment(mouseRay(mev.Xpos, mev.Ypos), 0.0, 50.0)
 		showSegs = append(showSegs, seg)
 
-		hitPanels, wheres := eshell.IntersectsPanels(seg)
+		if d := eshell.DoorAt(seg); d != nil {
+			dragging = d
+			startDrag(mev, d.Center(), d.Heading())
+			if d != selDoor {
+				selDoor = d
+				refreshDoorList()
+			}
+			redrawDoors()
+			return
+		}
 
-		if len(hitPanels) > 0 {
-			fmt.Printf("Hits: %d (%d)\n", len(hitPanels), len(wheres))
-		} else {
-			fmt.Println("MISSED!")
+		if b := eshell.BlockAt(mouseRay(mev.Xpos, mev.Ypos)); b != nil {
+			draggingBlock = b
+			startDrag(mev, b.At, b.Heading)
+			selBlock = b
+			redrawBlocks()
+			return
+		}
+
+		if measuring {
+			if mp, ok := eshell.SnapMeasure(seg.Ray(), seg.MaxD-seg.MinD); ok {
+				if measureFrom == nil {
+					measureFrom = &mp
+				} else {
+					m := eshell.Measure(*measureFrom, mp)
+					measurements = append(measurements, m)
+					measureFrom = nil
+					fmt.Printf("Measurement %d: %s\n", len(measurements), m.Text(eshell.Display))
+				}
+				redrawMeasures()
+			}
+			return
+		}
+
+		if pickEdges {
+			if ed, ok := eshell.PickEdge(seg.Ray(), seg.MaxD-seg.MinD); ok {
+				t := eshell.CycleTreatment(ed, mev.Mods&window.ModShift != 0)
+				fmt.Printf("Edge %d: %s\n", ed.Serial, t)
+				for _, p := range alivePanels(ed.Panels) {
+					if h, err := p.HemProfile(ed); err == nil {
+						fmt.Printf("   Panel %s: %s\n", PanelLabel(p), strings.Join(h.Sequence(), ", "))
+					}
+				}
+				redrawFunc()
+			}
+			return
 		}
 
+		// Select the nearest panel hit, shift-click to add it or take it out, click off the shell
+		//   to select nothing; ctrl-click selects an edge and alt-click a vertex instead
+		shift := mev.Mods&window.ModShift != 0
+		if mev.Mods&window.ModControl != 0 {
+			if ed, ok := eshell.PickEdge(seg.Ray(), seg.MaxD-seg.MinD); ok {
+				selection.SetEdge(ed)
+				redrawSelection()
+			}
+			return
+		}
+		if mev.Mods&window.ModAlt != 0 {
+			if v, ok := eshell.PickVertex(seg.Ray(), seg.MaxD-seg.MinD); ok {
+				selection.SetVertex(v)
+				redrawSelection()
+			}
+			return
+		}
+		if h, ok := eshell.PickPanel(seg.Ray(), seg.MaxD-seg.MinD); ok {
+			if shift {
+				selection.Toggle(h.Panel)
+			} else {
+				selection.Set(h.Panel)
+			}
+		} else if !shift {
+			selection.Clear()
+		}
+		redrawSelection()
+
 	}
 
 	a.Subscribe(window.OnMouseDown, onMouseDown)
 
+	onCursor := func(evname string, ev interface{}) {
+		cev := ev.(*window.CursorEvent)
+		if draggingBlock != nil {
+			if dragRotate {
+				draggingBlock.Heading = dragHeading(cev)
+			} else if at, hit := dragPoint(cev); hit {
+				draggingBlock.At = at
+			}
+			redrawBlocks()
+			return
+		}
+		if dragging == nil {
+			return
+		}
+		if dragRotate {
+			dragging.Face(dragHeading(cev))
+		} else if at, hit := dragPoint(cev); hit {
+			dragging.DragTo(at)
+		}
+		redrawDoors()
+	}
+	a.Subscribe(window.OnCursor, onCursor)
+
+	onMouseUp := func(evname string, ev interface{}) {
+		if dragging != nil || draggingBlock != nil {
+			dragging, draggingBlock = nil, nil
+			orbit.Enabled = true
+		}
+	}
+	a.Subscribe(window.OnMouseUp, onMouseUp)
+
 	onKey := func(evname string, ev interface{}) {
 		// var state bool
 		// if evname == window.OnKeyDown {
@@ -562,32 +2227,77 @@ func main() {
 		// 	state = false
 		// }
 		kev := ev.(*window.KeyEvent)
+		if job != nil { // the shell is being changed in the background
+			return
+		}
 
-		if (kev.Key == window.KeyW) || (kev.Key == window.KeyA) || (kev.Key == window.KeyS) || (kev.Key == window.KeyD) || (kev.Key == window.KeyQ) || (kev.Key == window.KeyE) {
+		// standard views, and orthographic or not
+		for i, k := range []window.Key{window.Key1, window.Key2, window.Key3, window.Key4} {
+			if kev.Key == k {
+				setView(Views[i])
+				return
+			}
+		}
+		if kev.Key == window.Key5 {
+			setOrtho(!orthoCheck.Value())
+			return
+		}
 
-			scene.Remove(door)
+		if walker != nil {
+			switch kev.Key {
+			case window.KeyW:
+				walker.Step(&eshell, walkStep, 0)
+			case window.KeyS:
+				walker.Step(&eshell, -walkStep, 0)
+			case window.KeyA:
+				walker.Step(&eshell, 0, walkStep)
+			case window.KeyD:
+				walker.Step(&eshell, 0, -walkStep)
+			case window.KeyQ:
+				walker.Turn(walkTurn)
+			case window.KeyE:
+				walker.Turn(-walkTurn)
+			default:
+				return
+			}
+			walkTo()
+			return
+		}
+
+		if (kev.Key == window.KeyW) || (kev.Key == window.KeyA) || (kev.Key == window.KeyS) || (kev.Key == window.KeyD) || (kev.Key == window.KeyQ) || (kev.Key == window.KeyE) || (kev.Key == window.KeyR) || (kev.Key == window.KeyO) || (kev.Key == window.KeyT) || (kev.Key == window.KeyG) || (kev.Key == window.KeyZ) || (kev.Key == window.KeyX) {
+
+			if selDoor == nil {
+				return
+			}
 
 			switch kev.Key {
 			case window.KeyW:
-				doorA.Translate(doorA.Normal.Scale(0.1))
+				selDoor.Translate(selDoor.facing().Scale(0.1))
 			case window.KeyS:
-				doorA.Translate(doorA.Normal.Scale(-0.1))
+				selDoor.Translate(selDoor.facing().Scale(-0.1))
 			case window.KeyD:
-				doorA.Translate(doorA.Wide.Normalized().Scale(-0.1))
+				selDoor.Translate(selDoor.Wide.Normalized().Scale(-0.1))
 			case window.KeyA:
-				doorA.Translate(doorA.Wide.Normalized().Scale(0.1))
+				selDoor.Translate(selDoor.Wide.Normalized().Scale(0.1))
 			case window.KeyE:
-				doorA.RotateZ(v3.Deg2Rad(2.5))
+				selDoor.RotateZ(v3.Deg2Rad(2.5))
 			case window.KeyQ:
-				doorA.RotateZ(v3.Deg2Rad(-2.5))
+				selDoor.RotateZ(v3.Deg2Rad(-2.5))
+			case window.KeyR: // next kind of door
+				selDoor.Kind = (selDoor.Kind + 1) % (DoubleSwing + 1)
+			case window.KeyO: // next way of opening
+				selDoor.Opens = (selDoor.Opens + 1) % (Top + 1)
+			case window.KeyT: // lean the top in
+				selDoor.SetTilt(selDoor.Tilt + v3.Deg2Rad(2.5))
+			case window.KeyG:
+				selDoor.SetTilt(selDoor.Tilt - v3.Deg2Rad(2.5))
+			case window.KeyX: // cut deeper
+				selDoor.SetDepth(selDoor.depthAt(selDoor.Corner) + 0.1)
+			case window.KeyZ:
+				selDoor.SetDepth(selDoor.depthAt(selDoor.Corner) - 0.1)
 			}
 
-			//			doorA = NewDoor(&eshell, doorWidth, doorHeight)
-			door = gl.NewLineSet(doorA.Display(&eshell), 3)
-
-			// doorLines = gl.LinesForPatch(doorPatch, true, doorColour)
-			// door = gl.NewLineSet(doorLines, 3)
-			scene.Add(door)
+			redrawDoors()
 
 		}
 
@@ -604,13 +2314,56 @@ func main() {
 	stats.SetText(eshell.Stats(cam.Materials))
 
 	// Compute the meshes etc.
+	eshell.MakeMesh(desiredL, tolerance)
 	setupFunc()
+	if len(os.Args) > 1 { // shelly <script> runs it once the GUI is up
+		runScript(os.Args[1])
+	}
 
 	fmt.Printf("Panels: %d,  Edges: %d,  Vertices: %d\n", len(eshell.Panels), len(eshell.Edges), len(eshell.Vertices))
 
 	// Run the application
 	a.Run(func(renderer *renderer.Renderer, deltaTime time.Duration) {
 		a.Gls().Clear(gls.DEPTH_BUFFER_BIT | gls.STENCIL_BUFFER_BIT | gls.COLOR_BUFFER_BIT)
+		if !regenDue.IsZero() && time.Now().After(regenDue) {
+			regenFunc("", nil)
+		}
+		select { // the job in the background finished
+		case f := <-jobDone:
+			job = nil
+			f()
+		default:
+		}
+		showJob()
+		if script != nil && job == nil { // the next line of the script, once the last has finished
+			if err := script.Step(); err != nil {
+				console.Errorf("Script: %s", err)
+				script = nil
+			} else if script.Done() {
+				console.Infof("Script finished")
+				script = nil
+			}
+		}
+		if consolePanel.Update() && !consoleCheck.Value() {
+			consoleCheck.SetValue(true)
+		}
+		if relaxing && job == nil { // an iteration of the solver each frame, until it's done
+			if relaxOnce() {
+				relaxCheck.SetValue(false)
+			} else {
+				redrawMesh()
+			}
+		}
+		if playing { // the next step of the assembly, when it's time
+			playTime += deltaTime.Seconds()
+			if playTime >= assemblyStepTime {
+				playTime = 0
+				if assemblyN < len(assembly.Steps) {
+					assemblyN++
+					redrawAssembly()
+				}
+			}
+		}
 		renderer.Render(scene, camA)
 	})
 
@@ -623,6 +2376,54 @@ func main() {
 // ╚██████╔╝   ██║   ██║███████╗███████║
 //  ╚═════╝    ╚═╝   ╚═╝╚══════╝╚══════╝
 
+// askFilename asks in the file dialog for a file to save to, then calls then with its name,
+//   with the extension added if it is missing; a script running gives the name instead
+func askFilename(ext string, then func(fname string)) {
+	if scriptFilename != "" {
+		fname := scriptFilename
+		scriptFilename = ""
+		if !strings.HasSuffix(fname, ext) {
+			fname += ext
+		}
+		then(fname)
+		return
+	}
+	fileDialog.Save(ext, then)
+}
+
+// openFilename asks in the file dialog for a file to read, then calls then with its name, or
+//   with the one a script running gives
+func openFilename(ext string, then func(fname string)) {
+	if scriptFilename != "" {
+		fname := scriptFilename
+		scriptFilename = ""
+		then(fname)
+		return
+	}
+	fileDialog.Open(ext, then)
+}
+
+// saveText writes a string to a file, reporting how it went on the console
+func saveText(fname string, s string) {
+	fmt.Printf("Will save in %s\n", fname)
+
+	f, err := os.Create(fname)
+	if err != nil {
+		console.Errorf("Creating %s: %s", fname, err)
+		return
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	n, err := w.WriteString(s)
+	if err != nil {
+		console.Errorf("Writing %s: %s", fname, err)
+		return
+	}
+	w.Flush()
+	fmt.Printf("Wrote %d bytes to %s\n", n, fname)
+}
+
 // loadRGBA loads an image from a filesystem
 func loadRGBA(name string, fs http.FileSystem) (rgba *image.RGBA, err error) {
 	imfile, err := fs.Open(name)
@@ -692,7 +2493,7 @@ func floatIn(ed *gui.Edit, old float64) float64 {
 	s := strings.TrimSpace(ed.Text())
 	f, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		fmt.Printf("Float conversion error text %s %s\n", s, err)
+		console.Warnf("Could not read %q as a number, keeping %g: %s", s, old, err)
 		return old
 	}
 	//	fmt.Printf("floatin old %4.1f text %s new %4.1f\n", old, s, f)
@@ -0,0 +1,225 @@
+package main
+
+// ███████╗██╗  ██╗██╗   ██╗██╗     ██╗ ██████╗ ██╗  ██╗████████╗
+// ██╔════╝██║ ██╔╝╚██╗ ██╔╝██║     ██║██╔════╝ ██║  ██║╚══██╔══╝
+// ███████╗█████╔╝  ╚████╔╝ ██║     ██║██║  ███╗███████║   ██║
+// ╚════██║██╔═██╗   ╚██╔╝  ██║     ██║██║   ██║██╔══██║   ██║
+// ███████║██║  ██╗   ██║   ███████╗██║╚██████╔╝██║  ██║   ██║
+// ╚══════╝╚═╝  ╚═╝   ╚═╝   ╚══════╝╚═╝ ╚═════╝ ╚═╝  ╚═╝   ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	gl "./gl"
+	v3 "./vec"
+)
+
+// Sizes for skylights
+const (
+	skylightRadius    = 0.5   // m, default distance from the zenith to the corners of the opening
+	skylightSides     = 6     // default number of sides of the opening
+	skylightBoltInset = 0.025 // m, bolt holes up from the bottom of the curb
+	skylightCutMargin = 0.5   // m, how far the cutting walls extend beyond the shell
+	skylightMinFold   = 0.01  // radians, corners of the ring turning less than this are not folded
+)
+
+// Skylight is a polygonal opening centred on the zenith with a curb round it
+type Skylight struct {
+	Sides   int       // number of sides of the opening polygon
+	Radius  float64   // m, from the zenith axis to the corners of the polygon
+	Opening []*Edge   // edges round the hole
+	Ring    []*Vertex // vertices round the hole, in order
+	Curb    []*Flange // one flange per opening edge, standing up vertically
+}
+
+// AddSkylight cuts a polygonal opening of n sides with corners r from the zenith axis,
+//   drops the panels inside and flanges the edges round the hole with a curb
+func (e *EShell) AddSkylight(r float64, n int) (*Skylight, error) {
+
+	if e.Skylight != nil {
+		return nil, fmt.Errorf("Shell already has a skylight")
+	}
+	if n < 3 {
+		return nil, fmt.Errorf("Skylight needs at least 3 sides, not %d", n)
+	}
+	zLow := math.Min(e.E.ZGivenXY(r, 0), e.E.ZGivenXY(0, r))
+	if math.IsNaN(zLow) || zLow-skylightCutMargin < e.Base {
+		return nil, fmt.Errorf("Skylight of radius %.2fm is too big for the shell", r)
+	}
+	zLow -= skylightCutMargin
+	height := e.E.H + skylightCutMargin - zLow
+
+	// The corners of the polygon and the inwards normals of its sides
+	corners := []v3.Vec{}
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		corners = append(corners, v3.NewSimVec(r*math.Cos(a), r*math.Sin(a), zLow))
+	}
+	inwards := []v3.Vec{}
+	var cutEdges []*Edge
+	for i, c := range corners {
+		along := corners[(i+1)%n].Subtract(c)
+		in := v3.Z.Cross(along).Normalized()
+		inwards = append(inwards, in)
+		cutEdges = append(cutEdges, e.CutWithPatch(v3.NewPatch(c, in, along, v3.Z.Scale(height)))...)
+	}
+
+	inside := func(p v3.Vec) bool {
+		if p.Z() < zLow {
+			return false
+		}
+		for i, c := range corners {
+			if p.Subtract(c).Dot(inwards[i]) < 0 {
+				return false
+			}
+		}
+		return true
+	}
+	for _, p := range e.Panels {
+		if p.Alive {
+			p.Update(e)
+			if inside(p.Center) {
+				e.RemovePanel(p)
+			}
+		}
+	}
+	e.Undertaker()
+
+	s := Skylight{Sides: n, Radius: r}
+	for _, ed := range cutEdges {
+		if ed.Alive && ed.IsBoundary() {
+			s.Opening = append(s.Opening, ed)
+		}
+	}
+	if len(s.Opening) == 0 {
+		return nil, fmt.Errorf("Skylight cut did not open a hole")
+	}
+	s.Ring = edgeLoop(s.Opening)
+
+	for _, ed := range s.Opening {
+		ed.Treatment = ETreatFlange
+		f := Flange{Edge: ed, Style: FStyleSkylightCurbMk1}
+		s.Curb = append(s.Curb, f.Shape(v3.Z))
+		e.Flanges = append(e.Flanges, &f)
+		e.Cuts = append(e.Cuts, CutSegment{start: ed.Vertices[0].Position, end: ed.Vertices[1].Position})
+	}
+
+	e.Skylight = &s
+	e.afterCut()
+	return &s, nil
+}
+
+// edgeLoop orders the vertices of a closed loop of edges, following the edges from the first
+func edgeLoop(edges []*Edge) []*Vertex {
+	if len(edges) == 0 {
+		return nil
+	}
+	next := map[*Vertex][]*Vertex{}
+	for _, ed := range edges {
+		v0, v1 := ed.Vertices[0], ed.Vertices[1]
+		next[v0] = append(next[v0], v1)
+		next[v1] = append(next[v1], v0)
+	}
+	loop := []*Vertex{edges[0].Vertices[0]}
+	seen := map[*Vertex]bool{loop[0]: true}
+	for {
+		here := loop[len(loop)-1]
+		var step *Vertex
+		for _, v := range next[here] {
+			if !seen[v] {
+				step = v
+				break
+			}
+		}
+		if step == nil {
+			break
+		}
+		seen[step] = true
+		loop = append(loop, step)
+	}
+	if len(loop) != len(edges) {
+		console.Errorf("Loop of %d edges is not simple, only followed %d vertices", len(edges), len(loop))
+	}
+	return loop
+}
+
+// curbBend is the fold in the curb at b, coming from a and going on to c. The strip is drawn
+//   looking at it from the right of the way round it goes, so turning left folds it down.
+func curbBend(a, b, c *Vertex) cam.Bend {
+	in, out := b.Position.Subtract(a.Position), c.Position.Subtract(b.Position)
+	turn := math.Atan2(in.X()*out.Y()-in.Y()*out.X(), in.X()*out.X()+in.Y()*out.Y())
+	return cam.Bend{Angle: math.Abs(turn), Up: turn < 0}
+}
+
+// CurbDrawing unrolls the curb into a flat strip, bottom following the actual heights of the
+//   ring, with folds at each real corner and bolt holes along the bottom
+func (s *Skylight) CurbDrawing() cam.Drawing {
+	d := cam.Drawing{Name: fmt.Sprintf("Skylight curb, %d sides", s.Sides)}
+	if len(s.Ring) < 3 {
+		return d
+	}
+	ring := append(append([]*Vertex{}, s.Ring...), s.Ring[0]) // closed, the strip is joined at the first vertex
+	zMin := math.Inf(1)
+	for _, v := range ring {
+		zMin = math.Min(zMin, v.Position.Z())
+	}
+
+	spec := FlangeSpecs[FStyleSkylightCurbMk1]
+	var bottom, top []cam.Vec2
+	x := 0.0
+	for i, v := range ring {
+		if i > 0 {
+			dv := v.Position.Subtract(ring[i-1].Position)
+			x += math.Hypot(dv.X(), dv.Y())
+		}
+		y := v.Position.Z() - zMin
+		bottom = append(bottom, cam.NewVec2(x*m2mm, y*m2mm))
+		top = append(top, cam.NewVec2(x*m2mm, (y+spec.Depth)*m2mm))
+	}
+
+	outline := append([]cam.Vec2{}, bottom...)
+	for i := len(top) - 1; i >= 0; i-- {
+		outline = append(outline, top[i])
+	}
+	d.Paths = append(d.Paths, cam.NewPolygonPath(outline, cam.EdgePath))
+
+	for i := 1; i < len(bottom)-1; i++ {
+		if b := curbBend(ring[i-1], ring[i], ring[i+1]); b.Angle > skylightMinFold { // not where a side was split
+			d.Paths = append(d.Paths, cam.NewFoldPath(bottom[i], top[i], b))
+		}
+	}
+
+	for i := 1; i < len(bottom); i++ {
+		run := bottom[i].Subtract(bottom[i-1])
+		steps := int(math.Ceil(run.Length() / (spec.HolePitch * m2mm)))
+		for j := 0; j < steps; j++ {
+			at := bottom[i-1].Add(run.Scale((float64(j) + 0.5) / float64(steps)))
+			at = at.Add(cam.NewVec2(0, skylightBoltInset*m2mm))
+			d.Paths = append(d.Paths, cam.NewCirclePath(at, spec.HoleDia*m2mm/2, cam.EdgePath))
+		}
+	}
+	return d
+}
+
+// Display generates the lines to show the skylight curb
+func (s *Skylight) Display() []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	for _, f := range s.Curb {
+		for i := range f.Corners {
+			ls = append(ls, gl.ColourLine{Start: f.Corners[i], End: f.Corners[(i+1)%len(f.Corners)], Colour: &gl.Aqua})
+		}
+	}
+	return ls
+}
+
+// String describes the skylight
+func (s *Skylight) String() string {
+	perim := 0.0
+	for _, ed := range s.Opening {
+		perim += ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position).Length()
+	}
+	return fmt.Sprintf("Skylight: %d sides, %4.2fm radius, %d edges, curb %4.2fm long\n",
+		s.Sides, s.Radius, len(s.Opening), perim)
+}
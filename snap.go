@@ -0,0 +1,47 @@
+package main
+
+// ███████╗███╗   ██╗ █████╗ ██████╗
+// ██╔════╝████╗  ██║██╔══██╗██╔══██╗
+// ███████╗██╔██╗ ██║███████║██████╔╝
+// ╚════██║██║╚██╗██║██╔══██║██╔═══╝
+// ███████║██║ ╚████║██║  ██║██║
+// ╚══════╝╚═╝  ╚═══╝╚═╝  ╚═╝╚═╝
+
+import (
+	"math"
+
+	v3 "./vec"
+)
+
+// Snap rounds where things dragged about the floor are put, and which way they face, so the
+//   same placement can be made again
+type Snap struct {
+	On    bool       // snapping unless the modifier key is held, and not if it is
+	Grid  float64    // m, on the floor
+	Angle v3.Degrees // of headings
+}
+
+// DefaultSnap is to 100mm and 5°
+var DefaultSnap = Snap{On: true, Grid: 0.1, Angle: 5}
+
+// Active is whether to snap, with or without the modifier key held
+func (s Snap) Active(modifier bool) bool {
+	return s.On != modifier
+}
+
+// Point is p with X and Y rounded to the grid, Z as it was
+func (s Snap) Point(p v3.Vec) v3.Vec {
+	if s.Grid <= 0 {
+		return p
+	}
+	return v3.NewSimVec(math.Round(p.X()/s.Grid)*s.Grid, math.Round(p.Y()/s.Grid)*s.Grid, p.Z())
+}
+
+// Heading is a rounded to the angle
+func (s Snap) Heading(a v3.Radians) v3.Radians {
+	step := v3.Deg2Rad(s.Angle)
+	if step <= 0 {
+		return a
+	}
+	return v3.Radians(math.Round(float64(a/step))) * step
+}
@@ -0,0 +1,156 @@
+package main
+
+// ███████╗██╗   ██╗██████╗ ██████╗ ██╗██╗   ██╗██╗██████╗ ███████╗
+// ██╔════╝██║   ██║██╔══██╗██╔══██╗██║██║   ██║██║██╔══██╗██╔════╝
+// ███████╗██║   ██║██████╔╝██║  ██║██║██║   ██║██║██║  ██║█████╗
+// ╚════██║██║   ██║██╔══██╗██║  ██║██║╚██╗ ██╔╝██║██║  ██║██╔══╝
+// ███████║╚██████╔╝██████╔╝██████╔╝██║ ╚████╔╝ ██║██████╔╝███████╗
+// ╚══════╝ ╚═════╝ ╚═════╝ ╚═════╝ ╚═╝  ╚═══╝  ╚═╝╚═════╝ ╚══════╝
+
+// edgeConstraints are the constraints for a new vertex along the edge: on the base if the edge
+//   is, on the straight line of a cut if the edge is on the boundary, else on the ellipsoid
+func (e *EShell) edgeConstraints(ed *Edge) Constraints {
+	if e.onBase(ed) {
+		return ed.Vertices[0].Constraints
+	}
+	if ed.IsBoundary() {
+		return Constraints{}
+	}
+	return Constraints{OnEllipsoid}
+}
+
+// edgeFinder finds or makes the edge between two vertices, copying the treatment of the
+//   edge it is part of, if any
+type edgeFinder map[[2]*Vertex]*Edge
+
+// get gives the edge between a and b, adding it to the shell if need be
+func (ef edgeFinder) get(e *EShell, a, b *Vertex, from *Edge) *Edge {
+	if a.Serial > b.Serial {
+		a, b = b, a
+	}
+	if ed, ok := ef[[2]*Vertex{a, b}]; ok {
+		return ed
+	}
+	ed := e.AddEdge([]*Vertex{a, b})
+	if from != nil {
+		ed.Treatment, ed.HemSize = from.Treatment, from.HemSize
+	}
+	ef[[2]*Vertex{a, b}] = ed
+	return ed
+}
+
+// Subdivide splits the triangular panel into n² smaller ones on a grid, the new vertices projected
+//   onto the ellipsoid. The panels next to it are split into fans to meet the new vertices on
+//   their shared edges, so the mesh stays closed. All the new panels are SubPanelOf the panel
+//   they came from. Returns the panels the panel itself was split into.
+func (p *Panel) Subdivide(n int) []*Panel {
+	e := p.Shell
+	if n < 2 || !p.Alive || len(p.Corners) != 3 {
+		return nil
+	}
+	a, b, c := p.Corners[0], p.Corners[1], p.Corners[2]
+	ef := edgeFinder{}
+
+	// The grid of vertices, (i, j) being a + (b-a)i/n + (c-a)j/n
+	grid := map[[2]int]*Vertex{{0, 0}: a, {n, 0}: b, {0, n}: c}
+	for i := 0; i <= n; i++ {
+		for j := 0; i+j <= n; j++ {
+			if _, ok := grid[[2]int{i, j}]; ok {
+				continue
+			}
+			var side *Edge
+			switch {
+			case j == 0:
+				side = p.EdgeBetween(a, b)
+			case i == 0:
+				side = p.EdgeBetween(a, c)
+			case i+j == n:
+				side = p.EdgeBetween(b, c)
+			}
+			cs := Constraints{OnEllipsoid}
+			if side != nil {
+				cs = e.edgeConstraints(side)
+			}
+			pos := a.Position.Add(b.Position.Subtract(a.Position).Scale(float64(i) / float64(n))).
+				Add(c.Position.Subtract(a.Position).Scale(float64(j) / float64(n)))
+			grid[[2]int{i, j}] = e.AddVertex(pos, cs)
+		}
+	}
+
+	// The chain of vertices along each side, from one end to the other, to split the neighbours
+	chains := map[*Edge][]*Vertex{}
+	chain := func(ed *Edge, at func(k int) [2]int) {
+		for k := 0; k <= n; k++ {
+			chains[ed] = append(chains[ed], grid[at(k)])
+		}
+	}
+	chain(p.EdgeBetween(a, b), func(k int) [2]int { return [2]int{k, 0} })
+	chain(p.EdgeBetween(a, c), func(k int) [2]int { return [2]int{0, k} })
+	chain(p.EdgeBetween(b, c), func(k int) [2]int { return [2]int{n - k, k} })
+
+	// An edge on a side of the panel copies that side's treatment
+	sideOf := func(u, v [2]int) *Edge {
+		if u[1] == 0 && v[1] == 0 {
+			return p.EdgeBetween(a, b)
+		}
+		if u[0] == 0 && v[0] == 0 {
+			return p.EdgeBetween(a, c)
+		}
+		if u[0]+u[1] == n && v[0]+v[1] == n {
+			return p.EdgeBetween(b, c)
+		}
+		return nil
+	}
+	tri := func(u, v, w [2]int) *Panel {
+		np := e.AddPanel([]*Edge{
+			ef.get(e, grid[u], grid[v], sideOf(u, v)),
+			ef.get(e, grid[v], grid[w], sideOf(v, w)),
+			ef.get(e, grid[w], grid[u], sideOf(w, u))})
+		np.SubPanelOf, np.Material, np.Gauge, np.Emit = p, p.Material, p.Gauge, p.Emit
+		return np
+	}
+
+	e.RemovePanel(p)
+	ps := []*Panel{}
+	for i := 0; i < n; i++ {
+		for j := 0; i+j < n; j++ {
+			ps = append(ps, tri([2]int{i, j}, [2]int{i + 1, j}, [2]int{i, j + 1}))
+			if i+j+1 < n {
+				ps = append(ps, tri([2]int{i + 1, j}, [2]int{i + 1, j + 1}, [2]int{i, j + 1}))
+			}
+		}
+	}
+
+	// Fan the neighbours from their far corners to the new vertices on the shared side
+	for ed, vs := range chains {
+		for _, q := range alivePanels(ed.Panels) {
+			var o *Vertex
+			for _, v := range q.Corners {
+				if !ed.HasVertex(v) {
+					o = v
+				}
+			}
+			if o == nil {
+				console.Errorf("Panel %d next to panel %d is not a triangle, not split", q.Serial, p.Serial)
+				continue
+			}
+			e.RemovePanel(q)
+			for k := 0; k < n; k++ {
+				u, v := vs[k], vs[k+1]
+				np := e.AddPanel([]*Edge{ef.get(e, u, v, ed), q.edgeTo(e, ef, v, o), q.edgeTo(e, ef, o, u)})
+				np.SubPanelOf, np.Material, np.Gauge, np.Emit = q, q.Material, q.Gauge, q.Emit
+			}
+		}
+		e.RemoveEdge(ed)
+	}
+	e.Undertaker()
+	return ps
+}
+
+// edgeTo is the panel's own edge between u and v if it has one, else one from the finder
+func (p *Panel) edgeTo(e *EShell, ef edgeFinder, u, v *Vertex) *Edge {
+	if ed := p.EdgeBetween(u, v); ed != nil {
+		return ed
+	}
+	return ef.get(e, u, v, nil)
+}
@@ -0,0 +1,251 @@
+package main
+
+// ███████╗██╗    ██╗███████╗███████╗██████╗
+// ██╔════╝██║    ██║██╔════╝██╔════╝██╔══██╗
+// ███████╗██║ █╗ ██║█████╗  █████╗  ██████╔╝
+// ╚════██║██║███╗██║██╔══╝  ██╔══╝  ██╔═══╝
+// ███████║╚███╔███╔╝███████╗███████╗██║
+// ╚══════╝ ╚══╝╚══╝ ╚══════╝╚══════╝╚═╝
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SweepRange is the values of one dimension to try: N of them evenly from From to To, just
+//   From if N is 1
+type SweepRange struct {
+	From, To float64
+	N        int
+}
+
+// Values are those to try
+func (r SweepRange) Values() []float64 {
+	if r.N <= 1 {
+		return []float64{r.From}
+	}
+	vs := make([]float64, r.N)
+	for i := range vs {
+		vs[i] = r.From + (r.To-r.From)*float64(i)/float64(r.N-1)
+	}
+	return vs
+}
+
+// ParseSweepRange reads from[:to[:n]], n being 2 if it is left out
+func ParseSweepRange(s string) (SweepRange, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return SweepRange{}, fmt.Errorf("Range %q is not from:to:n", s)
+	}
+	var fs []float64
+	for _, p := range parts[:minInt(len(parts), 2)] {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return SweepRange{}, err
+		}
+		fs = append(fs, f)
+	}
+	if len(fs) == 1 {
+		return SweepRange{From: fs[0], To: fs[0], N: 1}, nil
+	}
+	r := SweepRange{From: fs[0], To: fs[1], N: 2}
+	if len(parts) == 3 {
+		n, err := strconv.Atoi(parts[2])
+		if err != nil || n < 1 {
+			return SweepRange{}, fmt.Errorf("Range %q needs a count of at least 1", s)
+		}
+		r.N = n
+	}
+	return r, nil
+}
+
+// minInt is the smaller
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Sweep tries every combination of the widths, lengths, heights and panel sizes, the rest of
+//   the design as in Base
+type Sweep struct {
+	Base                         Design
+	Width, Length, Height, Panel SweepRange
+}
+
+// NewSweep tries just the base design, until ranges are set
+func NewSweep(base Design) Sweep {
+	one := func(v float64) SweepRange { return SweepRange{From: v, To: v, N: 1} }
+	return Sweep{Base: base, Width: one(base.Width), Length: one(base.Length), Height: one(base.Height), Panel: one(base.Panel)}
+}
+
+// Designs are every combination to try
+func (s Sweep) Designs() []Design {
+	var ds []Design
+	for _, w := range s.Width.Values() {
+		for _, l := range s.Length.Values() {
+			for _, h := range s.Height.Values() {
+				for _, p := range s.Panel.Values() {
+					d := s.Base
+					d.Width, d.Length, d.Height = w, l, h
+					if d.BasePanel == d.Panel { // not graded, so still not
+						d.BasePanel = p
+					}
+					d.Panel = p
+					ds = append(ds, d)
+				}
+			}
+		}
+	}
+	return ds
+}
+
+// SweepResult is how one design came out
+type SweepResult struct {
+	Design Design
+	Panels int
+	Area   float64 // sqm, of the alive panels
+	Sheets int     // all the stock they are nested on
+	Cost   float64 // of making them
+	Err    error   // if it could not be tessellated
+}
+
+// Run tessellates each design in turn, with the settings of like, and nests and prices it.
+//   Stops early, with what it has, if p is cancelled.
+func (s Sweep) Run(like *EShell, tolerance float64, p *Progress) []SweepResult {
+	ds := s.Designs()
+	var rs []SweepResult
+	for i, d := range ds {
+		if p.Cancelled() {
+			break
+		}
+		p.Set(fmt.Sprintf("Design %d of %d", i+1, len(ds)), float64(i)/float64(len(ds)))
+		r := SweepResult{Design: d}
+		e := d.NewShell(like, tolerance)
+		if r.Err = e.MakeMesh(d.Panel, tolerance); r.Err == nil {
+			for _, pan := range alivePanels(e.Panels) {
+				r.Panels++
+				r.Area += pan.Area
+			}
+			c := e.CostReport()
+			for _, sc := range c.Stocks {
+				r.Sheets += sc.Sheets
+			}
+			r.Cost = c.Total
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+// cheapestSweep is the result costing least, -1 if none worked
+func cheapestSweep(rs []SweepResult) int {
+	best := -1
+	for i, r := range rs {
+		if r.Err == nil && (best < 0 || r.Cost < rs[best].Cost) {
+			best = i
+		}
+	}
+	return best
+}
+
+// SweepTable compares the results, one design a line, the cheapest marked
+func SweepTable(rs []SweepResult, u DisplayUnits) string {
+	s := fmt.Sprintf("   %-8s %-8s %-8s %-8s %7s %12s %7s %10s\n", "Width", "Length", "Height", "Panel", "Panels", "Area", "Sheets", "Cost")
+	best := cheapestSweep(rs)
+	for i, r := range rs {
+		mark := "  "
+		if i == best {
+			mark = "* "
+		}
+		d := r.Design
+		s += fmt.Sprintf(" %s%-8s %-8s %-8s %-8s ", mark, u.Len(d.Width), u.Len(d.Length), u.Len(d.Height), u.Len(d.Panel))
+		if r.Err != nil {
+			s += fmt.Sprintf("ERROR: %s\n", r.Err)
+			continue
+		}
+		s += fmt.Sprintf("%7d %12s %7d %10.0f\n", r.Panels, u.Area(r.Area), r.Sheets, r.Cost)
+	}
+	if best >= 0 {
+		s += "* cheapest\n"
+	}
+	return s
+}
+
+// SweepCSV is the results, one design a row, lengths in m
+func SweepCSV(rs []SweepResult) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Width m", "Length m", "Height m", "Panel m", "Panels", "Area sqm", "Sheets", "Cost", "Error"})
+	for _, r := range rs {
+		d := r.Design
+		row := []string{fmt.Sprintf("%.3f", d.Width), fmt.Sprintf("%.3f", d.Length), fmt.Sprintf("%.3f", d.Height), fmt.Sprintf("%.3f", d.Panel)}
+		if r.Err != nil {
+			row = append(row, "", "", "", "", r.Err.Error())
+		} else {
+			row = append(row, fmt.Sprint(r.Panels), fmt.Sprintf("%.2f", r.Area), fmt.Sprint(r.Sheets), fmt.Sprintf("%.2f", r.Cost), "")
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// RunSweepArgs runs a sweep from the command line, without the GUI, eg
+//
+//   shelly -sweep width=8:10:3 panel=0.9:1.2:4 out=sweep.csv
+//
+//   Ranges of width, length, height and panel are from[:to[:n]] in m, the rest of the design
+//   as base. The table goes to stdout, and to a CSV file if out is given. Returns the exit code.
+func RunSweepArgs(args []string, base Design, like *EShell, tolerance float64) int {
+	s := NewSweep(base)
+	out := ""
+	for _, a := range args {
+		kv := strings.SplitN(a, "=", 2)
+		if len(kv) != 2 {
+			console.Errorf("Sweep argument %q is not name=value", a)
+			return 2
+		}
+		if kv[0] == "out" {
+			out = kv[1]
+			continue
+		}
+		r, err := ParseSweepRange(kv[1])
+		if err != nil {
+			console.Errorf("Sweep %s: %s", kv[0], err)
+			return 2
+		}
+		switch kv[0] {
+		case "width":
+			s.Width = r
+		case "length":
+			s.Length = r
+		case "height":
+			s.Height = r
+		case "panel":
+			s.Panel = r
+		default:
+			console.Errorf("Cannot sweep %q, only width, length, height and panel", kv[0])
+			return 2
+		}
+	}
+	console.Infof("Sweeping %d designs", len(s.Designs()))
+	rs := s.Run(like, tolerance, nil)
+	fmt.Print(SweepTable(rs, like.Display))
+	if out != "" {
+		c, err := SweepCSV(rs)
+		if err != nil {
+			console.Errorf("%s", err)
+			return 1
+		}
+		saveText(out, c)
+	}
+	if cheapestSweep(rs) < 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,259 @@
+package main
+
+// ███████╗██╗    ██╗██╗███╗   ██╗ ██████╗
+// ██╔════╝██║    ██║██║████╗  ██║██╔════╝
+// ███████╗██║ █╗ ██║██║██╔██╗ ██║██║  ███╗
+// ╚════██║██║███╗██║██║██║╚██╗██║██║   ██║
+// ███████║╚███╔███╔╝██║██║ ╚████║╚██████╔╝
+// ╚══════╝ ╚══╝╚══╝ ╚═╝╚═╝  ╚═══╝ ╚═════╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	gl "./gl"
+	v3 "./vec"
+)
+
+// Sizes for swing doors and their frames
+const (
+	swingGap         = 0.004 // m, clearance between leaf and frame or the other leaf
+	swingOpenAngle   = 90.0  // degrees a leaf should be able to open
+	swingArcStep     = 5.0   // degrees between checks as the leaf opens
+	swingJambFace    = 0.1   // m, width of the face of a jamb, the hinges go here
+	swingJambReturn  = 0.04  // m, the folded back part of the jamb that stiffens it, square to its face
+	swingHingeInset  = 0.02  // m, hinge holes from the hinge edge of a leaf
+	swingHingeHoles  = 3     // holes per hinge leaf
+	swingHingePitch  = 0.04  // m, between holes of one hinge
+	swingHingeDia    = 0.006 // m, M6
+	swingHingeBottom = 0.15  // m, bottom hinge from the bottom of the leaf
+	swingHingeTop    = 0.2   // m, top hinge from the top of the leaf
+	swingTallLeaf    = 2.0   // m, leaves taller than this get a third hinge
+)
+
+// DoorLeaf is one moving part of a swing door
+type DoorLeaf struct {
+	Hinge  v3.Vec  // bottom of the hinge axis
+	Across v3.Vec  // from the hinge to the latch edge when closed
+	Up     v3.Vec  // from bottom to top of the leaf
+	Into   v3.Vec  // unit vector into the shell, normal to the opening
+	Swing  float64 // +1 swings into the shell, -1 out of it
+}
+
+// Swinging is true for the kinds of door with hinged leaves
+func (d *Door) Swinging() bool {
+	return d.Kind == SingleSwing || d.Kind == DoubleSwing
+}
+
+// Leaves works out the leaves hung in the opening, according to the kind and way the door opens
+func (d *Door) Leaves() []DoorLeaf {
+	if !d.Swinging() {
+		return nil
+	}
+	bl, tl, br, tr, ok := d.jambs()
+	if !ok {
+		return nil
+	}
+	n := d.Normal.Normalized()
+	wn := d.Wide.Normalized()
+	corner := innerCorner(bl, tl, br, tr, d.Wide, n)
+
+	swing := 1.0
+	switch d.Opens {
+	case LeftOut, RightOut, CenterOut:
+		swing = -1
+	}
+	up := d.High.Normalized().Scale(float64(d.Height) - 2*swingGap)
+	left := corner.Add(wn.Scale(swingGap)).Add(v3.Z.Scale(swingGap))
+	right := corner.Add(d.Wide).Subtract(wn.Scale(swingGap)).Add(v3.Z.Scale(swingGap))
+
+	if d.Kind == DoubleSwing {
+		lw := (float64(d.Width) - 3*swingGap) / 2
+		return []DoorLeaf{
+			{Hinge: left, Across: wn.Scale(lw), Up: up, Into: n, Swing: swing},
+			{Hinge: right, Across: wn.Scale(-lw), Up: up, Into: n, Swing: swing}}
+	}
+	lw := float64(d.Width) - 2*swingGap
+	if d.Opens == RightIn || d.Opens == RightOut {
+		return []DoorLeaf{{Hinge: right, Across: wn.Scale(-lw), Up: up, Into: n, Swing: swing}}
+	}
+	return []DoorLeaf{{Hinge: left, Across: wn.Scale(lw), Up: up, Into: n, Swing: swing}}
+}
+
+// AcrossAt is the hinge to latch vector when the leaf is opened by deg degrees
+func (l DoorLeaf) AcrossAt(deg float64) v3.Vec {
+	a := deg * math.Pi / 180
+	return l.Across.Scale(math.Cos(a)).Add(l.Into.Scale(l.Across.Length() * math.Sin(a) * l.Swing))
+}
+
+// Corners are the corners of the leaf opened by deg degrees: hinge bottom, latch bottom,
+//   latch top, hinge top
+func (l DoorLeaf) Corners(deg float64) []v3.Vec {
+	a := l.AcrossAt(deg)
+	return []v3.Vec{l.Hinge, l.Hinge.Add(a), l.Hinge.Add(a).Add(l.Up), l.Hinge.Add(l.Up)}
+}
+
+// Width of the leaf
+func (l DoorLeaf) Width() float64 {
+	return l.Across.Length()
+}
+
+// Height of the leaf
+func (l DoorLeaf) Height() float64 {
+	return l.Up.Length()
+}
+
+// SwingClearance finds how far, in degrees, the leaf opens before its top hits the shell,
+//   up to swingOpenAngle
+func (d *Door) SwingClearance(l DoorLeaf) float64 {
+	wantInside := l.Swing > 0
+	for deg := swingArcStep; deg <= swingOpenAngle; deg += swingArcStep {
+		c := l.Corners(deg)
+		mid := c[2].Add(c[3]).Scale(0.5)
+		for _, p := range []v3.Vec{c[2], mid} {
+			if d.Shell.surface().Inside(p) != wantInside {
+				return deg - swingArcStep
+			}
+		}
+	}
+	return swingOpenAngle
+}
+
+// SwingLines shows the leaves closed and as far as they open, with the arc swept by the top
+//   of the latch edge; green if they open fully, red if not
+func (d *Door) SwingLines() []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	for _, l := range d.Leaves() {
+		max := d.SwingClearance(l)
+		colour := gl.Green
+		if max < swingOpenAngle {
+			colour = gl.Red
+		}
+		closed := l.Corners(0)
+		opened := l.Corners(max)
+		for i := range closed {
+			j := (i + 1) % len(closed)
+			ls = append(ls, gl.ColourLine{Start: closed[i], End: closed[j], Colour: &gl.White})
+			ls = append(ls, gl.ColourLine{Start: opened[i], End: opened[j], Colour: &colour})
+		}
+		prev := closed[2]
+		for deg := swingArcStep; deg <= swingOpenAngle; deg += swingArcStep {
+			at := l.Corners(deg)[2]
+			arcColour := gl.Green
+			if deg > max {
+				arcColour = gl.Red
+			}
+			ls = append(ls, gl.ColourLine{Start: prev, End: at, Colour: &arcColour})
+			prev = at
+		}
+	}
+	return ls
+}
+
+// hingeHeights are the heights of the hinge centres up a leaf of height h
+func hingeHeights(h float64) []float64 {
+	hs := []float64{swingHingeBottom, h - swingHingeTop}
+	if h > swingTallLeaf {
+		hs = append(hs, (swingHingeBottom+h-swingHingeTop)/2)
+	}
+	return hs
+}
+
+// hingeHoles adds the holes for all the hinges of a leaf of height h along a line x from the
+//   edge, the leaf's bottom y0 up the part, so a jamb's holes line up with its leaf's
+func hingeHoles(dr *cam.Drawing, x, y0, h float64) {
+	for _, hy := range hingeHeights(h) {
+		for i := 0; i < swingHingeHoles; i++ {
+			y := y0 + hy + swingHingePitch*(float64(i)-float64(swingHingeHoles-1)/2)
+			dr.Paths = append(dr.Paths, cam.NewCirclePath(cam.NewVec2(x*m2mm, y*m2mm), swingHingeDia*m2mm/2, cam.EdgePath))
+		}
+	}
+}
+
+// rectPath is a closed rectangle w by h, in m, at the origin
+func rectPath(w, h float64, kind cam.PathKind) cam.Path {
+	return cam.NewPolygonPath([]cam.Vec2{cam.NewVec2(0, 0), cam.NewVec2(w*m2mm, 0),
+		cam.NewVec2(w*m2mm, h*m2mm), cam.NewVec2(0, h*m2mm)}, kind)
+}
+
+// linePath is a single open line from a to b
+func linePath(a, b cam.Vec2, kind cam.PathKind) cam.Path {
+	p := cam.Path{}
+	p.Add(cam.Segment{Kind: kind, Start: a, End: b})
+	return p
+}
+
+// swingReturnBend folds the return of a jamb or head back, away from its face
+var swingReturnBend = cam.Bend{Angle: math.Pi / 2}
+
+// SwingParts makes the flat parts for a swing door: the leaves, then the two side jambs and
+//   the head of the frame, with hinge holes where the leaves hang
+func (d *Door) SwingParts() []cam.Drawing {
+	leaves := d.Leaves()
+	if len(leaves) == 0 {
+		return nil
+	}
+	parts := []cam.Drawing{}
+	hingeLeft, hingeRight := false, false
+	wn := d.Wide.Normalized()
+	for i, l := range leaves {
+		dr := cam.Drawing{Name: fmt.Sprintf("%s leaf %d", d.Name, i+1), ID: i + 1}
+		dr.Paths = append(dr.Paths, rectPath(l.Width(), l.Height(), cam.EdgePath))
+		hingeHoles(&dr, swingHingeInset, 0, l.Height())
+		parts = append(parts, dr)
+		if l.Across.Dot(wn) > 0 {
+			hingeLeft = true
+		} else {
+			hingeRight = true
+		}
+	}
+
+	h := float64(d.Height)
+	strip := swingJambFace + swingJambReturn
+	for i, side := range []struct {
+		name   string
+		hinged bool
+	}{{"left jamb", hingeLeft}, {"right jamb", hingeRight}} {
+		dr := cam.Drawing{Name: fmt.Sprintf("%s %s", d.Name, side.name), ID: len(leaves) + i + 1}
+		dr.Paths = append(dr.Paths, rectPath(strip, h, cam.EdgePath))
+		dr.Paths = append(dr.Paths, cam.NewFoldPath(cam.NewVec2(swingJambFace*m2mm, 0),
+			cam.NewVec2(swingJambFace*m2mm, h*m2mm), swingReturnBend))
+		if side.hinged {
+			hingeHoles(&dr, swingJambFace/2, swingGap, h-2*swingGap) // the leaves hang swingGap up
+		}
+		parts = append(parts, dr)
+	}
+
+	w := float64(d.Width) + 2*swingJambFace
+	head := cam.Drawing{Name: fmt.Sprintf("%s head", d.Name), ID: len(leaves) + 3}
+	head.Paths = append(head.Paths, rectPath(w, strip, cam.EdgePath))
+	head.Paths = append(head.Paths, cam.NewFoldPath(cam.NewVec2(0, swingJambFace*m2mm),
+		cam.NewVec2(w*m2mm, swingJambFace*m2mm), swingReturnBend))
+	parts = append(parts, head)
+
+	return parts
+}
+
+// DoorParts are the flat parts of the frames and leaves of all the swing doors
+func (e *EShell) DoorParts() []cam.Drawing {
+	var ds []cam.Drawing
+	for _, d := range e.Doors {
+		ds = append(ds, d.SwingParts()...)
+	}
+	return ds
+}
+
+// SwingStats describes how well the leaves swing
+func (d *Door) SwingStats() string {
+	s := ""
+	for i, l := range d.Leaves() {
+		max := d.SwingClearance(l)
+		s += fmt.Sprintf("      leaf %d: %4.0fmm x %4.0fmm opens %3.0f°, then clears the shell by %3.0fmm", i+1, l.Width()*m2mm, l.Height()*m2mm, max, d.LeafClearance(l, max)*m2mm)
+		if max < swingOpenAngle {
+			s += " -- HITS SHELL"
+		}
+		s += "\n"
+	}
+	return s
+}
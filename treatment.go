@@ -0,0 +1,123 @@
+package main
+
+// ████████╗██████╗ ███████╗ █████╗ ████████╗
+// ╚══██╔══╝██╔══██╗██╔════╝██╔══██╗╚══██╔══╝
+//    ██║   ██████╔╝█████╗  ███████║   ██║
+//    ██║   ██╔══██╗██╔══╝  ██╔══██║   ██║
+//    ██║   ██║  ██║███████╗██║  ██║   ██║
+//    ╚═╝   ╚═╝  ╚═╝╚══════╝╚═╝  ╚═╝   ╚═╝
+
+import (
+	"fmt"
+
+	v3 "./vec"
+
+	"github.com/g3n/engine/math32"
+)
+
+// EdgeTreatments lists them in the order clicking an edge cycles through them
+var EdgeTreatments = []EdgeTreatment{ETreatAsCut, ETreatOpenHemMk1, ETreatClosedHemMk1, ETreatTeardropHem, ETreatSmooth, ETreatFlange}
+
+// String names the treatment
+func (t EdgeTreatment) String() string {
+	switch t {
+	case ETreatAsCut:
+		return "As cut"
+	case ETreatOpenHemMk1:
+		return "Open hem"
+	case ETreatClosedHemMk1:
+		return "Closed hem"
+	case ETreatTeardropHem:
+		return "Teardrop hem"
+	case ETreatSmooth:
+		return "Smooth"
+	case ETreatFlange:
+		return "Flange"
+	}
+	return "?"
+}
+
+// treatmentColours show the treatment of each edge on the wireframe
+var treatmentColours = map[EdgeTreatment]math32.Color{
+	ETreatAsCut:        {R: 0.5, G: 0.5, B: 0.5}, // grey
+	ETreatOpenHemMk1:   {R: 1, G: 0.2, B: 0.2},   // red
+	ETreatClosedHemMk1: {R: 0.3, G: 0.5, B: 1},   // blue
+	ETreatTeardropHem:  {R: 1, G: 0, B: 1},       // magenta
+	ETreatSmooth:       {R: 1, G: 1, B: 1},       // white
+	ETreatFlange:       {R: 0, G: 1, B: 0},       // green
+}
+
+// PickEdge is the edge nearest where the ray first hits the shell, within maxT, if it hits it
+func (e *EShell) PickEdge(r v3.Ray, maxT float64) (*Edge, bool) {
+	h, ok := e.PickPanel(r, maxT)
+	if !ok {
+		return nil, false
+	}
+	var best *Edge
+	bestD := 0.0
+	for _, ed := range aliveEdges(h.Panel.Edges) {
+		d := v3.DistancePointSegment(h.Where, v3.NewSegment2Ends(ed.Vertices[0].Position, ed.Vertices[1].Position))
+		if best == nil || d < bestD {
+			best, bestD = ed, d
+		}
+	}
+	return best, best != nil
+}
+
+// SetTreatment gives the edge the treatment, with the default hem size if it is a hem, and
+//   makes the flanges again if it gains or loses one
+func (e *EShell) SetTreatment(ed *Edge, t EdgeTreatment) {
+	was := ed.Treatment
+	ed.Treatment = t
+	switch t {
+	case ETreatOpenHemMk1, ETreatClosedHemMk1, ETreatTeardropHem:
+		if ed.HemSize <= 0 {
+			ed.HemSize = hemDefaultSize
+		}
+	}
+	if was == ETreatFlange || t == ETreatFlange {
+		e.GenerateFlanges()
+	}
+}
+
+// CycleTreatment gives the edge the next treatment, or the one before if back, and returns it.
+//   On a seam, going from open hem to closed hem swaps which panel gets the open one.
+func (e *EShell) CycleTreatment(ed *Edge, back bool) EdgeTreatment {
+	step := 1
+	if back {
+		step = len(EdgeTreatments) - 1
+	}
+	i := 0
+	for j, t := range EdgeTreatments {
+		if t == ed.Treatment {
+			i = j
+		}
+	}
+	e.SetTreatment(ed, EdgeTreatments[(i+step)%len(EdgeTreatments)])
+	return ed.Treatment
+}
+
+// TreatmentLengths is the length of panel edge given each treatment, m. A seam counts once
+//   for each of its panels, so a hemmed seam is as much open hem as closed hem.
+func (e EShell) TreatmentLengths() map[EdgeTreatment]float64 {
+	ls := map[EdgeTreatment]float64{}
+	for _, ed := range aliveEdges(e.Edges) {
+		l := ed.Vertices[1].Position.Subtract(ed.Vertices[0].Position).Length()
+		for _, p := range alivePanels(ed.Panels) {
+			ls[ed.HemTreatment(p)] += l
+		}
+	}
+	return ls
+}
+
+// TreatmentSummary is the length of each treatment there is, for the stats
+func (e EShell) TreatmentSummary() string {
+	ls := e.TreatmentLengths()
+	s := "Edges:"
+	for _, t := range EdgeTreatments {
+		if l, ok := ls[t]; ok {
+			s += fmt.Sprintf("  %s %s", t, e.Display.Len(l))
+		}
+	}
+	return s + "\n"
+}
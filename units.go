@@ -0,0 +1,96 @@
+package main
+
+// ██╗   ██╗███╗   ██╗██╗████████╗███████╗
+// ██║   ██║████╗  ██║██║╚══██╔══╝██╔════╝
+// ██║   ██║██╔██╗ ██║██║   ██║   ███████╗
+// ██║   ██║██║╚██╗██║██║   ██║   ╚════██║
+// ╚██████╔╝██║ ╚████║██║   ██║   ███████║
+//  ╚═════╝ ╚═╝  ╚═══╝╚═╝   ╚═╝   ╚══════╝
+
+import (
+	"fmt"
+	"math"
+)
+
+// DisplayUnits are what lengths, areas and volumes are typed in and shown in, in the GUI and
+//   the stats. The shell itself is always in m, and the CAM files in its Units.
+type DisplayUnits int
+
+// Values of DisplayUnits
+const (
+	Imperial DisplayUnits = iota // feet
+	Metric                       // metres
+)
+
+// AllDisplayUnits are in menu order
+var AllDisplayUnits = []DisplayUnits{Imperial, Metric}
+
+func (u DisplayUnits) String() string {
+	if u == Metric {
+		return "Metric"
+	}
+	return "Imperial"
+}
+
+// LengthUnit is the name of the unit lengths are typed in
+func (u DisplayUnits) LengthUnit() string {
+	if u == Metric {
+		return "m"
+	}
+	return "ft"
+}
+
+// FromM is the length in m in these units
+func (u DisplayUnits) FromM(m float64) float64 {
+	if u == Metric {
+		return m
+	}
+	return m * m2ft
+}
+
+// ToM is the length in these units in m
+func (u DisplayUnits) ToM(l float64) float64 {
+	if u == Metric {
+		return l
+	}
+	return l * ft2m
+}
+
+// Len shows a length, eg 12.3' or 3.75m
+func (u DisplayUnits) Len(m float64) string {
+	if u == Metric {
+		return fmt.Sprintf("%.2fm", m)
+	}
+	return fmt.Sprintf("%.1f'", m*m2ft)
+}
+
+// Height shows a height, as feet and inches if imperial, eg 6'8"
+func (u DisplayUnits) Height(m float64) string {
+	if u == Metric {
+		return fmt.Sprintf("%.2fm", m)
+	}
+	in := math.Round(m / in2m) // carried into the feet, so never 5'12"
+	return fmt.Sprintf("%.0f'%.0f\"", math.Floor(in/12), math.Mod(in, 12))
+}
+
+// Input is the length in m as typed in these units, to enough places that changing the units
+//   there and back leaves the length as it was
+func (u DisplayUnits) Input(m float64) string {
+	return fmt.Sprintf("%.9g", u.FromM(m))
+}
+
+// Area shows an area, eg 123.4sqft or 11.47sqm
+func (u DisplayUnits) Area(sqm float64) string {
+	if u == Metric {
+		return fmt.Sprintf("%.2fsqm", sqm)
+	}
+	return fmt.Sprintf("%.1fsqft", sqm*sqM2sqFt)
+}
+
+// Volume shows a volume, eg 2300 cu ft or 65.1 cu m
+func (u DisplayUnits) Volume(cum float64) string {
+	if u == Metric {
+		return fmt.Sprintf("%.1f cu m", cum)
+	}
+	return fmt.Sprintf("%.0f cu ft", cum*m2ft*m2ft*m2ft)
+}
@@ -0,0 +1,117 @@
+// ██████╗  ██████╗ ██╗  ██╗
+// ██╔══██╗██╔═══██╗╚██╗██╔╝
+// ██████╔╝██║   ██║ ╚███╔╝
+// ██╔══██╗██║   ██║ ██╔██╗
+// ██████╔╝╚██████╔╝██╔╝ ██╗
+// ╚═════╝  ╚═════╝ ╚═╝  ╚═╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// Box is an axis aligned bounding box, from Min to Max
+type Box struct {
+	Min, Max Vec
+}
+
+// EmptyBox contains nothing, and grows to fit whatever is added to it
+var EmptyBox = Box{Min: NewSimVec(math.Inf(1), math.Inf(1), math.Inf(1)), Max: NewSimVec(math.Inf(-1), math.Inf(-1), math.Inf(-1))}
+
+// NewBox is the smallest box holding the points
+func NewBox(pts ...Vec) Box {
+	b := EmptyBox
+	for _, p := range pts {
+		b = b.Extend(p)
+	}
+	return b
+}
+
+// Empty is true if the box holds nothing
+func (b Box) Empty() bool {
+	return b.Min.X() > b.Max.X() || b.Min.Y() > b.Max.Y() || b.Min.Z() > b.Max.Z()
+}
+
+// Extend is the box grown to hold p too
+func (b Box) Extend(p Vec) Box {
+	return Box{
+		Min: NewSimVec(math.Min(b.Min.X(), p.X()), math.Min(b.Min.Y(), p.Y()), math.Min(b.Min.Z(), p.Z())),
+		Max: NewSimVec(math.Max(b.Max.X(), p.X()), math.Max(b.Max.Y(), p.Y()), math.Max(b.Max.Z(), p.Z()))}
+}
+
+// Union is the box holding both
+func (b Box) Union(c Box) Box {
+	if c.Empty() {
+		return b
+	}
+	return b.Extend(c.Min).Extend(c.Max)
+}
+
+// Grow is the box made bigger by d all round
+func (b Box) Grow(d float64) Box {
+	g := NewSimVec(d, d, d)
+	return Box{Min: b.Min.Subtract(g), Max: b.Max.Add(g)}
+}
+
+// Size is the vector from Min to Max
+func (b Box) Size() Vec {
+	return b.Max.Subtract(b.Min)
+}
+
+// Center is the middle of the box
+func (b Box) Center() Vec {
+	return b.Min.Add(b.Max).Scale(0.5)
+}
+
+// Contains is true if p is in the box, or on its surface
+func (b Box) Contains(p Vec) bool {
+	return p.X() >= b.Min.X() && p.X() <= b.Max.X() &&
+		p.Y() >= b.Min.Y() && p.Y() <= b.Max.Y() &&
+		p.Z() >= b.Min.Z() && p.Z() <= b.Max.Z()
+}
+
+// Overlaps is true if the boxes have any point in common
+func (b Box) Overlaps(c Box) bool {
+	return b.Min.X() <= c.Max.X() && c.Min.X() <= b.Max.X() &&
+		b.Min.Y() <= c.Max.Y() && c.Min.Y() <= b.Max.Y() &&
+		b.Min.Z() <= c.Max.Z() && c.Min.Z() <= b.Max.Z()
+}
+
+// IntersectsSegment is true if any part of the segment is in the box, by clipping it against
+//   each pair of faces in turn
+func (b Box) IntersectsSegment(s Segment) bool {
+	t0, t1 := s.MinD, s.MaxD
+	o, d := s.PointOn, s.AlongN
+	for _, ax := range [][4]float64{
+		{o.X(), d.X(), b.Min.X(), b.Max.X()},
+		{o.Y(), d.Y(), b.Min.Y(), b.Max.Y()},
+		{o.Z(), d.Z(), b.Min.Z(), b.Max.Z()}} {
+		o, d, lo, hi := ax[0], ax[1], ax[2], ax[3]
+		if math.Abs(d) < mayAsWellBeZero {
+			if o < lo || o > hi {
+				return false
+			}
+			continue
+		}
+		ta, tb := (lo-o)/d, (hi-o)/d
+		if ta > tb {
+			ta, tb = tb, ta
+		}
+		t0, t1 = math.Max(t0, ta), math.Min(t1, tb)
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsBox is true if any part of the segment is in the box
+func (seg Segment) IntersectsBox(b Box) bool {
+	return b.IntersectsSegment(seg)
+}
+
+func (b Box) String() string {
+	return fmt.Sprintf("Box from %s to %s", b.Min, b.Max)
+}
@@ -0,0 +1,46 @@
+package vec
+
+import (
+	"testing"
+)
+
+func TestBox(t *testing.T) {
+
+	if !EmptyBox.Empty() || NewBox(Origin).Empty() {
+		t.Errorf("Empty failed")
+	}
+
+	b := NewBox(NewSimVec(1, 2, 3), NewSimVec(-1, 0, 5), NewSimVec(0, 1, 4))
+	if !sameVec(b.Min, NewSimVec(-1, 0, 3)) || !sameVec(b.Max, NewSimVec(1, 2, 5)) {
+		t.Errorf("NewBox failed: %s", b)
+	}
+	if !sameVec(b.Center(), NewSimVec(0, 1, 4)) || !sameVec(b.Size(), NewSimVec(2, 2, 2)) {
+		t.Errorf("Center or Size failed")
+	}
+	if !b.Contains(NewSimVec(0, 1, 3)) || b.Contains(NewSimVec(0, 1, 2.9)) {
+		t.Errorf("Contains failed")
+	}
+	if !b.Overlaps(NewBox(NewSimVec(1, 2, 5), NewSimVec(3, 3, 6))) || b.Overlaps(NewBox(NewSimVec(1.1, 0, 3), NewSimVec(2, 1, 4))) {
+		t.Errorf("Overlaps failed")
+	}
+	if !b.Union(EmptyBox).Overlaps(b) || !b.Grow(1).Contains(NewSimVec(2, 3, 6)) {
+		t.Errorf("Union or Grow failed")
+	}
+
+	// Segments through, short of, past and alongside the box
+	if !b.IntersectsSegment(NewSegment2Ends(NewSimVec(-5, 1, 4), NewSimVec(5, 1, 4))) {
+		t.Errorf("Segment through the box missed")
+	}
+	if b.IntersectsSegment(NewSegment2Ends(NewSimVec(-5, 1, 4), NewSimVec(-2, 1, 4))) {
+		t.Errorf("Segment short of the box hit")
+	}
+	if b.IntersectsSegment(NewSegment2Ends(NewSimVec(-5, 1, 4), NewSimVec(-5, 10, 4))) {
+		t.Errorf("Segment past the box hit")
+	}
+	if b.IntersectsSegment(NewSegment2Ends(NewSimVec(-5, 3, 4), NewSimVec(5, 3, 4))) {
+		t.Errorf("Segment alongside the box hit")
+	}
+	if !b.IntersectsSegment(NewSegment2Ends(NewSimVec(-3, -2, 1), NewSimVec(3, 4, 7))) {
+		t.Errorf("Diagonal segment missed")
+	}
+}
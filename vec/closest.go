@@ -0,0 +1,77 @@
+//  ██████╗██╗      ██████╗ ███████╗███████╗███████╗████████╗
+// ██╔════╝██║     ██╔═══██╗██╔════╝██╔════╝██╔════╝╚══██╔══╝
+// ██║     ██║     ██║   ██║███████╗█████╗  ███████╗   ██║
+// ██║     ██║     ██║   ██║╚════██║██╔══╝  ╚════██║   ██║
+// ╚██████╗███████╗╚██████╔╝███████║███████╗███████║   ██║
+//  ╚═════╝╚══════╝ ╚═════╝ ╚══════╝╚══════╝╚══════╝   ╚═╝
+
+package vec
+
+import (
+	"math"
+)
+
+// ClosestPoint is the point on the segment closest to p
+func (seg Segment) ClosestPoint(p Vec) Vec {
+	d := clamp(p.Subtract(seg.PointOn).Dot(seg.AlongN), seg.MinD, seg.MaxD)
+	return seg.PointOn.Add(seg.AlongN.Scale(d))
+}
+
+// DistancePointSegment is how far p is from the nearest point of the segment
+func DistancePointSegment(p Vec, seg Segment) float64 {
+	return seg.ClosestPoint(p).Subtract(p).Length()
+}
+
+// ClosestPointOnTriangle is the point of the triangle abc, including its inside, closest to p,
+//   found by working out which corner, edge or face region p is in
+func ClosestPointOnTriangle(p, a, b, c Vec) Vec {
+	ab, ac, ap := b.Subtract(a), c.Subtract(a), p.Subtract(a)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+	bp := p.Subtract(b)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+	if vc := d1*d4 - d3*d2; vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return a.Add(ab.Scale(d1 / (d1 - d3))) // on ab
+	}
+	cp := p.Subtract(c)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+	if vb := d5*d2 - d1*d6; vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return a.Add(ac.Scale(d2 / (d2 - d6))) // on ac
+	}
+	if va := d3*d6 - d5*d4; va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return b.Add(c.Subtract(b).Scale((d4 - d3) / ((d4 - d3) + (d5 - d6)))) // on bc
+	}
+	va, vb, vc := d3*d6-d5*d4, d5*d2-d1*d6, d1*d4-d3*d2
+	den := va + vb + vc
+	if math.Abs(den) < mayAsWellBeZero {
+		return a // no area to speak of
+	}
+	return a.Add(ab.Scale(vb / den)).Add(ac.Scale(vc / den))
+}
+
+// DistancePointTriangle is how far p is from the nearest point of the triangle abc
+func DistancePointTriangle(p, a, b, c Vec) float64 {
+	return ClosestPointOnTriangle(p, a, b, c).Subtract(p).Length()
+}
+
+// Distance is how far p is from the box, 0 if it is inside
+func (b Box) Distance(p Vec) float64 {
+	if b.Empty() {
+		return math.Inf(1)
+	}
+	out := func(x, lo, hi float64) float64 { return math.Max(0, math.Max(lo-x, x-hi)) }
+	return math.Sqrt(sq(out(p.X(), b.Min.X(), b.Max.X())) + sq(out(p.Y(), b.Min.Y(), b.Max.Y())) + sq(out(p.Z(), b.Min.Z(), b.Max.Z())))
+}
+
+// sq is x squared
+func sq(x float64) float64 {
+	return x * x
+}
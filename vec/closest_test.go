@@ -0,0 +1,58 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistancePointSegment(t *testing.T) {
+
+	seg := NewSegment2Ends(NewSimVec(0, 0, 0), NewSimVec(4, 0, 0))
+	if d := DistancePointSegment(NewSimVec(2, 3, 4), seg); NotApprox(d, 5) {
+		t.Errorf("Distance to the middle should be 5, not %f", d)
+	}
+	if p := seg.ClosestPoint(NewSimVec(-3, 4, 0)); !sameVec(p, Origin) {
+		t.Errorf("Closest point off the start should be the start, not %s", p)
+	}
+	if d := DistancePointSegment(NewSimVec(7, 4, 0), seg); NotApprox(d, 5) {
+		t.Errorf("Distance off the end should be 5, not %f", d)
+	}
+}
+
+func TestClosestPointOnTriangle(t *testing.T) {
+
+	a, b, c := NewSimVec(0, 0, 0), NewSimVec(4, 0, 0), NewSimVec(0, 4, 0)
+	for _, tc := range []struct {
+		p, want Vec
+	}{
+		{NewSimVec(1, 1, 3), NewSimVec(1, 1, 0)},         // over the face
+		{NewSimVec(-1, -1, 1), a},                        // past a corner
+		{NewSimVec(6, -1, 0), b},                         // past another
+		{NewSimVec(-1, 6, 2), c},                         // and the last
+		{NewSimVec(2, -3, 0), NewSimVec(2, 0, 0)},        // beside ab
+		{NewSimVec(-2, 1, 1), NewSimVec(0, 1, 0)},        // beside ac
+		{NewSimVec(3, 3, -1), NewSimVec(2, 2, 0)},        // beside bc
+		{NewSimVec(0.5, 0.5, 0), NewSimVec(0.5, 0.5, 0)}, // on it
+	} {
+		if got := ClosestPointOnTriangle(tc.p, a, b, c); !sameVec(got, tc.want) {
+			t.Errorf("Closest to %s should be %s, not %s", tc.p, tc.want, got)
+		}
+	}
+	if d := DistancePointTriangle(NewSimVec(3, 3, 0), a, b, c); NotApprox(d, math.Sqrt2) {
+		t.Errorf("Distance beside bc should be √2, not %f", d)
+	}
+}
+
+func TestBoxDistance(t *testing.T) {
+
+	b := NewBox(NewSimVec(0, 0, 0), NewSimVec(1, 1, 1))
+	if d := b.Distance(NewSimVec(0.5, 0.5, 0.5)); d != 0 {
+		t.Errorf("Inside should be 0, not %f", d)
+	}
+	if d := b.Distance(NewSimVec(4, 5, 0.5)); NotApprox(d, 5) {
+		t.Errorf("Off a corner edge should be 5, not %f", d)
+	}
+	if d := EmptyBox.Distance(Origin); !math.IsInf(d, 1) {
+		t.Errorf("Empty box should be infinitely far, not %f", d)
+	}
+}
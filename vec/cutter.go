@@ -7,14 +7,19 @@ package vec
 // ╚██████╗╚██████╔╝   ██║      ██║   ███████╗██║  ██║
 //  ╚═════╝ ╚═════╝    ╚═╝      ╚═╝   ╚══════╝╚═╝  ╚═╝
 
-// Cutter is a planar rectangular cutting tool with horizontal top and bottom and vertical sides
+// Cutter is a planar rectangular cutting tool. Those made by NewCutter have horizontal top and
+//   bottom and vertical sides, and run to the X or Y plane; those made by NewBoxCutter may be
+//   placed any way and run between two ends.
 type Cutter struct {
-	Patch          // The 'face' of the cutter, position is BL corner
-	Width  Meters  // Width
-	Height Meters  // Height
-	Wide   Vec     // Vector from BL corner to BR corner
-	High   Vec     // Vector from BL corner to TL corner
-	Walls  []Patch // Sides of the cutter (original only)
+	Patch           // The 'face' of the cutter, position is BL corner
+	Width  Meters   // Width
+	Height Meters   // Height
+	Wide   Vec      // Vector from BL corner to BR corner
+	High   Vec      // Vector from BL corner to TL corner
+	Walls  []Patch  // Sides of the cutter (original only)
+	Near   float64  // for box cutters, how far along the normal from the frame's origin the face is
+	Far    float64  // and the far end
+	Frame  *Matrix4 // for box cutters, the placement of the cutter's own frame; nil otherwise
 }
 
 // Locations of the sides in the array
@@ -30,24 +35,52 @@ const (
 // SidesOnly are the four Walls that are sides, not ends
 var SidesOnly = []int{CutterWallBottom, CutterWallTop, CutterWallLeft, CutterWallRight}
 
+// AllWalls are the walls of a box cutter, ends included
+var AllWalls = []int{CutterWallBottom, CutterWallTop, CutterWallLeft, CutterWallRight, CutterWallNearEnd, CutterWallFarEnd}
+
 // Translate by a vector
 func (c Cutter) Translate(v Vec) *Cutter {
+	if c.Frame != nil {
+		return c.Transform(Translation(v))
+	}
 	newC := NewCutter(c.Width, c.Height, c.Corner.Add(v), c.Normal)
 	return newC
 }
 
 // RotateZ rotates about Z axis
 func (c Cutter) RotateZ(a Radians) *Cutter {
+	if c.Frame != nil { // about the corner, as for the others
+		return c.Transform(Translation(c.Corner.Scale(-1)).Then(RotationZ(a)).Then(Translation(c.Corner)))
+	}
 	newNorm := c.Normal.RotateZ(a)
 	newC := NewCutter(c.Width, c.Height, c.Corner, newNorm)
 	return newC
 }
 
+// Transform moves a box cutter by a general transform, which should not stretch it. Others are
+//   first made into box cutters with the same walls.
+func (c Cutter) Transform(m Matrix4) *Cutter {
+	if c.Frame == nil {
+		depth := 0.0
+		if len(c.Walls) > CutterWallNearEnd {
+			depth = c.Walls[CutterWallNearEnd].Corner.Subtract(c.Corner).Dot(c.Normal)
+		}
+		f := Frame(c.Corner, c.Wide.Normalized(), c.Normal, c.High.Normalized())
+		c.Frame, c.Near, c.Far = &f, 0, depth
+	}
+	return NewBoxCutter(c.Width, c.Height, c.Near, c.Far, c.Frame.Then(m))
+}
+
 // SidesContain returns true iff the four sides (not ends) contain the given point
 func (c Cutter) SidesContain(v Vec) bool {
+	return c.SidesContainTol(v, DefaultTolerance)
+}
+
+// SidesContainTol is SidesContain, with points within the tolerance outside counting
+func (c Cutter) SidesContainTol(v Vec, tol Tolerance) bool {
 	inside := true
 	for _, s := range SidesOnly {
-		if !c.Walls[s].Plane.NormalSide(v) {
+		if c.Walls[s].Plane.SideTol(v, tol) < 0 {
 			inside = false
 			break
 		}
@@ -55,6 +88,30 @@ func (c Cutter) SidesContain(v Vec) bool {
 	return inside
 }
 
+// Contains returns true iff the given point is within the four sides and between the
+//   face of the cutter and its far end
+func (c Cutter) Contains(v Vec) bool {
+	return c.ContainsTol(v, DefaultTolerance)
+}
+
+// ContainsTol is Contains, with points within the tolerance outside counting
+func (c Cutter) ContainsTol(v Vec, tol Tolerance) bool {
+	if len(c.Walls) > CutterWallFarEnd { // a box cutter, whose walls all face in
+		for _, w := range c.Walls {
+			if w.Plane.SideTol(v, tol) < 0 {
+				return false
+			}
+		}
+		return true
+	}
+	if len(c.Walls) <= CutterWallNearEnd || !c.SidesContainTol(v, tol) {
+		return false
+	}
+	d := v.Subtract(c.Corner).Dot(c.Normal)
+	depth := c.Walls[CutterWallNearEnd].Corner.Subtract(c.Corner).Dot(c.Normal)
+	return tol.Within(d, 0, depth)
+}
+
 // NewCutter makes a new one of width & height and position, at angle a (0=x,ccw)
 func NewCutter(w, h Meters, p, normal Vec) *Cutter {
 
@@ -109,6 +166,32 @@ func NewCutter(w, h Meters, p, normal Vec) *Cutter {
 
 }
 
+// NewBoxCutter makes a cutter w wide and h high running from near to far along its normal, placed
+//   by the transform from its own frame, in which x is across the face, y is along the normal into
+//   the cut and z is up the face, with the bottom left corner of the face at x=z=0. Tilted and
+//   turned any way, it cuts a recess, or through a wall, only between its ends.
+func NewBoxCutter(w, h Meters, near, far float64, frame Matrix4) *Cutter {
+	c := Cutter{Width: w, Height: h, Near: near, Far: far, Frame: &frame}
+	across := frame.Direction(X).Normalized()
+	into := frame.Direction(Y).Normalized()
+	up := frame.Direction(Z).Normalized()
+	c.Wide = across.Scale(float64(w))
+	c.High = up.Scale(float64(h))
+	bl := frame.Point(NewSimVec(0, near, 0))
+	c.Patch = NewPatch(bl, into, c.Wide, c.High)
+
+	deep := into.Scale(far - near)
+	tl, br := bl.Add(c.High), bl.Add(c.Wide)
+	c.Walls = make([]Patch, CutterWallFarEnd+1)
+	c.Walls[CutterWallBottom] = NewPatch(bl, up, deep, c.Wide)
+	c.Walls[CutterWallTop] = NewPatch(tl, up.Scale(-1), deep, c.Wide)
+	c.Walls[CutterWallLeft] = NewPatch(bl, across, deep, c.High)
+	c.Walls[CutterWallRight] = NewPatch(br, across.Scale(-1), deep, c.High)
+	c.Walls[CutterWallNearEnd] = NewPatch(bl, into, c.Wide, c.High)
+	c.Walls[CutterWallFarEnd] = NewPatch(bl.Add(deep), into.Scale(-1), c.Wide, c.High)
+	return &c
+}
+
 // InitDisplay sets up displayable items for a cutter
 // func (c *Cutter) InitDisplay() *Cutter {
 // 	return c
@@ -0,0 +1,43 @@
+package vec
+
+import (
+	"testing"
+)
+
+func TestBoxCutter(t *testing.T) {
+
+	// 1 wide, 2 high, facing +y from 1 to 3 along it
+	c := NewBoxCutter(1, 2, 1, 3, Identity())
+	if !sameVec(c.Corner, NewSimVec(0, 1, 0)) || !sameVec(c.Normal, Y) || len(c.Walls) != CutterWallFarEnd+1 {
+		t.Errorf("Box cutter wrong: %s", c.Patch)
+	}
+	if !c.Contains(NewSimVec(0.5, 2, 1)) || c.Contains(NewSimVec(0.5, 0.5, 1)) || c.Contains(NewSimVec(0.5, 3.5, 1)) {
+		t.Errorf("Box cutter contains wrong along its depth")
+	}
+	if c.Contains(NewSimVec(1.5, 2, 1)) || c.Contains(NewSimVec(0.5, 2, 2.5)) {
+		t.Errorf("Box cutter contains wrong across its face")
+	}
+	for i, w := range c.Walls { // all face in
+		if w.Plane.SideTol(NewSimVec(0.5, 2, 1), Exact) <= 0 {
+			t.Errorf("Wall %d faces out: %s", i, w.Normal)
+		}
+	}
+
+	// Tilted back 45 degrees about its bottom edge, so it cuts up at an angle
+	tilted := c.Transform(RotationX(Deg2Rad(45)))
+	if !sameVec(tilted.Normal, NewSimVec(0, 1, 1).Normalized()) {
+		t.Errorf("Tilted normal wrong: %s", tilted.Normal)
+	}
+	if !tilted.Contains(NewSimVec(0.5, 1, 1.5)) || tilted.Contains(NewSimVec(0.5, 2, 1)) {
+		t.Errorf("Tilted contains wrong")
+	}
+
+	// Moving keeps the depth
+	moved := c.Translate(NewSimVec(0, 0, 5)).RotateZ(Deg2Rad(90))
+	if !sameVec(moved.Corner, NewSimVec(0, 1, 5)) || !sameVec(moved.Normal, X.Scale(-1)) || moved.Far != 3 {
+		t.Errorf("Moved box cutter wrong: %s %s", moved.Corner, moved.Normal)
+	}
+	if !moved.Contains(NewSimVec(-1, 1.5, 6)) || moved.Contains(NewSimVec(-2.5, 1.5, 6)) {
+		t.Errorf("Moved box cutter contains wrong")
+	}
+}
@@ -129,10 +129,16 @@ func (p Plane) String() string {
 
 // IntersectLine determines whether the given line intersects this plane, and if so, where. hits = false -> line is parallel to plane.
 func (p Plane) IntersectLine(l Line) (where Vec, hits bool) {
-	ldotn := l.AlongN.Dot(p.Normal)
-	if math.Abs(ldotn) < mayAsWellBeZero { // effectively zero
+	return p.IntersectLineTol(l, DefaultTolerance)
+}
+
+// IntersectLineTol is IntersectLine, with lines within the tolerance's angle of the plane
+//   counting as parallel
+func (p Plane) IntersectLineTol(l Line, tol Tolerance) (where Vec, hits bool) {
+	if tol.Square(l.AlongN, p.Normal) { // effectively parallel
 		return where, false
 	}
+	ldotn := l.AlongN.Dot(p.Normal)
 	d := (p.PointOn.Subtract(l.PointOn).Dot(p.Normal) / ldotn)
 	where = l.PointOn.Add(l.AlongN.Scale(d))
 	return where, true
@@ -141,12 +147,18 @@ func (p Plane) IntersectLine(l Line) (where Vec, hits bool) {
 // IntersectSegment determines whether the given segment intersects this plane,
 //   and if so, where. hits = false -> line is parallel to plane.
 func (p Plane) IntersectSegment(s Segment) (where Vec, hits bool) {
-	whu, anyHit := p.IntersectLine(s.Line)
+	return p.IntersectSegmentTol(s, DefaultTolerance)
+}
+
+// IntersectSegmentTol is IntersectSegment, with hits within the tolerance beyond the ends of
+//   the segment counting. With no length tolerance, hits right on the ends do not count.
+func (p Plane) IntersectSegmentTol(s Segment, tol Tolerance) (where Vec, hits bool) {
+	whu, anyHit := p.IntersectLineTol(s.Line, tol)
 	if !anyHit { // bail, they are parallel
 		return where, false
 	}
 	howFar := whu.Subtract(s.PointOn).Dot(s.AlongN)
-	if howFar <= s.MinD || howFar >= s.MaxD { // on the line, but beyond the segment
+	if howFar <= s.MinD-tol.Length || howFar >= s.MaxD+tol.Length { // on the line, but beyond the segment
 		return where, false
 	}
 	return whu, true
@@ -164,14 +176,65 @@ func (p *Plane) Translate(by Vec) *Plane {
 	return p
 }
 
+// Transform moves the plane by a general transform, keeping the normal square to it
+func (p *Plane) Transform(m Matrix4) *Plane {
+	n := p.Normal
+	if inv, ok := m.Inverse(); ok { // normals go by the inverse transpose
+		n = NewSimVec(
+			inv[0][0]*n.X()+inv[1][0]*n.Y()+inv[2][0]*n.Z(),
+			inv[0][1]*n.X()+inv[1][1]*n.Y()+inv[2][1]*n.Z(),
+			inv[0][2]*n.X()+inv[1][2]*n.Y()+inv[2][2]*n.Z())
+	}
+	p.PointOn = m.Point(p.PointOn)
+	p.Normal = n.Normalized()
+	return p
+}
+
 // NormalSide returns true iff the given point is on
 //   the side of the plane to which the normal points
 func (p Plane) NormalSide(poi Vec) bool {
-	r := poi.Subtract(p.PointOn).Dot(p.Normal)
-	if r < 0 {
-		return false
+	return p.SideTol(poi, DefaultTolerance) >= 0
+}
+
+// SideTol is +1 if the point is on the side of the plane to which the normal points, -1 if on
+//   the other, and 0 if within the tolerance of the plane
+func (p Plane) SideTol(poi Vec, tol Tolerance) int {
+	return tol.Sign(poi.Subtract(p.PointOn).Dot(p.Normal))
+}
+
+// BasisAlong gives unit axes in the plane for a local 2D coordinate system: x along dir, as
+//   projected onto the plane, and y square to it so that x cross y is the normal
+func (p Plane) BasisAlong(dir Vec) (x, y Vec) {
+	x = dir.Subtract(p.Normal.Scale(dir.Dot(p.Normal)))
+	if x.Length() < mayAsWellBeZero {
+		return p.Basis()
+	}
+	x = x.Normalized()
+	return x, p.Normal.Cross(x)
+}
+
+// Basis gives unit axes in the plane for a local 2D coordinate system: x level, unless the
+//   plane is, when it is along X, and y square to it so that x cross y is the normal
+func (p Plane) Basis() (x, y Vec) {
+	x = Z.Cross(p.Normal)
+	if x.Length() < 1e-6 {
+		x = X.Subtract(p.Normal.Scale(X.Dot(p.Normal)))
 	}
-	return true
+	x = x.Normalized()
+	return x, p.Normal.Cross(x)
+}
+
+// ToLocal2D projects a point onto the plane, giving its coordinates on the Basis from PointOn
+func (p Plane) ToLocal2D(pt Vec) (u, v float64) {
+	x, y := p.Basis()
+	d := pt.Subtract(p.PointOn)
+	return d.Dot(x), d.Dot(y)
+}
+
+// FromLocal2D is the point in the plane at the given coordinates on the Basis from PointOn
+func (p Plane) FromLocal2D(u, v float64) Vec {
+	x, y := p.Basis()
+	return p.PointOn.Add(x.Scale(u)).Add(y.Scale(v))
 }
 
 // ██████╗  █████╗ ████████╗ ██████╗██╗  ██╗
@@ -218,6 +281,14 @@ func (pa *Patch) RotateZAbout(p Vec, a Radians) *Patch {
 	return pa
 }
 
+// Transform moves a patch in place by a general transform
+func (pa *Patch) Transform(m Matrix4) *Patch {
+	pa.Plane.Transform(m)
+	pa.Corner = m.Point(pa.Corner)
+	pa.Sides = []Vec{m.Direction(pa.Sides[0]), m.Direction(pa.Sides[1])}
+	return pa
+}
+
 // Translate moves a patch
 func (pa *Patch) Translate(by Vec) *Patch {
 	pa.Plane.Translate(by)
@@ -225,65 +296,130 @@ func (pa *Patch) Translate(by Vec) *Patch {
 	return pa
 }
 
-// sameSide is from https://blackpawn.com/texts/pointinpoly/default.html
-func sameSide(p1, p2, a, b Vec) bool {
-	bSubA := b.Subtract(a)
-	cp1 := bSubA.Cross(p1.Subtract(a))
-	cp2 := bSubA.Cross(p2.Subtract(a))
-	if cp1.Dot(cp2) >= 0 {
-		return true
-	}
-	return false
-}
-
-// inTriangle
-func inTriangle(p, a, b, c Vec) bool {
-	if sameSide(p, a, b, c) && sameSide(p, b, a, c) && sameSide(p, c, a, b) {
-		return true
-	}
-	return false
-}
-
 // TriIntersectSegment determines whether the given segment
 //   intersects the triangle defined by the sides of this patch,
 //   and if so, where. hits = false -> line is parallel to plane.
 func (pa Patch) TriIntersectSegment(s Segment) (where Vec, hits bool) {
+	return pa.TriIntersectSegmentTol(s, DefaultTolerance)
+}
 
-	const r2d = 180 / math.Pi
+// TriIntersectSegmentTol is TriIntersectSegment, with hits within the tolerance outside
+//   the triangle counting
+func (pa Patch) TriIntersectSegmentTol(s Segment, tol Tolerance) (where Vec, hits bool) {
 
-	whu, anyHit := pa.Plane.IntersectSegment(s) // does the segment intersect my containing plane?
-	if !anyHit {                                // nope, bail
+	whu, anyHit := pa.Plane.IntersectSegmentTol(s, tol) // does the segment intersect my containing plane?
+	if !anyHit {                                        // nope, bail
 		return where, false
 	}
 
-	if !inTriangle(whu, pa.Corner, pa.Corner.Add(pa.Sides[0]), pa.Corner.Add(pa.Sides[1])) {
+	u, v := pa.ParaCoords(whu)
+	eu, ev := pa.slack(tol)
+	if !(u >= -eu && v >= -ev && u+v <= 1+math.Max(eu, ev)) {
 		return where, false
 	}
 
 	return whu, true
 }
 
+// slack is the length tolerance as fractions of each side
+func (pa Patch) slack(tol Tolerance) (eu, ev float64) {
+	return tol.Length / pa.Sides[0].Length(), tol.Length / pa.Sides[1].Length()
+}
+
 // ParaIntersectSegment determines whether the given segment
 //   intersects the parallelagram defined by the sides of this patch,
 //   and if so, where. hits = false -> line is parallel to plane.
 func (pa Patch) ParaIntersectSegment(s Segment) (where Vec, hits bool) {
+	return pa.ParaIntersectSegmentTol(s, DefaultTolerance)
+}
 
-	whu, anyHit := pa.Plane.IntersectSegment(s) // does the segment intersect my containing plane?
-	if !anyHit {                                // nope, bail
+// ParaIntersectSegmentTol is ParaIntersectSegment, with hits within the tolerance outside
+//   the parallelogram counting
+func (pa Patch) ParaIntersectSegmentTol(s Segment, tol Tolerance) (where Vec, hits bool) {
+
+	whu, anyHit := pa.Plane.IntersectSegmentTol(s, tol) // does the segment intersect my containing plane?
+	if !anyHit {                                        // nope, bail
 		return where, false
 	}
 
-	b := pa.Corner.Add(pa.Sides[0])
-	c := pa.Corner.Add(pa.Sides[1])
-
-	if inTriangle(whu, pa.Corner, b, c) {
+	if pa.ParaContainsTol(whu, tol) {
 		return whu, true
 	}
 
-	if inTriangle(whu, b, b.Add(pa.Sides[1]), c) {
-		return whu, true
+	return where, false
+
+}
+
+// ParaCoords gives the position of a point in the plane of the patch in terms of
+//   its sides, i.e. p = Corner + u*Sides[0] + v*Sides[1]. Points off the plane are
+//   projected onto it.
+func (pa Patch) ParaCoords(p Vec) (u, v float64) {
+	r := p.Subtract(pa.Corner)
+	s0 := pa.Sides[0]
+	s1 := pa.Sides[1]
+	a := s0.Dot(s0)
+	b := s0.Dot(s1)
+	c := s1.Dot(s1)
+	det := a*c - b*b
+	if math.Abs(det) < mayAsWellBeZero { // degenerate patch
+		return math.NaN(), math.NaN()
 	}
+	r0 := r.Dot(s0)
+	r1 := r.Dot(s1)
+	return (c*r0 - b*r1) / det, (a*r1 - b*r0) / det
+}
 
-	return where, false
+// ParaContains returns true iff the point, projected onto the plane, is within the parallelogram
+func (pa Patch) ParaContains(p Vec) bool {
+	return pa.ParaContainsTol(p, DefaultTolerance)
+}
+
+// ParaContainsTol is ParaContains, with points within the tolerance outside counting
+func (pa Patch) ParaContainsTol(p Vec, tol Tolerance) bool {
+	u, v := pa.ParaCoords(p)
+	eu, ev := pa.slack(tol)
+	return u >= -eu && u <= 1+eu && v >= -ev && v <= 1+ev
+}
 
+// ParaOverlapsSegment returns true iff any part of the segment from a to b, projected onto
+//   the plane, lies within the parallelogram
+func (pa Patch) ParaOverlapsSegment(a, b Vec) bool {
+	return pa.ParaOverlapsSegmentTol(a, b, DefaultTolerance)
+}
+
+// ParaOverlapsSegmentTol is ParaOverlapsSegment, with the parallelogram grown by the tolerance
+func (pa Patch) ParaOverlapsSegmentTol(a, b Vec, tol Tolerance) bool {
+	eu, ev := pa.slack(tol)
+	u0, v0 := pa.ParaCoords(a)
+	u1, v1 := pa.ParaCoords(b)
+	if math.IsNaN(u0) || math.IsNaN(u1) {
+		return false
+	}
+	// Liang-Barsky clip of the (u,v) segment against the unit square
+	t0, t1 := 0.0, 1.0
+	du := u1 - u0
+	dv := v1 - v0
+	clip := func(p, q float64) bool {
+		if math.Abs(p) < mayAsWellBeZero {
+			return q >= 0
+		}
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return false
+			}
+			if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return false
+			}
+			if r < t1 {
+				t1 = r
+			}
+		}
+		return true
+	}
+	return clip(-du, u0+eu) && clip(du, 1+eu-u0) && clip(-dv, v0+ev) && clip(dv, 1+ev-v0) && t0 <= t1
 }
@@ -96,3 +96,61 @@ func TestLine(t *testing.T) {
 	// }
 
 }
+
+func TestParaCoords(t *testing.T) {
+
+	pa := NewPatch(NewSimVec(1, 1, 0), NewSimVec(0, 0, 1), NewSimVec(2, 0, 0), NewSimVec(1, 2, 0))
+
+	u, v := pa.ParaCoords(NewSimVec(2.5, 2, 7)) // off the plane, should be projected
+	if NotApprox(u, 0.5) || NotApprox(v, 0.5) {
+		t.Errorf("ParaCoords gave %f, %f", u, v)
+	}
+	if !pa.ParaContains(NewSimVec(3.9, 2.9, 0)) {
+		t.Error("Point should be inside parallelogram")
+	}
+	if pa.ParaContains(NewSimVec(1.1, 2.9, 0)) {
+		t.Error("Point should be outside parallelogram")
+	}
+
+	if !pa.ParaOverlapsSegment(NewSimVec(0, 2, 0), NewSimVec(5, 2, 0)) {
+		t.Error("Segment crossing parallelogram should overlap")
+	}
+	if pa.ParaOverlapsSegment(NewSimVec(0, 0, 0), NewSimVec(5, 0, 0)) {
+		t.Error("Segment below parallelogram should not overlap")
+	}
+	if !pa.ParaOverlapsSegment(NewSimVec(2, 1.5, 0), NewSimVec(2.1, 1.6, 0)) {
+		t.Error("Segment inside parallelogram should overlap")
+	}
+
+}
+
+func TestPlaneLocal2D(t *testing.T) {
+
+	// Level plane: the basis is X and Y
+	pl := NewPlane(NewSimVec(1, 2, 3), Z)
+	if x, y := pl.Basis(); !sameVec(x, X) || !sameVec(y, Y) {
+		t.Errorf("Level plane basis wrong: %s %s", x, y)
+	}
+	if u, v := pl.ToLocal2D(NewSimVec(4, 6, 10)); NotApprox(u, 3) || NotApprox(v, 4) {
+		t.Errorf("Level plane local coords wrong: %f %f", u, v)
+	}
+
+	// Tilted plane: x is level, x cross y is the normal, and points go there and back
+	pl = NewPlane(NewSimVec(1, -1, 2), NewSimVec(1, 1, 1))
+	x, y := pl.Basis()
+	if NotApprox(x.Z(), 0) || NotApprox(x.Length(), 1) || NotApprox(y.Length(), 1) || !sameVec(x.Cross(y), pl.Normal) {
+		t.Errorf("Tilted plane basis wrong: %s %s", x, y)
+	}
+	p := pl.FromLocal2D(2.5, -1.5)
+	if NotApprox(p.Subtract(pl.PointOn).Dot(pl.Normal), 0) {
+		t.Errorf("Point from local coords is off the plane: %s", p)
+	}
+	if u, v := pl.ToLocal2D(p.Add(pl.Normal.Scale(7))); NotApprox(u, 2.5) || NotApprox(v, -1.5) {
+		t.Errorf("Round trip through local coords wrong: %f %f", u, v)
+	}
+
+	// Along a given direction
+	if x, y := pl.BasisAlong(Z); NotApprox(x.Dot(pl.Normal), 0) || x.Z() <= 0 || !sameVec(x.Cross(y), pl.Normal) {
+		t.Errorf("Basis along Z wrong: %s %s", x, y)
+	}
+}
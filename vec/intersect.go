@@ -0,0 +1,105 @@
+// ██╗███╗   ██╗████████╗███████╗██████╗ ███████╗███████╗ ██████╗████████╗
+// ██║████╗  ██║╚══██╔══╝██╔════╝██╔══██╗██╔════╝██╔════╝██╔════╝╚══██╔══╝
+// ██║██╔██╗ ██║   ██║   █████╗  ██████╔╝███████╗█████╗  ██║        ██║
+// ██║██║╚██╗██║   ██║   ██╔══╝  ██╔══██╗╚════██║██╔══╝  ██║        ██║
+// ██║██║ ╚████║   ██║   ███████╗██║  ██║███████║███████╗╚██████╗   ██║
+// ╚═╝╚═╝  ╚═══╝   ╚═╝   ╚══════╝╚═╝  ╚═╝╚══════╝╚══════╝ ╚═════╝   ╚═╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Approach is where two segments come closest to each other
+type Approach struct {
+	A, B     Vec     // the closest points, on the first segment and the second
+	DA, DB   float64 // how far along each line they are, from MinD to MaxD
+	Distance float64 // between A and B
+}
+
+func (ap Approach) String() string {
+	return fmt.Sprintf("Approach %s to %s, 📏%.4g", ap.A, ap.B, ap.Distance)
+}
+
+// clamp keeps x between lo and hi
+func clamp(x, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, x))
+}
+
+// ClosestApproach finds the closest points on two segments. Where the segments are parallel and
+//   overlap, it picks the middle of the overlap.
+func (seg Segment) ClosestApproach(o Segment) Approach {
+	la, lb := seg.MaxD-seg.MinD, o.MaxD-o.MinD
+	r := seg.Start().Subtract(o.Start())
+	b := seg.AlongN.Dot(o.AlongN)
+	c := seg.AlongN.Dot(r)
+	f := o.AlongN.Dot(r)
+
+	// s and t are distances from the starts of each
+	var s float64
+	if denom := 1 - b*b; denom > mayAsWellBeZero {
+		s = clamp((b*f-c)/denom, 0, la)
+	} else { // parallel, so go for the middle of where they overlap, if they do
+		s0, s1 := -c, -c+b*lb
+		lo, hi := math.Max(0, math.Min(s0, s1)), math.Min(la, math.Max(s0, s1))
+		if lo <= hi {
+			s = (lo + hi) / 2
+		}
+	}
+	t := b*s + f
+	if t < 0 {
+		t = 0
+		s = clamp(-c, 0, la)
+	} else if t > lb {
+		t = lb
+		s = clamp(b*lb-c, 0, la)
+	}
+
+	ap := Approach{DA: seg.MinD + s, DB: o.MinD + t}
+	ap.A = seg.PointOn.Add(seg.AlongN.Scale(ap.DA))
+	ap.B = o.PointOn.Add(o.AlongN.Scale(ap.DB))
+	ap.Distance = ap.A.Subtract(ap.B).Length()
+	return ap
+}
+
+// Distance is the shortest distance between two segments
+func (seg Segment) Distance(o Segment) float64 {
+	return seg.ClosestApproach(o).Distance
+}
+
+// IntersectSegment determines whether two segments meet, to within tol, and if so where:
+//   half way between their closest points
+func (seg Segment) IntersectSegment(o Segment, tol float64) (where Vec, hits bool) {
+	ap := seg.ClosestApproach(o)
+	if ap.Distance > tol {
+		return nil, false
+	}
+	return ap.A.Add(ap.B).Scale(0.5), true
+}
+
+// IntersectPolyline finds where the segment meets the polyline through the points, to within
+//   tol, in order along the segment. A hit on a corner of the polyline is only counted once.
+func (seg Segment) IntersectPolyline(pts []Vec, tol float64) []Approach {
+	hits := []Approach{}
+	for i := 1; i < len(pts); i++ {
+		if pts[i].Subtract(pts[i-1]).Length() < PlanckLength {
+			continue
+		}
+		ap := seg.ClosestApproach(NewSegment2Ends(pts[i-1], pts[i]))
+		if ap.Distance <= tol {
+			hits = append(hits, ap)
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].DA < hits[j].DA })
+	uniq := []Approach{}
+	for _, h := range hits {
+		if len(uniq) > 0 && h.A.Subtract(uniq[len(uniq)-1].A).Length() <= tol {
+			continue
+		}
+		uniq = append(uniq, h)
+	}
+	return uniq
+}
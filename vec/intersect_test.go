@@ -0,0 +1,62 @@
+package vec
+
+import (
+	"testing"
+)
+
+func TestClosestApproach(t *testing.T) {
+
+	// Crossing at right angles, one above the other
+	a := NewSegment2Ends(NewSimVec(-1, 0, 0), NewSimVec(1, 0, 0))
+	b := NewSegment2Ends(NewSimVec(0, -1, 1), NewSimVec(0, 1, 1))
+	ap := a.ClosestApproach(b)
+	if NotApprox(ap.Distance, 1) || !sameVec(ap.A, NewSimVec(0, 0, 0)) || !sameVec(ap.B, NewSimVec(0, 0, 1)) {
+		t.Errorf("Skew segments closest approach wrong: %s", ap)
+	}
+	if NotApprox(ap.DA, 1) || NotApprox(ap.DB, 1) {
+		t.Errorf("Skew segments distances along wrong: %f %f", ap.DA, ap.DB)
+	}
+
+	// Where the closest point is off the end of one
+	c := NewSegment2Ends(NewSimVec(2, -1, 0), NewSimVec(2, 1, 0))
+	if ap := a.ClosestApproach(c); NotApprox(ap.Distance, 1) || !sameVec(ap.A, NewSimVec(1, 0, 0)) {
+		t.Errorf("Closest at an end wrong: %s", ap)
+	}
+
+	// Parallel and overlapping, and parallel and apart end to end
+	d := NewSegment2Ends(NewSimVec(0, 0.5, 0), NewSimVec(3, 0.5, 0))
+	if ap := a.ClosestApproach(d); NotApprox(ap.Distance, 0.5) || !sameVec(ap.A, NewSimVec(0.5, 0, 0)) {
+		t.Errorf("Overlapping parallel segments wrong: %s", ap)
+	}
+	f := NewSegment2Ends(NewSimVec(5, 0, 0), NewSimVec(3, 0, 0))
+	if ap := a.ClosestApproach(f); NotApprox(ap.Distance, 2) || !sameVec(ap.B, NewSimVec(3, 0, 0)) {
+		t.Errorf("End to end segments wrong: %s", ap)
+	}
+
+	if _, ok := a.IntersectSegment(b, 0.1); ok {
+		t.Errorf("Segments a distance apart intersected")
+	}
+	if w, ok := a.IntersectSegment(NewSegment2Ends(NewSimVec(0.5, -1, -1), NewSimVec(0.5, 1, 1)), 1e-9); !ok || !sameVec(w, NewSimVec(0.5, 0, 0)) {
+		t.Errorf("Crossing segments missed: %v %v", ok, w)
+	}
+}
+
+func TestIntersectPolyline(t *testing.T) {
+
+	zig := []Vec{NewSimVec(0, -1, 0), NewSimVec(1, 1, 0), NewSimVec(2, -1, 0), NewSimVec(3, 1, 0)}
+	seg := NewSegment2Ends(NewSimVec(4, 0, 0), NewSimVec(-1, 0, 0))
+	hits := seg.IntersectPolyline(zig, 1e-9)
+	if len(hits) != 3 {
+		t.Fatalf("Should cross the zigzag 3 times, not %d", len(hits))
+	}
+	for i, x := range []float64{2.5, 1.5, 0.5} {
+		if !sameVec(hits[i].A, NewSimVec(x, 0, 0)) {
+			t.Errorf("Hit %d should be at x=%f, not %s", i, x, hits[i].A)
+		}
+	}
+
+	// Through a corner counts once
+	if hits := NewSegment2Ends(NewSimVec(1, 2, 0), NewSimVec(1, 0, 0)).IntersectPolyline(zig, 1e-9); len(hits) != 1 {
+		t.Errorf("Through a corner should hit once, not %d", len(hits))
+	}
+}
@@ -0,0 +1,166 @@
+// ███╗   ███╗ █████╗ ████████╗██████╗ ██╗██╗  ██╗
+// ████╗ ████║██╔══██╗╚══██╔══╝██╔══██╗██║╚██╗██╔╝
+// ██╔████╔██║███████║   ██║   ██████╔╝██║ ╚███╔╝
+// ██║╚██╔╝██║██╔══██║   ██║   ██╔══██╗██║ ██╔██╗
+// ██║ ╚═╝ ██║██║  ██║   ██║   ██║  ██║██║██╔╝ ██╗
+// ╚═╝     ╚═╝╚═╝  ╚═╝   ╚═╝   ╚═╝  ╚═╝╚═╝╚═╝  ╚═╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// Matrix4 is a 3D transform in homogeneous coordinates, [row][column], acting on column
+//   vectors, so the translation is in the last column
+type Matrix4 [4][4]float64
+
+// Identity is the transform which does nothing
+func Identity() Matrix4 {
+	return Matrix4{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+}
+
+// Translation moves by the vector
+func Translation(by Vec) Matrix4 {
+	m := Identity()
+	m[0][3], m[1][3], m[2][3] = by.X(), by.Y(), by.Z()
+	return m
+}
+
+// Scaling scales along each axis about the origin
+func Scaling(sx, sy, sz float64) Matrix4 {
+	m := Identity()
+	m[0][0], m[1][1], m[2][2] = sx, sy, sz
+	return m
+}
+
+// RotationX rotates by a about the X axis, anticlockwise looking down it
+func RotationX(a Radians) Matrix4 {
+	c, s := Cos(a), Sin(a)
+	return Matrix4{{1, 0, 0, 0}, {0, c, -s, 0}, {0, s, c, 0}, {0, 0, 0, 1}}
+}
+
+// RotationY rotates by a about the Y axis, anticlockwise looking down it
+func RotationY(a Radians) Matrix4 {
+	c, s := Cos(a), Sin(a)
+	return Matrix4{{c, 0, s, 0}, {0, 1, 0, 0}, {-s, 0, c, 0}, {0, 0, 0, 1}}
+}
+
+// RotationZ rotates by a about the Z axis, anticlockwise looking down it, as Vec.RotateZ does
+func RotationZ(a Radians) Matrix4 {
+	c, s := Cos(a), Sin(a)
+	return Matrix4{{c, -s, 0, 0}, {s, c, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+}
+
+// RotationAbout rotates by a about the axis through the origin, anticlockwise looking down it
+func RotationAbout(axis Vec, a Radians) Matrix4 {
+	u := axis.Normalized()
+	x, y, z := u.X(), u.Y(), u.Z()
+	c, s := Cos(a), Sin(a)
+	t := 1 - c
+	return Matrix4{
+		{t*x*x + c, t*x*y - s*z, t*x*z + s*y, 0},
+		{t*x*y + s*z, t*y*y + c, t*y*z - s*x, 0},
+		{t*x*z - s*y, t*y*z + s*x, t*z*z + c, 0},
+		{0, 0, 0, 1}}
+}
+
+// Frame is the transform from a local frame to the world: the local origin goes to o and the
+//   local axes to x, y and z, which should be square to each other and length 1
+func Frame(o, x, y, z Vec) Matrix4 {
+	return Matrix4{
+		{x.X(), y.X(), z.X(), o.X()},
+		{x.Y(), y.Y(), z.Y(), o.Y()},
+		{x.Z(), y.Z(), z.Z(), o.Z()},
+		{0, 0, 0, 1}}
+}
+
+// Mul is the transform doing n then m
+func (m Matrix4) Mul(n Matrix4) Matrix4 {
+	var r Matrix4
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				r[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return r
+}
+
+// Then is the transform doing m then n, so transforms read in order: a.Then(b).Then(c)
+func (m Matrix4) Then(n Matrix4) Matrix4 {
+	return n.Mul(m)
+}
+
+// Inverse undoes the transform, by Gauss-Jordan elimination. False if it cannot be undone.
+func (m Matrix4) Inverse() (Matrix4, bool) {
+	a := m
+	r := Identity()
+	for c := 0; c < 4; c++ {
+		pivot := c // the biggest in the column, for accuracy
+		for i := c + 1; i < 4; i++ {
+			if math.Abs(a[i][c]) > math.Abs(a[pivot][c]) {
+				pivot = i
+			}
+		}
+		if math.Abs(a[pivot][c]) < PlanckLength {
+			return Identity(), false
+		}
+		a[c], a[pivot] = a[pivot], a[c]
+		r[c], r[pivot] = r[pivot], r[c]
+		f := 1 / a[c][c]
+		for j := 0; j < 4; j++ {
+			a[c][j] *= f
+			r[c][j] *= f
+		}
+		for i := 0; i < 4; i++ {
+			if i == c || a[i][c] == 0 {
+				continue
+			}
+			g := a[i][c]
+			for j := 0; j < 4; j++ {
+				a[i][j] -= g * a[c][j]
+				r[i][j] -= g * r[c][j]
+			}
+		}
+	}
+	return r, true
+}
+
+// Point transforms v as a position, so it is moved by any translation
+func (m Matrix4) Point(v Vec) Vec {
+	x, y, z := v.X(), v.Y(), v.Z()
+	w := m[3][0]*x + m[3][1]*y + m[3][2]*z + m[3][3]
+	if w == 0 {
+		w = 1
+	}
+	return v.New(
+		(m[0][0]*x+m[0][1]*y+m[0][2]*z+m[0][3])/w,
+		(m[1][0]*x+m[1][1]*y+m[1][2]*z+m[1][3])/w,
+		(m[2][0]*x+m[2][1]*y+m[2][2]*z+m[2][3])/w)
+}
+
+// Direction transforms v as a direction, which translation does not change
+func (m Matrix4) Direction(v Vec) Vec {
+	x, y, z := v.X(), v.Y(), v.Z()
+	return v.New(
+		m[0][0]*x+m[0][1]*y+m[0][2]*z,
+		m[1][0]*x+m[1][1]*y+m[1][2]*z,
+		m[2][0]*x+m[2][1]*y+m[2][2]*z)
+}
+
+// String shows the rows
+func (m Matrix4) String() string {
+	s := ""
+	for _, row := range m {
+		s += fmt.Sprintf("[%8.3f %8.3f %8.3f %8.3f]\n", row[0], row[1], row[2], row[3])
+	}
+	return s
+}
+
+// Apply transforms the vector as a point
+func (v SimVec) Apply(m Matrix4) Vec {
+	return m.Point(v)
+}
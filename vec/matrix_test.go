@@ -0,0 +1,78 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func sameVec(a, b Vec) bool {
+	return !NotApprox(a.X(), b.X()) && !NotApprox(a.Y(), b.Y()) && !NotApprox(a.Z(), b.Z())
+}
+
+func TestMatrix4(t *testing.T) {
+
+	p := NewSimVec(1, 2, 3)
+
+	if !sameVec(p.Apply(Identity()), p) {
+		t.Errorf("Identity moved the point")
+	}
+	if !sameVec(p.Apply(Translation(NewSimVec(1, -1, 2))), NewSimVec(2, 1, 5)) {
+		t.Errorf("Translation failed")
+	}
+	if !sameVec(Translation(NewSimVec(1, -1, 2)).Direction(p), p) {
+		t.Errorf("Translation moved a direction")
+	}
+	if !sameVec(p.Apply(RotationZ(Deg90)), p.RotateZ(Deg90)) {
+		t.Errorf("RotationZ does not match RotateZ")
+	}
+	if !sameVec(X.Apply(RotationY(Deg90)), NewSimVec(0, 0, -1)) {
+		t.Errorf("RotationY failed")
+	}
+	if !sameVec(Y.Apply(RotationX(Deg90)), Z) {
+		t.Errorf("RotationX failed")
+	}
+	if !sameVec(p.Apply(RotationAbout(NewSimVec(0, 0, 2), Deg60)), p.RotateZ(Deg60)) {
+		t.Errorf("RotationAbout Z does not match RotateZ")
+	}
+	if !sameVec(p.Apply(Scaling(2, 3, -1)), NewSimVec(2, 6, -3)) {
+		t.Errorf("Scaling failed")
+	}
+
+	// Then goes in order, Mul the other way
+	m := RotationZ(Deg90).Then(Translation(X))
+	if !sameVec(X.Apply(m), NewSimVec(1, 1, 0)) {
+		t.Errorf("Then is in the wrong order")
+	}
+	if !sameVec(X.Apply(Translation(X).Mul(RotationZ(Deg90))), NewSimVec(1, 1, 0)) {
+		t.Errorf("Mul is in the wrong order")
+	}
+
+	// Inverse undoes a general transform
+	g := Scaling(2, 0.5, 3).Then(RotationAbout(NewSimVec(1, 1, 1), 0.7)).Then(Translation(NewSimVec(-4, 5, 6)))
+	inv, ok := g.Inverse()
+	if !ok {
+		t.Fatalf("Inverse failed")
+	}
+	if !sameVec(p.Apply(g).Apply(inv), p) {
+		t.Errorf("Inverse does not undo the transform")
+	}
+	if _, ok := Scaling(1, 0, 1).Inverse(); ok {
+		t.Errorf("Inverse of a flattening succeeded")
+	}
+
+	// Frame puts local axes into the world
+	f := Frame(NewSimVec(10, 0, 0), Y, Z, X)
+	if !sameVec(NewSimVec(1, 2, 3).Apply(f), NewSimVec(13, 1, 2)) {
+		t.Errorf("Frame failed")
+	}
+
+	// Planes keep their normals square to them under scaling
+	pl := NewPlane(Origin, NewSimVec(1, 1, 0))
+	pl.Transform(Scaling(1, 2, 1))
+	if NotApprox(pl.Normal.Dot(NewSimVec(1, -2, 0).Normalized()), 0) || NotApprox(pl.Normal.Length(), 1) {
+		t.Errorf("Plane transform failed: %s", pl.Normal)
+	}
+	if NotApprox(math.Abs(pl.Normal.Z()), 0) {
+		t.Errorf("Plane normal left the XY plane")
+	}
+}
@@ -0,0 +1,314 @@
+// ██████╗  ██████╗ ██╗  ██╗   ██╗ ██████╗  ██████╗ ███╗   ██╗
+// ██╔══██╗██╔═══██╗██║  ╚██╗ ██╔╝██╔════╝ ██╔═══██╗████╗  ██║
+// ██████╔╝██║   ██║██║   ╚████╔╝ ██║  ███╗██║   ██║██╔██╗ ██║
+// ██╔═══╝ ██║   ██║██║    ╚██╔╝  ██║   ██║██║   ██║██║╚██╗██║
+// ██║     ╚██████╔╝███████╗██║   ╚██████╔╝╚██████╔╝██║ ╚████║
+// ╚═╝      ╚═════╝ ╚══════╝╚═╝    ╚═════╝  ╚═════╝ ╚═╝  ╚═══╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Polygon is a flat loop of points, with any holes in it. The outline may go round either way;
+//   its normal is by the right hand rule round it, and the holes are taken to be in its plane.
+type Polygon struct {
+	Points []Vec
+	Holes  [][]Vec
+}
+
+// NewPolygon makes one without holes
+func NewPolygon(pts ...Vec) Polygon {
+	return Polygon{Points: pts}
+}
+
+// AddHole adds a hole through it, which may go round either way
+func (pg *Polygon) AddHole(pts ...Vec) *Polygon {
+	pg.Holes = append(pg.Holes, pts)
+	return pg
+}
+
+func (pg Polygon) String() string {
+	return fmt.Sprintf("Polygon of %d points and %d holes, area %.4g", len(pg.Points), len(pg.Holes), pg.Area())
+}
+
+// newell is twice the vector area of the loop, by Newell's method, which copes with loops
+//   which are not quite flat
+func newell(pts []Vec) SimVec {
+	var x, y, z float64
+	for i, a := range pts {
+		b := pts[(i+1)%len(pts)]
+		x += (a.Y() - b.Y()) * (a.Z() + b.Z())
+		y += (a.Z() - b.Z()) * (a.X() + b.X())
+		z += (a.X() - b.X()) * (a.Y() + b.Y())
+	}
+	return NewSimVec(x, y, z)
+}
+
+// Normal is the unit normal, by the right hand rule round the outline
+func (pg Polygon) Normal() Vec {
+	return newell(pg.Points).Normalized()
+}
+
+// Area is that of the outline less the holes
+func (pg Polygon) Area() float64 {
+	a := newell(pg.Points).Length() / 2
+	for _, h := range pg.Holes {
+		a -= newell(h).Length() / 2
+	}
+	return a
+}
+
+// Centroid is the center of area, allowing for the holes
+func (pg Polygon) Centroid() Vec {
+	var sum Vec = Zero
+	total := 0.0
+	for _, t := range pg.Triangulate() {
+		a := t[1].Subtract(t[0]).Cross(t[2].Subtract(t[0])).Length() / 2
+		sum = sum.Add(t[0].Add(t[1]).Add(t[2]).Scale(a / 3))
+		total += a
+	}
+	if total < mayAsWellBeZero {
+		return Zero
+	}
+	return sum.Scale(1 / total)
+}
+
+// pt2 is a point of the polygon flattened into 2D, remembering where it came from
+type pt2 struct {
+	x, y float64
+	at   Vec
+}
+
+// turn is positive if o, a, b turn anticlockwise
+func turn(o, a, b pt2) float64 {
+	return (a.x-o.x)*(b.y-o.y) - (a.y-o.y)*(b.x-o.x)
+}
+
+// area2 is twice the signed area of the loop, positive if anticlockwise
+func area2(ps []pt2) float64 {
+	a := 0.0
+	for i, p := range ps {
+		q := ps[(i+1)%len(ps)]
+		a += p.x*q.y - q.x*p.y
+	}
+	return a
+}
+
+// flatten drops the points onto whichever of the axis planes is most nearly parallel to
+//   the normal's plane, so the winding seen looking down the normal is kept
+func flatten(pts []Vec, n Vec) []pt2 {
+	k := 2
+	if ax, ay, az := math.Abs(n.X()), math.Abs(n.Y()), math.Abs(n.Z()); ax >= ay && ax >= az {
+		k = 0
+	} else if ay >= az {
+		k = 1
+	}
+	comps := func(v Vec) [3]float64 { return [3]float64{v.X(), v.Y(), v.Z()} }
+	sign := 1.0
+	if comps(n)[k] < 0 {
+		sign = -1
+	}
+	ps := make([]pt2, len(pts))
+	for i, v := range pts {
+		c := comps(v)
+		ps[i] = pt2{x: sign * c[(k+1)%3], y: c[(k+2)%3], at: v}
+	}
+	return ps
+}
+
+// insideLoop is true if p is inside the loop, by counting crossings
+func insideLoop(p pt2, loop []pt2) bool {
+	in := false
+	for i, a := range loop {
+		b := loop[(i+1)%len(loop)]
+		if (a.y > p.y) != (b.y > p.y) && p.x < a.x+(p.y-a.y)*(b.x-a.x)/(b.y-a.y) {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Contains is true if the point, looked at along the normal, is inside the outline and not in
+//   a hole
+func (pg Polygon) Contains(p Vec) bool {
+	n := pg.Normal()
+	q := flatten([]Vec{p}, n)[0]
+	if !insideLoop(q, flatten(pg.Points, n)) {
+		return false
+	}
+	for _, h := range pg.Holes {
+		if insideLoop(q, flatten(h, n)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Triangulate cuts the polygon into triangles by clipping ears, after joining each hole to the
+//   outline by a bridge. The triangles go round the same way as the outline.
+func (pg Polygon) Triangulate() [][3]Vec {
+	if len(pg.Points) < 3 {
+		return nil
+	}
+	n := pg.Normal()
+	outer := flatten(pg.Points, n)
+	if area2(outer) < 0 {
+		outer = reversed(outer)
+	}
+	holes := [][]pt2{}
+	for _, h := range pg.Holes {
+		if len(h) < 3 {
+			continue
+		}
+		hole := flatten(h, n)
+		if area2(hole) > 0 {
+			hole = reversed(hole)
+		}
+		holes = append(holes, hole)
+	}
+	// Bridge in the holes from the right, so each bridge only crosses into the outline as it is
+	sort.SliceStable(holes, func(i, j int) bool { return maxX(holes[i]) > maxX(holes[j]) })
+	for _, h := range holes {
+		outer = bridge(outer, h)
+	}
+	return clipEars(outer)
+}
+
+// reversed is the loop going round the other way
+func reversed(ps []pt2) []pt2 {
+	r := make([]pt2, len(ps))
+	for i, p := range ps {
+		r[len(ps)-1-i] = p
+	}
+	return r
+}
+
+// maxX is how far right the loop goes
+func maxX(ps []pt2) float64 {
+	return ps[rightmost(ps)].x
+}
+
+// rightmost is the index of the rightmost point
+func rightmost(ps []pt2) int {
+	m := 0
+	for i, p := range ps {
+		if p.x > ps[m].x {
+			m = i
+		}
+	}
+	return m
+}
+
+// bridge joins a clockwise hole into the anticlockwise outline, by going from the hole's
+//   rightmost point to a point on the outline it can see, round the hole, and back
+func bridge(outer, hole []pt2) []pt2 {
+	mi := rightmost(hole)
+	m := hole[mi]
+
+	// Find the nearest edge of the outline to the right of m, level with it
+	pi, best := -1, math.Inf(1)
+	for i, a := range outer {
+		b := outer[(i+1)%len(outer)]
+		if (a.y > m.y) == (b.y > m.y) || a.y == b.y {
+			continue
+		}
+		x := a.x + (m.y-a.y)*(b.x-a.x)/(b.y-a.y)
+		if x >= m.x && x < best {
+			best = x
+			pi = i
+			if b.x > a.x {
+				pi = (i + 1) % len(outer)
+			}
+		}
+	}
+	if pi < 0 {
+		return outer // the hole is not inside
+	}
+
+	// A point of the outline inside the triangle from m to the hit and the chosen end could
+	//   block the view, so pick the one of those at the least angle from m instead
+	hit := pt2{x: best, y: m.y}
+	p := outer[pi]
+	tri := []pt2{m, hit, p}
+	if area2(tri) < 0 {
+		tri = reversed(tri)
+	}
+	bestAngle, end := math.Inf(1), pi
+	for i, r := range outer {
+		if i == end || turn(outer[(i+len(outer)-1)%len(outer)], r, outer[(i+1)%len(outer)]) > 0 {
+			continue // only reflex points can block
+		}
+		if turn(tri[0], tri[1], r) >= 0 && turn(tri[1], tri[2], r) >= 0 && turn(tri[2], tri[0], r) >= 0 {
+			if a := math.Abs(math.Atan2(r.y-m.y, r.x-m.x)); a < bestAngle {
+				bestAngle = a
+				pi = i
+			}
+		}
+	}
+
+	joined := make([]pt2, 0, len(outer)+len(hole)+2)
+	joined = append(joined, outer[:pi+1]...)
+	for i := 0; i <= len(hole); i++ {
+		joined = append(joined, hole[(mi+i)%len(hole)])
+	}
+	joined = append(joined, outer[pi:]...)
+	return joined
+}
+
+// clipEars triangulates an anticlockwise loop, which may touch itself along bridges
+func clipEars(loop []pt2) [][3]Vec {
+	minX, maxX, minY, maxY := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, p := range loop {
+		minX, maxX = math.Min(minX, p.x), math.Max(maxX, p.x)
+		minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+	}
+	size := math.Max(maxX-minX, maxY-minY)
+	eps := mayAsWellBeZero * size * size
+	same := func(a, b pt2) bool { return a.x == b.x && a.y == b.y }
+
+	tris := [][3]Vec{}
+	ps := append([]pt2{}, loop...)
+	for len(ps) > 3 {
+		clipped := false
+		for i := range ps {
+			a, b, c := ps[(i+len(ps)-1)%len(ps)], ps[i], ps[(i+1)%len(ps)]
+			t := turn(a, b, c)
+			if math.Abs(t) <= eps { // no triangle here, just a point on a straight bit
+				ps = append(ps[:i], ps[i+1:]...)
+				clipped = true
+				break
+			}
+			if t < 0 {
+				continue
+			}
+			ear := true
+			for j, r := range ps {
+				if j == i || same(r, a) || same(r, b) || same(r, c) {
+					continue
+				}
+				if turn(a, b, r) >= 0 && turn(b, c, r) >= 0 && turn(c, a, r) >= 0 {
+					ear = false
+					break
+				}
+			}
+			if ear {
+				tris = append(tris, [3]Vec{a.at, b.at, c.at})
+				ps = append(ps[:i], ps[i+1:]...)
+				clipped = true
+				break
+			}
+		}
+		if !clipped {
+			fmt.Printf("ERROR: Polygon could not be triangulated, %d points left over\n", len(ps))
+			return tris
+		}
+	}
+	if len(ps) == 3 && math.Abs(turn(ps[0], ps[1], ps[2])) > eps {
+		tris = append(tris, [3]Vec{ps[0].at, ps[1].at, ps[2].at})
+	}
+	return tris
+}
@@ -0,0 +1,63 @@
+package vec
+
+import (
+	"testing"
+)
+
+// trisArea is the total area of the triangles, and false if any goes round against the normal
+func trisArea(tris [][3]Vec, n Vec) (float64, bool) {
+	a, ok := 0.0, true
+	for _, t := range tris {
+		c := t[1].Subtract(t[0]).Cross(t[2].Subtract(t[0]))
+		if c.Dot(n) < 0 {
+			ok = false
+		}
+		a += c.Length() / 2
+	}
+	return a, ok
+}
+
+func TestPolygon(t *testing.T) {
+
+	// An L, upright in the xz plane, going round so its normal is +y
+	l := NewPolygon(NewSimVec(0, 0, 0), NewSimVec(0, 0, 2), NewSimVec(1, 0, 2), NewSimVec(1, 0, 1), NewSimVec(2, 0, 1), NewSimVec(2, 0, 0))
+	if !sameVec(l.Normal(), NewSimVec(0, 1, 0)) {
+		t.Errorf("L normal wrong: %s", l.Normal())
+	}
+	if NotApprox(l.Area(), 3) {
+		t.Errorf("L area should be 3, not %f", l.Area())
+	}
+	if !sameVec(l.Centroid(), NewSimVec(5.0/6, 0, 5.0/6)) {
+		t.Errorf("L centroid wrong: %s", l.Centroid())
+	}
+	if !l.Contains(NewSimVec(0.5, 0, 1.5)) || l.Contains(NewSimVec(1.5, 0, 1.5)) {
+		t.Errorf("L contains wrong")
+	}
+	tris := l.Triangulate()
+	if a, ok := trisArea(tris, l.Normal()); len(tris) != 4 || NotApprox(a, 3) || !ok {
+		t.Errorf("L triangulated wrong: %d triangles, area %f, wound right %v", len(tris), a, ok)
+	}
+
+	// A square with a square hole off center, both going round anticlockwise
+	sq := NewPolygon(NewSimVec(0, 0, 1), NewSimVec(4, 0, 1), NewSimVec(4, 4, 1), NewSimVec(0, 4, 1))
+	sq.AddHole(NewSimVec(1, 1, 1), NewSimVec(2, 1, 1), NewSimVec(2, 2, 1), NewSimVec(1, 2, 1))
+	if NotApprox(sq.Area(), 15) {
+		t.Errorf("Square with hole area should be 15, not %f", sq.Area())
+	}
+	if !sameVec(sq.Centroid(), NewSimVec(30.5/15, 30.5/15, 1)) {
+		t.Errorf("Square with hole centroid wrong: %s", sq.Centroid())
+	}
+	if sq.Contains(NewSimVec(1.5, 1.5, 0)) || !sq.Contains(NewSimVec(3, 3, 5)) {
+		t.Errorf("Square with hole contains wrong")
+	}
+	tris = sq.Triangulate()
+	if a, ok := trisArea(tris, Z); NotApprox(a, 15) || !ok {
+		t.Errorf("Square with hole triangulated wrong: area %f, wound right %v", a, ok)
+	}
+
+	// Two holes
+	sq.AddHole(NewSimVec(3, 3, 1), NewSimVec(3, 2.5, 1), NewSimVec(3.5, 3, 1))
+	if a, ok := trisArea(sq.Triangulate(), Z); NotApprox(a, 14.875) || !ok {
+		t.Errorf("Square with two holes triangulated wrong: area %f, wound right %v", a, ok)
+	}
+}
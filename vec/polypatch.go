@@ -0,0 +1,175 @@
+// ██████╗  ██████╗ ██╗  ██╗   ██╗██████╗  █████╗ ████████╗ ██████╗██╗  ██╗
+// ██╔══██╗██╔═══██╗██║  ╚██╗ ██╔╝██╔══██╗██╔══██╗╚══██╔══╝██╔════╝██║  ██║
+// ██████╔╝██║   ██║██║   ╚████╔╝ ██████╔╝███████║   ██║   ██║     ███████║
+// ██╔═══╝ ██║   ██║██║    ╚██╔╝  ██╔═══╝ ██╔══██║   ██║   ██║     ██╔══██║
+// ██║     ╚██████╔╝███████╗██║   ██║     ██║  ██║   ██║   ╚██████╗██║  ██║
+// ╚═╝      ╚═════╝ ╚══════╝╚═╝   ╚═╝     ╚═╝  ╚═╝   ╚═╝    ╚═════╝╚═╝  ╚═╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// PolyPatch is a convex flat area bounded by any number of corners, going round anticlockwise
+//   seen from the side the normal points to
+type PolyPatch struct {
+	Plane
+	Points []Vec
+}
+
+// NewPolyPatch makes one with the corners in order; its normal is by the right hand rule round them
+func NewPolyPatch(pts ...Vec) PolyPatch {
+	pp := PolyPatch{Points: pts}
+	if len(pts) > 0 {
+		pp.Plane = NewPlane(pts[0], newell(pts))
+	}
+	return pp
+}
+
+// RegularPolyPatch is a regular polygon of n corners round the center, in the plane square to
+//   normal, close enough to a circle of the radius for a round vent if n is large
+func RegularPolyPatch(center, normal Vec, radius float64, n int) PolyPatch {
+	x, y := NewPlane(center, normal).Basis()
+	pts := []Vec{}
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		pts = append(pts, center.Add(x.Scale(radius*math.Cos(a))).Add(y.Scale(radius*math.Sin(a))))
+	}
+	return NewPolyPatch(pts...)
+}
+
+// ArchPolyPatch is an arched opening: straight sides from the bottom corner, along wide and up
+//   high, under a half circle across the top made of n straight pieces. Its normal is
+//   wide cross high.
+func ArchPolyPatch(corner, wide, high Vec, n int) PolyPatch {
+	r := wide.Length() / 2
+	across, up := wide.Normalized(), high.Normalized()
+	mid := corner.Add(high).Add(wide.Scale(0.5))
+	pts := []Vec{corner, corner.Add(wide)}
+	for i := 0; i <= n; i++ {
+		a := math.Pi * float64(i) / float64(n)
+		pts = append(pts, mid.Add(across.Scale(r*math.Cos(a))).Add(up.Scale(r*math.Sin(a))))
+	}
+	return NewPolyPatch(pts...)
+}
+
+func (pp PolyPatch) String() string {
+	return fmt.Sprintf("PolyPatch of %d corners, normal %s", len(pp.Points), pp.Normal)
+}
+
+// Polygon is the same outline as a Polygon
+func (pp PolyPatch) Polygon() Polygon {
+	return NewPolygon(pp.Points...)
+}
+
+// Translate moves it
+func (pp *PolyPatch) Translate(by Vec) *PolyPatch {
+	pp.Plane.Translate(by)
+	for i, p := range pp.Points {
+		pp.Points[i] = p.Add(by)
+	}
+	return pp
+}
+
+// Transform moves it by a general transform
+func (pp *PolyPatch) Transform(m Matrix4) *PolyPatch {
+	for i, p := range pp.Points {
+		pp.Points[i] = m.Point(p)
+	}
+	*pp = NewPolyPatch(pp.Points...)
+	return pp
+}
+
+// inward is the unit vector in the plane square to the side from a to b, pointing inside
+func (pp PolyPatch) inward(a, b Vec) Vec {
+	return pp.Normal.Cross(b.Subtract(a)).Normalized()
+}
+
+// Contains is true if the point, projected onto the plane, is inside the outline
+func (pp PolyPatch) Contains(p Vec) bool {
+	return pp.ContainsTol(p, DefaultTolerance)
+}
+
+// ContainsTol is Contains, with points within the tolerance outside counting
+func (pp PolyPatch) ContainsTol(p Vec, tol Tolerance) bool {
+	if len(pp.Points) < 3 {
+		return false
+	}
+	for i, a := range pp.Points {
+		b := pp.Points[(i+1)%len(pp.Points)]
+		if tol.Sign(p.Subtract(a).Dot(pp.inward(a, b))) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectSegment determines whether the segment passes through the outline, and if so where
+func (pp PolyPatch) IntersectSegment(s Segment) (where Vec, hits bool) {
+	return pp.IntersectSegmentTol(s, DefaultTolerance)
+}
+
+// IntersectSegmentTol is IntersectSegment, with hits within the tolerance outside counting
+func (pp PolyPatch) IntersectSegmentTol(s Segment, tol Tolerance) (where Vec, hits bool) {
+	whu, anyHit := pp.Plane.IntersectSegmentTol(s, tol)
+	if !anyHit || !pp.ContainsTol(whu, tol) {
+		return where, false
+	}
+	return whu, true
+}
+
+// OverlapsSegment is true if any part of the segment from a to b, projected onto the plane,
+//   is inside the outline
+func (pp PolyPatch) OverlapsSegment(a, b Vec) bool {
+	return pp.OverlapsSegmentTol(a, b, DefaultTolerance)
+}
+
+// OverlapsSegmentTol is OverlapsSegment with the outline grown by the tolerance, clipping the
+//   segment against each side in turn
+func (pp PolyPatch) OverlapsSegmentTol(a, b Vec, tol Tolerance) bool {
+	if len(pp.Points) < 3 {
+		return false
+	}
+	t0, t1 := 0.0, 1.0
+	d := b.Subtract(a)
+	for i, c := range pp.Points {
+		in := pp.inward(c, pp.Points[(i+1)%len(pp.Points)])
+		q := a.Subtract(c).Dot(in) + tol.Length // how far inside the start is
+		p := d.Dot(in)                          // how fast it goes in
+		if math.Abs(p) < mayAsWellBeZero {
+			if q < 0 {
+				return false
+			}
+			continue
+		}
+		if r := -q / p; p > 0 {
+			t0 = math.Max(t0, r)
+		} else {
+			t1 = math.Min(t1, r)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+// Walls are the sides of the prism swept from the outline along its normal for depth, as
+//   patches whose normals point inside it, like the walls of a Cutter
+func (pp PolyPatch) Walls(depth float64) []Patch {
+	along := pp.Normal.Scale(depth)
+	ws := []Patch{}
+	for i, a := range pp.Points {
+		b := pp.Points[(i+1)%len(pp.Points)]
+		ws = append(ws, NewPatch(a, pp.inward(a, b), b.Subtract(a), along))
+	}
+	return ws
+}
+
+// PrismContains is true if the point is inside the prism swept from the outline along its
+//   normal for depth
+func (pp PolyPatch) PrismContains(p Vec, depth float64, tol Tolerance) bool {
+	return pp.ContainsTol(p, tol) && tol.Within(p.Subtract(pp.PointOn).Dot(pp.Normal), 0, depth)
+}
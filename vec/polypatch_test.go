@@ -0,0 +1,57 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolyPatch(t *testing.T) {
+
+	// A hexagon of radius 1 round (0,0,2), facing up
+	hex := RegularPolyPatch(NewSimVec(0, 0, 2), Z, 1, 6)
+	if !sameVec(hex.Normal, Z) || NotApprox(hex.Polygon().Area(), 3*math.Sqrt(3)/2) {
+		t.Errorf("Hexagon wrong: %s", hex)
+	}
+	if !hex.Contains(NewSimVec(0.8, 0, 7)) || hex.Contains(NewSimVec(0, 0.9, 2)) {
+		t.Errorf("Hexagon contains wrong")
+	}
+	if !hex.ContainsTol(NewSimVec(0, math.Sqrt(3)/2+0.0005, 2), Tolerance{Length: 0.001}) {
+		t.Errorf("Hexagon contains within tolerance wrong")
+	}
+
+	down := NewSegment2Ends(NewSimVec(0.5, 0.2, 3), NewSimVec(0.5, 0.2, 1))
+	if w, hit := hex.IntersectSegment(down); !hit || !sameVec(w, NewSimVec(0.5, 0.2, 2)) {
+		t.Errorf("Segment through the hexagon missed: %v %v", hit, w)
+	}
+	if _, hit := hex.IntersectSegment(NewSegment2Ends(NewSimVec(1.5, 0, 3), NewSimVec(1.5, 0, 1))); hit {
+		t.Errorf("Segment beside the hexagon hit")
+	}
+
+	if !hex.OverlapsSegment(NewSimVec(-3, 0, 0), NewSimVec(3, 0, 0)) || hex.OverlapsSegment(NewSimVec(-3, 1, 0), NewSimVec(3, 1, 0)) {
+		t.Errorf("Hexagon overlaps segment wrong")
+	}
+
+	// An arch 2 wide with 1 high sides, in the xz plane facing -y
+	arch := ArchPolyPatch(Origin, NewSimVec(2, 0, 0), NewSimVec(0, 0, 1), 32)
+	if !sameVec(arch.Normal, NewSimVec(0, -1, 0)) {
+		t.Errorf("Arch normal wrong: %s", arch.Normal)
+	}
+	if !arch.Contains(NewSimVec(1, 0, 1.95)) || arch.Contains(NewSimVec(0.1, 0, 1.9)) {
+		t.Errorf("Arch contains wrong")
+	}
+
+	// The walls of the prism face inwards
+	for i, w := range hex.Walls(0.5) {
+		if !w.NormalSide(NewSimVec(0, 0, 2.25)) {
+			t.Errorf("Wall %d faces out", i)
+		}
+	}
+	if !hex.PrismContains(NewSimVec(0, 0, 2.4), 0.5, Exact) || hex.PrismContains(NewSimVec(0, 0, 2.6), 0.5, Exact) {
+		t.Errorf("Prism contains wrong")
+	}
+
+	hex.Transform(Translation(NewSimVec(1, 0, 0)))
+	if !hex.Contains(NewSimVec(1.8, 0, 2)) || !sameVec(hex.Normal, Z) {
+		t.Errorf("Transformed hexagon wrong: %s", hex)
+	}
+}
@@ -0,0 +1,144 @@
+//  ██████╗ ██╗   ██╗ █████╗ ████████╗███████╗██████╗ ███╗   ██╗██╗ ██████╗ ███╗   ██╗
+// ██╔═══██╗██║   ██║██╔══██╗╚══██╔══╝██╔════╝██╔══██╗████╗  ██║██║██╔═══██╗████╗  ██║
+// ██║   ██║██║   ██║███████║   ██║   █████╗  ██████╔╝██╔██╗ ██║██║██║   ██║██╔██╗ ██║
+// ██║▄▄ ██║██║   ██║██╔══██║   ██║   ██╔══╝  ██╔══██╗██║╚██╗██║██║██║   ██║██║╚██╗██║
+// ╚██████╔╝╚██████╔╝██║  ██║   ██║   ███████╗██║  ██║██║ ╚████║██║╚██████╔╝██║ ╚████║
+//  ╚══▀▀═╝  ╚═════╝ ╚═╝  ╚═╝   ╚═╝   ╚══════╝╚═╝  ╚═╝╚═╝  ╚═══╝╚═╝ ╚═════╝ ╚═╝  ╚═══╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// Quaternion is a rotation, W the real part and X, Y, Z the imaginary. Rotations have length 1.
+type Quaternion struct {
+	W, X, Y, Z float64
+}
+
+// NoRotation is the quaternion which leaves everything where it is
+var NoRotation = Quaternion{W: 1}
+
+// FromAxisAngle is the rotation by a about the axis, anticlockwise looking down it
+func FromAxisAngle(axis Vec, a Radians) Quaternion {
+	if axis.Length() < PlanckLength {
+		return NoRotation
+	}
+	u := axis.Normalized()
+	s := Sin(a / 2)
+	return Quaternion{W: Cos(a / 2), X: u.X() * s, Y: u.Y() * s, Z: u.Z() * s}
+}
+
+// Between is the shortest rotation turning direction from onto direction to
+func Between(from, to Vec) Quaternion {
+	f, t := from.Normalized(), to.Normalized()
+	d := f.Dot(t)
+	if d < -1+1e-9 { // opposite: half a turn about anything square to them
+		axis := X.Cross(f)
+		if axis.Length() < 1e-6 {
+			axis = Y.Cross(f)
+		}
+		return FromAxisAngle(axis, Deg180)
+	}
+	c := f.Cross(t)
+	return Quaternion{W: 1 + d, X: c.X(), Y: c.Y(), Z: c.Z()}.Normalized()
+}
+
+// Length is the norm
+func (q Quaternion) Length() float64 {
+	return math.Sqrt(q.W*q.W + q.X*q.X + q.Y*q.Y + q.Z*q.Z)
+}
+
+// Normalized is a copy with length 1
+func (q Quaternion) Normalized() Quaternion {
+	l := q.Length()
+	if l < PlanckLength {
+		return NoRotation
+	}
+	return Quaternion{W: q.W / l, X: q.X / l, Y: q.Y / l, Z: q.Z / l}
+}
+
+// Conjugate is the opposite rotation, for one of length 1
+func (q Quaternion) Conjugate() Quaternion {
+	return Quaternion{W: q.W, X: -q.X, Y: -q.Y, Z: -q.Z}
+}
+
+// Mul is the rotation doing r then q
+func (q Quaternion) Mul(r Quaternion) Quaternion {
+	return Quaternion{
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W}
+}
+
+// Then is the rotation doing q then r
+func (q Quaternion) Then(r Quaternion) Quaternion {
+	return r.Mul(q)
+}
+
+// Rotate turns the vector about the origin
+func (q Quaternion) Rotate(v Vec) Vec {
+	// v + 2w(u x v) + 2u x (u x v), with u the imaginary part
+	u := NewSimVec(q.X, q.Y, q.Z)
+	t := u.Cross(v).Scale(2)
+	r := v.Add(t.Scale(q.W)).Add(u.Cross(t))
+	return v.New(r.X(), r.Y(), r.Z())
+}
+
+// AxisAngle is the axis and the angle turned about it, anticlockwise looking down it
+func (q Quaternion) AxisAngle() (Vec, Radians) {
+	q = q.Normalized()
+	s := math.Sqrt(1 - q.W*q.W)
+	if s < 1e-9 {
+		return Z, 0
+	}
+	return NewSimVec(q.X/s, q.Y/s, q.Z/s), Radians(2 * math.Acos(math.Max(-1, math.Min(1, q.W))))
+}
+
+// Slerp goes smoothly from q at t=0 to r at t=1, at a steady rate, the short way round
+func (q Quaternion) Slerp(r Quaternion, t float64) Quaternion {
+	d := q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+	if d < 0 { // the same rotation as -r, which is nearer
+		r = Quaternion{W: -r.W, X: -r.X, Y: -r.Y, Z: -r.Z}
+		d = -d
+	}
+	a, b := 1-t, t
+	if d < 1-1e-9 {
+		th := math.Acos(d)
+		a, b = math.Sin((1-t)*th)/math.Sin(th), math.Sin(t*th)/math.Sin(th)
+	}
+	return Quaternion{W: a*q.W + b*r.W, X: a*q.X + b*r.X, Y: a*q.Y + b*r.Y, Z: a*q.Z + b*r.Z}.Normalized()
+}
+
+// Matrix is the same rotation as a transform
+func (q Quaternion) Matrix() Matrix4 {
+	q = q.Normalized()
+	w, x, y, z := q.W, q.X, q.Y, q.Z
+	return Matrix4{
+		{1 - 2*(y*y+z*z), 2 * (x*y - w*z), 2 * (x*z + w*y), 0},
+		{2 * (x*y + w*z), 1 - 2*(x*x+z*z), 2 * (y*z - w*x), 0},
+		{2 * (x*z - w*y), 2 * (y*z + w*x), 1 - 2*(x*x+y*y), 0},
+		{0, 0, 0, 1}}
+}
+
+func (q Quaternion) String() string {
+	axis, a := q.AxisAngle()
+	return fmt.Sprintf("Rotation %.3g° about %s", Rad2Deg(a), axis)
+}
+
+// Rotate turns the plane about p
+func (p *Plane) Rotate(q Quaternion, about Vec) *Plane {
+	p.PointOn = q.Rotate(p.PointOn.Subtract(about)).Add(about)
+	p.Normal = q.Rotate(p.Normal)
+	return p
+}
+
+// Rotate turns the patch in place about p
+func (pa *Patch) Rotate(q Quaternion, about Vec) *Patch {
+	pa.Plane.Rotate(q, about)
+	pa.Corner = q.Rotate(pa.Corner.Subtract(about)).Add(about)
+	pa.Sides = []Vec{q.Rotate(pa.Sides[0]), q.Rotate(pa.Sides[1])}
+	return pa
+}
@@ -0,0 +1,60 @@
+package vec
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuaternion(t *testing.T) {
+
+	p := NewSimVec(1, 2, 3)
+
+	if !sameVec(FromAxisAngle(Z, Deg90).Rotate(p), p.RotateZ(Deg90)) {
+		t.Errorf("Rotation about Z does not match RotateZ")
+	}
+	q := FromAxisAngle(NewSimVec(1, -2, 0.5), 1.1)
+	if !sameVec(q.Rotate(p), p.Apply(RotationAbout(NewSimVec(1, -2, 0.5), 1.1))) {
+		t.Errorf("Rotation does not match RotationAbout")
+	}
+	if !sameVec(q.Rotate(p), p.Apply(q.Matrix())) {
+		t.Errorf("Matrix does not match Rotate")
+	}
+	if !sameVec(q.Conjugate().Rotate(q.Rotate(p)), p) {
+		t.Errorf("Conjugate does not undo the rotation")
+	}
+
+	// Then goes in order
+	r := FromAxisAngle(Z, Deg90).Then(FromAxisAngle(X, Deg90))
+	if !sameVec(r.Rotate(X), Z) {
+		t.Errorf("Then is in the wrong order: %s", r.Rotate(X))
+	}
+
+	axis, a := FromAxisAngle(NewSimVec(0, 3, 0), 0.4).AxisAngle()
+	if !sameVec(axis, Y) || NotApprox(float64(a), 0.4) {
+		t.Errorf("AxisAngle failed: %s %f", axis, a)
+	}
+
+	b := Between(NewSimVec(1, 1, 0), Z)
+	if !sameVec(b.Rotate(NewSimVec(1, 1, 0).Normalized()), Z) {
+		t.Errorf("Between failed")
+	}
+	if !sameVec(Between(X, X.Scale(-1)).Rotate(X), X.Scale(-1)) {
+		t.Errorf("Between opposites failed")
+	}
+
+	// Slerp goes at a steady rate
+	s := NoRotation.Slerp(FromAxisAngle(Z, Deg90), 1.0/3)
+	if !sameVec(s.Rotate(X), X.RotateZ(math.Pi/6)) {
+		t.Errorf("Slerp failed: %s", s.Rotate(X))
+	}
+	if !sameVec(NoRotation.Slerp(q, 1).Rotate(p), q.Rotate(p)) {
+		t.Errorf("Slerp does not end at the end")
+	}
+
+	// Tilting a patch
+	pa := NewPatch(Origin, Z, X, Y)
+	pa.Rotate(FromAxisAngle(X, Deg90), NewSimVec(0, 0, 1))
+	if !sameVec(pa.Normal, Y.Scale(-1)) || !sameVec(pa.Corner, NewSimVec(0, 1, 1)) || !sameVec(pa.Sides[1], Z) {
+		t.Errorf("Patch rotation failed: %s", pa)
+	}
+}
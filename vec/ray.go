@@ -0,0 +1,75 @@
+// ██████╗  █████╗ ██╗   ██╗
+// ██╔══██╗██╔══██╗╚██╗ ██╔╝
+// ██████╔╝███████║ ╚████╔╝
+// ██╔══██╗██╔══██║  ╚██╔╝
+// ██║  ██║██║  ██║   ██║
+// ╚═╝  ╚═╝╚═╝  ╚═╝   ╚═╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// rayEdgeTolerance lets a ray which just grazes the edge of a triangle hit it, as a fraction
+//   of the triangle, so a ray along the edge between two panels hits one of them
+const rayEdgeTolerance = 1e-9
+
+// Ray is a half line from Origin in the direction Dir, which has length 1
+type Ray struct {
+	Origin Vec
+	Dir    Vec
+}
+
+// NewRay makes one, normalizing the direction
+func NewRay(origin, dir Vec) Ray {
+	return Ray{Origin: origin, Dir: dir.Normalized()}
+}
+
+// Ray is the ray from the start of the segment along it; the segment ends at MaxD-MinD
+func (seg Segment) Ray() Ray {
+	return Ray{Origin: seg.Start(), Dir: seg.AlongN}
+}
+
+// At is the point t along the ray
+func (r Ray) At(t float64) Vec {
+	return r.Origin.Add(r.Dir.Scale(t))
+}
+
+// TriangleHit is where a ray hits a triangle abc: Where = a + U(b-a) + V(c-a), T along the ray
+type TriangleHit struct {
+	T, U, V float64
+	Where   Vec
+}
+
+// IntersectTriangle finds where the ray hits the triangle abc from either side, by the
+//   Möller–Trumbore method. Rays in the plane of the triangle miss it.
+func (r Ray) IntersectTriangle(a, b, c Vec) (TriangleHit, bool) {
+	ab, ac := b.Subtract(a), c.Subtract(a)
+	p := r.Dir.Cross(ac)
+	det := ab.Dot(p)
+	if math.Abs(det) < mayAsWellBeZero*ab.Length()*ac.Length() {
+		return TriangleHit{}, false // parallel
+	}
+	inv := 1 / det
+	s := r.Origin.Subtract(a)
+	u := s.Dot(p) * inv
+	if u < -rayEdgeTolerance || u > 1+rayEdgeTolerance {
+		return TriangleHit{}, false
+	}
+	q := s.Cross(ab)
+	v := r.Dir.Dot(q) * inv
+	if v < -rayEdgeTolerance || u+v > 1+rayEdgeTolerance {
+		return TriangleHit{}, false
+	}
+	t := ac.Dot(q) * inv
+	if t < 0 {
+		return TriangleHit{}, false // behind
+	}
+	return TriangleHit{T: t, U: u, V: v, Where: r.At(t)}, true
+}
+
+func (r Ray) String() string {
+	return fmt.Sprintf("Ray from %s direction %s", r.Origin, r.Dir)
+}
@@ -0,0 +1,46 @@
+package vec
+
+import (
+	"testing"
+)
+
+func TestRay(t *testing.T) {
+
+	a, b, c := NewSimVec(0, 0, 1), NewSimVec(2, 0, 1), NewSimVec(0, 2, 1)
+
+	r := NewRay(NewSimVec(0.5, 0.5, 0), NewSimVec(0, 0, 3))
+	h, ok := r.IntersectTriangle(a, b, c)
+	if !ok || NotApprox(h.T, 1) || NotApprox(h.U, 0.25) || NotApprox(h.V, 0.25) || !sameVec(h.Where, NewSimVec(0.5, 0.5, 1)) {
+		t.Errorf("Ray through the triangle failed: %v %v", ok, h)
+	}
+
+	// From behind, the other way, and with the corners the other way round
+	if _, ok := NewRay(NewSimVec(0.5, 0.5, 2), NewSimVec(0, 0, -1)).IntersectTriangle(a, c, b); !ok {
+		t.Errorf("Ray from the other side missed")
+	}
+	if _, ok := NewRay(NewSimVec(0.5, 0.5, 2), NewSimVec(0, 0, 1)).IntersectTriangle(a, b, c); ok {
+		t.Errorf("Ray pointing away hit")
+	}
+
+	// Grazing an edge and a corner, and just missing
+	if _, ok := NewRay(NewSimVec(1, 1, 0), Z).IntersectTriangle(a, b, c); !ok {
+		t.Errorf("Ray through the long edge missed")
+	}
+	if _, ok := NewRay(NewSimVec(0, 0, 0), Z).IntersectTriangle(a, b, c); !ok {
+		t.Errorf("Ray through a corner missed")
+	}
+	if _, ok := NewRay(NewSimVec(1.01, 1, 0), Z).IntersectTriangle(a, b, c); ok {
+		t.Errorf("Ray beyond the long edge hit")
+	}
+
+	// In the plane of the triangle
+	if _, ok := NewRay(NewSimVec(-1, 0.5, 1), X).IntersectTriangle(a, b, c); ok {
+		t.Errorf("Ray in the plane hit")
+	}
+
+	// A segment's ray starts at its start
+	s := NewSegment(NewLine(NewSimVec(0.5, 0.5, -1), Z), 1, 3)
+	if !sameVec(s.Ray().Origin, NewSimVec(0.5, 0.5, 0)) || !sameVec(s.Ray().At(1), NewSimVec(0.5, 0.5, 1)) {
+		t.Errorf("Segment ray failed")
+	}
+}
@@ -0,0 +1,61 @@
+// ████████╗ ██████╗ ██╗
+// ╚══██╔══╝██╔═══██╗██║
+//    ██║   ██║   ██║██║
+//    ██║   ██║   ██║██║
+//    ██║   ╚██████╔╝███████╗
+//    ╚═╝    ╚═════╝ ╚══════╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tolerance is how near is near enough in the tests of where things are, such as whether a
+//   segment hits a plane or a point is inside a patch. Things within Length of a boundary count
+//   as on it, so are inside, and directions within Angle of square count as square.
+type Tolerance struct {
+	Length float64 // m
+	Angle  float64 // radians
+}
+
+// Tolerances for different jobs
+var (
+	Exact        = Tolerance{Length: 0, Angle: mayAsWellBeZero} // only what is really on the boundary
+	CutTolerance = Tolerance{Length: 0.0001, Angle: 1e-9}       // a tenth of a mm, the scale of cutting sheet
+)
+
+// DefaultTolerance is used by the tests which do not take one
+var DefaultTolerance = Exact
+
+func (t Tolerance) String() string {
+	return fmt.Sprintf("Tolerance %.3gmm, %.3g rad", t.Length*1000, t.Angle)
+}
+
+// Sign is 0 for a distance within the tolerance, else its sign
+func (t Tolerance) Sign(d float64) int {
+	switch {
+	case d > t.Length:
+		return 1
+	case d < -t.Length:
+		return -1
+	}
+	return 0
+}
+
+// Within is true if x is between lo and hi, or within the tolerance of them
+func (t Tolerance) Within(x, lo, hi float64) bool {
+	return x >= lo-t.Length && x <= hi+t.Length
+}
+
+// Square is true if the unit vectors are within the angle of being at right angles, e.g. a
+//   direction along a plane and its normal
+func (t Tolerance) Square(a, b Vec) bool {
+	return math.Abs(a.Dot(b)) < math.Max(t.Angle, mayAsWellBeZero)
+}
+
+// Same is true if the points are within the tolerance of each other
+func (t Tolerance) Same(a, b Vec) bool {
+	return a.Subtract(b).Length() <= t.Length
+}
@@ -0,0 +1,46 @@
+package vec
+
+import (
+	"testing"
+)
+
+func TestTolerance(t *testing.T) {
+
+	tol := Tolerance{Length: 0.001, Angle: 1e-6}
+	if tol.Sign(0.0005) != 0 || tol.Sign(0.002) != 1 || tol.Sign(-0.002) != -1 {
+		t.Errorf("Sign wrong")
+	}
+	if !tol.Within(1.0009, 0, 1) || tol.Within(-0.0011, 0, 1) {
+		t.Errorf("Within wrong")
+	}
+
+	// A segment stopping just short of a plane only hits it with a tolerance
+	pl := NewPlane(NewSimVec(0, 0, 1), Z)
+	seg := NewSegment2Ends(NewSimVec(0, 0, 0), NewSimVec(0, 0, 0.9995))
+	if _, hit := pl.IntersectSegment(seg); hit {
+		t.Errorf("Segment short of the plane hit it exactly")
+	}
+	if w, hit := pl.IntersectSegmentTol(seg, tol); !hit || !sameVec(w, NewSimVec(0, 0, 1)) {
+		t.Errorf("Segment short of the plane missed it within tolerance: %v %v", hit, w)
+	}
+	if pl.SideTol(NewSimVec(5, 5, 1.0005), tol) != 0 || pl.SideTol(NewSimVec(5, 5, 1.0005), Exact) != 1 {
+		t.Errorf("SideTol wrong")
+	}
+
+	// Just outside a 2m square patch
+	pa := NewPatch(Origin, Z, NewSimVec(2, 0, 0), NewSimVec(0, 2, 0))
+	p := NewSimVec(2.0005, 1, 0)
+	if pa.ParaContains(p) || !pa.ParaContainsTol(p, tol) {
+		t.Errorf("ParaContainsTol wrong")
+	}
+	if pa.ParaOverlapsSegment(p, NewSimVec(3, 1, 0)) || !pa.ParaOverlapsSegmentTol(p, NewSimVec(3, 1, 0), tol) {
+		t.Errorf("ParaOverlapsSegmentTol wrong")
+	}
+	down := NewSegment2Ends(NewSimVec(1.5005, 0.5, 1), NewSimVec(1.5005, 0.5, -1))
+	if _, hit := pa.TriIntersectSegment(down); hit {
+		t.Errorf("Segment just outside the triangle hit it exactly")
+	}
+	if _, hit := pa.TriIntersectSegmentTol(down, tol); !hit {
+		t.Errorf("Segment just outside the triangle missed it within tolerance")
+	}
+}
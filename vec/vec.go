@@ -18,9 +18,9 @@ type Vec interface {
 	X() float64
 	Y() float64
 	Z() float64
-	SetX(x float64)
-	SetY(y float64)
-	SetZ(z float64)
+	WithX(x float64) Vec // a copy with X changed; vectors are values, never changed in place
+	WithY(y float64) Vec
+	WithZ(z float64) Vec
 	Length() float64
 	LengthSq() float64
 	Normalized() Vec
@@ -32,6 +32,7 @@ type Vec interface {
 	String() string
 	Stl() string
 	RotateZ(a Radians) Vec // Rotate about the Z axis by a radians
+	Apply(m Matrix4) Vec   // transform as a point
 }
 
 // Radians are angles 0-2pi
@@ -77,24 +78,33 @@ func Cos(r Radians) float64 {
 // ███████║██║██║ ╚═╝ ██║ ╚████╔╝ ███████╗╚██████╗
 // ╚══════╝╚═╝╚═╝     ╚═╝  ╚═══╝  ╚══════╝ ╚═════╝
 
-// SimVec is a naive 3 vector
+// SimVec is the 3 vector behind Vec. It is a value: nothing changes one in place, so it can be
+//   shared and copied freely.
 type SimVec struct {
 	x, y, z float64
 }
 
-// SetX is obvious
-func (v SimVec) SetX(newVal float64) {
-	v.x = newVal
+// Sim is any Vec as a SimVec, without the panic of a type assertion
+func Sim(v Vec) SimVec {
+	if s, ok := v.(SimVec); ok {
+		return s
+	}
+	return SimVec{v.X(), v.Y(), v.Z()}
 }
 
-// SetY is obvious
-func (v SimVec) SetY(newVal float64) {
-	v.y = newVal
+// WithX is a copy with X changed
+func (v SimVec) WithX(x float64) Vec {
+	return SimVec{x, v.y, v.z}
 }
 
-// SetZ is obvious
-func (v SimVec) SetZ(newVal float64) {
-	v.z = newVal
+// WithY is a copy with Y changed
+func (v SimVec) WithY(y float64) Vec {
+	return SimVec{v.x, y, v.z}
+}
+
+// WithZ is a copy with Z changed
+func (v SimVec) WithZ(z float64) Vec {
+	return SimVec{v.x, v.y, z}
 }
 
 // Stl renders it as a string suitable for output in an stl file
@@ -107,12 +117,9 @@ func (v SimVec) String() string {
 	return fmt.Sprintf("[X:%.3g, Y:%.3g, Z:%.3g 📏%.3g]", v.x, v.y, v.z, v.Length())
 }
 
-// Used only for calls to new
-var simvec = SimVec{}
-
 // NewSimVec makes one
 func NewSimVec(x, y, z float64) SimVec {
-	return simvec.New(x, y, z).(SimVec)
+	return SimVec{x: x, y: y, z: z}
 }
 
 // New makes a new one from the given components. Values in receiver are ignored.
@@ -193,82 +200,18 @@ func (v SimVec) RotateZ(a Radians) Vec {
 // ╚██████╗██║     ╚██████╔╝ ╚████╔╝ ███████╗╚██████╗
 //  ╚═════╝╚═╝      ╚═════╝   ╚═══╝  ╚══════╝ ╚═════╝
 
-// CPUVec is a 3D vector optimized for CPU speed over storage size
-type CPUVec struct {
-	SimVec
-	//	x, y, z    float64
-	l, ll      float64 // length and length squared
-	xN, yN, zN float64 // normalized x,y,z
-}
-
-// Used only for calls to new
-var cpuvec = CPUVec{}
-
-// New makes a new one from the given components. Values in receiver are ignored.
-func (v CPUVec) New(x, y, z float64) Vec {
-	n := CPUVec{}
-	n.x = x
-	n.y = y
-	n.z = z
-	n.ll = x*x + y*y + z*z
-	n.l = math.Sqrt(n.ll)
-	n.xN = x / n.l
-	n.yN = y / n.l
-	n.zN = z / n.l
-	return n
-}
+// CPUVec was a SimVec caching its length, which went stale if anything changed it. It is now
+//   just another name for SimVec, kept so old code still builds.
+type CPUVec = SimVec
 
 // NewCPUVec makes one
 func NewCPUVec(x, y, z float64) CPUVec {
-	return cpuvec.New(x, y, z).(CPUVec)
-}
-
-// LengthSq returns the square of the length
-func (v CPUVec) LengthSq() float64 {
-	return v.ll
-}
-
-// Length returns the length
-func (v CPUVec) Length() float64 {
-	return v.l
-}
-
-// Normalized returns a naive copy, length 1
-func (v CPUVec) Normalized() Vec {
-	return v.New(v.xN, v.yN, v.zN)
-}
-
-// Scale returns a scaled version
-func (v CPUVec) Scale(f float64) Vec {
-	return v.New(v.x*f, v.y*f, v.z*f)
+	return NewSimVec(x, y, z)
 }
 
 // Simple returns a SimVec copy
-func (v CPUVec) Simple() SimVec {
-	return NewSimVec(v.x, v.y, v.z)
-}
-
-// Dot is the dot product
-func (v CPUVec) Dot(w Vec) float64 {
-	return v.x*w.X() + v.y*w.Y() + v.z*w.Z()
-}
-
-// Cross is the cross product
-func (v CPUVec) Cross(w Vec) Vec {
-	cx := v.y*w.Z() - v.z*w.Y()
-	cy := v.z*w.X() - v.x*w.Z()
-	cz := v.x*w.Y() - v.y*w.X()
-	return v.New(cx, cy, cz)
-}
-
-// Add returns v+w
-func (v CPUVec) Add(w Vec) Vec {
-	return v.New(v.x+w.X(), v.y+w.Y(), v.z+w.Z())
-}
-
-// Subtract returns v-w
-func (v CPUVec) Subtract(w Vec) Vec {
-	return v.New(v.x-w.X(), v.y-w.Y(), v.z-w.Z())
+func (v SimVec) Simple() SimVec {
+	return v
 }
 
 // ██╗   ██╗████████╗██╗██╗     ███████╗
@@ -0,0 +1,88 @@
+// ██╗   ██╗███████╗ ██████╗██████╗
+// ██║   ██║██╔════╝██╔════╝╚════██╗
+// ██║   ██║█████╗  ██║      █████╔╝
+// ╚██╗ ██╔╝██╔══╝  ██║      ╚═══██╗
+//  ╚████╔╝ ███████╗╚██████╗██████╔╝
+//   ╚═══╝  ╚══════╝ ╚═════╝╚═════╝
+
+package vec
+
+import (
+	"fmt"
+	"math"
+)
+
+// Vec3 is a plain 3 vector used by value. Its arithmetic never goes through the Vec interface,
+//   so never allocates, which matters in the inner loops of relaxation. Convert at the edges
+//   with V3 and Vec.
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+// V3 makes a Vec3 from any Vec
+func V3(v Vec) Vec3 {
+	switch w := v.(type) {
+	case SimVec:
+		return Vec3{w.x, w.y, w.z}
+	}
+	return Vec3{v.X(), v.Y(), v.Z()}
+}
+
+// V3 is the same vector as a Vec3
+func (v SimVec) V3() Vec3 {
+	return Vec3{v.x, v.y, v.z}
+}
+
+// Vec is the same vector behind the Vec interface, as a SimVec
+func (v Vec3) Vec() Vec {
+	return SimVec{v.X, v.Y, v.Z}
+}
+
+// Sim is the same vector as a SimVec
+func (v Vec3) Sim() SimVec {
+	return SimVec{v.X, v.Y, v.Z}
+}
+
+// Add returns v+w
+func (v Vec3) Add(w Vec3) Vec3 {
+	return Vec3{v.X + w.X, v.Y + w.Y, v.Z + w.Z}
+}
+
+// Subtract returns v-w
+func (v Vec3) Subtract(w Vec3) Vec3 {
+	return Vec3{v.X - w.X, v.Y - w.Y, v.Z - w.Z}
+}
+
+// Scale returns a scaled version
+func (v Vec3) Scale(f float64) Vec3 {
+	return Vec3{v.X * f, v.Y * f, v.Z * f}
+}
+
+// Dot is the dot product
+func (v Vec3) Dot(w Vec3) float64 {
+	return v.X*w.X + v.Y*w.Y + v.Z*w.Z
+}
+
+// Cross is the cross product
+func (v Vec3) Cross(w Vec3) Vec3 {
+	return Vec3{v.Y*w.Z - v.Z*w.Y, v.Z*w.X - v.X*w.Z, v.X*w.Y - v.Y*w.X}
+}
+
+// LengthSq returns the square of the length
+func (v Vec3) LengthSq() float64 {
+	return v.X*v.X + v.Y*v.Y + v.Z*v.Z
+}
+
+// Length returns the length
+func (v Vec3) Length() float64 {
+	return math.Sqrt(v.LengthSq())
+}
+
+// Normalized returns a copy, length 1
+func (v Vec3) Normalized() Vec3 {
+	return v.Scale(1 / v.Length())
+}
+
+func (v Vec3) String() string {
+	return fmt.Sprintf("[X:%.3g, Y:%.3g, Z:%.3g 📏%.3g]", v.X, v.Y, v.Z, v.Length())
+}
@@ -0,0 +1,57 @@
+package vec
+
+import (
+	"testing"
+)
+
+func TestVec3(t *testing.T) {
+
+	a, b := NewSimVec(1, 2, 3), NewSimVec(-2, 0.5, 4)
+	a3, b3 := V3(a), b.V3()
+	if !sameVec(a3.Add(b3).Vec(), a.Add(b)) || !sameVec(a3.Subtract(b3).Vec(), a.Subtract(b)) {
+		t.Errorf("Vec3 sum or difference differs from SimVec")
+	}
+	if !sameVec(a3.Cross(b3).Vec(), a.Cross(b)) || NotApprox(a3.Dot(b3), a.Dot(b)) {
+		t.Errorf("Vec3 products differ from SimVec")
+	}
+	if !sameVec(a3.Normalized().Scale(2).Vec(), a.Normalized().Scale(2)) || NotApprox(a3.Length(), a.Length()) {
+		t.Errorf("Vec3 scaling differs from SimVec")
+	}
+	if V3(NewCPUVec(1, 2, 3)) != a3 || a3.Sim() != a {
+		t.Errorf("Vec3 conversions wrong")
+	}
+}
+
+// The relaxation inner loop: sum the forces of the edges on a vertex, and step it
+
+var (
+	benchAlong = []Vec{NewSimVec(1, 0.2, 0.1), NewSimVec(-0.3, 1, 0.2), NewSimVec(0.1, -0.9, 0.4), NewSimVec(-1, -0.1, 0.3), NewSimVec(0.5, 0.5, -1), NewSimVec(0.2, 0.1, 1)}
+	benchSink  Vec
+	benchSink3 Vec3
+)
+
+func BenchmarkForcesVec(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var f Vec = Zero
+		for j, a := range benchAlong {
+			f = f.Add(a.Normalized().Scale(float64(j) * 0.1))
+		}
+		benchSink = f.Scale(0.01).Cross(Z)
+	}
+}
+
+func BenchmarkForcesVec3(b *testing.B) {
+	b.ReportAllocs()
+	along := make([]Vec3, len(benchAlong))
+	for j, a := range benchAlong {
+		along[j] = V3(a)
+	}
+	for i := 0; i < b.N; i++ {
+		var f Vec3
+		for j, a := range along {
+			f = f.Add(a.Normalized().Scale(float64(j) * 0.1))
+		}
+		benchSink3 = f.Scale(0.01).Cross(Vec3{0, 0, 1})
+	}
+}
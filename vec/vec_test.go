@@ -84,3 +84,24 @@ func NotApprox(a, b float64) bool {
 	}
 	return false
 }
+
+func TestVecValues(t *testing.T) {
+
+	a := NewSimVec(1, 2, 3)
+	b := a.WithX(7).WithZ(-1)
+	if a != NewSimVec(1, 2, 3) {
+		t.Errorf("WithX changed the original: %s", a)
+	}
+	if !sameVec(b, NewSimVec(7, 2, -1)) || !sameVec(a.WithY(0), NewSimVec(1, 0, 3)) {
+		t.Errorf("With gave %s", b)
+	}
+
+	// Sim converts any Vec, and NewCPUVec is now the same thing
+	if Sim(Vec3{4, 5, 6}.Vec()) != NewSimVec(4, 5, 6) || Sim(NewCPUVec(1, 2, 3)) != a {
+		t.Errorf("Sim conversion failed")
+	}
+	c := NewCPUVec(3, 4, 0)
+	if NotApprox(c.Add(X).Length(), math.Sqrt(32)) {
+		t.Errorf("CPUVec length after adding should be fresh, got %f", c.Add(X).Length())
+	}
+}
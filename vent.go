@@ -0,0 +1,193 @@
+package main
+
+// ██╗   ██╗███████╗███╗   ██╗████████╗
+// ██║   ██║██╔════╝████╗  ██║╚══██╔══╝
+// ██║   ██║█████╗  ██╔██╗ ██║   ██║
+// ╚██╗ ██╔╝██╔══╝  ██║╚██╗██║   ██║
+//  ╚████╔╝ ███████╗██║ ╚████║   ██║
+//   ╚═══╝  ╚══════╝╚═╝  ╚═══╝   ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	cam "./cam"
+	gl "./gl"
+	v3 "./vec"
+)
+
+// VentShape is the shape of the opening of a vent
+type VentShape int
+
+// Values of VentShape
+const (
+	VentRound VentShape = iota // circular opening, bolts on a circle
+	VentRect                   // rectangular opening, bolts around the rim
+)
+
+func (s VentShape) String() string {
+	if s == VentRect {
+		return "Rect"
+	}
+	return "Round"
+}
+
+// Defaults for commercial vent units
+const (
+	ventFreeRatio = 0.5   // fraction of the opening left free by a typical louvre & mesh
+	ventRimWidth  = 0.025 // m, from edge of opening to bolt centres
+	ventBoltDia   = 0.006 // m, M6
+	ventBoltPitch = 0.15  // m, max spacing of bolts on a rectangular vent
+	ventAspect    = 1.5   // width/height of rectangular vents
+)
+
+// ventFreeArea is the net free area of the vents added from the GUI, m2
+const ventFreeArea = 0.01
+
+// Vent is a PAtypeVentMk1 accessory: an opening in a panel for a commercial vent unit
+type Vent struct {
+	Panel     *Panel
+	Shape     VentShape
+	FreeArea  float64  // m2, net free area required
+	FreeRatio float64  // fraction of the opening which is free, depends on the unit
+	Width     float64  // m, of the opening, or its diameter if round
+	Height    float64  // m, of the opening, same as width if round
+	Center    v3.Vec   // center of the opening, world coords
+	Outline   []v3.Vec // corners of the opening (polygon for round ones), world coords
+	Bolts     []v3.Vec // positions of the bolt holes, world coords
+	BoltDia   float64  // m
+	x, y      v3.Vec   // directions across and up the opening, in the panel
+}
+
+// AddVent puts a vent of the given shape and free area in the center of a panel
+func (e *EShell) AddVent(p *Panel, shape VentShape, freeArea float64) (*Vent, error) {
+
+	if !p.Alive {
+		return nil, fmt.Errorf("Panel %d is not alive, cannot take a vent", p.Serial)
+	}
+	if p.Accessory != PAtypePlain {
+		return nil, fmt.Errorf("Panel %d already has an accessory", p.Serial)
+	}
+	p.Update(e)
+
+	v := Vent{Panel: p, Shape: shape, FreeArea: freeArea, FreeRatio: ventFreeRatio, BoltDia: ventBoltDia}
+	open := freeArea / v.FreeRatio
+	switch shape {
+	case VentRound:
+		v.Width = 2 * math.Sqrt(open/math.Pi)
+		v.Height = v.Width
+	case VentRect:
+		v.Width = math.Sqrt(open * ventAspect)
+		v.Height = open / v.Width
+	}
+
+	// Put it at the incenter, with its width as horizontal as the panel allows
+	v.Center = p.Incenter()
+	v.x = v3.Z.Cross(p.Normal)
+	if v.x.Length() < v3.PlanckLength { // panel is flat, at the apex
+		_, v.x, _ = p.Frame()
+	}
+	v.x = v.x.Normalized()
+	v.y = p.Normal.Cross(v.x).Normalized()
+
+	reach := math.Hypot(v.Width/2, v.Height/2) + ventRimWidth + v.BoltDia // furthest bit of the unit
+	if shape == VentRound {
+		reach = v.Width/2 + ventRimWidth + v.BoltDia
+	}
+	if reach > p.Inradius() {
+		return nil, fmt.Errorf("Vent with %.3gm2 free area needs %.2fm clear, panel %d only has %.2fm",
+			freeArea, reach, p.Serial, p.Inradius())
+	}
+
+	v.makeGeometry()
+	p.Accessory = PAtypeVentMk1
+	e.Vents = append(e.Vents, &v)
+	return &v, nil
+}
+
+// at returns the world position of a point in the vent's own coords
+func (v *Vent) at(x, y float64) v3.Vec {
+	return v.Center.Add(v.x.Scale(x)).Add(v.y.Scale(y))
+}
+
+// makeGeometry works out the outline and bolt positions
+func (v *Vent) makeGeometry() {
+	v.Outline = nil
+	v.Bolts = nil
+	switch v.Shape {
+	case VentRound:
+		r := v.Width / 2
+		n := cam.CircleSides(r * m2mm)
+		for i := 0; i < n; i++ {
+			a := 2 * math.Pi * float64(i) / float64(n)
+			v.Outline = append(v.Outline, v.at(r*math.Cos(a), r*math.Sin(a)))
+		}
+		nb := 4 // more bolts on bigger units
+		if v.Width > 0.2 {
+			nb = 6
+		}
+		if v.Width > 0.4 {
+			nb = 8
+		}
+		rb := r + ventRimWidth
+		for i := 0; i < nb; i++ {
+			a := 2*math.Pi*float64(i)/float64(nb) + math.Pi/float64(nb)
+			v.Bolts = append(v.Bolts, v.at(rb*math.Cos(a), rb*math.Sin(a)))
+		}
+	case VentRect:
+		w := v.Width / 2
+		h := v.Height / 2
+		v.Outline = []v3.Vec{v.at(-w, -h), v.at(w, -h), v.at(w, h), v.at(-w, h)}
+		bw := w + ventRimWidth
+		bh := h + ventRimWidth
+		corners := [][2]float64{{-bw, -bh}, {bw, -bh}, {bw, bh}, {-bw, bh}}
+		for i, c := range corners { // bolts at the corners and evenly along each side
+			n := corners[(i+1)%4]
+			l := math.Hypot(n[0]-c[0], n[1]-c[1])
+			steps := int(math.Ceil(l / ventBoltPitch))
+			for j := 0; j < steps; j++ {
+				f := float64(j) / float64(steps)
+				v.Bolts = append(v.Bolts, v.at(c[0]+f*(n[0]-c[0]), c[1]+f*(n[1]-c[1])))
+			}
+		}
+	}
+}
+
+// Drawing makes the CAM cut paths for the vent, in the panel's flat coordinates (mm)
+func (v *Vent) Drawing() cam.Drawing {
+	d := cam.Drawing{Name: fmt.Sprintf("Vent in panel %d", v.Panel.Serial), ID: v.Panel.Serial}
+	var pts []cam.Vec2
+	for _, o := range v.Outline {
+		pts = append(pts, v.Panel.Flat(o))
+	}
+	d.Paths = append(d.Paths, cam.NewPolygonPath(pts, cam.EdgePath))
+	for _, b := range v.Bolts {
+		d.Paths = append(d.Paths, cam.NewCirclePath(v.Panel.Flat(b), v.BoltDia*m2mm/2, cam.EdgePath))
+	}
+	return d
+}
+
+// Display generates the lines to show a vent
+func (v *Vent) Display() []gl.ColourLine {
+	ls := []gl.ColourLine{}
+	for i := range v.Outline {
+		ls = append(ls, gl.ColourLine{Start: v.Outline[i], End: v.Outline[(i+1)%len(v.Outline)], Colour: &gl.Aqua})
+	}
+	for _, b := range v.Bolts {
+		r := v.BoltDia / 2
+		ls = append(ls, gl.ColourLine{Start: b.Add(v.x.Scale(-r)), End: b.Add(v.x.Scale(r)), Colour: &gl.Aqua})
+		ls = append(ls, gl.ColourLine{Start: b.Add(v.y.Scale(-r)), End: b.Add(v.y.Scale(r)), Colour: &gl.Aqua})
+	}
+	return ls
+}
+
+// VentsIn are the vents cut in the panel
+func (e *EShell) VentsIn(p *Panel) []*Vent {
+	var vs []*Vent
+	for _, v := range e.Vents {
+		if v.Panel == p {
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
@@ -0,0 +1,92 @@
+package main
+
+// ██╗   ██╗██╗███████╗██╗    ██╗
+// ██║   ██║██║██╔════╝██║    ██║
+// ██║   ██║██║█████╗  ██║ █╗ ██║
+// ╚██╗ ██╔╝██║██╔══╝  ██║███╗██║
+//  ╚████╔╝ ██║███████╗╚███╔███╔╝
+//   ╚═══╝  ╚═╝╚══════╝ ╚══╝╚══╝
+
+import (
+	"math"
+
+	v3 "./vec"
+
+	"github.com/g3n/engine/math32"
+)
+
+// viewBack is how far the camera stands from the shell, in sizes of it
+const viewBack = 2.5
+
+// viewMargin is how much more than the shell an orthographic view shows
+const viewMargin = 1.2
+
+// View is a standard direction to look at the shell from
+type View int
+
+// Values of View
+const (
+	ViewTop   View = iota // looking down, X across
+	ViewFront             // from -Y, looking along it
+	ViewSide              // from +X
+	ViewIso               // from above and to the front left, as it starts
+)
+
+// Views are in the order of the keys choosing them, 1 to 4
+var Views = []View{ViewTop, ViewFront, ViewSide, ViewIso}
+
+func (v View) String() string {
+	switch v {
+	case ViewTop:
+		return "Top"
+	case ViewFront:
+		return "Front"
+	case ViewSide:
+		return "Side"
+	case ViewIso:
+		return "Iso"
+	}
+	return "Unknown"
+}
+
+// Bounds is the box round the alive vertices
+func (e *EShell) Bounds() v3.Box {
+	b := v3.EmptyBox
+	for _, v := range e.Vertices {
+		if v.Alive {
+			b = b.Extend(v.Position)
+		}
+	}
+	return b
+}
+
+// Size is the longest side of the box round the shell, 1 if it has none
+func (e *EShell) Size() float64 {
+	b := e.Bounds()
+	if b.Empty() {
+		return 1
+	}
+	d := b.Max.Subtract(b.Min)
+	return math.Max(d.X(), math.Max(d.Y(), d.Z()))
+}
+
+// Pose is where the camera goes to look at the origin from the view, and which way is up, in
+//   the viewer's coords, which have Y up
+func (v View) Pose(size float64) (eye, up math32.Vector3) {
+	d := float32(viewBack * size)
+	switch v {
+	case ViewTop:
+		return math32.Vector3{X: 0, Y: d, Z: 0}, math32.Vector3{X: 0, Y: 0, Z: -1}
+	case ViewFront:
+		return math32.Vector3{X: 0, Y: 0, Z: -d}, math32.Vector3{X: 0, Y: 1, Z: 0}
+	case ViewSide:
+		return math32.Vector3{X: d, Y: 0, Z: 0}, math32.Vector3{X: 0, Y: 1, Z: 0}
+	}
+	s := d / float32(math.Sqrt(3))
+	return math32.Vector3{X: -s, Y: s, Z: s}, math32.Vector3{X: 0, Y: 1, Z: 0}
+}
+
+// OrthoSize is the height an orthographic view shows to fit the shell in
+func OrthoSize(size float64) float32 {
+	return float32(viewMargin * size)
+}
@@ -0,0 +1,84 @@
+package main
+
+// ██╗   ██╗ ██████╗ ██╗     ██╗   ██╗███╗   ███╗███████╗
+// ██║   ██║██╔═══██╗██║     ██║   ██║████╗ ████║██╔════╝
+// ██║   ██║██║   ██║██║     ██║   ██║██╔████╔██║█████╗
+// ╚██╗ ██╔╝██║   ██║██║     ██║   ██║██║╚██╔╝██║██╔══╝
+//  ╚████╔╝ ╚██████╔╝███████╗╚██████╔╝██║ ╚═╝ ██║███████╗
+//   ╚═══╝   ╚═════╝ ╚══════╝ ╚═════╝ ╚═╝     ╚═╝╚══════╝
+
+import (
+	"fmt"
+	"math"
+
+	v3 "./vec"
+)
+
+// Headrooms is the standing heights for the floor area table, m
+var Headrooms = []float64{4 * ft2m, 5 * ft2m, 6 * ft2m, 80 * in2m, 7 * ft2m, 8 * ft2m}
+
+// areaVector is the panel's outward normal scaled by its area, from the corners as they are now
+func (p *Panel) areaVector() v3.Vec {
+	a, b, c := p.Corners[0].Position, p.Corners[1].Position, p.Corners[2].Position
+	n := b.Subtract(a).Cross(c.Subtract(a)).Scale(0.5)
+	if n.Dot(a) < 0 {
+		n = n.Scale(-1)
+	}
+	return n
+}
+
+// Volume is the volume enclosed by the alive panels and the floor, by the divergence theorem
+//   with the field (x, y, 0)/2, to which the flat floor contributes nothing. Openings are left
+//   open, so a shell with doors cut in it comes out a little small.
+func (e *EShell) Volume() float64 {
+	v := 0.0
+	for _, p := range e.Panels {
+		if !p.Alive || len(p.Corners) != 3 {
+			continue
+		}
+		n := p.areaVector()
+		c := p.Corners[0].Position.Add(p.Corners[1].Position).Add(p.Corners[2].Position).Scale(1.0 / 3)
+		v += (c.X()*n.X() + c.Y()*n.Y()) / 2
+	}
+	return v
+}
+
+// AreaWithHeadroom is the floor area over which the panels are at least h above the floor: the
+//   area of the section through the shell at that height
+func (e *EShell) AreaWithHeadroom(h float64) float64 {
+	z := e.Base + h
+	area := 0.0
+	for _, p := range e.Panels {
+		if !p.Alive || len(p.Corners) != 3 {
+			continue
+		}
+		var cut []v3.Vec // where the panel's sides cross the plane
+		for i := range p.Corners {
+			a, b := p.Corners[i].Position, p.Corners[(i+1)%3].Position
+			if (a.Z() < z) != (b.Z() < z) {
+				cut = append(cut, a.Add(b.Subtract(a).Scale((z-a.Z())/(b.Z()-a.Z()))))
+			}
+		}
+		if len(cut) != 2 {
+			continue
+		}
+		// Anticlockwise round the section has the outward normal on the right
+		p1, p2 := cut[0], cut[1]
+		dx, dy := p2.X()-p1.X(), p2.Y()-p1.Y()
+		n := p.areaVector()
+		if dy*n.X()-dx*n.Y() < 0 {
+			p1, p2 = p2, p1
+		}
+		area += (p1.X()*p2.Y() - p2.X()*p1.Y()) / 2
+	}
+	return math.Max(0, area)
+}
+
+// HeadroomTable lists the floor area with each of the Headrooms
+func (e *EShell) HeadroomTable() string {
+	s := fmt.Sprintf("Volume: %s\n", e.Display.Volume(e.Volume()))
+	for _, h := range Headrooms {
+		s += fmt.Sprintf("   Headroom %s: %s\n", e.Display.Height(h), e.Display.Area(e.AreaWithHeadroom(h)))
+	}
+	return s
+}
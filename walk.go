@@ -0,0 +1,93 @@
+package main
+
+// ██╗    ██╗ █████╗ ██╗     ██╗  ██╗
+// ██║    ██║██╔══██╗██║     ██║ ██╔╝
+// ██║ █╗ ██║███████║██║     █████╔╝
+// ██║███╗██║██╔══██║██║     ██╔═██╗
+// ╚███╔███╔╝██║  ██║███████╗██║  ██╗
+//  ╚══╝╚══╝ ╚═╝  ╚═╝╚══════╝╚═╝  ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	v3 "./vec"
+
+	"github.com/g3n/engine/math32"
+)
+
+// Sizes for walking about inside the shell
+const (
+	walkEyeHeight = 1.6           // m, above the floor
+	walkStep      = 0.1           // m, each press of a key
+	walkTurn      = v3.Degrees(5) // each press of a key
+	walkClearance = 0.25          // m, nearest the walker goes to the shell
+	walkFar       = 50.0          // m, furthest looked for the shell overhead
+)
+
+// Walker stands on the floor of the shell, looking horizontally
+type Walker struct {
+	At      v3.Vec     // on the floor
+	Heading v3.Radians // anticlockwise from +X
+}
+
+// NewWalker stands in the middle of the floor, facing +Y
+func (e *EShell) NewWalker() *Walker {
+	return &Walker{At: v3.NewSimVec(0, 0, e.Base), Heading: math.Pi / 2}
+}
+
+// facing is the horizontal direction the walker looks in
+func (w *Walker) facing() v3.Vec {
+	return v3.NewSimVec(math.Cos(float64(w.Heading)), math.Sin(float64(w.Heading)), 0)
+}
+
+// Eye is where the walker sees from
+func (w *Walker) Eye() v3.Vec {
+	return w.At.Add(v3.Z.Scale(walkEyeHeight))
+}
+
+// Turn turns the walker by degrees, anticlockwise seen from above
+func (w *Walker) Turn(deg v3.Degrees) {
+	w.Heading += v3.Deg2Rad(deg)
+}
+
+// Step moves the walker forward and to the left by the distances, along the floor, unless the
+//   shell is in the way at their feet or eyes; false if it is
+func (w *Walker) Step(e *EShell, forward, left float64) bool {
+	f := w.facing()
+	l := v3.Z.Cross(f)
+	move := f.Scale(forward).Add(l.Scale(left))
+	d := move.Length()
+	if d == 0 {
+		return true
+	}
+	for _, h := range []float64{walkClearance, walkEyeHeight} { // feet are off the floor, which is part of it
+		from := w.At.Add(v3.Z.Scale(h))
+		if len(e.RayHits(v3.NewRay(from, move.Normalized()), d+walkClearance)) > 0 {
+			return false
+		}
+	}
+	w.At = w.At.Add(move)
+	return true
+}
+
+// Headroom is how far above the walker's feet the shell is, infinite if it isn't
+func (w *Walker) Headroom(e *EShell) float64 {
+	if h, ok := e.PickPanel(v3.NewRay(w.At, v3.Z), walkFar); ok {
+		return h.T
+	}
+	return math.Inf(1)
+}
+
+// Info says where the walker is and how much room there is over them
+func (w *Walker) Info(e *EShell) string {
+	return fmt.Sprintf("Walking at %s, %s, headroom %s, WS forward and back, AD sideways, QE turn",
+		e.Display.Len(w.At.X()), e.Display.Len(w.At.Y()), e.Display.Height(w.Headroom(e)))
+}
+
+// Pose is where the camera goes and what it looks at, in the viewer's coords, which have Y up
+func (w *Walker) Pose() (eye, target math32.Vector3) {
+	e, t := w.Eye(), w.Eye().Add(w.facing())
+	return math32.Vector3{X: float32(e.X()), Y: float32(e.Z()), Z: float32(e.Y())},
+		math32.Vector3{X: float32(t.X()), Y: float32(t.Z()), Z: float32(t.Y())}
+}
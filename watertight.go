@@ -0,0 +1,186 @@
+package main
+
+// ██╗    ██╗ █████╗ ████████╗███████╗██████╗ ████████╗██╗ ██████╗ ██╗  ██╗████████╗
+// ██║    ██║██╔══██╗╚══██╔══╝██╔════╝██╔══██╗╚══██╔══╝██║██╔════╝ ██║  ██║╚══██╔══╝
+// ██║ █╗ ██║███████║   ██║   █████╗  ██████╔╝   ██║   ██║██║  ███╗███████║   ██║
+// ██║███╗██║██╔══██║   ██║   ██╔══╝  ██╔══██╗   ██║   ██║██║   ██║██╔══██║   ██║
+// ╚███╔███╔╝██║  ██║   ██║   ███████╗██║  ██║   ██║   ██║╚██████╔╝██║  ██║   ██║
+//  ╚══╝╚══╝ ╚═╝  ╚═╝   ╚═╝   ╚══════╝╚═╝  ╚═╝   ╚═╝   ╚═╝ ╚═════╝ ╚═╝  ╚═╝   ╚═╝
+
+import (
+	"fmt"
+	"math"
+
+	v3 "./vec"
+)
+
+// duplicateTolerance is how close two vertices must be to count as the same point, m
+const duplicateTolerance = 0.0001
+
+// WatertightReport lists what stops the alive panels being a clean closed surface, apart from
+//   the floor and the openings which are meant to be there
+type WatertightReport struct {
+	Holes       []*Edge      // edges on one panel which are not on the floor or round a cut
+	NonManifold []*Edge      // edges on more than two panels
+	Unwound     []*Edge      // edges whose two panels run along them the same way
+	Backwards   []*Panel     // panels whose corners go round clockwise, seen from outside
+	Duplicates  [][2]*Vertex // separate vertices at the same point
+}
+
+// OK is true if nothing is wrong
+func (r WatertightReport) OK() bool {
+	return len(r.Holes)+len(r.NonManifold)+len(r.Unwound)+len(r.Backwards)+len(r.Duplicates) == 0
+}
+
+// String summarises the report
+func (r WatertightReport) String() string {
+	if r.OK() {
+		return "Watertight: OK\n"
+	}
+	return fmt.Sprintf("Watertight: %d holes, %d non-manifold edges, %d edges wound inconsistently, %d panels backwards, %d duplicate vertices\n",
+		len(r.Holes), len(r.NonManifold), len(r.Unwound), len(r.Backwards), len(r.Duplicates))
+}
+
+// winds is +1 if the panel's corners go from a to b along the edge, -1 if from b to a, else 0
+func (p *Panel) winds(a, b *Vertex) int {
+	n := len(p.Corners)
+	for i, c := range p.Corners {
+		if c == a && p.Corners[(i+1)%n] == b {
+			return 1
+		}
+		if c == b && p.Corners[(i+1)%n] == a {
+			return -1
+		}
+	}
+	return 0
+}
+
+// woundOutwards is true if the corners go anticlockwise seen from outside, by the panel's normal
+func (p *Panel) woundOutwards() bool {
+	a, b, c := p.Corners[0].Position, p.Corners[1].Position, p.Corners[2].Position
+	return b.Subtract(a).Cross(c.Subtract(a)).Dot(p.Normal) >= 0
+}
+
+// onCut is true if the edge lies along one of the recorded cuts
+func (e *EShell) onCut(ed *Edge) bool {
+	a, b := ed.Vertices[0].Position, ed.Vertices[1].Position
+	same := func(p, q v3.Vec) bool { return p.Subtract(q).Length() < duplicateTolerance }
+	for _, c := range e.Cuts {
+		if (same(a, c.start) && same(b, c.end)) || (same(a, c.end) && same(b, c.start)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Watertight checks that the shell would make a clean closed solid for STL: every edge is on
+//   two panels, except on the floor or round an opening, neighbouring panels wind the same way
+//   and no two vertices are at the same point
+func (e *EShell) Watertight() WatertightReport {
+	r := WatertightReport{}
+	for _, p := range alivePanels(e.Panels) {
+		if len(p.Corners) != 3 {
+			continue
+		}
+		p.Update(e)
+		if !p.woundOutwards() {
+			r.Backwards = append(r.Backwards, p)
+		}
+	}
+	for _, ed := range aliveEdges(e.Edges) {
+		ps := alivePanels(ed.Panels)
+		switch {
+		case len(ps) == 1:
+			if !e.onBase(ed) && !e.onCut(ed) {
+				r.Holes = append(r.Holes, ed)
+			}
+		case len(ps) > 2:
+			r.NonManifold = append(r.NonManifold, ed)
+		case len(ps) == 2:
+			a, b := ed.Vertices[0], ed.Vertices[1]
+			if ps[0].winds(a, b)*ps[1].winds(a, b) >= 0 {
+				r.Unwound = append(r.Unwound, ed)
+			}
+		}
+	}
+	r.Duplicates = e.duplicateVertices(duplicateTolerance)
+	return r
+}
+
+// duplicateVertices finds pairs of alive vertices closer than tol, by sorting them into cells
+func (e *EShell) duplicateVertices(tol float64) [][2]*Vertex {
+	cell := func(p v3.Vec) [3]int {
+		return [3]int{int(math.Floor(p.X() / tol)), int(math.Floor(p.Y() / tol)), int(math.Floor(p.Z() / tol))}
+	}
+	cells := map[[3]int][]*Vertex{}
+	var dups [][2]*Vertex
+	for _, v := range e.Vertices {
+		if !v.Alive {
+			continue
+		}
+		c := cell(v.Position)
+		for dx := -1; dx <= 1; dx++ {
+			for dy := -1; dy <= 1; dy++ {
+				for dz := -1; dz <= 1; dz++ {
+					for _, w := range cells[[3]int{c[0] + dx, c[1] + dy, c[2] + dz}] {
+						if w.Position.Subtract(v.Position).Length() < tol {
+							dups = append(dups, [2]*Vertex{w, v})
+						}
+					}
+				}
+			}
+		}
+		cells[c] = append(cells[c], v)
+	}
+	return dups
+}
+
+// RepairWatertight fixes what it can: duplicate vertices are merged, dropping any edges and
+//   panels which collapse, and the panels are all wound the same way by Orient. Holes and
+//   non-manifold edges are left alone. Returns the report after repair.
+func (e *EShell) RepairWatertight() WatertightReport {
+	r := e.Watertight()
+	for _, d := range r.Duplicates {
+		if d[0].Alive && d[1].Alive {
+			e.mergeVertices(d[0], d[1])
+		}
+	}
+	e.Undertaker()
+	e.Orient()
+	return e.Watertight()
+}
+
+// mergeVertices combines v1 into v0, where they are at the same point, then tidies up: edges
+//   which now join v0 to itself go, as do panels with a doubled corner, and edges which now
+//   duplicate another are merged into it
+func (e *EShell) mergeVertices(v0, v1 *Vertex) {
+	e.CombineVertices(v0, v1, v0.Position)
+	e.RemoveVertex(v1)
+	for _, p := range alivePanels(v0.Panels) {
+		if p.Corners[0] == p.Corners[1] || p.Corners[1] == p.Corners[2] || p.Corners[2] == p.Corners[0] {
+			e.RemovePanel(p)
+		}
+	}
+	to := map[*Vertex]*Edge{} // the edge kept to each neighbour
+	for _, ed := range aliveEdges(v0.Edges) {
+		o := ed.OtherEnd(v0)
+		if o == v0 {
+			e.RemoveEdge(ed)
+			continue
+		}
+		keep, ok := to[o]
+		if !ok {
+			to[o] = ed
+			continue
+		}
+		for _, p := range ed.Panels {
+			for i, pe := range p.Edges {
+				if pe == ed {
+					p.Edges[i] = keep
+				}
+			}
+			keep.Panels = appendUniquePanel(keep.Panels, p)
+		}
+		e.RemoveEdge(ed)
+	}
+}